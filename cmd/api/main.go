@@ -18,11 +18,18 @@ import (
 
 	_ "github.com/gibbon/finace-dashboard/docs"
 	"github.com/gibbon/finace-dashboard/internal/config"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	domainRepository "github.com/gibbon/finace-dashboard/internal/domain/repository"
 	"github.com/gibbon/finace-dashboard/internal/handlers"
 	appMiddleware "github.com/gibbon/finace-dashboard/internal/middleware"
 	"github.com/gibbon/finace-dashboard/internal/repository"
 	"github.com/gibbon/finace-dashboard/internal/service"
+	"github.com/gibbon/finace-dashboard/pkg/blobstore"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
 	"github.com/gibbon/finace-dashboard/pkg/jwt"
+	"github.com/gibbon/finace-dashboard/pkg/notify"
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -61,6 +68,19 @@ func main() {
 	txRepo := repository.NewPostgresTransactionRepository(dbPool)
 	categoryRepo := repository.NewPostgresCategoryRepository(dbPool)
 	ruleRepo := repository.NewPostgresUserCategoryRuleRepository(dbPool)
+	splitRepo := repository.NewPostgresTransactionSplitRepository(dbPool)
+	tagRepo := repository.NewPostgresTagRepository(dbPool)
+	attachmentRepo := repository.NewPostgresAttachmentRepository(dbPool)
+	receiptRepo := repository.NewPostgresFiscalReceiptRepository(dbPool)
+	merchantRepo := repository.NewPostgresMerchantRepository(dbPool)
+	goalRepo := repository.NewPostgresGoalRepository(dbPool)
+	anomalyRepo := repository.NewPostgresAnomalyRepository(dbPool)
+	notificationRepo := repository.NewPostgresNotificationRepository(dbPool)
+	webhookRepo := repository.NewPostgresWebhookRepository(dbPool)
+	syncRepo := repository.NewPostgresSyncRepository(dbPool)
+	auditRepo := repository.NewPostgresAuditRepository(dbPool)
+	workspaceRepo := repository.NewPostgresWorkspaceRepository(dbPool)
+	transactor := repository.NewPostgresTransactor(dbPool)
 
 	authService := service.NewAuthService(userRepo, service.AuthServiceConfig{
 		JWTSecret:     cfg.JWT.Secret,
@@ -68,14 +88,83 @@ func main() {
 		RefreshExpiry: cfg.JWT.RefreshExpiry,
 	})
 
-	txService := service.NewTransactionService(txRepo, categoryRepo, ruleRepo)
+	// Права на транзакции и правила с учётом ролей в рабочих пространствах
+	policy := service.NewAccessPolicy(workspaceRepo)
+
+	bus, err := newEventBus(cfg.Events, cfg.Redis, transactor)
+	if err != nil {
+		log.Fatalf("Failed to initialize event bus: %v", err)
+	}
+
+	notifiers, err := newNotifiers(cfg.Notify)
+	if err != nil {
+		log.Fatalf("Failed to initialize notification channels: %v", err)
+	}
+	notificationService := service.NewNotificationService(notificationRepo, notifiers, bus, transactor)
+	anomalyService := service.NewAnomalyService(anomalyRepo, categoryRepo, notificationService, transactor)
+	webhookService := service.NewWebhookService(webhookRepo, webhook.NewSender(nil))
+	streamService := service.NewStreamService()
+	auditService := service.NewAuditService(auditRepo, policy)
+	webhookService.Subscribe(bus)
+	anomalyService.Subscribe(bus)
+	streamService.Subscribe(bus)
+	auditService.Subscribe(bus)
+
+	workspaceService := service.NewWorkspaceService(workspaceRepo, userRepo, notifiers[model.NotificationEmail], transactor)
+
+	txService := service.NewTransactionService(txRepo, categoryRepo, ruleRepo, splitRepo, tagRepo, merchantRepo, bus, transactor, policy)
+	categoryService := service.NewCategoryService(categoryRepo, bus, transactor, policy)
+	analyticsService := service.NewAnalyticsService(txRepo, categoryRepo, tagRepo, merchantRepo, policy)
+	splitService := service.NewSplitService(txRepo, splitRepo, categoryRepo, bus, transactor, policy)
+	tagService := service.NewTagService(tagRepo, txRepo, transactor, policy)
+	receiptService := service.NewReceiptService(receiptRepo, categoryRepo, txService, bus, transactor)
+	merchantService := service.NewMerchantService(merchantRepo, transactor)
+	goalService := service.NewGoalService(goalRepo, tagRepo, txService, transactor)
+	syncService := service.NewSyncService(syncRepo, txRepo, categoryRepo, tagRepo, txService, policy)
+	trashService := service.NewTrashService(txRepo, tagRepo, bus, transactor, policy, cfg.Trash.Retention)
+
+	blobStore, err := newBlobStore(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+	attachmentService := service.NewAttachmentService(attachmentRepo, txRepo, blobStore, service.AttachmentServiceConfig{
+		MaxSize: cfg.Storage.MaxAttachmentSize,
+	}, policy)
+
+	// Фоновые задачи останавливаются вместе с сервером
+	appCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	go bus.Run(appCtx)
+	go attachmentService.RunBlobCleanup(appCtx, cfg.Storage.CleanupInterval)
+	go anomalyService.RunDetection(appCtx, cfg.Anomaly.ScanInterval)
+	go notificationService.RunDelivery(appCtx, cfg.Notify.DeliveryInterval)
+	go webhookService.RunDelivery(appCtx, cfg.Webhook.DeliveryInterval)
+	go trashService.RunPurge(appCtx, cfg.Trash.PurgeInterval)
 
 	jwtManager := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry)
 	authHandler := handlers.NewAuthHandler(authService)
 	authMiddleware := appMiddleware.NewAuthMiddleware(jwtManager)
 	txHandler := handlers.NewTransactionHandler(txService)
-	categoryHandler := handlers.NewCategoryHandler(txService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	categoryRuleHandler := handlers.NewCategoryRuleHandler(txService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	reviewHandler := handlers.NewReviewHandler(txService)
+	splitHandler := handlers.NewSplitHandler(splitService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	merchantHandler := handlers.NewMerchantHandler(merchantService)
+	goalHandler := handlers.NewGoalHandler(goalService)
+	insightsHandler := handlers.NewInsightsHandler(anomalyService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	streamHandler := handlers.NewStreamHandler(streamService, cfg.Stream.HeartbeatInterval)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	historyHandler := handlers.NewHistoryHandler(txService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
 
 	r := chi.NewRouter()
 
@@ -84,12 +173,13 @@ func main() {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(appMiddleware.Timeout(60*time.Second, "/api/v1/stream"))
+	r.Use(appMiddleware.LocaleMiddleware)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -112,13 +202,10 @@ func main() {
 			r.Post("/logout", authHandler.Logout)
 		})
 
-		// Категории
-		// TODO: сделать protected
-		r.Get("/categories", categoryHandler.GetAll)
-
 		// Protected routes
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware.Middleware)
+			r.Use(appMiddleware.AuditMiddleware)
 
 			// User info
 			r.Get("/me", func(w http.ResponseWriter, r *http.Request) {
@@ -128,21 +215,147 @@ func main() {
 				fmt.Fprintf(w, `{"user_id": "%s", "email": "%s"}`, userID, email)
 			})
 
+			// Поток обновлений (SSE), без общего таймаута запроса
+			r.Get("/stream", streamHandler.Stream)
+
 			// Транзакции
 			r.Route("/transactions", func(r chi.Router) {
 				r.Post("/", txHandler.Create)
 				r.Get("/", txHandler.GetAll)
+				r.Post("/bulk", txHandler.Bulk)
+				r.Post("/receipt", receiptHandler.Import)
+				r.Post("/receipt/image", receiptHandler.ImportImage)
+				r.Get("/review", reviewHandler.GetQueue)
+				r.Get("/review/count", reviewHandler.GetCounts)
+				r.Get("/trash", trashHandler.GetAll)
 				r.Get("/{id}", txHandler.GetByID)
 				r.Put("/{id}", txHandler.Update)
+				r.Patch("/{id}", txHandler.Patch)
+				r.Post("/{id}/review", reviewHandler.Review)
+				r.Post("/{id}/restore", trashHandler.Restore)
+				r.Get("/{id}/history", historyHandler.GetHistory)
+				r.Post("/{id}/revert/{revision}", historyHandler.Revert)
+				r.Get("/{id}/splits", splitHandler.Get)
+				r.Post("/{id}/splits", splitHandler.Create)
+				r.Put("/{id}/splits", splitHandler.Replace)
+				r.Delete("/{id}/splits", splitHandler.Delete)
+				r.Put("/{id}/tags", tagHandler.SetTransactionTags)
+				r.Get("/{id}/attachments", attachmentHandler.GetByTransaction)
+				r.Post("/{id}/attachments", attachmentHandler.Upload)
 				r.Delete("/{id}", txHandler.Delete)
 			})
 
+			// Категории
+			r.Route("/categories", func(r chi.Router) {
+				r.Post("/", categoryHandler.Create)
+				r.Get("/", categoryHandler.GetAll)
+				r.Get("/{id}", categoryHandler.GetByID)
+				r.Put("/{id}", categoryHandler.Update)
+				r.Delete("/{id}", categoryHandler.Delete)
+			})
+
 			// Правила категорий
 			r.Route("/category-rules", func(r chi.Router) {
 				r.Post("/", categoryRuleHandler.Create)
 				r.Get("/", categoryRuleHandler.GetAll)
 				r.Delete("/{id}", categoryRuleHandler.Delete)
 			})
+
+			// Вложения
+			r.Route("/attachments", func(r chi.Router) {
+				r.Get("/{id}", attachmentHandler.Download)
+				r.Get("/{id}/thumbnail", attachmentHandler.Thumbnail)
+				r.Delete("/{id}", attachmentHandler.Delete)
+			})
+
+			// Метки
+			r.Route("/tags", func(r chi.Router) {
+				r.Get("/", tagHandler.GetAll)
+				r.Post("/", tagHandler.Create)
+				r.Post("/bulk", tagHandler.Bulk)
+				r.Get("/{id}", tagHandler.GetByID)
+				r.Put("/{id}", tagHandler.Update)
+				r.Delete("/{id}", tagHandler.Delete)
+			})
+
+			// Продавцы
+			r.Route("/merchants", func(r chi.Router) {
+				r.Get("/", merchantHandler.GetAll)
+				r.Post("/match", merchantHandler.Match)
+				r.Get("/{id}", merchantHandler.GetByID)
+				r.Put("/{id}", merchantHandler.Update)
+				r.Delete("/{id}", merchantHandler.Delete)
+				r.Post("/{id}/aliases", merchantHandler.AddAlias)
+				r.Delete("/{id}/aliases/{alias}", merchantHandler.RemoveAlias)
+				r.Post("/{id}/merge", merchantHandler.Merge)
+			})
+
+			// Цели накопления
+			r.Route("/goals", func(r chi.Router) {
+				r.Get("/", goalHandler.GetAll)
+				r.Post("/", goalHandler.Create)
+				r.Get("/{id}", goalHandler.GetByID)
+				r.Put("/{id}", goalHandler.Update)
+				r.Delete("/{id}", goalHandler.Delete)
+				r.Post("/{id}/contributions", goalHandler.Contribute)
+			})
+
+			// Аналитика
+			r.Route("/analytics", func(r chi.Router) {
+				r.Get("/categories", analyticsHandler.ByCategory)
+				r.Get("/tags", analyticsHandler.ByTag)
+				r.Get("/merchants", analyticsHandler.ByMerchant)
+				r.Get("/places", analyticsHandler.ByPlace)
+				r.Get("/forecast", analyticsHandler.Forecast)
+			})
+
+			// Необычные траты
+			r.Route("/insights", func(r chi.Router) {
+				r.Get("/anomalies", insightsHandler.GetAnomalies)
+				r.Post("/anomalies/{id}/dismiss", insightsHandler.DismissAnomaly)
+			})
+
+			// Уведомления
+			r.Route("/notifications", func(r chi.Router) {
+				r.Get("/channels", notificationHandler.GetChannels)
+				r.Put("/channels/{channel}", notificationHandler.SetChannel)
+				r.Delete("/channels/{channel}", notificationHandler.DeleteChannel)
+				r.Get("/preferences", notificationHandler.GetPreferences)
+				r.Put("/preferences", notificationHandler.SetPreferences)
+			})
+
+			// Исходящие вебхуки
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Get("/", webhookHandler.GetAll)
+				r.Post("/", webhookHandler.Create)
+				r.Put("/{id}", webhookHandler.Update)
+				r.Delete("/{id}", webhookHandler.Delete)
+				r.Get("/{id}/deliveries", webhookHandler.GetDeliveries)
+				r.Post("/{id}/deliveries/{deliveryID}/redeliver", webhookHandler.Redeliver)
+			})
+
+			// Синхронизация мобильных клиентов
+			r.Get("/sync", syncHandler.Pull)
+			r.Post("/sync", syncHandler.Push)
+
+			// Журнал аудита
+			r.Get("/audit", auditHandler.GetAll)
+
+			// Рабочие пространства
+			r.Route("/workspaces", func(r chi.Router) {
+				r.Get("/", workspaceHandler.GetAll)
+				r.Post("/", workspaceHandler.Create)
+				r.Post("/invitations/accept", workspaceHandler.AcceptInvitation)
+				r.Get("/{id}", workspaceHandler.GetByID)
+				r.Put("/{id}", workspaceHandler.Update)
+				r.Delete("/{id}", workspaceHandler.Delete)
+				r.Get("/{id}/members", workspaceHandler.GetMembers)
+				r.Put("/{id}/members/{userID}", workspaceHandler.UpdateMember)
+				r.Delete("/{id}/members/{userID}", workspaceHandler.RemoveMember)
+				r.Get("/{id}/invitations", workspaceHandler.GetInvitations)
+				r.Post("/{id}/invitations", workspaceHandler.Invite)
+				r.Delete("/{id}/invitations/{invitationID}", workspaceHandler.RevokeInvitation)
+			})
 		})
 	})
 
@@ -153,6 +366,8 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Потоки SSE не завершаются сами, без этого остановка ждала бы отключения клиентов
+	server.RegisterOnShutdown(streamHandler.Close)
 
 	// Graceful shutdown
 	go func() {
@@ -161,6 +376,7 @@ func main() {
 		<-sigint
 
 		log.Println("Shutting down server...")
+		stopBackground()
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -175,3 +391,74 @@ func main() {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
+
+// newBlobStore создаёт хранилище вложений по конфигурации
+func newBlobStore(cfg config.StorageConfig) (blobstore.BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return blobstore.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+}
+
+// newEventBus создаёт шину событий. Асинхронные подписчики получают события после фиксации транзакции
+func newEventBus(cfg config.EventsConfig, redis config.RedisConfig, transactor domainRepository.Transactor) (*eventbus.Bus, error) {
+	busCfg := eventbus.Config{AfterCommit: transactor.AfterCommit}
+	switch cfg.Backend {
+	case "memory":
+	case "redis":
+		busCfg.Transport = eventbus.NewRedisStreams(eventbus.RedisConfig{
+			Addr:     redis.Address(),
+			Password: redis.Password,
+			Stream:   cfg.RedisStream,
+			MaxLen:   cfg.RedisMaxLen,
+		})
+		busCfg.Decode = func(name string, data []byte) (eventbus.Event, error) {
+			return event.Decode(name, data)
+		}
+	default:
+		return nil, fmt.Errorf("unknown events backend %q", cfg.Backend)
+	}
+	return eventbus.New(busCfg), nil
+}
+
+// newNotifiers создаёт отправителей для каналов уведомлений, настроенных в окружении
+func newNotifiers(cfg config.NotifyConfig) (map[model.NotificationChannel]notify.Notifier, error) {
+	notifiers := make(map[model.NotificationChannel]notify.Notifier)
+
+	if cfg.SMTPHost != "" {
+		smtpNotifier, err := notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers[model.NotificationEmail] = smtpNotifier
+	}
+
+	if cfg.WebhookEnabled {
+		notifiers[model.NotificationWebhook] = notify.NewWebhookNotifier(notify.WebhookConfig{Secret: cfg.WebhookSecret})
+	}
+
+	if cfg.TelegramToken != "" {
+		telegramNotifier, err := notify.NewTelegramNotifier(notify.TelegramConfig{Token: cfg.TelegramToken})
+		if err != nil {
+			return nil, err
+		}
+		notifiers[model.NotificationTelegram] = telegramNotifier
+	}
+
+	return notifiers, nil
+}
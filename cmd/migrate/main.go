@@ -161,6 +161,733 @@ func runMigrations(db *sql.DB, direction string) error {
 			`,
 			down: "DROP TABLE IF EXISTS user_category_rules;",
 		},
+		{
+			version: 5,
+			up: `
+				ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+
+				ALTER TABLE categories
+					ADD COLUMN user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+					ADD COLUMN parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+					ADD COLUMN icon VARCHAR(50),
+					ADD COLUMN color VARCHAR(7),
+					ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+				CREATE UNIQUE INDEX idx_categories_default_name ON categories(name) WHERE user_id IS NULL;
+				CREATE UNIQUE INDEX idx_categories_user_name ON categories(user_id, name) WHERE user_id IS NOT NULL;
+				CREATE INDEX idx_categories_user_id ON categories(user_id);
+				CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+				CREATE TRIGGER update_categories_updated_at
+					BEFORE UPDATE ON categories
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				-- Удаление категории теперь требует явного переназначения транзакций
+				ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_id_fkey;
+				ALTER TABLE transactions
+					ADD CONSTRAINT transactions_category_id_fkey
+					FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+			`,
+			down: `
+				ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_id_fkey;
+				ALTER TABLE transactions
+					ADD CONSTRAINT transactions_category_id_fkey
+					FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL;
+
+				DELETE FROM categories WHERE user_id IS NOT NULL;
+				DROP TRIGGER IF EXISTS update_categories_updated_at ON categories;
+				DROP INDEX IF EXISTS idx_categories_default_name;
+				DROP INDEX IF EXISTS idx_categories_user_name;
+				DROP INDEX IF EXISTS idx_categories_user_id;
+				DROP INDEX IF EXISTS idx_categories_parent_id;
+
+				ALTER TABLE categories
+					DROP COLUMN IF EXISTS user_id,
+					DROP COLUMN IF EXISTS parent_id,
+					DROP COLUMN IF EXISTS icon,
+					DROP COLUMN IF EXISTS color,
+					DROP COLUMN IF EXISTS updated_at;
+
+				ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+			`,
+		},
+		{
+			version: 6,
+			up: `
+				ALTER TABLE categories ADD COLUMN translation_key VARCHAR(100) UNIQUE;
+
+				CREATE TABLE category_translations (
+					translation_key VARCHAR(100) NOT NULL,
+					locale VARCHAR(10) NOT NULL,
+					name VARCHAR(100) NOT NULL,
+					PRIMARY KEY (translation_key, locale)
+				);
+
+				UPDATE categories SET translation_key = k.key
+				FROM (VALUES
+					('Продукты', 'category.groceries'),
+					('Транспорт', 'category.transport'),
+					('Рестораны', 'category.restaurants'),
+					('Здоровье', 'category.health'),
+					('Развлечения', 'category.entertainment'),
+					('Дом', 'category.home'),
+					('Одежда', 'category.clothing'),
+					('Красота', 'category.beauty'),
+					('Образование', 'category.education'),
+					('Переводы', 'category.transfers'),
+					('Налоги и сборы', 'category.taxes'),
+					('Доходы', 'category.income'),
+					('Другое', 'category.other')
+				) AS k(name, key)
+				WHERE categories.name = k.name AND categories.user_id IS NULL;
+
+				INSERT INTO category_translations (translation_key, locale, name) VALUES
+					('category.groceries', 'ru', 'Продукты'),
+					('category.groceries', 'en', 'Groceries'),
+					('category.transport', 'ru', 'Транспорт'),
+					('category.transport', 'en', 'Transport'),
+					('category.restaurants', 'ru', 'Рестораны'),
+					('category.restaurants', 'en', 'Restaurants'),
+					('category.health', 'ru', 'Здоровье'),
+					('category.health', 'en', 'Health'),
+					('category.entertainment', 'ru', 'Развлечения'),
+					('category.entertainment', 'en', 'Entertainment'),
+					('category.home', 'ru', 'Дом'),
+					('category.home', 'en', 'Home'),
+					('category.clothing', 'ru', 'Одежда'),
+					('category.clothing', 'en', 'Clothing'),
+					('category.beauty', 'ru', 'Красота'),
+					('category.beauty', 'en', 'Beauty'),
+					('category.education', 'ru', 'Образование'),
+					('category.education', 'en', 'Education'),
+					('category.transfers', 'ru', 'Переводы'),
+					('category.transfers', 'en', 'Transfers'),
+					('category.taxes', 'ru', 'Налоги и сборы'),
+					('category.taxes', 'en', 'Taxes and fees'),
+					('category.income', 'ru', 'Доходы'),
+					('category.income', 'en', 'Income'),
+					('category.other', 'ru', 'Другое'),
+					('category.other', 'en', 'Other');
+			`,
+			down: `
+				DROP TABLE IF EXISTS category_translations;
+				ALTER TABLE categories DROP COLUMN IF EXISTS translation_key;
+			`,
+		},
+		{
+			version: 7,
+			up: `
+				-- Очередь проверки и подсказка категории по истории подтверждённых транзакций
+				CREATE INDEX idx_transactions_user_unconfirmed ON transactions(user_id, date DESC) WHERE NOT is_confirmed;
+				CREATE INDEX idx_transactions_user_description ON transactions(user_id, lower(description)) WHERE is_confirmed;
+			`,
+			down: `
+				DROP INDEX IF EXISTS idx_transactions_user_unconfirmed;
+				DROP INDEX IF EXISTS idx_transactions_user_description;
+			`,
+		},
+		{
+			version: 8,
+			up: `
+				CREATE TABLE transaction_splits (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+					category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+					amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+					note VARCHAR(500),
+					position INTEGER NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					UNIQUE(transaction_id, position)
+				);
+
+				CREATE INDEX idx_transaction_splits_category_id ON transaction_splits(category_id);
+			`,
+			down: "DROP TABLE IF EXISTS transaction_splits;",
+		},
+		{
+			version: 9,
+			up: `
+				CREATE TABLE tags (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					name VARCHAR(50) NOT NULL,
+					color VARCHAR(7),
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, lower(name));
+
+				CREATE TRIGGER update_tags_updated_at
+					BEFORE UPDATE ON tags
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				CREATE TABLE transaction_tags (
+					transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+					tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+					PRIMARY KEY (transaction_id, tag_id)
+				);
+
+				CREATE INDEX idx_transaction_tags_tag_id ON transaction_tags(tag_id);
+			`,
+			down: `
+				DROP TABLE IF EXISTS transaction_tags;
+				DROP TABLE IF EXISTS tags;
+			`,
+		},
+		{
+			version: 10,
+			up: `
+				CREATE TABLE attachments (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					file_name VARCHAR(255) NOT NULL,
+					content_type VARCHAR(100) NOT NULL,
+					size BIGINT NOT NULL,
+					storage_key VARCHAR(500) NOT NULL UNIQUE,
+					thumbnail_key VARCHAR(500),
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_attachments_transaction_id ON attachments(transaction_id);
+
+				-- Файлы удаляются из хранилища асинхронно: триггер ставит ключи в очередь
+				-- при любом удалении вложения, в том числе каскадном вместе с транзакцией
+				CREATE TABLE blob_deletions (
+					id BIGSERIAL PRIMARY KEY,
+					storage_key VARCHAR(500) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE OR REPLACE FUNCTION enqueue_attachment_blobs()
+				RETURNS TRIGGER AS $$
+				BEGIN
+					INSERT INTO blob_deletions (storage_key) VALUES (OLD.storage_key);
+					IF OLD.thumbnail_key IS NOT NULL THEN
+						INSERT INTO blob_deletions (storage_key) VALUES (OLD.thumbnail_key);
+					END IF;
+					RETURN OLD;
+				END;
+				$$ LANGUAGE plpgsql;
+
+				CREATE TRIGGER enqueue_attachment_blobs
+					AFTER DELETE ON attachments
+					FOR EACH ROW
+					EXECUTE FUNCTION enqueue_attachment_blobs();
+			`,
+			down: `
+				DROP TABLE IF EXISTS attachments;
+				DROP FUNCTION IF EXISTS enqueue_attachment_blobs();
+				DROP TABLE IF EXISTS blob_deletions;
+			`,
+		},
+		{
+			version: 11,
+			up: `
+				CREATE TABLE fiscal_receipts (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+					fn VARCHAR(16) NOT NULL,
+					fd VARCHAR(10) NOT NULL,
+					fp VARCHAR(10) NOT NULL,
+					operation SMALLINT NOT NULL,
+					raw TEXT NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					UNIQUE(user_id, fn, fd, fp)
+				);
+			`,
+			down: "DROP TABLE IF EXISTS fiscal_receipts;",
+		},
+		{
+			version: 12,
+			up: `
+				-- Поиск транзакций в области и карта расходов
+				CREATE INDEX idx_transactions_user_place ON transactions(user_id, place_lat, place_lon)
+					WHERE place_lat IS NOT NULL AND place_lon IS NOT NULL;
+			`,
+			down: "DROP INDEX IF EXISTS idx_transactions_user_place;",
+		},
+		{
+			version: 13,
+			up: `
+				CREATE TABLE merchants (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					name VARCHAR(255) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_merchants_user_id ON merchants(user_id);
+
+				CREATE TRIGGER update_merchants_updated_at
+					BEFORE UPDATE ON merchants
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				-- Ключ нормализованного описания однозначно указывает на продавца пользователя
+				CREATE TABLE merchant_aliases (
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					key VARCHAR(255) NOT NULL,
+					merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (user_id, key)
+				);
+
+				CREATE INDEX idx_merchant_aliases_merchant_id ON merchant_aliases(merchant_id);
+
+				ALTER TABLE transactions ADD COLUMN merchant_id UUID REFERENCES merchants(id) ON DELETE SET NULL;
+				CREATE INDEX idx_transactions_merchant_id ON transactions(merchant_id);
+				CREATE INDEX idx_transactions_user_unmatched ON transactions(user_id) WHERE merchant_id IS NULL;
+			`,
+			down: `
+				ALTER TABLE transactions DROP COLUMN IF EXISTS merchant_id;
+				DROP TABLE IF EXISTS merchant_aliases;
+				DROP TABLE IF EXISTS merchants;
+			`,
+		},
+		{
+			version: 14,
+			up: `
+				-- Цель накопления; взносы — транзакции с меткой цели
+				CREATE TABLE goals (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					name VARCHAR(100) NOT NULL,
+					target_amount DECIMAL(15, 2) NOT NULL CHECK (target_amount > 0),
+					currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+					deadline TIMESTAMP WITH TIME ZONE,
+					tag_id UUID REFERENCES tags(id) ON DELETE SET NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_goals_user_id ON goals(user_id);
+
+				CREATE TRIGGER update_goals_updated_at
+					BEFORE UPDATE ON goals
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+			`,
+			down: "DROP TABLE IF EXISTS goals;",
+		},
+		{
+			version: 15,
+			up: `
+				CREATE TABLE anomalies (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					kind VARCHAR(30) NOT NULL,
+					transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+					category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+					merchant_id UUID REFERENCES merchants(id) ON DELETE SET NULL,
+					period DATE,
+					amount DECIMAL(15, 2) NOT NULL,
+					baseline DECIMAL(15, 2) NOT NULL,
+					ratio DOUBLE PRECISION NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					dismissed_at TIMESTAMP WITH TIME ZONE,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_anomalies_user_created ON anomalies(user_id, created_at DESC);
+
+				-- Одна аномалия на транзакцию каждого вида и один всплеск на категорию за месяц
+				CREATE UNIQUE INDEX idx_anomalies_transaction_kind ON anomalies(transaction_id, kind)
+					WHERE period IS NULL;
+				CREATE UNIQUE INDEX idx_anomalies_category_period ON anomalies(user_id, category_id, currency, period)
+					WHERE period IS NOT NULL;
+
+				CREATE INDEX idx_transactions_created_at ON transactions(created_at);
+			`,
+			down: `
+				DROP INDEX IF EXISTS idx_transactions_created_at;
+				DROP TABLE IF EXISTS anomalies;
+			`,
+		},
+		{
+			version: 16,
+			up: `
+				CREATE TABLE notification_targets (
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					channel VARCHAR(20) NOT NULL,
+					address VARCHAR(2048) NOT NULL,
+					locale VARCHAR(10) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (user_id, channel)
+				);
+
+				CREATE TRIGGER update_notification_targets_updated_at
+					BEFORE UPDATE ON notification_targets
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				CREATE TABLE notification_preferences (
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					event VARCHAR(50) NOT NULL,
+					channel VARCHAR(20) NOT NULL,
+					enabled BOOLEAN NOT NULL,
+					PRIMARY KEY (user_id, event, channel)
+				);
+
+				-- Очередь отправки: уведомление пишется в одной транзакции с событием
+				CREATE TABLE notification_outbox (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					event VARCHAR(50) NOT NULL,
+					channel VARCHAR(20) NOT NULL,
+					address VARCHAR(2048) NOT NULL,
+					subject TEXT NOT NULL,
+					text TEXT NOT NULL,
+					data JSONB,
+					status VARCHAR(10) NOT NULL DEFAULT 'pending',
+					attempts INTEGER NOT NULL DEFAULT 0,
+					next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					last_error TEXT,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					sent_at TIMESTAMP WITH TIME ZONE
+				);
+
+				CREATE INDEX idx_notification_outbox_due ON notification_outbox(next_attempt_at)
+					WHERE status = 'pending';
+				CREATE INDEX idx_notification_outbox_created ON notification_outbox(created_at)
+					WHERE status <> 'pending';
+			`,
+			down: `
+				DROP TABLE IF EXISTS notification_outbox;
+				DROP TABLE IF EXISTS notification_preferences;
+				DROP TABLE IF EXISTS notification_targets;
+			`,
+		},
+		{
+			version: 17,
+			up: `
+				CREATE TABLE webhooks (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					url VARCHAR(2048) NOT NULL,
+					secret VARCHAR(100) NOT NULL,
+					events TEXT[] NOT NULL,
+					active BOOLEAN NOT NULL DEFAULT TRUE,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_webhooks_user ON webhooks(user_id);
+
+				CREATE TRIGGER update_webhooks_updated_at
+					BEFORE UPDATE ON webhooks
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				-- Очередь отправки и журнал доставок: доставка пишется в одной транзакции с изменением
+				CREATE TABLE webhook_deliveries (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					event_id UUID NOT NULL,
+					event VARCHAR(50) NOT NULL,
+					payload JSONB NOT NULL,
+					status VARCHAR(10) NOT NULL DEFAULT 'pending',
+					attempts INTEGER NOT NULL DEFAULT 0,
+					next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					response_status INTEGER,
+					response_body TEXT,
+					duration_ms BIGINT,
+					last_error TEXT,
+					redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					delivered_at TIMESTAMP WITH TIME ZONE
+				);
+
+				CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+				CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at)
+					WHERE status = 'pending';
+				CREATE INDEX idx_webhook_deliveries_created ON webhook_deliveries(created_at)
+					WHERE status <> 'pending';
+			`,
+			down: `
+				DROP TABLE IF EXISTS webhook_deliveries;
+				DROP TABLE IF EXISTS webhooks;
+			`,
+		},
+		{
+			version: 18,
+			up: `
+				-- Версия изменения для синхронизации — номер транзакции БД (xid8 не переполняется).
+				-- Токен клиента — xmin снимка: все транзакции с меньшим номером уже завершены
+				CREATE OR REPLACE FUNCTION set_sync_xid()
+				RETURNS TRIGGER AS $$
+				BEGIN
+					NEW.sync_xid = pg_current_xact_id()::text::bigint;
+					IF TG_OP = 'INSERT' THEN
+						NEW.sync_created_xid = NEW.sync_xid;
+					END IF;
+					RETURN NEW;
+				END;
+				$$ LANGUAGE plpgsql;
+
+				ALTER TABLE transactions
+					ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+					ADD COLUMN sync_created_xid BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN sync_xid BIGINT NOT NULL DEFAULT 0;
+				ALTER TABLE user_category_rules
+					ADD COLUMN sync_created_xid BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN sync_xid BIGINT NOT NULL DEFAULT 0;
+				ALTER TABLE categories
+					ADD COLUMN sync_created_xid BIGINT NOT NULL DEFAULT 0,
+					ADD COLUMN sync_xid BIGINT NOT NULL DEFAULT 0;
+
+				CREATE INDEX idx_transactions_user_sync ON transactions(user_id, sync_xid);
+				CREATE INDEX idx_user_category_rules_user_sync ON user_category_rules(user_id, sync_xid);
+				CREATE INDEX idx_categories_sync ON categories(sync_xid);
+
+				CREATE TRIGGER set_transactions_sync_xid
+					BEFORE INSERT OR UPDATE ON transactions
+					FOR EACH ROW
+					EXECUTE FUNCTION set_sync_xid();
+				CREATE TRIGGER set_user_category_rules_sync_xid
+					BEFORE INSERT OR UPDATE ON user_category_rules
+					FOR EACH ROW
+					EXECUTE FUNCTION set_sync_xid();
+				CREATE TRIGGER set_categories_sync_xid
+					BEFORE INSERT OR UPDATE ON categories
+					FOR EACH ROW
+					EXECUTE FUNCTION set_sync_xid();
+
+				-- Следы удалённых правил и категорий. Без внешнего ключа на users:
+				-- каскадное удаление пользователя тоже пишет следы
+				CREATE TABLE sync_tombstones (
+					entity VARCHAR(20) NOT NULL,
+					entity_id VARCHAR(36) NOT NULL,
+					user_id UUID,
+					sync_xid BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint,
+					deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_sync_tombstones_user ON sync_tombstones(entity, user_id, sync_xid);
+				CREATE INDEX idx_sync_tombstones_deleted ON sync_tombstones(deleted_at);
+
+				CREATE OR REPLACE FUNCTION record_sync_tombstone()
+				RETURNS TRIGGER AS $$
+				BEGIN
+					INSERT INTO sync_tombstones (entity, entity_id, user_id)
+					VALUES (TG_ARGV[0], OLD.id::text, OLD.user_id);
+					RETURN OLD;
+				END;
+				$$ LANGUAGE plpgsql;
+
+				CREATE TRIGGER record_user_category_rules_tombstone
+					AFTER DELETE ON user_category_rules
+					FOR EACH ROW
+					EXECUTE FUNCTION record_sync_tombstone('rule');
+				CREATE TRIGGER record_categories_tombstone
+					AFTER DELETE ON categories
+					FOR EACH ROW
+					EXECUTE FUNCTION record_sync_tombstone('category');
+			`,
+			down: `
+				DROP TRIGGER IF EXISTS record_categories_tombstone ON categories;
+				DROP TRIGGER IF EXISTS record_user_category_rules_tombstone ON user_category_rules;
+				DROP FUNCTION IF EXISTS record_sync_tombstone();
+				DROP TABLE IF EXISTS sync_tombstones;
+
+				DROP TRIGGER IF EXISTS set_categories_sync_xid ON categories;
+				DROP TRIGGER IF EXISTS set_user_category_rules_sync_xid ON user_category_rules;
+				DROP TRIGGER IF EXISTS set_transactions_sync_xid ON transactions;
+
+				-- Удалённые транзакции при откате удаляются окончательно
+				DELETE FROM transactions WHERE deleted_at IS NOT NULL;
+
+				ALTER TABLE categories DROP COLUMN IF EXISTS sync_xid, DROP COLUMN IF EXISTS sync_created_xid;
+				ALTER TABLE user_category_rules DROP COLUMN IF EXISTS sync_xid, DROP COLUMN IF EXISTS sync_created_xid;
+				ALTER TABLE transactions
+					DROP COLUMN IF EXISTS sync_xid,
+					DROP COLUMN IF EXISTS sync_created_xid,
+					DROP COLUMN IF EXISTS deleted_at;
+				DROP FUNCTION IF EXISTS set_sync_xid();
+			`,
+		},
+		{
+			version: 19,
+			up: `
+				-- Корзина: удалённые транзакции пользователя и очистка по сроку хранения
+				CREATE INDEX idx_transactions_user_deleted ON transactions(user_id, deleted_at DESC)
+					WHERE deleted_at IS NOT NULL;
+				CREATE INDEX idx_transactions_deleted_at ON transactions(deleted_at)
+					WHERE deleted_at IS NOT NULL;
+
+				-- Окончательно удалённая из корзины транзакция оставляет след для клиентов
+				-- синхронизации, не получивших мягкое удаление
+				CREATE TRIGGER record_transactions_tombstone
+					AFTER DELETE ON transactions
+					FOR EACH ROW
+					WHEN (OLD.deleted_at IS NOT NULL)
+					EXECUTE FUNCTION record_sync_tombstone('transaction');
+			`,
+			down: `
+				DROP TRIGGER IF EXISTS record_transactions_tombstone ON transactions;
+				DELETE FROM sync_tombstones WHERE entity = 'transaction';
+				DROP INDEX IF EXISTS idx_transactions_deleted_at;
+				DROP INDEX IF EXISTS idx_transactions_user_deleted;
+			`,
+		},
+		{
+			version: 20,
+			up: `
+				-- Журнал аудита изменений: записи только добавляются
+				CREATE TABLE audit_log (
+					id BIGSERIAL PRIMARY KEY,
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					actor_id UUID,
+					entity VARCHAR(20) NOT NULL,
+					entity_id VARCHAR(36) NOT NULL,
+					action VARCHAR(10) NOT NULL,
+					before JSONB,
+					after JSONB,
+					request_id VARCHAR(100),
+					client_ip VARCHAR(45),
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_audit_log_user_entity ON audit_log(user_id, entity, entity_id, id DESC);
+
+				-- Удаление разрешено только каскадом при удалении пользователя: такой DELETE
+				-- выполняется из триггера внешнего ключа, и глубина триггеров больше единицы
+				CREATE OR REPLACE FUNCTION forbid_audit_log_change()
+				RETURNS TRIGGER AS $$
+				BEGIN
+					IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+						RETURN OLD;
+					END IF;
+					RAISE EXCEPTION 'audit_log is append-only';
+				END;
+				$$ LANGUAGE plpgsql;
+
+				CREATE TRIGGER audit_log_append_only
+					BEFORE UPDATE OR DELETE ON audit_log
+					FOR EACH ROW
+					EXECUTE FUNCTION forbid_audit_log_change();
+			`,
+			down: `
+				DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+				DROP FUNCTION IF EXISTS forbid_audit_log_change();
+				DROP TABLE IF EXISTS audit_log;
+			`,
+		},
+		{
+			version: 21,
+			up: `
+				-- Прежние версии транзакций: строка добавляется при каждом изменении.
+				-- Номер версии — порядковый номер строки среди версий транзакции
+				CREATE TABLE transaction_revisions (
+					id BIGSERIAL PRIMARY KEY,
+					transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+					amount DECIMAL(15, 2) NOT NULL,
+					currency VARCHAR(3) NOT NULL,
+					description TEXT NOT NULL,
+					date TIMESTAMP WITH TIME ZONE NOT NULL,
+					place_name VARCHAR(255),
+					place_lat DECIMAL(10, 8),
+					place_lon DECIMAL(11, 8),
+					category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+					merchant_id UUID REFERENCES merchants(id) ON DELETE SET NULL,
+					is_confirmed BOOLEAN NOT NULL,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+					replaced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_transaction_revisions_transaction ON transaction_revisions(transaction_id, id);
+			`,
+			down: `
+				DROP TABLE IF EXISTS transaction_revisions;
+			`,
+		},
+		{
+			version: 22,
+			up: `
+				-- Рабочие пространства: общие транзакции и правила нескольких пользователей
+				CREATE TABLE workspaces (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					name VARCHAR(100) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE TRIGGER update_workspaces_updated_at
+					BEFORE UPDATE ON workspaces
+					FOR EACH ROW
+					EXECUTE FUNCTION update_updated_at_column();
+
+				CREATE TABLE workspace_members (
+					workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+					user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					role VARCHAR(10) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (workspace_id, user_id)
+				);
+
+				CREATE INDEX idx_workspace_members_user ON workspace_members(user_id);
+
+				-- Хранится только SHA-256 токена приглашения
+				CREATE TABLE workspace_invitations (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+					email VARCHAR(255) NOT NULL,
+					role VARCHAR(10) NOT NULL,
+					token_hash VARCHAR(64) NOT NULL UNIQUE,
+					invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+					expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+					accepted_at TIMESTAMP WITH TIME ZONE,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX idx_workspace_invitations_workspace ON workspace_invitations(workspace_id);
+
+				-- При удалении пространства его транзакции становятся личными у авторов, а правила удаляются
+				ALTER TABLE transactions ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE SET NULL;
+				CREATE INDEX idx_transactions_workspace_date ON transactions(workspace_id, date)
+					WHERE workspace_id IS NOT NULL;
+
+				ALTER TABLE user_category_rules ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+				ALTER TABLE user_category_rules DROP CONSTRAINT user_category_rules_user_id_keyword_key;
+				CREATE UNIQUE INDEX idx_user_category_rules_personal_keyword ON user_category_rules(user_id, keyword)
+					WHERE workspace_id IS NULL;
+				CREATE UNIQUE INDEX idx_user_category_rules_workspace_keyword ON user_category_rules(workspace_id, keyword)
+					WHERE workspace_id IS NOT NULL;
+			`,
+			down: `
+				DROP INDEX IF EXISTS idx_user_category_rules_workspace_keyword;
+				DROP INDEX IF EXISTS idx_user_category_rules_personal_keyword;
+				DELETE FROM user_category_rules WHERE workspace_id IS NOT NULL;
+				ALTER TABLE user_category_rules DROP COLUMN IF EXISTS workspace_id;
+				ALTER TABLE user_category_rules ADD CONSTRAINT user_category_rules_user_id_keyword_key UNIQUE (user_id, keyword);
+				DROP INDEX IF EXISTS idx_transactions_workspace_date;
+				ALTER TABLE transactions DROP COLUMN IF EXISTS workspace_id;
+				DROP TABLE IF EXISTS workspace_invitations;
+				DROP TABLE IF EXISTS workspace_members;
+				DROP TABLE IF EXISTS workspaces;
+			`,
+		},
+		{
+			version: 23,
+			up: `
+				-- Записи журнала об изменениях в рабочем пространстве. Внешнего ключа нет: журнал
+				-- только дополняется, и история удалённого пространства остаётся в нём
+				ALTER TABLE audit_log ADD COLUMN workspace_id UUID;
+				CREATE INDEX idx_audit_log_workspace_entity ON audit_log(workspace_id, entity, entity_id, id DESC)
+					WHERE workspace_id IS NOT NULL;
+			`,
+			down: `
+				DROP INDEX IF EXISTS idx_audit_log_workspace_entity;
+				ALTER TABLE audit_log DROP COLUMN IF EXISTS workspace_id;
+			`,
+		},
 	}
 
 	if direction == "up" {
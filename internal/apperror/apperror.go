@@ -0,0 +1,126 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind класс ошибки, определяющий HTTP статус
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindPreconditionFailed
+	KindPreconditionRequired
+	KindUnsupportedMediaType
+	KindPayloadTooLarge
+	KindNotImplemented
+)
+
+// Status возвращает HTTP статус для класса ошибки
+func (k Kind) Status() int {
+	switch k {
+	case KindBadRequest, KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	case KindUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindNotImplemented:
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+// Error ошибка приложения со стабильным машиночитаемым кодом.
+// Code одновременно является ключом каталога сообщений i18n
+type Error struct {
+	Kind   Kind
+	Code   string
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError ошибка валидации отдельного поля
+type FieldError struct {
+	Field   string         `json:"field"`
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// New создаёт ошибку с кодом
+func New(kind Kind, code string) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+// Wrap создаёт ошибку с кодом, сохраняя исходную причину
+func Wrap(err error, kind Kind, code string) *Error {
+	return &Error{Kind: kind, Code: code, Err: err}
+}
+
+// Validation создаёт ошибку валидации с перечнем полей
+func Validation(fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidationFailed, Fields: fields}
+}
+
+// Field создаёт ошибку валидации поля
+func Field(field, code string) FieldError {
+	return FieldError{Field: field, Code: code}
+}
+
+// FieldWithParams создаёт ошибку валидации поля с параметрами для сообщения
+func FieldWithParams(field, code string, params map[string]any) FieldError {
+	return FieldError{Field: field, Code: code, Params: params}
+}
+
+// Коды общих ошибок
+const (
+	CodeInternal         = "internal_error"
+	CodeValidationFailed = "validation_failed"
+)
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сравнивает ошибки по коду, чтобы обёрнутые копии совпадали с исходной
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// From извлекает ошибку приложения из цепочки.
+// Неизвестные ошибки превращаются во внутреннюю ошибку с сохранением причины
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Wrap(err, KindInternal, CodeInternal)
+}
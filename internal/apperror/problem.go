@@ -0,0 +1,62 @@
+package apperror
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+)
+
+// ContentTypeProblem тип содержимого ответа об ошибке (RFC 7807)
+const ContentTypeProblem = "application/problem+json"
+
+// problemTypePrefix префикс URI типа проблемы, к нему добавляется код ошибки
+const problemTypePrefix = "urn:finance-dashboard:problem:"
+
+// Problem тело ответа об ошибке в формате RFC 7807
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// Write отправляет ошибку в формате application/problem+json.
+// Сообщения локализуются по локали из контекста запроса
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := From(err)
+	status := appErr.Kind.Status()
+
+	if status >= http.StatusInternalServerError {
+		log.Printf("[%s] %s %s: %v", middleware.GetReqID(r.Context()), r.Method, r.URL.Path, err)
+	}
+
+	problem := Problem{
+		Type:      problemTypePrefix + appErr.Code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    i18n.T(r.Context(), appErr.Code),
+		Instance:  r.URL.Path,
+		Code:      appErr.Code,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+
+	for _, field := range appErr.Fields {
+		if field.Message == "" {
+			field.Message = i18n.Tf(r.Context(), "validation."+field.Code, field.Params)
+		}
+		problem.Errors = append(problem.Errors, field)
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
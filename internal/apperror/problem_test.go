@@ -0,0 +1,109 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+)
+
+var errTestNotFound = New(KindNotFound, "transaction_not_found")
+
+func serveError(err error, locale string) *httptest.ResponseRecorder {
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r.WithContext(i18n.WithLocale(r.Context(), locale)), err)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/transactions/42", nil))
+	return rec
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	return problem
+}
+
+func TestWrite_DomainError(t *testing.T) {
+	rec := serveError(fmt.Errorf("get transaction: %w", errTestNotFound), i18n.LocaleEN)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentTypeProblem {
+		t.Errorf("Expected content type %s, got %s", ContentTypeProblem, ct)
+	}
+
+	problem := decodeProblem(t, rec)
+	if problem.Code != "transaction_not_found" {
+		t.Errorf("Expected code transaction_not_found, got %s", problem.Code)
+	}
+	if problem.Detail != "transaction not found" {
+		t.Errorf("Expected localized detail, got %s", problem.Detail)
+	}
+	if problem.Instance != "/api/v1/transactions/42" {
+		t.Errorf("Expected instance to be request path, got %s", problem.Instance)
+	}
+	if problem.RequestID == "" {
+		t.Error("RequestID should not be empty")
+	}
+}
+
+func TestWrite_Validation(t *testing.T) {
+	err := Validation(
+		Field("amount", "must_be_positive"),
+		FieldWithParams("password", "too_short", map[string]any{"min": 8}),
+	)
+	rec := serveError(err, i18n.LocaleRU)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+
+	problem := decodeProblem(t, rec)
+	if problem.Code != CodeValidationFailed {
+		t.Errorf("Expected code %s, got %s", CodeValidationFailed, problem.Code)
+	}
+	if len(problem.Errors) != 2 {
+		t.Fatalf("Expected 2 field errors, got %d", len(problem.Errors))
+	}
+	if problem.Errors[1].Message != "должно содержать не менее 8 символов" {
+		t.Errorf("Expected message with params, got %s", problem.Errors[1].Message)
+	}
+}
+
+func TestWrite_UnknownError(t *testing.T) {
+	rec := serveError(errors.New("connection refused"), i18n.LocaleEN)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+
+	problem := decodeProblem(t, rec)
+	if problem.Code != CodeInternal {
+		t.Errorf("Expected code %s, got %s", CodeInternal, problem.Code)
+	}
+	if problem.Detail == "connection refused" {
+		t.Error("Internal error cause should not leak to client")
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	wrapped := Wrap(errors.New("no rows"), KindNotFound, "transaction_not_found")
+	if !errors.Is(wrapped, errTestNotFound) {
+		t.Error("Expected wrapped error to match sentinel by code")
+	}
+	if errors.Is(wrapped, New(KindNotFound, "rule_not_found")) {
+		t.Error("Errors with different codes should not match")
+	}
+}
@@ -0,0 +1,24 @@
+// Package audit передаёт в журнал аудита сведения о запросе, изменившем данные
+package audit
+
+import "context"
+
+// Request кто и откуда изменил данные. Поля пусты, если изменение сделано не по запросу пользователя
+type Request struct {
+	ActorID   string
+	RequestID string
+	ClientIP  string
+}
+
+type contextKey struct{}
+
+// WithRequest сохраняет сведения о запросе в контексте
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+// RequestFromContext извлекает сведения о запросе из контекста
+func RequestFromContext(ctx context.Context) Request {
+	req, _ := ctx.Value(contextKey{}).(Request)
+	return req
+}
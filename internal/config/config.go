@@ -13,6 +13,13 @@ type Config struct {
 	Redis     RedisConfig
 	JWT       JWTConfig
 	MLService MLServiceConfig
+	Storage   StorageConfig
+	Anomaly   AnomalyConfig
+	Notify    NotifyConfig
+	Webhook   WebhookConfig
+	Events    EventsConfig
+	Stream    StreamConfig
+	Trash     TrashConfig
 }
 
 type ServerConfig struct {
@@ -57,6 +64,62 @@ type MLServiceConfig struct {
 	Port string `envconfig:"ML_SERVICE_PORT" default:"50051"`
 }
 
+// StorageConfig хранилище вложений: локальный каталог (local) или S3-совместимое (s3)
+type StorageConfig struct {
+	Backend           string        `envconfig:"STORAGE_BACKEND" default:"local"`
+	LocalDir          string        `envconfig:"STORAGE_LOCAL_DIR" default:"./data/attachments"`
+	S3Endpoint        string        `envconfig:"S3_ENDPOINT"`
+	S3Region          string        `envconfig:"S3_REGION" default:"us-east-1"`
+	S3Bucket          string        `envconfig:"S3_BUCKET"`
+	S3AccessKey       string        `envconfig:"S3_ACCESS_KEY"`
+	S3SecretKey       string        `envconfig:"S3_SECRET_KEY"`
+	MaxAttachmentSize int64         `envconfig:"ATTACHMENT_MAX_SIZE" default:"10485760"`
+	CleanupInterval   time.Duration `envconfig:"ATTACHMENT_CLEANUP_INTERVAL" default:"1m"`
+}
+
+// AnomalyConfig периодический поиск необычных трат среди новых транзакций
+type AnomalyConfig struct {
+	ScanInterval time.Duration `envconfig:"ANOMALY_SCAN_INTERVAL" default:"1h"`
+}
+
+// NotifyConfig каналы уведомлений. Пользователи могут настроить только каналы,
+// заданные здесь: почту — при указанном SMTP_HOST, Telegram — при указанном токене бота
+type NotifyConfig struct {
+	DeliveryInterval time.Duration `envconfig:"NOTIFY_DELIVERY_INTERVAL" default:"10s"`
+	SMTPHost         string        `envconfig:"SMTP_HOST"`
+	SMTPPort         int           `envconfig:"SMTP_PORT" default:"587"`
+	SMTPUsername     string        `envconfig:"SMTP_USERNAME"`
+	SMTPPassword     string        `envconfig:"SMTP_PASSWORD"`
+	SMTPFrom         string        `envconfig:"SMTP_FROM"`
+	WebhookEnabled   bool          `envconfig:"NOTIFY_WEBHOOK_ENABLED" default:"true"`
+	WebhookSecret    string        `envconfig:"NOTIFY_WEBHOOK_SECRET"`
+	TelegramToken    string        `envconfig:"TELEGRAM_BOT_TOKEN"`
+}
+
+// WebhookConfig отправка исходящих вебхуков пользователей
+type WebhookConfig struct {
+	DeliveryInterval time.Duration `envconfig:"WEBHOOK_DELIVERY_INTERVAL" default:"10s"`
+}
+
+// EventsConfig шина доменных событий: memory — внутри процесса, redis — события рассылаются
+// всем экземплярам сервиса через поток Redis Streams (подключение из RedisConfig)
+type EventsConfig struct {
+	Backend     string `envconfig:"EVENTS_BACKEND" default:"memory"`
+	RedisStream string `envconfig:"EVENTS_REDIS_STREAM" default:"finance:events"`
+	RedisMaxLen int64  `envconfig:"EVENTS_REDIS_MAXLEN" default:"10000"`
+}
+
+// StreamConfig поток обновлений по Server-Sent Events
+type StreamConfig struct {
+	HeartbeatInterval time.Duration `envconfig:"STREAM_HEARTBEAT_INTERVAL" default:"15s"`
+}
+
+// TrashConfig корзина удалённых транзакций: сколько они хранятся до окончательного удаления
+type TrashConfig struct {
+	Retention     time.Duration `envconfig:"TRASH_RETENTION" default:"720h"`
+	PurgeInterval time.Duration `envconfig:"TRASH_PURGE_INTERVAL" default:"1h"`
+}
+
 func Load() (*Config, error) {
 	var cfg Config
 	if err := envconfig.Process("", &cfg); err != nil {
@@ -0,0 +1,245 @@
+// Package event описывает доменные события, которые публикуют сервисы.
+// Подписчики получают события через шину pkg/eventbus
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// Имена событий
+const (
+	TransactionCreatedName = "transaction.created"
+	TransactionUpdatedName = "transaction.updated"
+	TransactionDeletedName = "transaction.deleted"
+	RuleCreatedName        = "rule.created"
+	RuleDeletedName        = "rule.deleted"
+	CategoryCreatedName    = "category.created"
+	CategoryUpdatedName    = "category.updated"
+	CategoryDeletedName    = "category.deleted"
+	SettingsUpdatedName    = "settings.updated"
+	ImportProgressName     = "import.progress"
+)
+
+// Event доменное событие пользователя
+type Event interface {
+	EventName() string
+	EventID() string
+	UserID() string
+}
+
+// Meta общие поля событий. ID — UUIDv7: события, созданные позже, имеют большие ID
+// и на всех экземплярах сервиса упорядочиваются одинаково
+type Meta struct {
+	ID         string    `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func NewMeta() Meta {
+	return Meta{ID: uuid.Must(uuid.NewV7()).String(), OccurredAt: time.Now().UTC()}
+}
+
+func (m Meta) EventID() string { return m.ID }
+
+// TransactionCreated транзакция создана, в том числе массово или из чека
+type TransactionCreated struct {
+	Meta
+	Transaction *model.Transaction `json:"transaction"`
+}
+
+func NewTransactionCreated(tx *model.Transaction) TransactionCreated {
+	return TransactionCreated{Meta: NewMeta(), Transaction: tx}
+}
+
+func (TransactionCreated) EventName() string { return TransactionCreatedName }
+func (e TransactionCreated) UserID() string  { return e.Transaction.UserID }
+
+// TransactionUpdated транзакция изменена. Previous — состояние до изменения
+type TransactionUpdated struct {
+	Meta
+	Transaction *model.Transaction `json:"transaction"`
+	Previous    *model.Transaction `json:"previous"`
+}
+
+func NewTransactionUpdated(tx, previous *model.Transaction) TransactionUpdated {
+	return TransactionUpdated{Meta: NewMeta(), Transaction: tx, Previous: previous}
+}
+
+func (TransactionUpdated) EventName() string { return TransactionUpdatedName }
+func (e TransactionUpdated) UserID() string  { return e.Transaction.UserID }
+
+// TransactionDeleted транзакция удалена. Transaction — состояние до удаления
+type TransactionDeleted struct {
+	Meta
+	Transaction *model.Transaction `json:"transaction"`
+}
+
+func NewTransactionDeleted(tx *model.Transaction) TransactionDeleted {
+	return TransactionDeleted{Meta: NewMeta(), Transaction: tx}
+}
+
+func (TransactionDeleted) EventName() string { return TransactionDeletedName }
+func (e TransactionDeleted) UserID() string  { return e.Transaction.UserID }
+
+// RuleCreated создано правило категоризации
+type RuleCreated struct {
+	Meta
+	Rule *model.UserCategoryRule `json:"rule"`
+}
+
+func NewRuleCreated(rule *model.UserCategoryRule) RuleCreated {
+	return RuleCreated{Meta: NewMeta(), Rule: rule}
+}
+
+func (RuleCreated) EventName() string { return RuleCreatedName }
+func (e RuleCreated) UserID() string  { return e.Rule.UserID }
+
+// RuleDeleted удалено правило категоризации. Rule — состояние до удаления
+type RuleDeleted struct {
+	Meta
+	Rule *model.UserCategoryRule `json:"rule"`
+}
+
+func NewRuleDeleted(rule *model.UserCategoryRule) RuleDeleted {
+	return RuleDeleted{Meta: NewMeta(), Rule: rule}
+}
+
+func (RuleDeleted) EventName() string { return RuleDeletedName }
+func (e RuleDeleted) UserID() string  { return e.Rule.UserID }
+
+// CategoryCreated создана пользовательская категория
+type CategoryCreated struct {
+	Meta
+	Category *model.Category `json:"category"`
+}
+
+func NewCategoryCreated(category *model.Category) CategoryCreated {
+	return CategoryCreated{Meta: NewMeta(), Category: category}
+}
+
+func (CategoryCreated) EventName() string { return CategoryCreatedName }
+func (e CategoryCreated) UserID() string  { return categoryOwner(e.Category) }
+
+// CategoryUpdated изменена пользовательская категория. Previous — состояние до изменения
+type CategoryUpdated struct {
+	Meta
+	Category *model.Category `json:"category"`
+	Previous *model.Category `json:"previous"`
+}
+
+func NewCategoryUpdated(category, previous *model.Category) CategoryUpdated {
+	return CategoryUpdated{Meta: NewMeta(), Category: category, Previous: previous}
+}
+
+func (CategoryUpdated) EventName() string { return CategoryUpdatedName }
+func (e CategoryUpdated) UserID() string  { return categoryOwner(e.Category) }
+
+// CategoryDeleted удалена пользовательская категория. Category — состояние до удаления,
+// ReassignTo — категория, на которую переназначены транзакции и правила
+type CategoryDeleted struct {
+	Meta
+	Category   *model.Category `json:"category"`
+	ReassignTo *int            `json:"reassign_to,omitempty"`
+}
+
+func NewCategoryDeleted(category *model.Category, reassignTo *int) CategoryDeleted {
+	return CategoryDeleted{Meta: NewMeta(), Category: category, ReassignTo: reassignTo}
+}
+
+func (CategoryDeleted) EventName() string { return CategoryDeletedName }
+func (e CategoryDeleted) UserID() string  { return categoryOwner(e.Category) }
+
+// categoryOwner владелец категории; события публикуются только для пользовательских категорий
+func categoryOwner(category *model.Category) string {
+	if category.UserID == nil {
+		return ""
+	}
+	return *category.UserID
+}
+
+// SettingsUpdated изменены настройки пользователя User. Previous — настройки до изменения
+type SettingsUpdated struct {
+	Meta
+	User     string              `json:"user_id"`
+	Settings *model.UserSettings `json:"settings"`
+	Previous *model.UserSettings `json:"previous"`
+}
+
+func NewSettingsUpdated(userID string, settings, previous *model.UserSettings) SettingsUpdated {
+	return SettingsUpdated{Meta: NewMeta(), User: userID, Settings: settings, Previous: previous}
+}
+
+func (SettingsUpdated) EventName() string { return SettingsUpdatedName }
+func (e SettingsUpdated) UserID() string  { return e.User }
+
+// Источники и этапы импорта
+const (
+	ImportSourceReceipt = "receipt"
+
+	ImportStarted    = "started"
+	ImportRecognized = "recognized"
+	ImportCompleted  = "completed"
+	ImportFailed     = "failed"
+)
+
+// ImportProgress этап импорта пользователя User. ImportID связывает этапы одного импорта,
+// TransactionID заполнен у завершённого импорта, Error — код ошибки у неудавшегося
+type ImportProgress struct {
+	Meta
+	User          string  `json:"user_id"`
+	ImportID      string  `json:"import_id"`
+	Source        string  `json:"source"`
+	Stage         string  `json:"stage"`
+	TransactionID *string `json:"transaction_id,omitempty"`
+	Error         *string `json:"error,omitempty"`
+}
+
+func NewImportProgress(userID, importID, source, stage string) ImportProgress {
+	return ImportProgress{Meta: NewMeta(), User: userID, ImportID: importID, Source: source, Stage: stage}
+}
+
+func (ImportProgress) EventName() string { return ImportProgressName }
+func (e ImportProgress) UserID() string  { return e.User }
+
+// Decode восстанавливает событие из JSON по имени, например полученное от другого экземпляра сервиса
+func Decode(name string, data []byte) (Event, error) {
+	switch name {
+	case TransactionCreatedName:
+		return decode(name, data, func(e TransactionCreated) bool { return e.Transaction != nil })
+	case TransactionUpdatedName:
+		return decode(name, data, func(e TransactionUpdated) bool { return e.Transaction != nil })
+	case TransactionDeletedName:
+		return decode(name, data, func(e TransactionDeleted) bool { return e.Transaction != nil })
+	case RuleCreatedName:
+		return decode(name, data, func(e RuleCreated) bool { return e.Rule != nil })
+	case RuleDeletedName:
+		return decode(name, data, func(e RuleDeleted) bool { return e.Rule != nil })
+	case CategoryCreatedName:
+		return decode(name, data, func(e CategoryCreated) bool { return e.Category != nil })
+	case CategoryUpdatedName:
+		return decode(name, data, func(e CategoryUpdated) bool { return e.Category != nil })
+	case CategoryDeletedName:
+		return decode(name, data, func(e CategoryDeleted) bool { return e.Category != nil })
+	case SettingsUpdatedName:
+		return decode(name, data, func(e SettingsUpdated) bool { return e.Settings != nil })
+	case ImportProgressName:
+		return decode(name, data, func(e ImportProgress) bool { return e.ImportID != "" })
+	}
+	return nil, fmt.Errorf("unknown event %q", name)
+}
+
+// decode разбирает событие и проверяет, что в нём есть изменённая сущность
+func decode[E Event](name string, data []byte, hasPayload func(E) bool) (Event, error) {
+	var e E
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if !hasPayload(e) {
+		return nil, fmt.Errorf("event %q has no payload", name)
+	}
+	return e, nil
+}
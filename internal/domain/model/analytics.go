@@ -0,0 +1,18 @@
+package model
+
+// CategoryTotal сумма транзакций по одной категории
+type CategoryTotal struct {
+	CategoryID *int
+	Total      float64
+	Count      int64
+}
+
+// CategorySpending агрегированные расходы по категории верхнего уровня.
+// Суммы подкатегорий включены в Total родителя и перечислены в Subcategories
+type CategorySpending struct {
+	CategoryID    *int
+	Name          string
+	Total         float64
+	Count         int64
+	Subcategories []*CategorySpending
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// AnomalyKind вид необычной траты
+type AnomalyKind string
+
+const (
+	// AnomalyCategoryAmount сумма транзакции намного выше обычной для категории
+	AnomalyCategoryAmount AnomalyKind = "category_amount"
+	// AnomalyMerchantAmount сумма транзакции намного выше обычной для продавца
+	AnomalyMerchantAmount AnomalyKind = "merchant_amount"
+	// AnomalyNewMerchant первая и при этом крупная трата у продавца
+	AnomalyNewMerchant AnomalyKind = "new_merchant"
+	// AnomalyCategorySpike расходы категории за месяц намного выше среднего за предыдущие месяцы
+	AnomalyCategorySpike AnomalyKind = "category_spike"
+)
+
+// IsValid проверяет вид аномалии
+func (k AnomalyKind) IsValid() bool {
+	switch k {
+	case AnomalyCategoryAmount, AnomalyMerchantAmount, AnomalyNewMerchant, AnomalyCategorySpike:
+		return true
+	}
+	return false
+}
+
+// Anomaly необычная трата пользователя. Amount сравнивается с Baseline — обычной суммой
+// (медианой транзакций или средним за месяц), Ratio = Amount / Baseline.
+// Для всплеска категории Period — начало месяца, а TransactionID пуст.
+// CategoryName и MerchantName заполняются при чтении
+type Anomaly struct {
+	ID            string
+	UserID        string
+	Kind          AnomalyKind
+	TransactionID *string
+	CategoryID    *int
+	MerchantID    *string
+	Period        *time.Time
+	Amount        float64
+	Baseline      float64
+	Ratio         float64
+	Currency      string
+	CategoryName  *string
+	MerchantName  *string
+	DismissedAt   *time.Time
+	CreatedAt     time.Time
+}
+
+// AnomalyFilter параметры списка аномалий
+type AnomalyFilter struct {
+	UserID           string
+	Kind             *AnomalyKind
+	IncludeDismissed bool
+	Limit            int
+	Offset           int
+}
+
+// AmountSample выборка сумм прошлых транзакций пользователя в одной валюте для сравнения.
+// С WorkspaceID выбираются транзакции пространства, без него — личные транзакции UserID.
+// Если заданы CategoryID или MerchantID, выбираются только их транзакции
+type AmountSample struct {
+	UserID      string
+	WorkspaceID *string
+	Currency    string
+	CategoryID  *int
+	MerchantID  *string
+	Since       time.Time
+	ExcludeID   string
+	Limit       int
+}
+
+// MonthTotal сумма за календарный месяц
+type MonthTotal struct {
+	Month time.Time
+	Total float64
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Attachment файл (фото или PDF чека), прикреплённый к транзакции.
+// Содержимое хранится в BlobStore по ключу StorageKey
+type Attachment struct {
+	ID            string
+	TransactionID string
+	UserID        string
+	FileName      string
+	ContentType   string
+	Size          int64
+	StorageKey    string
+	ThumbnailKey  *string
+	CreatedAt     time.Time
+}
+
+// BlobDeletion объект хранилища, ожидающий удаления после удаления вложения
+type BlobDeletion struct {
+	ID         int64
+	StorageKey string
+}
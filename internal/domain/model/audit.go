@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// AuditEntity сущность, изменения которой пишутся в журнал аудита
+type AuditEntity string
+
+const (
+	AuditTransaction AuditEntity = "transaction"
+	AuditRule        AuditEntity = "rule"
+	AuditCategory    AuditEntity = "category"
+	// AuditSettings настройки пользователя; EntityID — ID пользователя
+	AuditSettings AuditEntity = "settings"
+)
+
+// IsValid проверяет сущность
+func (e AuditEntity) IsValid() bool {
+	switch e {
+	case AuditTransaction, AuditRule, AuditCategory, AuditSettings:
+		return true
+	}
+	return false
+}
+
+// AuditAction вид изменения
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEntry запись журнала аудита. Записи только добавляются.
+// Before и After содержат изменённые поля до и после изменения: при создании Before пуст,
+// при удалении пуст After. ActorID, RequestID и ClientIP пусты у изменений не по запросу пользователя.
+// WorkspaceID заполнен у изменений в рабочем пространстве, UserID тогда — автор сущности
+type AuditEntry struct {
+	ID          int64
+	UserID      string
+	WorkspaceID *string
+	ActorID     *string
+	Entity      AuditEntity
+	EntityID    string
+	Action      AuditAction
+	Before      map[string]any
+	After       map[string]any
+	RequestID   *string
+	ClientIP    *string
+	CreatedAt   time.Time
+}
+
+// AuditFilter параметры выборки журнала аудита. Без WorkspaceID отбираются личные записи UserID,
+// с ним — записи пространства
+type AuditFilter struct {
+	UserID      string
+	WorkspaceID *string
+	Entity      *AuditEntity
+	EntityID    *string
+	Limit       int
+	Offset      int
+}
+
+// UserSettings настройки пользователя: адреса и настройки уведомлений
+type UserSettings struct {
+	NotificationTargets     []*NotificationTarget
+	NotificationPreferences []*NotificationPreference
+}
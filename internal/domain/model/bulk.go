@@ -0,0 +1,33 @@
+package model
+
+// MaxBulkItems максимальное число элементов во всех операциях одного bulk-запроса
+const MaxBulkItems = 1000
+
+// BulkOperationType тип массовой операции над транзакциями
+type BulkOperationType string
+
+const (
+	BulkCreate         BulkOperationType = "create"
+	BulkUpdateCategory BulkOperationType = "update_category"
+	BulkConfirm        BulkOperationType = "confirm"
+	BulkDelete         BulkOperationType = "delete"
+)
+
+// BulkOperation одна операция bulk-запроса.
+// Для create заполняется Transactions, для остальных типов — IDs
+type BulkOperation struct {
+	Type         BulkOperationType
+	IDs          []string
+	CategoryID   *int
+	Transactions []*Transaction
+}
+
+// BulkItemResult результат обработки одного элемента операции.
+// Err == nil означает, что элемент применён
+type BulkItemResult struct {
+	Operation int
+	Type      BulkOperationType
+	Index     int
+	ID        string
+	Err       error
+}
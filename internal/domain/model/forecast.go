@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// DailyTotal сумма транзакций категории за календарный день (UTC)
+type DailyTotal struct {
+	Date       time.Time
+	CategoryID *int
+	Total      float64
+}
+
+// ForecastMethod метод прогноза категории
+type ForecastMethod string
+
+const (
+	// ForecastSeasonalNaive повторяет значение того же месяца год назад
+	ForecastSeasonalNaive ForecastMethod = "seasonal_naive"
+	// ForecastMovingAverage среднее за последние месяцы
+	ForecastMovingAverage ForecastMethod = "moving_average"
+)
+
+// ForecastValue прогнозное значение с доверительным интервалом
+type ForecastValue struct {
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// CategoryForecast прогноз месячной суммы по категории верхнего уровня.
+// Income отмечает категорию доходов и её подкатегории
+type CategoryForecast struct {
+	CategoryID *int
+	Name       string
+	Income     bool
+	Method     ForecastMethod
+	Amount     ForecastValue
+}
+
+// MonthForecast прогноз доходов и расходов за календарный месяц
+type MonthForecast struct {
+	Month      time.Time
+	Income     ForecastValue
+	Expense    ForecastValue
+	Categories []*CategoryForecast
+}
+
+// DayForecast прогноз изменения баланса с начала прогноза на конец дня
+type DayForecast struct {
+	Date    time.Time
+	Balance ForecastValue
+}
+
+// Forecast прогноз денежного потока на несколько календарных месяцев вперёд.
+// HistoryMonths — число полных месяцев истории, на которых построен прогноз
+type Forecast struct {
+	From          time.Time
+	To            time.Time
+	Confidence    float64
+	HistoryMonths int
+	Months        []*MonthForecast
+	Days          []*DayForecast
+}
@@ -0,0 +1,45 @@
+package model
+
+// GeoBounds прямоугольная область в градусах WGS 84.
+// Если MinLon > MaxLon, область пересекает 180-й меридиан
+type GeoBounds struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+// GeoCircle окружность с центром в точке и радиусом в метрах
+type GeoCircle struct {
+	Lat    float64
+	Lon    float64
+	Radius float64
+}
+
+// PlaceGrouping способ группировки транзакций на карте расходов
+type PlaceGrouping string
+
+const (
+	// PlaceGroupingPlace группирует по названию места и координатам
+	PlaceGroupingPlace PlaceGrouping = "place"
+	// PlaceGroupingGrid группирует по ячейкам сетки для тепловой карты
+	PlaceGroupingGrid PlaceGrouping = "grid"
+)
+
+// PlaceFilter параметры карты расходов. CellSize задаёт сторону ячейки сетки в градусах
+type PlaceFilter struct {
+	TransactionFilter
+	GroupBy  PlaceGrouping
+	CellSize float64
+}
+
+// PlaceTotal сумма транзакций в одном месте или ячейке сетки.
+// Lat и Lon указывают центр группы, Cell заполняется только при группировке по сетке
+type PlaceTotal struct {
+	Name  *string
+	Lat   float64
+	Lon   float64
+	Cell  *GeoBounds
+	Total float64
+	Count int64
+}
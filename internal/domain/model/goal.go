@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Goal цель накопления. Взносами считаются транзакции с меткой TagID в валюте цели.
+// Если метку удалили, TagID становится nil и прогресс не считается до привязки новой.
+// Progress заполняется сервисом на момент запроса
+type Goal struct {
+	ID           string
+	UserID       string
+	Name         string
+	TargetAmount float64
+	Currency     string
+	Deadline     *time.Time
+	TagID        *string
+	Progress     *GoalProgress
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// GoalContributions сумма и количество взносов в цель
+type GoalContributions struct {
+	Total     float64
+	Count     int64
+	FirstDate *time.Time
+}
+
+// GoalProgress прогресс накопления на момент расчёта.
+// MonthlyRequired — сколько откладывать в месяц, чтобы успеть к сроку (nil без срока).
+// ProjectedDate — дата достижения цели при текущем темпе взносов (nil, если взносов нет или цель достигнута)
+type GoalProgress struct {
+	Saved           float64
+	Remaining       float64
+	Percent         float64
+	Contributions   int64
+	Achieved        bool
+	MonthlyRequired *float64
+	ProjectedDate   *time.Time
+	OnTrack         *bool
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Merchant продавец, к которому сводятся транзакции с разными описаниями.
+// Aliases — ключи описаний (pkg/merchant.Key), по которым новые транзакции привязываются к продавцу
+type Merchant struct {
+	ID        string
+	UserID    string
+	Name      string
+	Aliases   []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MerchantTotal сумма и количество транзакций продавца.
+// Транзакции без продавца попадают в группу с MerchantID == nil
+type MerchantTotal struct {
+	MerchantID *string
+	Name       string
+	Total      float64
+	Count      int64
+}
@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+// NotificationChannel канал доставки уведомлений
+type NotificationChannel string
+
+const (
+	NotificationEmail    NotificationChannel = "email"
+	NotificationWebhook  NotificationChannel = "webhook"
+	NotificationTelegram NotificationChannel = "telegram"
+)
+
+// NotificationChannels все каналы в порядке вывода
+var NotificationChannels = []NotificationChannel{NotificationEmail, NotificationWebhook, NotificationTelegram}
+
+// IsValid проверяет канал
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationEmail, NotificationWebhook, NotificationTelegram:
+		return true
+	}
+	return false
+}
+
+// NotificationEvent событие, о котором уведомляется пользователь
+type NotificationEvent string
+
+const (
+	// NotificationAnomalyDetected найдена необычная трата
+	NotificationAnomalyDetected NotificationEvent = "anomaly.detected"
+)
+
+// NotificationEvents все события в порядке вывода
+var NotificationEvents = []NotificationEvent{NotificationAnomalyDetected}
+
+// IsValid проверяет событие
+func (e NotificationEvent) IsValid() bool {
+	switch e {
+	case NotificationAnomalyDetected:
+		return true
+	}
+	return false
+}
+
+// NotificationTarget адрес пользователя в канале: email, URL вебхука или chat_id Telegram.
+// Уведомления в канал пишутся на языке Locale
+type NotificationTarget struct {
+	UserID    string
+	Channel   NotificationChannel
+	Address   string
+	Locale    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NotificationPreference отправлять ли событие Event в канал Channel.
+// Без сохранённой настройки событие отправляется во все каналы пользователя
+type NotificationPreference struct {
+	Event   NotificationEvent
+	Channel NotificationChannel
+	Enabled bool
+}
+
+// NotificationStatus состояние уведомления в очереди отправки
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending"
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed"
+)
+
+// Notification уведомление в очереди отправки (outbox). Ставится в очередь в одной
+// транзакции с изменением, о котором уведомляет, и отправляется фоновой задачей
+type Notification struct {
+	ID            string
+	UserID        string
+	Event         NotificationEvent
+	Channel       NotificationChannel
+	Address       string
+	Subject       string
+	Text          string
+	Data          []byte
+	Status        NotificationStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+// NotificationContent текст уведомления на одном языке. Data — данные события
+// для вебхука, сериализуются в JSON
+type NotificationContent struct {
+	Subject string
+	Text    string
+	Data    any
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// FiscalReceipt кассовый чек, по QR-коду которого создана транзакция.
+// Тройка FN (фискальный накопитель), FD (номер документа) и FP (фискальный признак)
+// однозначно идентифицирует чек и защищает от повторного импорта
+type FiscalReceipt struct {
+	ID            string
+	UserID        string
+	TransactionID string
+	FN            string
+	FD            string
+	FP            string
+	Operation     int
+	Raw           string
+	Transaction   *Transaction
+	CreatedAt     time.Time
+}
@@ -0,0 +1,44 @@
+package model
+
+// ReviewSort порядок очереди проверки
+type ReviewSort string
+
+const (
+	ReviewSortDate   ReviewSort = "date"
+	ReviewSortAmount ReviewSort = "amount"
+)
+
+// ReviewFilter параметры очереди неподтверждённых транзакций. Без WorkspaceID в очереди
+// личные транзакции UserID, с ним — транзакции пространства
+type ReviewFilter struct {
+	UserID      string
+	WorkspaceID *string
+	Sort        ReviewSort
+	Limit       int
+	Offset      int
+}
+
+// ReviewItem неподтверждённая транзакция с предложенной категорией.
+// Предложение берётся из автокатегоризации, а если её нет — из истории подтверждённых
+// транзакций с тем же описанием
+type ReviewItem struct {
+	Transaction         *Transaction
+	SuggestedCategoryID *int
+	SuggestedCategory   *Category
+}
+
+// ReviewCounts счётчики для бейджа очереди проверки
+type ReviewCounts struct {
+	Unconfirmed   int64
+	Uncategorized int64
+}
+
+// ReviewAction решение пользователя по транзакции из очереди
+type ReviewAction string
+
+const (
+	// ReviewAccept подтверждает предложенную (или переданную) категорию
+	ReviewAccept ReviewAction = "accept"
+	// ReviewReject отклоняет предложение и назначает другую категорию
+	ReviewReject ReviewAction = "reject"
+)
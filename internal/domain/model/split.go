@@ -0,0 +1,35 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// MinSplitLines минимальное число строк разбивки: одна строка равна обычной транзакции
+const MinSplitLines = 2
+
+// TransactionSplit строка разбивки транзакции по категориям.
+// Сумма строк равна сумме родительской транзакции
+type TransactionSplit struct {
+	ID            string
+	TransactionID string
+	CategoryID    int
+	Amount        float64
+	Note          *string
+	Position      int
+	CreatedAt     time.Time
+}
+
+// Cents переводит сумму в копейки, чтобы сравнивать суммы без ошибок округления float64
+func Cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// SplitsTotal возвращает сумму строк разбивки в копейках
+func SplitsTotal(splits []*TransactionSplit) int64 {
+	var total int64
+	for _, split := range splits {
+		total += Cents(split.Amount)
+	}
+	return total
+}
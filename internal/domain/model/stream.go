@@ -0,0 +1,9 @@
+package model
+
+// StreamEvent событие для клиентов, подключённых к потоку обновлений.
+// Data кодируется в JSON
+type StreamEvent struct {
+	ID   string
+	Name string
+	Data any
+}
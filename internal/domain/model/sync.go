@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// MaxSyncPushItems максимальное число изменений в одном запросе отправки
+const MaxSyncPushItems = 500
+
+// SyncSet изменения одной сущности после токена синхронизации.
+// Created — записи, созданные после токена, Updated — изменённые, Deleted — ID удалённых
+type SyncSet[T any, K comparable] struct {
+	Created []T
+	Updated []T
+	Deleted []K
+}
+
+// SyncChanges изменения данных пользователя для мобильного клиента.
+// Token передаётся в следующий запрос, чтобы получить только новые изменения
+type SyncChanges struct {
+	Token        string
+	Transactions *SyncSet[*Transaction, string]
+	Rules        *SyncSet[*UserCategoryRule, string]
+	Categories   *SyncSet[*Category, int]
+}
+
+// SyncOperation тип изменения, сделанного клиентом офлайн
+type SyncOperation string
+
+const (
+	SyncCreate SyncOperation = "create"
+	SyncUpdate SyncOperation = "update"
+	SyncDelete SyncOperation = "delete"
+)
+
+// SyncChange изменение транзакции от клиента. ID транзакции генерирует клиент.
+// BaseUpdatedAt — версия с сервера, от которой клиент вносил изменение
+type SyncChange struct {
+	Operation     SyncOperation
+	Transaction   *Transaction
+	BaseUpdatedAt *time.Time
+}
+
+// SyncStatus итог применения изменения клиента
+type SyncStatus string
+
+const (
+	SyncApplied  SyncStatus = "applied"
+	SyncConflict SyncStatus = "conflict"
+	SyncRejected SyncStatus = "rejected"
+)
+
+// SyncResult результат применения одного изменения.
+// Transaction — актуальная версия на сервере, nil если транзакция удалена.
+// Err объясняет конфликт или отказ
+type SyncResult struct {
+	Operation   SyncOperation
+	ID          string
+	Status      SyncStatus
+	Transaction *Transaction
+	Err         error
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Tag пользовательская метка транзакций (например, "vacation-2026").
+// В отличие от категории, у транзакции может быть несколько меток
+type Tag struct {
+	ID        string
+	UserID    string
+	Name      string
+	Color     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TagTotal сумма и количество транзакций с меткой
+type TagTotal struct {
+	TagID string
+	Name  string
+	Color *string
+	Total float64
+	Count int64
+}
+
+// TagAction массовое действие с метками
+type TagAction string
+
+const (
+	TagActionAdd    TagAction = "tag"
+	TagActionRemove TagAction = "untag"
+)
+
+// TagBulkResult итог массовой расстановки меток.
+// Skipped содержит ID транзакций, которые не найдены или принадлежат другому пользователю
+type TagBulkResult struct {
+	Affected int64
+	Skipped  []string
+}
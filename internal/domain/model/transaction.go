@@ -2,10 +2,14 @@ package model
 
 import "time"
 
-// Transaction представляет финансовую транзакцию пользователя
+// Transaction представляет финансовую транзакцию пользователя.
+// WorkspaceID заполнен у транзакции рабочего пространства, UserID тогда — её автор.
+// DeletedAt заполнен у удалённой транзакции: она лежит в корзине до окончательной очистки
+// и служит следом удаления для синхронизации
 type Transaction struct {
 	ID          string
 	UserID      string
+	WorkspaceID *string
 	Amount      float64
 	Currency    string
 	Description string
@@ -14,34 +18,82 @@ type Transaction struct {
 	PlaceLat    *float64
 	PlaceLon    *float64
 	CategoryID  *int
+	MerchantID  *string
 	IsConfirmed bool
+	Tags        []*Tag
+	Splits      []*TransactionSplit
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   *time.Time
 }
 
-// TransactionFilter параметры для поиска транзакций
+// TrashedTransaction удалённая транзакция в корзине. PurgeAt — время, после которого
+// транзакция будет удалена окончательно
+type TrashedTransaction struct {
+	*Transaction
+	PurgeAt time.Time
+}
+
+// TransactionRevision прежняя версия транзакции, сохранённая при её изменении.
+// UpdatedAt транзакции — когда версия стала текущей, ReplacedAt — когда её сменила следующая
+type TransactionRevision struct {
+	*Transaction
+	Revision   int
+	ReplacedAt time.Time
+}
+
+// TransactionFilter параметры для поиска транзакций.
+// Без WorkspaceID отбираются личные транзакции UserID, с ним — транзакции пространства.
+// TagIDs отбирает транзакции, у которых есть все перечисленные метки.
+// Bounds и Near отбирают транзакции с координатами внутри области
 type TransactionFilter struct {
-	UserID     string
-	CategoryID *int
-	FromDate   *time.Time
-	ToDate     *time.Time
-	Limit      int
-	Offset     int
+	UserID      string
+	WorkspaceID *string
+	CategoryID  *int
+	MerchantID  *string
+	TagIDs      []string
+	FromDate    *time.Time
+	ToDate      *time.Time
+	Bounds      *GeoBounds
+	Near        *GeoCircle
+	Limit       int
+	Offset      int
 }
 
-// Category представляет категорию транзакции
+// Category представляет категорию транзакции.
+// Системные категории имеют UserID == nil, пользовательские принадлежат владельцу.
+// ParentID задаёт двухуровневое дерево: подкатегория ссылается на категорию верхнего уровня.
+// TranslationKey указывает на переводы названия системной категории
 type Category struct {
-	ID        int
-	Name      string
-	IsDefault bool
-	CreatedAt time.Time
+	ID             int
+	UserID         *string
+	ParentID       *int
+	Name           string
+	TranslationKey *string
+	Icon           *string
+	Color          *string
+	IsDefault      bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
-// UserCategoryRule представляет правило категоризации пользователя
+// IsVisibleTo проверяет, доступна ли категория пользователю
+func (c *Category) IsVisibleTo(userID string) bool {
+	return c.UserID == nil || *c.UserID == userID
+}
+
+// IsOwnedBy проверяет, может ли пользователь изменять категорию
+func (c *Category) IsOwnedBy(userID string) bool {
+	return c.UserID != nil && *c.UserID == userID
+}
+
+// UserCategoryRule представляет правило категоризации пользователя.
+// Правило рабочего пространства (WorkspaceID) применяется к транзакциям этого пространства
 type UserCategoryRule struct {
-	ID         string
-	UserID     string
-	Keyword    string
-	CategoryID int
-	CreatedAt  time.Time
+	ID          string
+	UserID      string
+	WorkspaceID *string
+	Keyword     string
+	CategoryID  int
+	CreatedAt   time.Time
 }
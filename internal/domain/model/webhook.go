@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+// WebhookEvent событие, на которое подписывается вебхук
+type WebhookEvent string
+
+const (
+	WebhookTransactionCreated WebhookEvent = "transaction.created"
+	WebhookTransactionUpdated WebhookEvent = "transaction.updated"
+	WebhookTransactionDeleted WebhookEvent = "transaction.deleted"
+)
+
+// WebhookEvents все события в порядке вывода
+var WebhookEvents = []WebhookEvent{WebhookTransactionCreated, WebhookTransactionUpdated, WebhookTransactionDeleted}
+
+// IsValid проверяет событие
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookTransactionCreated, WebhookTransactionUpdated, WebhookTransactionDeleted:
+		return true
+	}
+	return false
+}
+
+// Webhook подписка пользователя на события. Secret — ключ подписи тел запросов,
+// показывается пользователю только при создании. Неактивная подписка событий не получает
+type Webhook struct {
+	ID        string
+	UserID    string
+	URL       string
+	Secret    string
+	Events    []WebhookEvent
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Subscribed проверяет, подписан ли вебхук на событие
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus состояние доставки события
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// IsValid проверяет состояние доставки
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery доставка события одному вебхуку: запись очереди отправки (outbox)
+// и журнала доставок. EventID общий у доставок одного события разным вебхукам и у
+// повторных доставок. ResponseStatus, ResponseBody и DurationMs относятся к последней попытке
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	UserID         string
+	EventID        string
+	Event          WebhookEvent
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus *int
+	ResponseBody   *string
+	DurationMs     *int64
+	LastError      *string
+	RedeliveryOf   *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookDeliveryFilter параметры журнала доставок вебхука
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	Status    *WebhookDeliveryStatus
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// WorkspaceRole роль участника рабочего пространства
+type WorkspaceRole string
+
+const (
+	// WorkspaceOwner создатель пространства: управляет участниками и приглашениями
+	WorkspaceOwner WorkspaceRole = "owner"
+	// WorkspaceEditor создаёт и изменяет транзакции и правила пространства
+	WorkspaceEditor WorkspaceRole = "editor"
+	// WorkspaceViewer только читает данные пространства
+	WorkspaceViewer WorkspaceRole = "viewer"
+)
+
+// IsValid проверяет роль
+func (r WorkspaceRole) IsValid() bool {
+	switch r {
+	case WorkspaceOwner, WorkspaceEditor, WorkspaceViewer:
+		return true
+	}
+	return false
+}
+
+// Can сообщает, разрешено ли роли действие
+func (r WorkspaceRole) Can(p Permission) bool {
+	switch p {
+	case PermissionRead:
+		return r.IsValid()
+	case PermissionWrite:
+		return r == WorkspaceOwner || r == WorkspaceEditor
+	case PermissionManage:
+		return r == WorkspaceOwner
+	}
+	return false
+}
+
+// Permission действие с данными, право на которое проверяет политика доступа
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	// PermissionManage управление пространством: участники, приглашения, название
+	PermissionManage Permission = "manage"
+)
+
+// Workspace рабочее пространство: общие транзакции и правила нескольких пользователей.
+// Role — роль пользователя, запросившего пространство
+type Workspace struct {
+	ID        string
+	Name      string
+	Role      WorkspaceRole
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WorkspaceMember участник рабочего пространства
+type WorkspaceMember struct {
+	WorkspaceID string
+	UserID      string
+	Email       string
+	Role        WorkspaceRole
+	CreatedAt   time.Time
+}
+
+// WorkspaceInvitation приглашение в рабочее пространство. Принять его может пользователь
+// с адресом Email, предъявивший токен; хранится только хэш токена
+type WorkspaceInvitation struct {
+	ID          string
+	WorkspaceID string
+	Email       string
+	Role        WorkspaceRole
+	TokenHash   string
+	InvitedBy   string
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// AnomalyRepository определяет интерфейс для работы с необычными тратами
+type AnomalyRepository interface {
+	// Create сохраняет аномалию. Повтор той же аномалии не сохраняется, тогда возвращается false
+	Create(ctx context.Context, anomaly *model.Anomaly) (bool, error)
+
+	// GetByUserID возвращает аномалии пользователя, новые первыми, вместе с названиями категории и продавца
+	GetByUserID(ctx context.Context, filter model.AnomalyFilter) ([]*model.Anomaly, error)
+
+	// GetByID находит аномалию по ID
+	GetByID(ctx context.Context, id string) (*model.Anomaly, error)
+
+	// Dismiss отмечает аномалию просмотренной
+	Dismiss(ctx context.Context, id string) error
+
+	// GetAmounts возвращает суммы последних транзакций выборки
+	GetAmounts(ctx context.Context, sample model.AmountSample) ([]float64, error)
+
+	// SumCategoryByMonth возвращает расходы категории в валюте по месяцам [from, to):
+	// личные расходы пользователя или, с workspaceID, расходы пространства
+	SumCategoryByMonth(ctx context.Context, userID string, workspaceID *string, categoryID int, currency string, from, to time.Time) ([]*model.MonthTotal, error)
+
+	// GetCreatedSince возвращает транзакции всех пользователей, созданные начиная с since
+	GetCreatedSince(ctx context.Context, since time.Time) ([]*model.Transaction, error)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// AttachmentRepository определяет интерфейс для работы с вложениями транзакций
+type AttachmentRepository interface {
+	// Create сохраняет метаданные вложения
+	Create(ctx context.Context, attachment *model.Attachment) error
+
+	// GetByID находит вложение по ID
+	GetByID(ctx context.Context, id string) (*model.Attachment, error)
+
+	// GetByTransactionID возвращает вложения транзакции в порядке загрузки
+	GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Attachment, error)
+
+	// Delete удаляет вложение. Объекты хранилища ставятся в очередь на удаление триггером
+	Delete(ctx context.Context, id string) error
+
+	// GetBlobDeletions возвращает до limit объектов, ожидающих удаления из хранилища
+	GetBlobDeletions(ctx context.Context, limit int) ([]*model.BlobDeletion, error)
+
+	// CompleteBlobDeletions убирает обработанные объекты из очереди
+	CompleteBlobDeletions(ctx context.Context, ids []int64) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// AuditRepository определяет интерфейс журнала аудита. Записи только добавляются
+type AuditRepository interface {
+	// Create добавляет запись в журнал
+	Create(ctx context.Context, entry *model.AuditEntry) error
+
+	// GetByUserID возвращает записи пользователя, последние первыми
+	GetByUserID(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// GoalRepository определяет интерфейс для работы с целями накопления
+type GoalRepository interface {
+	// GetByUserID возвращает цели пользователя
+	GetByUserID(ctx context.Context, userID string) ([]*model.Goal, error)
+
+	// GetByID находит цель по ID
+	GetByID(ctx context.Context, id string) (*model.Goal, error)
+
+	// Create создаёт цель
+	Create(ctx context.Context, goal *model.Goal) error
+
+	// Update обновляет цель
+	Update(ctx context.Context, goal *model.Goal) error
+
+	// Delete удаляет цель, транзакции-взносы остаются
+	Delete(ctx context.Context, id string) error
+
+	// SumContributions возвращает взносы в цели, сгруппированные по ID цели.
+	// Цели без взносов в результат не попадают
+	SumContributions(ctx context.Context, goalIDs []string) (map[string]*model.GoalContributions, error)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// MerchantRepository определяет интерфейс для работы с продавцами
+type MerchantRepository interface {
+	// GetByUserID возвращает продавцов пользователя вместе с ключами
+	GetByUserID(ctx context.Context, userID string) ([]*model.Merchant, error)
+
+	// GetByID находит продавца по ID вместе с ключами
+	GetByID(ctx context.Context, id string) (*model.Merchant, error)
+
+	// Update переименовывает продавца
+	Update(ctx context.Context, merchant *model.Merchant) error
+
+	// Delete удаляет продавца, транзакции остаются без продавца
+	Delete(ctx context.Context, id string) error
+
+	// Resolve возвращает ID продавцов по ключам, создавая недостающих.
+	// names содержит название для нового продавца по каждому ключу
+	Resolve(ctx context.Context, userID string, names map[string]string) (map[string]string, error)
+
+	// AddAlias привязывает ключ к продавцу
+	AddAlias(ctx context.Context, userID, merchantID, key string) error
+
+	// DeleteAlias отвязывает ключ от продавца
+	DeleteAlias(ctx context.Context, merchantID, key string) error
+
+	// Merge переносит ключи и транзакции продавцов sourceIDs к targetID и удаляет их
+	Merge(ctx context.Context, targetID string, sourceIDs []string) error
+
+	// GetUnmatchedDescriptions возвращает различные описания транзакций пользователя без продавца
+	GetUnmatchedDescriptions(ctx context.Context, userID string) ([]string, error)
+
+	// AssignByDescription привязывает транзакции без продавца по описанию: описание -> ID продавца.
+	// Возвращает число изменённых транзакций
+	AssignByDescription(ctx context.Context, userID string, merchantIDs map[string]string) (int64, error)
+
+	// SumByMerchant возвращает суммы транзакций пользователя по продавцам
+	SumByMerchant(ctx context.Context, filter model.TransactionFilter) ([]*model.MerchantTotal, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// NotificationRepository определяет интерфейс для работы с настройками и очередью уведомлений
+type NotificationRepository interface {
+	// GetTargets возвращает адреса пользователя в каналах
+	GetTargets(ctx context.Context, userID string) ([]*model.NotificationTarget, error)
+
+	// UpsertTarget сохраняет адрес пользователя в канале
+	UpsertTarget(ctx context.Context, target *model.NotificationTarget) error
+
+	// DeleteTarget удаляет адрес пользователя в канале
+	DeleteTarget(ctx context.Context, userID string, channel model.NotificationChannel) error
+
+	// GetPreferences возвращает сохранённые настройки событий пользователя
+	GetPreferences(ctx context.Context, userID string) ([]*model.NotificationPreference, error)
+
+	// SetPreferences сохраняет настройки событий, остальные не меняются
+	SetPreferences(ctx context.Context, userID string, prefs []*model.NotificationPreference) error
+
+	// Enqueue ставит уведомления в очередь отправки
+	Enqueue(ctx context.Context, notifications []*model.Notification) error
+
+	// ClaimDue выбирает до limit уведомлений, срок отправки которых наступил, и откладывает
+	// их следующую попытку на lease, чтобы их не взял другой экземпляр сервиса
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.Notification, error)
+
+	// MarkSent отмечает уведомление отправленным
+	MarkSent(ctx context.Context, id string) error
+
+	// MarkFailed записывает неудачную попытку. Если nextAttemptAt nil, попытки прекращаются
+	MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt *time.Time) error
+
+	// DeleteSentBefore удаляет отправленные и окончательно не отправленные уведомления старше before
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
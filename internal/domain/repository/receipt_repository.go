@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// FiscalReceiptRepository определяет интерфейс для работы с импортированными чеками
+type FiscalReceiptRepository interface {
+	// Create сохраняет чек. Повторный чек пользователя возвращает ErrReceiptExists
+	Create(ctx context.Context, receipt *model.FiscalReceipt) error
+
+	// GetByFiscalID находит чек пользователя по фискальным реквизитам
+	GetByFiscalID(ctx context.Context, userID, fn, fd, fp string) (*model.FiscalReceipt, error)
+}
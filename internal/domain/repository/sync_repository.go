@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// SyncRepository определяет интерфейс для чтения изменений при синхронизации клиентов.
+// Токен — номер транзакции БД: изменения с номером не меньше токена ещё не переданы клиенту
+type SyncRepository interface {
+	// CurrentToken возвращает токен, все изменения до которого уже зафиксированы
+	CurrentToken(ctx context.Context) (int64, error)
+
+	// GetTransactionChanges возвращает транзакции пользователя, изменённые начиная с токена since.
+	// При since == 0 возвращаются все транзакции, кроме удалённых
+	GetTransactionChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.Transaction, string], error)
+
+	// GetRuleChanges возвращает правила категоризации пользователя, изменённые начиная с токена since
+	GetRuleChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.UserCategoryRule, string], error)
+
+	// GetCategoryChanges возвращает системные и пользовательские категории, изменённые начиная с токена since
+	GetCategoryChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.Category, int], error)
+}
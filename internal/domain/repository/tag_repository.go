@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// TagRepository определяет интерфейс для работы с метками
+type TagRepository interface {
+	// GetByUserID возвращает метки пользователя
+	GetByUserID(ctx context.Context, userID string) ([]*model.Tag, error)
+
+	// GetByID находит метку по ID
+	GetByID(ctx context.Context, id string) (*model.Tag, error)
+
+	// GetByIDs находит метки по списку ID
+	GetByIDs(ctx context.Context, ids []string) ([]*model.Tag, error)
+
+	// Create создаёт метку
+	Create(ctx context.Context, tag *model.Tag) error
+
+	// Update обновляет метку
+	Update(ctx context.Context, tag *model.Tag) error
+
+	// Delete удаляет метку и её связи с транзакциями
+	Delete(ctx context.Context, id string) error
+
+	// GetByTransactionIDs возвращает метки транзакций, сгруппированные по ID транзакции
+	GetByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*model.Tag, error)
+
+	// Attach добавляет каждую метку к каждой транзакции и возвращает число новых связей
+	Attach(ctx context.Context, tagIDs, transactionIDs []string) (int64, error)
+
+	// Detach снимает метки с транзакций и возвращает число удалённых связей
+	Detach(ctx context.Context, tagIDs, transactionIDs []string) (int64, error)
+
+	// ReplaceForTransaction заменяет набор меток пользователя userID на транзакции.
+	// Метки других участников рабочего пространства остаются
+	ReplaceForTransaction(ctx context.Context, userID, transactionID string, tagIDs []string) error
+
+	// SumByTag возвращает суммы транзакций пользователя по меткам
+	SumByTag(ctx context.Context, filter model.TransactionFilter) ([]*model.TagTotal, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
 )
@@ -14,29 +15,130 @@ type TransactionRepository interface {
 	// GetByID находит транзакцию по ID
 	GetByID(ctx context.Context, id string) (*model.Transaction, error)
 
-	// GetByUserID находит транзакции пользователя
+	// GetByUserID находит личные транзакции пользователя или транзакции пространства filter.WorkspaceID
 	GetByUserID(ctx context.Context, filter model.TransactionFilter) ([]*model.Transaction, error)
 
-	// Update обновляет транзакцию
-	Update(ctx context.Context, tx *model.Transaction) error
+	// Update обновляет транзакцию и сохраняет её прежнее состояние как версию.
+	// Если expectedUpdatedAt задан, обновление выполняется только при совпадении версии,
+	// иначе возвращается ошибка конфликта версий
+	Update(ctx context.Context, tx *model.Transaction, expectedUpdatedAt *time.Time) error
 
-	// Delete удаляет транзакцию по ID
+	// Touch отмечает изменение связанных с транзакцией данных (например, разбивки):
+	// обновляет её updated_at в tx и версию для синхронизации
+	Touch(ctx context.Context, tx *model.Transaction) error
+
+	// GetRevisions возвращает прежние версии транзакции, последние первыми
+	GetRevisions(ctx context.Context, id string) ([]*model.TransactionRevision, error)
+
+	// GetRevision находит прежнюю версию транзакции по номеру
+	GetRevision(ctx context.Context, id string, revision int) (*model.TransactionRevision, error)
+
+	// Delete помечает транзакцию удалённой. Удалённые транзакции не возвращаются
+	// остальными методами, кроме GetByIDsWithDeleted
 	Delete(ctx context.Context, id string) error
 
-	// GetTotalCount возвращает общее количество транзакций пользователя
-	GetTotalCount(ctx context.Context, userID string) (int64, error)
+	// GetTotalCount возвращает количество транзакций, отобранных фильтром как в GetByUserID,
+	// без учёта Limit и Offset
+	GetTotalCount(ctx context.Context, filter model.TransactionFilter) (int64, error)
+
+	// SumByCategory возвращает суммы транзакций пользователя по категориям
+	SumByCategory(ctx context.Context, filter model.TransactionFilter) ([]*model.CategoryTotal, error)
+
+	// SumByDay возвращает суммы транзакций пользователя по дням и категориям
+	SumByDay(ctx context.Context, filter model.TransactionFilter) ([]*model.DailyTotal, error)
+
+	// SumByPlace возвращает суммы транзакций с координатами по местам или ячейкам сетки
+	SumByPlace(ctx context.Context, filter model.PlaceFilter) ([]*model.PlaceTotal, error)
+
+	// CreateMany создаёт транзакции одной командой COPY
+	CreateMany(ctx context.Context, txs []*model.Transaction) error
+
+	// GetByIDs находит транзакции по списку ID
+	GetByIDs(ctx context.Context, ids []string) ([]*model.Transaction, error)
+
+	// GetByIDsWithDeleted находит транзакции по списку ID, включая удалённые
+	GetByIDsWithDeleted(ctx context.Context, ids []string) ([]*model.Transaction, error)
+
+	// GetDeleted возвращает удалённые личные транзакции пользователя или транзакции
+	// пространства workspaceID, последние удалённые первыми
+	GetDeleted(ctx context.Context, userID string, workspaceID *string, limit, offset int) ([]*model.Transaction, error)
+
+	// CountDeleted возвращает количество удалённых транзакций, отобранных как в GetDeleted
+	CountDeleted(ctx context.Context, userID string, workspaceID *string) (int64, error)
+
+	// Restore снимает пометку удаления с транзакции
+	Restore(ctx context.Context, id string) error
+
+	// Purge окончательно удаляет до limit транзакций, помеченных удалёнными раньше before,
+	// и возвращает количество удалённых
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+
+	// SetCategory меняет категорию транзакций и возвращает ID изменённых записей
+	SetCategory(ctx context.Context, ids []string, categoryID *int) ([]string, error)
+
+	// Confirm подтверждает категорию транзакций и возвращает ID изменённых записей
+	Confirm(ctx context.Context, ids []string) ([]string, error)
+
+	// DeleteMany помечает транзакции удалёнными и возвращает ID удалённых записей
+	DeleteMany(ctx context.Context, ids []string) ([]string, error)
+
+	// GetForReview возвращает неподтверждённые транзакции с предложенными категориями
+	GetForReview(ctx context.Context, filter model.ReviewFilter) ([]*model.ReviewItem, error)
+
+	// CountForReview возвращает счётчики очереди проверки личных транзакций пользователя
+	// или транзакций пространства workspaceID
+	CountForReview(ctx context.Context, userID string, workspaceID *string) (*model.ReviewCounts, error)
+
+	// SuggestCategory возвращает самую частую категорию подтверждённых транзакций с тем же описанием
+	// среди транзакций пространства tx или, для личной транзакции, личных транзакций её автора
+	SuggestCategory(ctx context.Context, tx *model.Transaction) (*int, error)
+}
+
+// TransactionSplitRepository определяет интерфейс для работы с разбивкой транзакций
+type TransactionSplitRepository interface {
+	// GetByTransactionID возвращает строки разбивки транзакции в исходном порядке
+	GetByTransactionID(ctx context.Context, transactionID string) ([]*model.TransactionSplit, error)
+
+	// Replace заменяет все строки разбивки транзакции
+	Replace(ctx context.Context, transactionID string, splits []*model.TransactionSplit) error
+
+	// DeleteByTransactionID удаляет разбивку транзакции
+	DeleteByTransactionID(ctx context.Context, transactionID string) error
 }
 
 // CategoryRepository определяет интерфейс для работы с категориями
 type CategoryRepository interface {
-	// GetAll возвращает все категории
-	GetAll(ctx context.Context) ([]*model.Category, error)
+	// GetByUserID возвращает системные категории и категории пользователя
+	GetByUserID(ctx context.Context, userID string) ([]*model.Category, error)
+
+	// GetByWorkspaceID возвращает системные категории, категории участников пространства
+	// и категории, которые уже назначены его транзакциям
+	GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.Category, error)
 
 	// GetByID находит категорию по ID
 	GetByID(ctx context.Context, id int) (*model.Category, error)
 
 	// GetDefault возвращает системные категории
 	GetDefault(ctx context.Context) ([]*model.Category, error)
+
+	// GetTranslations возвращает переводы названий категорий для локали: translation_key -> name
+	GetTranslations(ctx context.Context, locale string) (map[string]string, error)
+
+	// GetChildren возвращает подкатегории категории
+	GetChildren(ctx context.Context, parentID int) ([]*model.Category, error)
+
+	// Create создаёт пользовательскую категорию
+	Create(ctx context.Context, category *model.Category) error
+
+	// Update обновляет пользовательскую категорию
+	Update(ctx context.Context, category *model.Category) error
+
+	// GetWorkspaceIDs возвращает пространства, транзакции, разбивки или правила которых
+	// ссылаются на категорию
+	GetWorkspaceIDs(ctx context.Context, id int) ([]string, error)
+
+	// Delete удаляет категорию, переназначая транзакции и правила на reassignTo
+	Delete(ctx context.Context, id int, reassignTo *int) error
 }
 
 // UserCategoryRuleRepository определяет интерфейс для работы с правилами категоризации
@@ -44,9 +146,15 @@ type UserCategoryRuleRepository interface {
 	// Create создаёт новое правило
 	Create(ctx context.Context, rule *model.UserCategoryRule) error
 
-	// GetByUserID возвращает правила пользователя
+	// GetByID находит правило по ID
+	GetByID(ctx context.Context, id string) (*model.UserCategoryRule, error)
+
+	// GetByUserID возвращает личные правила пользователя
 	GetByUserID(ctx context.Context, userID string) ([]*model.UserCategoryRule, error)
 
+	// GetByWorkspaceID возвращает правила рабочего пространства
+	GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.UserCategoryRule, error)
+
 	// GetByKeyword находит правило по ключевому слову
 	GetByKeyword(ctx context.Context, userID, keyword string) (*model.UserCategoryRule, error)
 
@@ -0,0 +1,15 @@
+package repository
+
+import "context"
+
+// Transactor выполняет несколько операций репозиториев в одной транзакции БД.
+// Репозитории, получившие контекст из fn, работают внутри этой транзакции
+type Transactor interface {
+	// WithinTransaction выполняет fn в транзакции: фиксирует её при успехе и откатывает при ошибке.
+	// Вложенный вызов переиспользует уже открытую транзакцию
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// AfterCommit откладывает fn до фиксации транзакции из ctx. После отката fn не вызывается.
+	// Вне транзакции fn вызывается сразу
+	AfterCommit(ctx context.Context, fn func())
+}
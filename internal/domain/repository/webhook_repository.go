@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// WebhookRepository определяет интерфейс для работы с вебхуками и их доставками
+type WebhookRepository interface {
+	// GetByUserID возвращает вебхуки пользователя
+	GetByUserID(ctx context.Context, userID string) ([]*model.Webhook, error)
+
+	// GetByID находит вебхук по ID
+	GetByID(ctx context.Context, id string) (*model.Webhook, error)
+
+	// GetSubscribed возвращает активные вебхуки пользователя, подписанные на событие
+	GetSubscribed(ctx context.Context, userID string, event model.WebhookEvent) ([]*model.Webhook, error)
+
+	// Create создаёт вебхук
+	Create(ctx context.Context, webhook *model.Webhook) error
+
+	// Update обновляет адрес, события и активность вебхука
+	Update(ctx context.Context, webhook *model.Webhook) error
+
+	// Delete удаляет вебхук вместе с журналом доставок
+	Delete(ctx context.Context, id string) error
+
+	// CreateDeliveries ставит доставки в очередь
+	CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+
+	// GetDeliveries возвращает журнал доставок вебхука, новые сначала
+	GetDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+
+	// GetDelivery находит доставку по ID
+	GetDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error)
+
+	// ClaimDue выбирает до limit доставок, срок которых наступил, и откладывает их
+	// следующую попытку на lease, чтобы их не взял другой экземпляр сервиса
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+
+	// RecordAttempt сохраняет результат попытки: состояние, число попыток, ответ и срок следующей попытки
+	RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// DeleteDeliveriesBefore удаляет завершённые доставки старше before
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
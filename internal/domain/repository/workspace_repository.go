@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// WorkspaceRepository определяет интерфейс для работы с рабочими пространствами,
+// их участниками и приглашениями
+type WorkspaceRepository interface {
+	// Create создаёт пространство
+	Create(ctx context.Context, workspace *model.Workspace) error
+
+	// GetByID находит пространство по ID вместе с ролью в нём пользователя userID
+	GetByID(ctx context.Context, id, userID string) (*model.Workspace, error)
+
+	// GetByUserID возвращает пространства, в которых состоит пользователь, с его ролью
+	GetByUserID(ctx context.Context, userID string) ([]*model.Workspace, error)
+
+	// Update переименовывает пространство
+	Update(ctx context.Context, workspace *model.Workspace) error
+
+	// Delete удаляет пространство вместе с участниками и приглашениями
+	Delete(ctx context.Context, id string) error
+
+	// GetMemberRole возвращает роль пользователя в пространстве или ErrNotFound, если он не участник
+	GetMemberRole(ctx context.Context, workspaceID, userID string) (model.WorkspaceRole, error)
+
+	// GetMembers возвращает участников пространства, владелец первым
+	GetMembers(ctx context.Context, workspaceID string) ([]*model.WorkspaceMember, error)
+
+	// AddMember добавляет участника. Если пользователь уже участник, возвращает ErrMemberExists
+	AddMember(ctx context.Context, member *model.WorkspaceMember) error
+
+	// UpdateMemberRole меняет роль участника
+	UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.WorkspaceRole) error
+
+	// RemoveMember исключает участника
+	RemoveMember(ctx context.Context, workspaceID, userID string) error
+
+	// CreateInvitation создаёт приглашение
+	CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error
+
+	// GetInvitationByTokenHash находит приглашение по хэшу токена
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*model.WorkspaceInvitation, error)
+
+	// GetPendingInvitations возвращает непринятые приглашения пространства, последние первыми
+	GetPendingInvitations(ctx context.Context, workspaceID string) ([]*model.WorkspaceInvitation, error)
+
+	// AcceptInvitation отмечает приглашение принятым. Уже принятое приглашение не найдётся
+	AcceptInvitation(ctx context.Context, id string) error
+
+	// DeleteInvitation отзывает приглашение пространства
+	DeleteInvitation(ctx context.Context, workspaceID, id string) error
+}
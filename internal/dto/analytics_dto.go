@@ -0,0 +1,50 @@
+package dto
+
+// Расходы по категории с разбивкой по подкатегориям
+type CategorySpendingResponse struct {
+	CategoryID    *int                        `json:"category_id"`
+	Name          string                      `json:"name"`
+	Total         float64                     `json:"total"`
+	Count         int64                       `json:"count"`
+	Subcategories []*CategorySpendingResponse `json:"subcategories,omitempty"`
+}
+
+// Прогнозное значение с доверительным интервалом
+type ForecastValueResponse struct {
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// Прогноз месячной суммы по категории верхнего уровня
+type CategoryForecastResponse struct {
+	CategoryID *int                  `json:"category_id"`
+	Name       string                `json:"name"`
+	Income     bool                  `json:"income"`
+	Method     string                `json:"method"`
+	Amount     ForecastValueResponse `json:"amount"`
+}
+
+// Прогноз доходов и расходов за месяц
+type MonthForecastResponse struct {
+	Month      string                      `json:"month"`
+	Income     ForecastValueResponse       `json:"income"`
+	Expense    ForecastValueResponse       `json:"expense"`
+	Categories []*CategoryForecastResponse `json:"categories"`
+}
+
+// Прогноз изменения баланса на конец дня
+type DayForecastResponse struct {
+	Date    string                `json:"date"`
+	Balance ForecastValueResponse `json:"balance"`
+}
+
+// Прогноз денежного потока
+type ForecastResponse struct {
+	From          string                   `json:"from"`
+	To            string                   `json:"to"`
+	Confidence    float64                  `json:"confidence"`
+	HistoryMonths int                      `json:"history_months"`
+	Months        []*MonthForecastResponse `json:"months"`
+	Days          []*DayForecastResponse   `json:"days"`
+}
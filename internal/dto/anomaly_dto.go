@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// Необычная трата с объяснением на языке запроса.
+// Amount сравнивается с Baseline — обычной суммой, Ratio = Amount / Baseline
+type AnomalyResponse struct {
+	ID            string     `json:"id"`
+	Kind          string     `json:"kind" example:"merchant_amount"`
+	TransactionID *string    `json:"transaction_id,omitempty"`
+	CategoryID    *int       `json:"category_id,omitempty"`
+	MerchantID    *string    `json:"merchant_id,omitempty"`
+	Period        *string    `json:"period,omitempty" example:"2026-10"`
+	Amount        float64    `json:"amount"`
+	Baseline      float64    `json:"baseline"`
+	Ratio         float64    `json:"ratio"`
+	Currency      string     `json:"currency"`
+	Explanation   string     `json:"explanation"`
+	DismissedAt   *time.Time `json:"dismissed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
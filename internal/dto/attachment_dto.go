@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// Вложение транзакции (фото или PDF чека)
+type AttachmentResponse struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	URL           string    `json:"url"`
+	ThumbnailURL  *string   `json:"thumbnail_url,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
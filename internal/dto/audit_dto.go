@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// Запись журнала аудита. before и after содержат только изменённые поля:
+// при создании нет before, при удалении нет after
+type AuditEntryResponse struct {
+	ID        int64          `json:"id"`
+	Entity    string         `json:"entity" example:"transaction"`
+	EntityID  string         `json:"entity_id"`
+	Action    string         `json:"action" example:"update"`
+	ActorID   *string        `json:"actor_id,omitempty"`
+	Before    map[string]any `json:"before,omitempty"`
+	After     map[string]any `json:"after,omitempty"`
+	RequestID *string        `json:"request_id,omitempty"`
+	ClientIP  *string        `json:"client_ip,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Журнал аудита с пагинацией
+type AuditListResponse struct {
+	Entries []*AuditEntryResponse `json:"entries"`
+	Limit   int                   `json:"limit"`
+	Offset  int                   `json:"offset"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// Одна операция массового запроса.
+// create использует transactions, update_category/confirm/delete — ids
+type BulkOperationRequest struct {
+	Op           string                     `json:"op" validate:"required,oneof=create update_category confirm delete"`
+	IDs          []string                   `json:"ids,omitempty" validate:"omitempty,max=1000"`
+	CategoryID   *int                       `json:"category_id,omitempty" validate:"omitempty,gt=0"`
+	Transactions []CreateTransactionRequest `json:"transactions,omitempty" validate:"omitempty,max=1000"`
+}
+
+// Запрос на массовые операции с транзакциями
+type BulkRequest struct {
+	Operations []BulkOperationRequest `json:"operations" validate:"required,max=50"`
+}
+
+// Ошибка обработки элемента
+type BulkItemError struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// Результат обработки одного элемента
+type BulkItemResponse struct {
+	Operation int            `json:"operation"`
+	Op        string         `json:"op"`
+	Index     int            `json:"index"`
+	ID        string         `json:"id,omitempty"`
+	Status    string         `json:"status"`
+	Error     *BulkItemError `json:"error,omitempty"`
+}
+
+// Ответ на массовый запрос
+type BulkResponse struct {
+	Results   []*BulkItemResponse `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+// Коллекция объектов GeoJSON (RFC 7946) для карты расходов
+type PlaceFeatureCollection struct {
+	Type     string          `json:"type"`
+	Features []*PlaceFeature `json:"features"`
+}
+
+// Объект GeoJSON: место или ячейка сетки. Для ячейки bbox содержит её границы
+// [min_lon, min_lat, max_lon, max_lat], а точка указывает центр транзакций в ячейке
+type PlaceFeature struct {
+	Type       string           `json:"type"`
+	BBox       []float64        `json:"bbox,omitempty"`
+	Geometry   *PointGeometry   `json:"geometry"`
+	Properties *PlaceProperties `json:"properties"`
+}
+
+// Точка GeoJSON, координаты в порядке [lon, lat]
+type PointGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// Расходы в месте или ячейке сетки
+type PlaceProperties struct {
+	Name  *string `json:"name,omitempty"`
+	Total float64 `json:"total"`
+	Count int64   `json:"count"`
+}
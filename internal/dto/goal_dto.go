@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// Запрос на создание или обновление цели накопления.
+// Взносами считаются транзакции с меткой tag_id в валюте цели
+type GoalRequest struct {
+	Name         string  `json:"name" validate:"required,max=100"`
+	TargetAmount float64 `json:"target_amount" validate:"gt=0,lt=10000000000000"`
+	Currency     string  `json:"currency" validate:"omitempty,currency"`
+	Deadline     *string `json:"deadline,omitempty"`
+	TagID        *string `json:"tag_id,omitempty"`
+}
+
+// Запрос на взнос в цель
+type GoalContributionRequest struct {
+	Amount      float64 `json:"amount" validate:"gt=0,lt=10000000000000"`
+	Currency    string  `json:"currency" validate:"omitempty,currency"`
+	Description string  `json:"description" validate:"max=500"`
+	Date        string  `json:"date" validate:"omitempty,date"`
+}
+
+// Прогресс накопления
+type GoalProgressResponse struct {
+	Saved           float64    `json:"saved"`
+	Remaining       float64    `json:"remaining"`
+	Percent         float64    `json:"percent"`
+	Contributions   int64      `json:"contributions"`
+	Achieved        bool       `json:"achieved"`
+	MonthlyRequired *float64   `json:"monthly_required,omitempty"`
+	ProjectedDate   *time.Time `json:"projected_date,omitempty"`
+	OnTrack         *bool      `json:"on_track,omitempty"`
+}
+
+// Ответ с данными цели
+type GoalResponse struct {
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	TargetAmount float64               `json:"target_amount"`
+	Currency     string                `json:"currency"`
+	Deadline     *time.Time            `json:"deadline,omitempty"`
+	TagID        *string               `json:"tag_id,omitempty"`
+	Progress     *GoalProgressResponse `json:"progress"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// Прежняя версия транзакции. transaction.updated_at — когда версия стала текущей,
+// replaced_at — когда её сменила следующая
+type TransactionRevisionResponse struct {
+	Revision    int                  `json:"revision" example:"1"`
+	Transaction *TransactionResponse `json:"transaction"`
+	ReplacedAt  time.Time            `json:"replaced_at"`
+}
+
+// История изменений транзакции, последние версии первыми
+type TransactionHistoryResponse struct {
+	Revisions []*TransactionRevisionResponse `json:"revisions"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+// Ответ с данными продавца. Aliases — нормализованные описания, по которым к продавцу
+// привязываются транзакции
+type MerchantResponse struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// Запрос на переименование продавца
+type UpdateMerchantRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// Запрос на привязку описания к продавцу
+type AddMerchantAliasRequest struct {
+	Alias string `json:"alias" validate:"required,max=500"`
+}
+
+// Запрос на объединение продавцов
+type MergeMerchantsRequest struct {
+	SourceIDs []string `json:"source_ids" validate:"min=1,max=100"`
+}
+
+// Итог привязки транзакций к продавцам
+type MerchantMatchResponse struct {
+	Matched int64 `json:"matched"`
+}
+
+// Расходы по продавцу. Транзакции без продавца приходят с merchant_id = null
+type MerchantSpendingResponse struct {
+	MerchantID *string `json:"merchant_id"`
+	Name       string  `json:"name"`
+	Total      float64 `json:"total"`
+	Count      int64   `json:"count"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// Канал уведомлений: доступен ли он на сервере и адрес пользователя, если канал настроен
+type NotificationChannelResponse struct {
+	Channel   string     `json:"channel" example:"telegram"`
+	Available bool       `json:"available"`
+	Address   *string    `json:"address,omitempty"`
+	Locale    *string    `json:"locale,omitempty" example:"ru"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Запрос на настройку канала: email, HTTPS-адрес вебхука или chat_id Telegram
+type NotificationChannelRequest struct {
+	Address string `json:"address" validate:"required,max=2048"`
+}
+
+// Настройка отправки события в канал
+type NotificationPreferenceDTO struct {
+	Event   string `json:"event" example:"anomaly.detected"`
+	Channel string `json:"channel" example:"email"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Запрос на изменение настроек событий. Не указанные пары событие — канал не меняются
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceDTO `json:"preferences" validate:"required,max=100"`
+}
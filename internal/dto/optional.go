@@ -0,0 +1,48 @@
+package dto
+
+import "encoding/json"
+
+// Optional поле запроса JSON Merge Patch (RFC 7396).
+// Различает три состояния: поле отсутствует (Set == false),
+// передан null (Null == true) и передано значение (Value)
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON вызывается только для присутствующих в теле полей
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// apply применяет поле к значению: null сбрасывает значение в нулевое
+func (o Optional[T]) apply(dst *T) {
+	if !o.Set {
+		return
+	}
+	var zero T
+	if o.Null {
+		*dst = zero
+		return
+	}
+	*dst = o.Value
+}
+
+// applyPtr применяет поле к необязательному значению: null удаляет его
+func (o Optional[T]) applyPtr(dst **T) {
+	if !o.Set {
+		return
+	}
+	if o.Null {
+		*dst = nil
+		return
+	}
+	v := o.Value
+	*dst = &v
+}
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestPatchTransactionRequest_ApplyTo(t *testing.T) {
+	base := func() *UpdateTransactionRequest {
+		return &UpdateTransactionRequest{
+			Amount:      100,
+			Currency:    "RUB",
+			Description: "Кофе",
+			Date:        "2024-01-15T10:00:00Z",
+			PlaceName:   strPtr("Кофейня"),
+			IsConfirmed: true,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		check func(t *testing.T, req *UpdateTransactionRequest)
+	}{
+		{
+			name: "absent fields are unchanged",
+			body: `{"amount": 250}`,
+			check: func(t *testing.T, req *UpdateTransactionRequest) {
+				if req.Amount != 250 {
+					t.Errorf("amount = %v, want 250", req.Amount)
+				}
+				if req.Description != "Кофе" || req.PlaceName == nil || !req.IsConfirmed {
+					t.Errorf("untouched fields changed: %+v", req)
+				}
+			},
+		},
+		{
+			name: "null removes optional value",
+			body: `{"place_name": null}`,
+			check: func(t *testing.T, req *UpdateTransactionRequest) {
+				if req.PlaceName != nil {
+					t.Errorf("place_name = %q, want nil", *req.PlaceName)
+				}
+			},
+		},
+		{
+			name: "value sets optional field",
+			body: `{"category_id": 3, "is_confirmed": false}`,
+			check: func(t *testing.T, req *UpdateTransactionRequest) {
+				if req.CategoryID == nil || *req.CategoryID != 3 {
+					t.Errorf("category_id = %v, want 3", req.CategoryID)
+				}
+				if req.IsConfirmed {
+					t.Error("is_confirmed = true, want false")
+				}
+			},
+		},
+		{
+			name: "null on required field clears it for validation",
+			body: `{"description": null}`,
+			check: func(t *testing.T, req *UpdateTransactionRequest) {
+				if req.Description != "" {
+					t.Errorf("description = %q, want empty", req.Description)
+				}
+				if err := Validate(req); err == nil {
+					t.Error("expected validation error")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch PatchTransactionRequest
+			if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			req := base()
+			patch.ApplyTo(req)
+			tt.check(t, req)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package dto
+
+// Запрос на импорт кассового чека по строке из QR-кода
+type ImportReceiptRequest struct {
+	QR          string  `json:"qr" validate:"required,max=500"`
+	Timezone    *string `json:"timezone,omitempty" validate:"omitempty,max=64"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+}
+
+// Параметры импорта чека по фотографии, передаются в query
+type ImportReceiptImageParams struct {
+	Timezone    string `json:"timezone" validate:"max=64"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// Фискальные реквизиты чека
+type FiscalReceiptResponse struct {
+	FN        string `json:"fn"`
+	FD        string `json:"fd"`
+	FP        string `json:"fp"`
+	Operation int    `json:"operation"`
+}
+
+// Ответ на импорт чека: созданная транзакция и реквизиты чека
+type ImportReceiptResponse struct {
+	Transaction *TransactionResponse   `json:"transaction"`
+	Receipt     *FiscalReceiptResponse `json:"receipt"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+// Транзакция из очереди проверки с предложенной категорией
+type ReviewItemResponse struct {
+	Transaction         *TransactionResponse `json:"transaction"`
+	SuggestedCategoryID *int                 `json:"suggested_category_id,omitempty"`
+	SuggestedCategory   *string              `json:"suggested_category,omitempty"`
+}
+
+// Очередь проверки с пагинацией
+type ReviewQueueResponse struct {
+	Items  []*ReviewItemResponse `json:"items"`
+	Total  int64                 `json:"total"`
+	Limit  int                   `json:"limit"`
+	Offset int                   `json:"offset"`
+}
+
+// Счётчики очереди проверки для бейджа
+type ReviewCountsResponse struct {
+	Unconfirmed   int64 `json:"unconfirmed"`
+	Uncategorized int64 `json:"uncategorized"`
+}
+
+// Решение по транзакции из очереди.
+// accept подтверждает предложенную категорию (или category_id), reject требует category_id
+type ReviewTransactionRequest struct {
+	Action     string `json:"action" validate:"required,oneof=accept reject"`
+	CategoryID *int   `json:"category_id,omitempty" validate:"omitempty,gt=0"`
+}
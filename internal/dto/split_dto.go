@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// Строка разбивки в запросе
+type SplitLineRequest struct {
+	CategoryID int     `json:"category_id" validate:"gt=0"`
+	Amount     float64 `json:"amount" validate:"gt=0,lt=10000000000000"`
+	Note       *string `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// Запрос на создание или замену разбивки транзакции.
+// Сумма строк должна совпадать с суммой транзакции
+type SplitTransactionRequest struct {
+	Splits []SplitLineRequest `json:"splits" validate:"min=2,max=50"`
+}
+
+// Строка разбивки в ответе
+type SplitLineResponse struct {
+	ID         string    `json:"id"`
+	CategoryID int       `json:"category_id"`
+	Amount     float64   `json:"amount"`
+	Note       *string   `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Разбивка транзакции
+type SplitsResponse struct {
+	TransactionID string               `json:"transaction_id"`
+	Splits        []*SplitLineResponse `json:"splits"`
+}
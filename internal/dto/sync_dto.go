@@ -0,0 +1,100 @@
+package dto
+
+import "time"
+
+// Изменения транзакций после токена
+type SyncTransactionChanges struct {
+	Created []*TransactionResponse `json:"created"`
+	Updated []*TransactionResponse `json:"updated"`
+	Deleted []string               `json:"deleted"`
+}
+
+// Правило категоризации в ответе синхронизации
+type SyncRuleResponse struct {
+	ID          string    `json:"id"`
+	WorkspaceID *string   `json:"workspace_id,omitempty"`
+	Keyword     string    `json:"keyword"`
+	CategoryID  int       `json:"category_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Изменения правил категоризации после токена
+type SyncRuleChanges struct {
+	Created []*SyncRuleResponse `json:"created"`
+	Updated []*SyncRuleResponse `json:"updated"`
+	Deleted []string            `json:"deleted"`
+}
+
+// Изменения категорий после токена
+type SyncCategoryChanges struct {
+	Created []CategoryResponse `json:"created"`
+	Updated []CategoryResponse `json:"updated"`
+	Deleted []int              `json:"deleted"`
+}
+
+// Изменения по сущностям
+type SyncChanges struct {
+	Transactions SyncTransactionChanges `json:"transactions"`
+	Rules        SyncRuleChanges        `json:"rules"`
+	Categories   SyncCategoryChanges    `json:"categories"`
+}
+
+// Ответ на запрос изменений. token передаётся в следующий запрос как since
+type SyncPullResponse struct {
+	Token   string      `json:"token" example:"7482913"`
+	Changes SyncChanges `json:"changes"`
+}
+
+// Транзакция, созданная или изменённая клиентом офлайн. ID генерирует клиент.
+// base_updated_at — updated_at версии с сервера, от которой клиент вносил изменение; обязателен для updated
+type SyncTransactionRequest struct {
+	ID            string     `json:"id" validate:"required"`
+	BaseUpdatedAt *time.Time `json:"base_updated_at,omitempty"`
+	Amount        float64    `json:"amount" validate:"gt=0,lt=10000000000000"`
+	Currency      string     `json:"currency" validate:"required,currency"`
+	Description   string     `json:"description" validate:"required,max=500"`
+	Date          string     `json:"date" validate:"required,date"`
+	PlaceName     *string    `json:"place_name,omitempty" validate:"omitempty,max=255"`
+	PlaceLat      *float64   `json:"place_lat,omitempty" validate:"required_with=PlaceLon,omitempty,latitude"`
+	PlaceLon      *float64   `json:"place_lon,omitempty" validate:"required_with=PlaceLat,omitempty,longitude"`
+	CategoryID    *int       `json:"category_id,omitempty" validate:"omitempty,gt=0"`
+	IsConfirmed   bool       `json:"is_confirmed"`
+}
+
+// Транзакция, удалённая клиентом офлайн.
+// Если base_updated_at задан, удаление не применяется к более новой версии на сервере
+type SyncDeleteRequest struct {
+	ID            string     `json:"id" validate:"required"`
+	BaseUpdatedAt *time.Time `json:"base_updated_at,omitempty"`
+}
+
+// Офлайн-изменения транзакций. Применяются по порядку: created, updated, deleted
+type SyncPushTransactions struct {
+	Created []SyncTransactionRequest `json:"created"`
+	Updated []SyncTransactionRequest `json:"updated"`
+	Deleted []SyncDeleteRequest      `json:"deleted"`
+}
+
+// Запрос на отправку офлайн-изменений
+type SyncPushRequest struct {
+	Transactions SyncPushTransactions `json:"transactions"`
+}
+
+// Результат применения одного изменения.
+// transaction — актуальная версия на сервере: после применения или при конфликте
+type SyncItemResponse struct {
+	Op          string               `json:"op" example:"update"`
+	Index       int                  `json:"index"`
+	ID          string               `json:"id"`
+	Status      string               `json:"status" example:"applied"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+	Error       *BulkItemError       `json:"error,omitempty"`
+}
+
+// Ответ на отправку офлайн-изменений
+type SyncPushResponse struct {
+	Results   []*SyncItemResponse `json:"results"`
+	Applied   int                 `json:"applied"`
+	Conflicts int                 `json:"conflicts"`
+	Rejected  int                 `json:"rejected"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+// Ответ с данными метки
+type TagResponse struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+}
+
+// Запрос на создание метки
+type CreateTagRequest struct {
+	Name  string  `json:"name" validate:"required,max=50"`
+	Color *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// Запрос на обновление метки
+type UpdateTagRequest struct {
+	Name  string  `json:"name" validate:"required,max=50"`
+	Color *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// Запрос на массовую расстановку (tag) или снятие (untag) меток
+type TagBulkRequest struct {
+	Action         string   `json:"action" validate:"required,oneof=tag untag"`
+	TagIDs         []string `json:"tag_ids" validate:"min=1,max=50"`
+	TransactionIDs []string `json:"transaction_ids" validate:"min=1,max=1000"`
+}
+
+// Итог массовой операции с метками
+type TagBulkResponse struct {
+	Affected int64    `json:"affected"`
+	Skipped  []string `json:"skipped"`
+}
+
+// Запрос на замену меток транзакции
+type SetTransactionTagsRequest struct {
+	TagIDs []string `json:"tag_ids" validate:"max=50"`
+}
+
+// Расходы по метке
+type TagSpendingResponse struct {
+	TagID string  `json:"tag_id"`
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+	Total float64 `json:"total"`
+	Count int64   `json:"count"`
+}
@@ -4,63 +4,120 @@ import "time"
 
 // Запрос на создание транзакции
 type CreateTransactionRequest struct {
-	Amount      float64  `json:"amount"`
-	Currency    string   `json:"currency"`
-	Description string   `json:"description"`
-	Date        string   `json:"date"`
-	PlaceName   *string  `json:"place_name,omitempty"`
-	PlaceLat    *float64 `json:"place_lat,omitempty"`
-	PlaceLon    *float64 `json:"place_lon,omitempty"`
+	Amount      float64  `json:"amount" validate:"gt=0,lt=10000000000000"`
+	Currency    string   `json:"currency" validate:"omitempty,currency"`
+	Description string   `json:"description" validate:"required,max=500"`
+	Date        string   `json:"date" validate:"required,date"`
+	PlaceName   *string  `json:"place_name,omitempty" validate:"omitempty,max=255"`
+	PlaceLat    *float64 `json:"place_lat,omitempty" validate:"required_with=PlaceLon,omitempty,latitude"`
+	PlaceLon    *float64 `json:"place_lon,omitempty" validate:"required_with=PlaceLat,omitempty,longitude"`
+	// Рабочее пространство; без него транзакция личная
+	WorkspaceID *string `json:"workspace_id,omitempty" validate:"omitempty,uuid"`
 }
 
 // Запрос на обновление транзакции
 type UpdateTransactionRequest struct {
-	Amount      float64  `json:"amount"`
-	Currency    string   `json:"currency"`
-	Description string   `json:"description"`
-	Date        string   `json:"date"`
-	PlaceName   *string  `json:"place_name,omitempty"`
-	PlaceLat    *float64 `json:"place_lat,omitempty"`
-	PlaceLon    *float64 `json:"place_lon,omitempty"`
-	CategoryID  *int     `json:"category_id,omitempty"`
+	Amount      float64  `json:"amount" validate:"gt=0,lt=10000000000000"`
+	Currency    string   `json:"currency" validate:"required,currency"`
+	Description string   `json:"description" validate:"required,max=500"`
+	Date        string   `json:"date" validate:"required,date"`
+	PlaceName   *string  `json:"place_name,omitempty" validate:"omitempty,max=255"`
+	PlaceLat    *float64 `json:"place_lat,omitempty" validate:"required_with=PlaceLon,omitempty,latitude"`
+	PlaceLon    *float64 `json:"place_lon,omitempty" validate:"required_with=PlaceLat,omitempty,longitude"`
+	CategoryID  *int     `json:"category_id,omitempty" validate:"omitempty,gt=0"`
 	IsConfirmed bool     `json:"is_confirmed"`
 }
 
+// Запрос на частичное обновление транзакции (JSON Merge Patch).
+// Отсутствующие поля не меняются, null удаляет необязательное значение
+type PatchTransactionRequest struct {
+	Amount      Optional[float64] `json:"amount" swaggertype:"number"`
+	Currency    Optional[string]  `json:"currency" swaggertype:"string"`
+	Description Optional[string]  `json:"description" swaggertype:"string"`
+	Date        Optional[string]  `json:"date" swaggertype:"string"`
+	PlaceName   Optional[string]  `json:"place_name" swaggertype:"string"`
+	PlaceLat    Optional[float64] `json:"place_lat" swaggertype:"number"`
+	PlaceLon    Optional[float64] `json:"place_lon" swaggertype:"number"`
+	CategoryID  Optional[int]     `json:"category_id" swaggertype:"integer"`
+	IsConfirmed Optional[bool]    `json:"is_confirmed" swaggertype:"boolean"`
+}
+
+// ApplyTo накладывает патч на полное представление транзакции.
+// Результат проверяется теми же правилами, что и PUT
+func (p *PatchTransactionRequest) ApplyTo(req *UpdateTransactionRequest) {
+	p.Amount.apply(&req.Amount)
+	p.Currency.apply(&req.Currency)
+	p.Description.apply(&req.Description)
+	p.Date.apply(&req.Date)
+	p.PlaceName.applyPtr(&req.PlaceName)
+	p.PlaceLat.applyPtr(&req.PlaceLat)
+	p.PlaceLon.applyPtr(&req.PlaceLon)
+	p.CategoryID.applyPtr(&req.CategoryID)
+	p.IsConfirmed.apply(&req.IsConfirmed)
+}
+
 // Jтвет с данными транзакции
 type TransactionResponse struct {
-	ID          string     `json:"id"`
-	Amount      float64    `json:"amount"`
-	Currency    string     `json:"currency"`
-	Description string     `json:"description"`
-	Date        time.Time  `json:"date"`
-	PlaceName   *string    `json:"place_name,omitempty"`
-	PlaceLat    *float64   `json:"place_lat,omitempty"`
-	PlaceLon    *float64   `json:"place_lon,omitempty"`
-	CategoryID  *int       `json:"category_id,omitempty"`
-	Category    *string    `json:"category,omitempty"`
-	IsConfirmed bool       `json:"is_confirmed"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          string         `json:"id"`
+	Amount      float64        `json:"amount"`
+	Currency    string         `json:"currency"`
+	Description string         `json:"description"`
+	Date        time.Time      `json:"date"`
+	PlaceName   *string        `json:"place_name,omitempty"`
+	PlaceLat    *float64       `json:"place_lat,omitempty"`
+	PlaceLon    *float64       `json:"place_lon,omitempty"`
+	CategoryID  *int           `json:"category_id,omitempty"`
+	Category    *string        `json:"category,omitempty"`
+	MerchantID  *string        `json:"merchant_id,omitempty"`
+	WorkspaceID *string        `json:"workspace_id,omitempty"`
+	IsConfirmed bool           `json:"is_confirmed"`
+	Tags        []*TagResponse `json:"tags"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
 // Ответ с данными категории
 type CategoryResponse struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Key       *string `json:"key,omitempty"`
+	ParentID  *int    `json:"parent_id,omitempty"`
+	Icon      *string `json:"icon,omitempty"`
+	Color     *string `json:"color,omitempty"`
+	IsDefault bool    `json:"is_default"`
+}
+
+// Запрос на создание пользовательской категории
+type CreateCategoryRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	ParentID *int    `json:"parent_id,omitempty" validate:"omitempty,gt=0"`
+	Icon     *string `json:"icon,omitempty" validate:"omitempty,max=50"`
+	Color    *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// Запрос на обновление пользовательской категории
+type UpdateCategoryRequest struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	ParentID *int    `json:"parent_id,omitempty" validate:"omitempty,gt=0"`
+	Icon     *string `json:"icon,omitempty" validate:"omitempty,max=50"`
+	Color    *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
 }
 
 // Запрос на создание правила категоризации
 type CreateCategoryRuleRequest struct {
-	Keyword    string `json:"keyword"`
-	CategoryID int    `json:"category_id"`
+	Keyword    string `json:"keyword" validate:"required,max=255"`
+	CategoryID int    `json:"category_id" validate:"gt=0"`
+	// Рабочее пространство; без него правило личное
+	WorkspaceID *string `json:"workspace_id,omitempty" validate:"omitempty,uuid"`
 }
 
 // Ответ с данными правила
 type CategoryRuleResponse struct {
-	ID         string `json:"id"`
-	Keyword    string `json:"keyword"`
-	CategoryID int    `json:"category_id"`
-	Category   string `json:"category"`
+	ID          string  `json:"id"`
+	Keyword     string  `json:"keyword"`
+	CategoryID  int     `json:"category_id"`
+	Category    string  `json:"category"`
+	WorkspaceID *string `json:"workspace_id,omitempty"`
 }
 
 // Список транзакций с пагинацией
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// Транзакция в корзине. purge_at — время окончательного удаления
+type TrashItemResponse struct {
+	Transaction *TransactionResponse `json:"transaction"`
+	DeletedAt   time.Time            `json:"deleted_at"`
+	PurgeAt     time.Time            `json:"purge_at"`
+}
+
+// Корзина с пагинацией
+type TrashListResponse struct {
+	Items  []*TrashItemResponse `json:"items"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
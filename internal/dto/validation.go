@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/pkg/validator"
+)
+
+var requestValidator = newRequestValidator()
+
+func newRequestValidator() *validator.Validator {
+	v := validator.New()
+
+	// currency поддерживаемая валюта (ISO 4217)
+	v.Register("currency", func(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+		if !model.Currency(field.String()).IsValid() {
+			return "unsupported_currency", nil
+		}
+		return "", nil
+	})
+
+	// uuid идентификатор в формате UUID
+	v.Register("uuid", func(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+		if _, err := uuid.Parse(field.String()); err != nil {
+			return "invalid_format", nil
+		}
+		return "", nil
+	})
+
+	return v
+}
+
+// Validate проверяет запрос по тегам validate и возвращает все нарушения сразу
+func Validate(req any) error {
+	return requestValidator.Struct(req)
+}
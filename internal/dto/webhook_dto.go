@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// Запрос на создание или изменение вебхука
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required,max=2048" example:"https://example.com/hooks/finance"`
+	Events []string `json:"events" validate:"required,max=10" example:"transaction.created,transaction.updated"`
+	// По умолчанию true
+	Active *bool `json:"active,omitempty"`
+}
+
+// Вебхук. Ключ подписи возвращается только при создании
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty" example:"whsec_3f1c..."`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Попытка доставки события вебхуку
+type WebhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event" example:"transaction.created"`
+	Status         string     `json:"status" example:"succeeded"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int       `json:"response_status,omitempty" example:"200"`
+	ResponseBody   *string    `json:"response_body,omitempty"`
+	DurationMs     *int64     `json:"duration_ms,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	RedeliveryOf   *string    `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// Запрос на создание или переименование рабочего пространства
+type WorkspaceRequest struct {
+	Name string `json:"name" validate:"required,max=100" example:"Семья"`
+}
+
+// Рабочее пространство. Role — роль текущего пользователя в нём
+type WorkspaceResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role" example:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Участник рабочего пространства
+type WorkspaceMemberResponse struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role" example:"editor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Запрос на смену роли участника
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor viewer" example:"viewer"`
+}
+
+// Запрос на приглашение в рабочее пространство
+type WorkspaceInvitationRequest struct {
+	Email string `json:"email" validate:"required,email" example:"partner@example.com"`
+	Role  string `json:"role" validate:"required,oneof=editor viewer" example:"editor"`
+}
+
+// Приглашение в рабочее пространство. Токен возвращается только при создании
+type WorkspaceInvitationResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role" example:"editor"`
+	Token     string    `json:"token,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Запрос на принятие приглашения
+type AcceptWorkspaceInvitationRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
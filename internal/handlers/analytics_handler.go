@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// defaultForecastMonths горизонт прогноза по умолчанию
+const defaultForecastMonths = 3
+
+// AnalyticsHandler обрабатывает HTTP запросы аналитики
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+// NewAnalyticsHandler создаёт новый AnalyticsHandler
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// ByCategory
+// @Summary Расходы по категориям
+// @Description Суммы транзакций по категориям верхнего уровня, подкатегории сворачиваются в родителя
+// @Tags analytics
+// @Produce json
+// @Param Accept-Language header string false "Язык названий (ru, en)"
+// @Param from_date query string false "Дата от (RFC3339)"
+// @Param to_date query string false "Дата до (RFC3339)"
+// @Param workspace_id query string false "ID рабочего пространства; без него считаются личные транзакции"
+// @Success 200 {array} dto.CategorySpendingResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/analytics/categories [get]
+func (h *AnalyticsHandler) ByCategory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(r, userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if filter.WorkspaceID, err = parseWorkspaceID(r); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	spending, err := h.analyticsService.SpendingByCategory(r.Context(), filter, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toCategorySpendingResponses(spending))
+}
+
+// ByTag
+// @Summary Расходы по меткам
+// @Description Суммы транзакций по меткам. Транзакция с несколькими метками учитывается в каждой из них
+// @Tags analytics
+// @Produce json
+// @Param from_date query string false "Дата от (RFC3339)"
+// @Param to_date query string false "Дата до (RFC3339)"
+// @Success 200 {array} dto.TagSpendingResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/analytics/tags [get]
+func (h *AnalyticsHandler) ByTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(r, userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	totals, err := h.analyticsService.SpendingByTag(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.TagSpendingResponse, len(totals))
+	for i, total := range totals {
+		response[i] = &dto.TagSpendingResponse{
+			TagID: total.TagID,
+			Name:  total.Name,
+			Color: total.Color,
+			Total: total.Total,
+			Count: total.Count,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ByMerchant
+// @Summary Расходы по продавцам
+// @Description Суммы транзакций по продавцам. Транзакции без продавца собраны в последнюю группу с merchant_id = null
+// @Tags analytics
+// @Produce json
+// @Param from_date query string false "Дата от (RFC3339)"
+// @Param to_date query string false "Дата до (RFC3339)"
+// @Success 200 {array} dto.MerchantSpendingResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/analytics/merchants [get]
+func (h *AnalyticsHandler) ByMerchant(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(r, userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	totals, err := h.analyticsService.SpendingByMerchant(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.MerchantSpendingResponse, len(totals))
+	for i, total := range totals {
+		response[i] = &dto.MerchantSpendingResponse{
+			MerchantID: total.MerchantID,
+			Name:       total.Name,
+			Total:      total.Total,
+			Count:      total.Count,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ByPlace
+// @Summary Карта расходов
+// @Description Суммы транзакций с координатами в формате GeoJSON FeatureCollection.
+// @Description group_by=place группирует по местам, group_by=grid — по ячейкам сетки для тепловой карты
+// @Tags analytics
+// @Produce json
+// @Param group_by query string false "Группировка" Enums(place, grid) default(place)
+// @Param cell query number false "Сторона ячейки сетки в градусах" default(0.01)
+// @Param from_date query string false "Дата от (RFC3339)"
+// @Param to_date query string false "Дата до (RFC3339)"
+// @Param bbox query string false "Область min_lon,min_lat,max_lon,max_lat"
+// @Param lat query number false "Широта центра"
+// @Param lon query number false "Долгота центра"
+// @Param radius query number false "Радиус в метрах" default(1000)
+// @Success 200 {object} dto.PlaceFeatureCollection
+// @Failure 400 {object} apperror.Problem "Ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/analytics/places [get]
+func (h *AnalyticsHandler) ByPlace(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	txFilter, err := parseAnalyticsFilter(r, userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := parseGeoFilter(r, &txFilter); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := model.PlaceFilter{TransactionFilter: txFilter, GroupBy: model.PlaceGroupingPlace}
+	switch groupBy := model.PlaceGrouping(r.URL.Query().Get("group_by")); groupBy {
+	case "", model.PlaceGroupingPlace:
+	case model.PlaceGroupingGrid:
+		filter.GroupBy = groupBy
+	default:
+		writeError(w, r, apperror.Validation(apperror.FieldWithParams("group_by", "not_allowed", map[string]any{"allowed": "place, grid"})))
+		return
+	}
+
+	if cell := r.URL.Query().Get("cell"); cell != "" {
+		size, err := strconv.ParseFloat(cell, 64)
+		if err != nil || size < minPlaceCellSize || size > maxPlaceCellSize {
+			writeError(w, r, apperror.Validation(outOfRange("cell", minPlaceCellSize, maxPlaceCellSize)))
+			return
+		}
+		filter.CellSize = size
+	}
+
+	totals, err := h.analyticsService.SpendingByPlace(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(toPlaceFeatureCollection(totals))
+}
+
+// Forecast
+// @Summary Прогноз денежного потока
+// @Description Прогноз доходов и расходов по категориям на months календарных месяцев начиная со следующего
+// @Description и изменение баланса по дням относительно начала прогноза. Категории прогнозируются сезонным
+// @Description наивным методом при двух годах истории, иначе скользящим средним за три месяца.
+// @Description lower и upper — границы доверительного интервала уровня confidence
+// @Tags analytics
+// @Produce json
+// @Param Accept-Language header string false "Язык названий (ru, en)"
+// @Param months query int false "Горизонт прогноза в месяцах" minimum(1) maximum(12) default(3)
+// @Success 200 {object} dto.ForecastResponse
+// @Failure 400 {object} apperror.Problem "Ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/analytics/forecast [get]
+func (h *AnalyticsHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	months := defaultForecastMonths
+	if value := r.URL.Query().Get("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > service.MaxForecastMonths {
+			writeError(w, r, apperror.Validation(outOfRange("months", 1, service.MaxForecastMonths)))
+			return
+		}
+		months = parsed
+	}
+
+	forecast, err := h.analyticsService.Forecast(r.Context(), userID, months, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toForecastResponse(forecast))
+}
+
+// parseAnalyticsFilter разбирает общий для аналитики период from_date/to_date
+func parseAnalyticsFilter(r *http.Request, userID string) (model.TransactionFilter, error) {
+	filter := model.TransactionFilter{UserID: userID}
+
+	if fromDate := r.URL.Query().Get("from_date"); fromDate != "" {
+		date, err := time.Parse(time.RFC3339, fromDate)
+		if err != nil {
+			return filter, apperror.Validation(apperror.Field("from_date", "invalid_date"))
+		}
+		filter.FromDate = &date
+	}
+
+	if toDate := r.URL.Query().Get("to_date"); toDate != "" {
+		date, err := time.Parse(time.RFC3339, toDate)
+		if err != nil {
+			return filter, apperror.Validation(apperror.Field("to_date", "invalid_date"))
+		}
+		filter.ToDate = &date
+	}
+
+	return filter, nil
+}
+
+func toCategorySpendingResponses(items []*model.CategorySpending) []*dto.CategorySpendingResponse {
+	responses := make([]*dto.CategorySpendingResponse, len(items))
+	for i, item := range items {
+		responses[i] = &dto.CategorySpendingResponse{
+			CategoryID: item.CategoryID,
+			Name:       item.Name,
+			Total:      item.Total,
+			Count:      item.Count,
+		}
+		if len(item.Subcategories) > 0 {
+			responses[i].Subcategories = toCategorySpendingResponses(item.Subcategories)
+		}
+	}
+	return responses
+}
+
+func toForecastResponse(forecast *model.Forecast) *dto.ForecastResponse {
+	response := &dto.ForecastResponse{
+		From:          forecast.From.Format(time.DateOnly),
+		To:            forecast.To.Format(time.DateOnly),
+		Confidence:    forecast.Confidence,
+		HistoryMonths: forecast.HistoryMonths,
+		Months:        make([]*dto.MonthForecastResponse, len(forecast.Months)),
+		Days:          make([]*dto.DayForecastResponse, len(forecast.Days)),
+	}
+
+	for i, month := range forecast.Months {
+		categories := make([]*dto.CategoryForecastResponse, len(month.Categories))
+		for j, item := range month.Categories {
+			categories[j] = &dto.CategoryForecastResponse{
+				CategoryID: item.CategoryID,
+				Name:       item.Name,
+				Income:     item.Income,
+				Method:     string(item.Method),
+				Amount:     toForecastValue(item.Amount),
+			}
+		}
+		response.Months[i] = &dto.MonthForecastResponse{
+			Month:      month.Month.Format("2006-01"),
+			Income:     toForecastValue(month.Income),
+			Expense:    toForecastValue(month.Expense),
+			Categories: categories,
+		}
+	}
+
+	for i, day := range forecast.Days {
+		response.Days[i] = &dto.DayForecastResponse{
+			Date:    day.Date.Format(time.DateOnly),
+			Balance: toForecastValue(day.Balance),
+		}
+	}
+
+	return response
+}
+
+func toForecastValue(value model.ForecastValue) dto.ForecastValueResponse {
+	return dto.ForecastValueResponse{Value: value.Value, Lower: value.Lower, Upper: value.Upper}
+}
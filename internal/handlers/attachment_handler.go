@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+	"github.com/gibbon/finace-dashboard/pkg/thumbnail"
+)
+
+// multipartOverhead запас на заголовки частей и границы multipart сверх размера файла
+const multipartOverhead = 64 << 10
+
+// AttachmentHandler обрабатывает HTTP запросы для вложений транзакций
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+// NewAttachmentHandler создаёт новый AttachmentHandler
+func NewAttachmentHandler(attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// Upload
+// @Summary Прикрепить файл к транзакции
+// @Description Загрузка фото (JPEG, PNG, GIF, WebP) или PDF чека в поле file формы multipart/form-data.
+// @Description Тип определяется по содержимому файла. Для изображений строится миниатюра
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param file formData file true "Файл вложения"
+// @Success 201 {object} dto.AttachmentResponse
+// @Failure 400 {object} apperror.Problem "Файл не передан"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Транзакция не найдена"
+// @Failure 413 {object} apperror.Problem "Файл слишком большой"
+// @Failure 415 {object} apperror.Problem "Неподдерживаемый тип файла"
+// @Router /api/v1/transactions/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		writeError(w, r, errMultipartRequired)
+		return
+	}
+
+	maxSize := h.attachmentService.MaxSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+	fileName, data, err := readMultipartFile(r, "file", maxSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	attachment, err := h.attachmentService.Upload(r.Context(), userID, chi.URLParam(r, "id"), fileName, data)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAttachmentResponse(attachment))
+}
+
+// GetByTransaction
+// @Summary Получить вложения транзакции
+// @Tags attachments
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Success 200 {array} dto.AttachmentResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Транзакция не найдена"
+// @Router /api/v1/transactions/{id}/attachments [get]
+func (h *AttachmentHandler) GetByTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	attachments, err := h.attachmentService.GetByTransactionID(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	responses := make([]*dto.AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		responses[i] = toAttachmentResponse(attachment)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
+
+// Download
+// @Summary Скачать вложение
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "ID вложения"
+// @Success 200 {file} file
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/attachments/{id} [get]
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, false)
+}
+
+// Thumbnail
+// @Summary Получить миниатюру вложения
+// @Description Миниатюра в формате JPEG (до 256 пикселей по большей стороне). Есть только у изображений
+// @Tags attachments
+// @Produce jpeg
+// @Param id path string true "ID вложения"
+// @Success 200 {file} file
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, true)
+}
+
+// Delete
+// @Summary Удалить вложение
+// @Tags attachments
+// @Produce json
+// @Param id path string true "ID вложения"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.attachmentService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "attachment_deleted")})
+}
+
+func (h *AttachmentHandler) serve(w http.ResponseWriter, r *http.Request, thumb bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	attachment, content, err := h.attachmentService.Open(r.Context(), userID, chi.URLParam(r, "id"), thumb)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	contentType := attachment.ContentType
+	if thumb {
+		contentType = thumbnail.ContentType
+	} else {
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	io.Copy(w, content)
+}
+
+// readMultipartFile читает часть формы с именем field, не загружая в память больше maxSize+1 байт
+func readMultipartFile(r *http.Request, field string, maxSize int64) (string, []byte, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return "", nil, errInvalidBody
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return "", nil, errFileRequired
+		}
+		if err != nil {
+			return "", nil, multipartError(err)
+		}
+
+		if part.FormName() != field {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxSize+1))
+		part.Close()
+		if err != nil {
+			return "", nil, multipartError(err)
+		}
+		if int64(len(data)) > maxSize {
+			return "", nil, service.ErrAttachmentTooLarge
+		}
+		return part.FileName(), data, nil
+	}
+}
+
+func multipartError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return service.ErrAttachmentTooLarge
+	}
+	return errInvalidBody
+}
+
+func toAttachmentResponse(attachment *model.Attachment) *dto.AttachmentResponse {
+	url := "/api/v1/attachments/" + attachment.ID
+	response := &dto.AttachmentResponse{
+		ID:            attachment.ID,
+		TransactionID: attachment.TransactionID,
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		Size:          attachment.Size,
+		URL:           url,
+		CreatedAt:     attachment.CreatedAt,
+	}
+	if attachment.ThumbnailKey != nil {
+		thumbnailURL := url + "/thumbnail"
+		response.ThumbnailURL = &thumbnailURL
+	}
+	return response
+}
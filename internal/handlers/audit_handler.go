@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// AuditHandler обрабатывает HTTP запросы журнала аудита
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler создаёт новый AuditHandler
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAll
+// @Summary Журнал аудита
+// @Description Изменения транзакций, правил, категорий и настроек пользователя, последние первыми.
+// @Description Для настроек id — ID пользователя. С workspace_id возвращаются изменения транзакций
+// @Description и правил рабочего пространства, доступные любому его участнику
+// @Tags audit
+// @Produce json
+// @Param workspace_id query string false "ID рабочего пространства; без него возвращается личный журнал"
+// @Param entity query string false "Сущность: transaction, rule, category или settings"
+// @Param id query string false "ID сущности, только вместе с entity"
+// @Param limit query int false "Лимит" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} dto.AuditListResponse
+// @Failure 400 {object} apperror.Problem "Ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа к рабочему пространству"
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := model.AuditFilter{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Limit:       20,
+	}
+
+	if entity := r.URL.Query().Get("entity"); entity != "" {
+		value := model.AuditEntity(entity)
+		if !value.IsValid() {
+			writeError(w, r, apperror.Validation(apperror.FieldWithParams("entity", "not_allowed", map[string]any{"allowed": "transaction, rule, category, settings"})))
+			return
+		}
+		filter.Entity = &value
+	}
+
+	if id := r.URL.Query().Get("id"); id != "" {
+		if filter.Entity == nil {
+			writeError(w, r, apperror.Validation(apperror.Field("entity", "required")))
+			return
+		}
+		filter.EntityID = &id
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+	}
+
+	entries, err := h.auditService.GetAll(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := dto.AuditListResponse{
+		Entries: make([]*dto.AuditEntryResponse, len(entries)),
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}
+	for i, entry := range entries {
+		response.Entries[i] = &dto.AuditEntryResponse{
+			ID:        entry.ID,
+			Entity:    string(entry.Entity),
+			EntityID:  entry.EntityID,
+			Action:    string(entry.Action),
+			ActorID:   entry.ActorID,
+			Before:    entry.Before,
+			After:     entry.After,
+			RequestID: entry.RequestID,
+			ClientIP:  entry.ClientIP,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -2,12 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
 	domainService "github.com/gibbon/finace-dashboard/internal/domain/service"
 	"github.com/gibbon/finace-dashboard/internal/dto"
-	"github.com/gibbon/finace-dashboard/internal/service"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
 )
 
 // Обрабатка HTTP запросов аутентификации
@@ -29,39 +28,25 @@ func NewAuthHandler(authService domainService.AuthService) *AuthHandler {
 // @Produce json
 // @Param request body dto.RegisterRequest true "Данные для регистрации"
 // @Success 201 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 409 {object} map[string]string "Пользователь уже существует"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 409 {object} apperror.Problem "Пользователь уже существует"
 // @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req dto.RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
-		return
-	}
-
-	if req.Email == "" || req.Password == "" {
-		http.Error(w, `{"error": "email and password are required"}`, http.StatusBadRequest)
-		return
-	}
-
-	if len(req.Password) < 8 {
-		http.Error(w, `{"error": "password must be at least 8 characters"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	user, err := h.authService.Register(r.Context(), req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, service.ErrUserAlreadyExists) {
-			http.Error(w, `{"error": "user already exists"}`, http.StatusConflict)
-			return
-		}
-		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	accessToken, refreshToken, err := h.authService.GenerateTokens(user)
 	if err != nil {
-		http.Error(w, `{"error": "failed to generate tokens"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -88,29 +73,25 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body dto.LoginRequest true "Данные для входа"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 401 {object} map[string]string "Неверные учётные данные"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неверные учётные данные"
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req dto.LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	user, err := h.authService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidCredentials) {
-			http.Error(w, `{"error": "invalid credentials"}`, http.StatusUnauthorized)
-			return
-		}
-		http.Error(w, `{"error": "internal server error"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	accessToken, refreshToken, err := h.authService.GenerateTokens(user)
 	if err != nil {
-		http.Error(w, `{"error": "failed to generate tokens"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -136,19 +117,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body dto.RefreshRequest true "Refresh токен"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 401 {object} map[string]string "Невалидный токен"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Невалидный токен"
 // @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	var req dto.RefreshRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	userID, err := h.authService.ValidateRefreshToken(req.RefreshToken)
 	if err != nil {
-		http.Error(w, `{"error": "invalid refresh token"}`, http.StatusUnauthorized)
+		writeError(w, r, errInvalidRefresh)
 		return
 	}
 
@@ -156,7 +137,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	_ = userID
 
 	// TODO: Получить пользователя и сгенерировать новые токены
-	http.Error(w, `{"error": "not implemented"}`, http.StatusNotImplemented)
+	writeError(w, r, errNotImplemented)
 }
 
 // Logout
@@ -169,5 +150,5 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// TODO: Реализовать blacklist для токенов в Redis
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "logged out successfully"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "logged_out")})
 }
@@ -2,54 +2,251 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
 	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
 	"github.com/gibbon/finace-dashboard/internal/middleware"
 	"github.com/gibbon/finace-dashboard/internal/service"
 )
 
 // CategoryHandler обрабатывает HTTP запросы для категорий
 type CategoryHandler struct {
-	txService service.TransactionService
+	categoryService service.CategoryService
 }
 
 // NewCategoryHandler создаёт новый CategoryHandler
-func NewCategoryHandler(txService service.TransactionService) *CategoryHandler {
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
 	return &CategoryHandler{
-		txService: txService,
+		categoryService: categoryService,
 	}
 }
 
 // GetAll
 // @Summary Получить все категории
-// @Description Получение списка всех категорий
+// @Description Получение системных категорий и категорий пользователя
 // @Tags categories
 // @Produce json
+// @Param Accept-Language header string false "Язык названий (ru, en)"
 // @Success 200 {array} dto.CategoryResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
 // @Router /api/v1/categories [get]
 func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.txService.GetCategories(r.Context())
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	categories, err := h.categoryService.GetAll(r.Context(), userID, i18n.LocaleFromContext(r.Context()))
 	if err != nil {
-		http.Error(w, `{"error": "failed to get categories"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	response := make([]dto.CategoryResponse, len(categories))
 	for i, cat := range categories {
-		response[i] = dto.CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
-		}
+		response[i] = toCategoryResponse(cat)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetByID
+// @Summary Получить категорию по ID
+// @Description Получение категории, доступной пользователю
+// @Tags categories
+// @Produce json
+// @Param Accept-Language header string false "Язык названий (ru, en)"
+// @Param id path int true "ID категории"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/categories/{id} [get]
+func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errInvalidCategoryID)
+		return
+	}
+
+	category, err := h.categoryService.GetByID(r.Context(), userID, id, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toCategoryResponse(category))
+}
+
+// Create
+// @Summary Создать категорию
+// @Description Создание пользовательской категории или подкатегории
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCategoryRequest true "Данные категории"
+// @Success 201 {object} dto.CategoryResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 409 {object} apperror.Problem "Категория уже существует"
+// @Router /api/v1/categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateCategoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	category := &model.Category{
+		Name:     strings.TrimSpace(req.Name),
+		ParentID: req.ParentID,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	}
+
+	created, err := h.categoryService.Create(r.Context(), userID, category)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toCategoryResponse(created))
+}
+
+// Update
+// @Summary Обновить категорию
+// @Description Обновление пользовательской категории. Системные категории изменять нельзя
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param request body dto.UpdateCategoryRequest true "Данные категории"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/categories/{id} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errInvalidCategoryID)
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	category := &model.Category{
+		ID:       id,
+		Name:     strings.TrimSpace(req.Name),
+		ParentID: req.ParentID,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	}
+
+	updated, err := h.categoryService.Update(r.Context(), userID, category)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toCategoryResponse(updated))
+}
+
+// Delete
+// @Summary Удалить категорию
+// @Description Удаление пользовательской категории. Транзакции и правила переносятся в категорию reassign_to,
+// @Description которая должна быть доступна и всем рабочим пространствам, использующим удаляемую категорию
+// @Tags categories
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param reassign_to query int false "ID категории для переназначения транзакций"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Категория используется"
+// @Router /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, errInvalidCategoryID)
+		return
+	}
+
+	var reassignTo *int
+	if value := r.URL.Query().Get("reassign_to"); value != "" {
+		target, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, r, apperror.Validation(apperror.Field("reassign_to", "invalid_format")))
+			return
+		}
+		reassignTo = &target
+	}
+
+	if err := h.categoryService.Delete(r.Context(), userID, id, reassignTo); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "category_deleted")})
+}
+
+func toCategoryResponse(cat *model.Category) dto.CategoryResponse {
+	return dto.CategoryResponse{
+		ID:        cat.ID,
+		Name:      cat.Name,
+		Key:       cat.TranslationKey,
+		ParentID:  cat.ParentID,
+		Icon:      cat.Icon,
+		Color:     cat.Color,
+		IsDefault: cat.IsDefault,
+	}
+}
+
 // CategoryRuleHandler обрабатывает HTTP запросы для правил категоризации
 type CategoryRuleHandler struct {
 	txService service.TransactionService
@@ -70,42 +267,33 @@ func NewCategoryRuleHandler(txService service.TransactionService) *CategoryRuleH
 // @Produce json
 // @Param request body dto.CreateCategoryRuleRequest true "Данные правила"
 // @Success 201 {object} dto.CategoryRuleResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 401 {object} map[string]string "Неавторизован"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
 // @Router /api/v1/category-rules [post]
 func (h *CategoryRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	var req dto.CreateCategoryRuleRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if req.Keyword == "" {
-		http.Error(w, `{"error": "keyword is required"}`, http.StatusBadRequest)
-		return
-	}
-
-	if req.CategoryID <= 0 {
-		http.Error(w, `{"error": "category_id must be positive"}`, http.StatusBadRequest)
-		return
-	}
-
-	rule, err := h.txService.CreateRule(r.Context(), userID, req.Keyword, req.CategoryID)
+	rule, err := h.txService.CreateRule(r.Context(), userID, req.WorkspaceID, req.Keyword, req.CategoryID)
 	if err != nil {
-		http.Error(w, `{"error": "failed to create rule"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	response := dto.CategoryRuleResponse{
-		ID:         rule.ID,
-		Keyword:    rule.Keyword,
-		CategoryID: rule.CategoryID,
+		ID:          rule.ID,
+		Keyword:     rule.Keyword,
+		CategoryID:  rule.CategoryID,
+		WorkspaceID: rule.WorkspaceID,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -115,27 +303,34 @@ func (h *CategoryRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // GetAll
 // @Summary Получить правила пользователя
-// @Description Получение списка правил категоризации пользователя
+// @Description Получение списка личных правил категоризации пользователя или правил рабочего пространства
 // @Tags category-rules
 // @Produce json
+// @Param workspace_id query string false "ID рабочего пространства"
 // @Success 200 {array} dto.CategoryRuleResponse
-// @Failure 401 {object} map[string]string "Неавторизован"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
 // @Router /api/v1/category-rules [get]
 func (h *CategoryRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	rules, err := h.txService.GetRules(r.Context(), userID)
+	rules, err := h.txService.GetRules(r.Context(), userID, workspaceID)
 	if err != nil {
-		http.Error(w, `{"error": "failed to get rules"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	// Получаем категории для названий
-	categories, _ := h.txService.GetCategories(r.Context())
+	categories, _ := h.txService.GetCategories(r.Context(), userID, i18n.LocaleFromContext(r.Context()))
 	categoryMap := make(map[int]string)
 	for _, cat := range categories {
 		categoryMap[cat.ID] = cat.Name
@@ -144,10 +339,11 @@ func (h *CategoryRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response := make([]dto.CategoryRuleResponse, len(rules))
 	for i, rule := range rules {
 		response[i] = dto.CategoryRuleResponse{
-			ID:         rule.ID,
-			Keyword:    rule.Keyword,
-			CategoryID: rule.CategoryID,
-			Category:   categoryMap[rule.CategoryID],
+			ID:          rule.ID,
+			Keyword:     rule.Keyword,
+			CategoryID:  rule.CategoryID,
+			Category:    categoryMap[rule.CategoryID],
+			WorkspaceID: rule.WorkspaceID,
 		}
 	}
 
@@ -162,61 +358,28 @@ func (h *CategoryRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "ID правила"
 // @Success 200 {object} map[string]string "Успешно"
-// @Failure 401 {object} map[string]string "Неавторизован"
-// @Failure 404 {object} map[string]string "Не найдено"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
 // @Router /api/v1/category-rules/{id} [delete]
 func (h *CategoryRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, `{"error": "id is required"}`, http.StatusBadRequest)
+		writeError(w, r, errIDRequired)
 		return
 	}
 
 	if err := h.txService.DeleteRule(r.Context(), userID, id); err != nil {
-		if errors.Is(err, service.ErrUnauthorized) {
-			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
-			return
-		}
-		if errors.Is(err, service.ErrUserNotFound) {
-			http.Error(w, `{"error": "rule not found"}`, http.StatusNotFound)
-			return
-		}
-		http.Error(w, `{"error": "failed to delete rule"}`, http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "rule deleted successfully"})
-}
-
-// GetCategories
-// @Summary Получить все категории
-// @Description Получение списка всех категорий для использования в правилах
-// @Tags categories
-// @Produce json
-// @Success 200 {array} dto.CategoryResponse
-// @Router /api/v1/categories [get]
-func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.txService.GetCategories(r.Context())
-	if err != nil {
-		http.Error(w, `{"error": "failed to get categories"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	response := make([]dto.CategoryResponse, len(categories))
-	for i, cat := range categories {
-		response[i] = dto.CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
-		}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "rule_deleted")})
 }
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+)
+
+// Ошибки уровня HTTP, не связанные с бизнес-логикой
+var (
+	errUnauthenticated   = apperror.New(apperror.KindUnauthorized, "unauthorized")
+	errInvalidBody       = apperror.New(apperror.KindBadRequest, "invalid_request_body")
+	errIDRequired        = apperror.New(apperror.KindBadRequest, "id_required")
+	errInvalidRefresh    = apperror.New(apperror.KindUnauthorized, "invalid_refresh_token")
+	errNotImplemented    = apperror.New(apperror.KindNotImplemented, "not_implemented")
+	errInvalidCategoryID = apperror.New(apperror.KindBadRequest, "invalid_category_id")
+	errIfMatchRequired   = apperror.New(apperror.KindPreconditionRequired, "precondition_required")
+	errUnsupportedMedia  = apperror.New(apperror.KindUnsupportedMediaType, "unsupported_media_type")
+	errMultipartRequired = apperror.New(apperror.KindUnsupportedMediaType, "multipart_required")
+	errFileRequired      = apperror.New(apperror.KindBadRequest, "file_required")
+	errInvalidRevision   = apperror.New(apperror.KindBadRequest, "invalid_revision")
+)
+
+// writeError отправляет ошибку в формате application/problem+json
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	apperror.Write(w, r, err)
+}
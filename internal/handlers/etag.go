@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// transactionETag строит сильный ETag из версии транзакции (updated_at с точностью до микросекунд)
+func transactionETag(tx *model.Transaction) string {
+	return `"` + strconv.FormatInt(tx.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// etagMatches проверяет заголовок If-Match / If-None-Match.
+// Для If-Match используется сильное сравнение, поэтому слабые теги W/ не совпадают
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// weakETagMatches сравнивает теги для If-None-Match (слабое сравнение, RFC 9110)
+func weakETagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// setETag выставляет ETag текущей версии транзакции
+func setETag(w http.ResponseWriter, tx *model.Transaction) {
+	w.Header().Set("ETag", transactionETag(tx))
+}
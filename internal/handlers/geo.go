@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+)
+
+const (
+	// defaultNearRadius радиус поиска по умолчанию в метрах
+	defaultNearRadius = 1000
+	// maxNearRadius максимальный радиус поиска в метрах
+	maxNearRadius = 1000000
+	// Допустимая сторона ячейки сетки карты расходов в градусах
+	minPlaceCellSize = 0.0001
+	maxPlaceCellSize = 10
+)
+
+// parseGeoFilter разбирает параметры области: bbox=min_lon,min_lat,max_lon,max_lat
+// и окружность lat, lon, radius (в метрах). Порядок координат в bbox как в GeoJSON
+func parseGeoFilter(r *http.Request, filter *model.TransactionFilter) error {
+	query := r.URL.Query()
+	var fields []apperror.FieldError
+
+	if bbox := query.Get("bbox"); bbox != "" {
+		bounds, ok := parseBBox(bbox)
+		if ok {
+			filter.Bounds = bounds
+		} else {
+			fields = append(fields, apperror.Field("bbox", "invalid_format"))
+		}
+	}
+
+	lat, lon, radius := query.Get("lat"), query.Get("lon"), query.Get("radius")
+	if lat != "" || lon != "" || radius != "" {
+		circle := &model.GeoCircle{Radius: defaultNearRadius}
+		fields = parseCoordinate(fields, "lat", lat, "lon", -90, 90, &circle.Lat)
+		fields = parseCoordinate(fields, "lon", lon, "lat", -180, 180, &circle.Lon)
+		if radius != "" {
+			value, err := strconv.ParseFloat(radius, 64)
+			if err != nil || value < 1 || value > maxNearRadius {
+				fields = append(fields, outOfRange("radius", 1, maxNearRadius))
+			}
+			circle.Radius = value
+		}
+		filter.Near = circle
+	}
+
+	if len(fields) > 0 {
+		return apperror.Validation(fields...)
+	}
+	return nil
+}
+
+// parseCoordinate разбирает обязательную в паре координату и проверяет диапазон
+func parseCoordinate(fields []apperror.FieldError, name, value, pair string, min, max float64, dst *float64) []apperror.FieldError {
+	if value == "" {
+		return append(fields, apperror.FieldWithParams(name, "required_with", map[string]any{"field": pair}))
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || v < min || v > max {
+		return append(fields, outOfRange(name, min, max))
+	}
+	*dst = v
+	return fields
+}
+
+func parseBBox(value string) (*model.GeoBounds, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, false
+	}
+
+	var coords [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
+		}
+		coords[i] = v
+	}
+
+	b := &model.GeoBounds{MinLon: coords[0], MinLat: coords[1], MaxLon: coords[2], MaxLat: coords[3]}
+	// MinLon > MaxLon допустим: область пересекает 180-й меридиан
+	if b.MinLat < -90 || b.MaxLat > 90 || b.MinLat > b.MaxLat ||
+		b.MinLon < -180 || b.MinLon > 180 || b.MaxLon < -180 || b.MaxLon > 180 {
+		return nil, false
+	}
+	return b, true
+}
+
+func outOfRange(field string, min, max float64) apperror.FieldError {
+	return apperror.FieldWithParams(field, "out_of_range", map[string]any{"min": min, "max": max})
+}
+
+func toPlaceFeatureCollection(totals []*model.PlaceTotal) *dto.PlaceFeatureCollection {
+	features := make([]*dto.PlaceFeature, len(totals))
+	for i, total := range totals {
+		feature := &dto.PlaceFeature{
+			Type: "Feature",
+			Geometry: &dto.PointGeometry{
+				Type:        "Point",
+				Coordinates: []float64{total.Lon, total.Lat},
+			},
+			Properties: &dto.PlaceProperties{
+				Name:  total.Name,
+				Total: total.Total,
+				Count: total.Count,
+			},
+		}
+		if cell := total.Cell; cell != nil {
+			feature.BBox = []float64{cell.MinLon, cell.MinLat, cell.MaxLon, cell.MaxLat}
+		}
+		features[i] = feature
+	}
+	return &dto.PlaceFeatureCollection{Type: "FeatureCollection", Features: features}
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// GoalHandler обрабатывает HTTP запросы для целей накопления
+type GoalHandler struct {
+	goalService service.GoalService
+}
+
+// NewGoalHandler создаёт новый GoalHandler
+func NewGoalHandler(goalService service.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// GetAll
+// @Summary Получить цели накопления
+// @Description Цели пользователя с прогрессом, нужным ежемесячным взносом и прогнозом даты достижения
+// @Tags goals
+// @Produce json
+// @Success 200 {array} dto.GoalResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/goals [get]
+func (h *GoalHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	goals, err := h.goalService.GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.GoalResponse, len(goals))
+	for i, goal := range goals {
+		response[i] = toGoalResponse(goal)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetByID
+// @Summary Получить цель по ID
+// @Tags goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {object} dto.GoalResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/goals/{id} [get]
+func (h *GoalHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	goal, err := h.goalService.GetByID(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toGoalResponse(goal))
+}
+
+// Create
+// @Summary Создать цель накопления
+// @Description Взносами считаются транзакции с меткой tag_id в валюте цели
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param request body dto.GoalRequest true "Данные цели"
+// @Success 201 {object} dto.GoalResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/goals [post]
+func (h *GoalHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	goal, err := decodeGoal(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.goalService.Create(r.Context(), userID, goal)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toGoalResponse(created))
+}
+
+// Update
+// @Summary Обновить цель накопления
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цели"
+// @Param request body dto.GoalRequest true "Данные цели"
+// @Success 200 {object} dto.GoalResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/goals/{id} [put]
+func (h *GoalHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	goal, err := decodeGoal(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	goal.ID = chi.URLParam(r, "id")
+
+	updated, err := h.goalService.Update(r.Context(), userID, goal)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toGoalResponse(updated))
+}
+
+// Delete
+// @Summary Удалить цель накопления
+// @Description Транзакции-взносы и метка цели не удаляются
+// @Tags goals
+// @Produce json
+// @Param id path string true "ID цели"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/goals/{id} [delete]
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.goalService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "goal_deleted")})
+}
+
+// Contribute
+// @Summary Внести в цель
+// @Description Создаёт транзакцию в валюте цели с меткой цели. По умолчанию дата — текущий момент,
+// @Description описание — название цели. Взносом также становится любая транзакция, на которую поставлена метка цели
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path string true "ID цели"
+// @Param request body dto.GoalContributionRequest true "Взнос"
+// @Success 201 {object} dto.TransactionResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "У цели нет метки"
+// @Router /api/v1/goals/{id}/contributions [post]
+func (h *GoalHandler) Contribute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.GoalContributionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		// Дата уже проверена валидатором
+		date, _ = time.Parse(time.RFC3339, req.Date)
+	}
+
+	tx := &model.Transaction{
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: strings.TrimSpace(req.Description),
+		Date:        date,
+	}
+
+	created, err := h.goalService.Contribute(r.Context(), userID, chi.URLParam(r, "id"), tx)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, created)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toTransactionResponse(created))
+}
+
+// decodeGoal разбирает тело запроса цели и срок в формате RFC3339
+func decodeGoal(r *http.Request) (*model.Goal, error) {
+	var req dto.GoalRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	goal := &model.Goal{
+		Name:         strings.TrimSpace(req.Name),
+		TargetAmount: req.TargetAmount,
+		Currency:     req.Currency,
+		TagID:        req.TagID,
+	}
+	if goal.Currency == "" {
+		goal.Currency = string(model.CurrencyRUB)
+	}
+
+	if req.Deadline != nil {
+		deadline, err := time.Parse(time.RFC3339, *req.Deadline)
+		if err != nil {
+			return nil, apperror.Validation(apperror.Field("deadline", "invalid_date"))
+		}
+		goal.Deadline = &deadline
+	}
+
+	return goal, nil
+}
+
+func toGoalResponse(goal *model.Goal) *dto.GoalResponse {
+	response := &dto.GoalResponse{
+		ID:           goal.ID,
+		Name:         goal.Name,
+		TargetAmount: goal.TargetAmount,
+		Currency:     goal.Currency,
+		Deadline:     goal.Deadline,
+		TagID:        goal.TagID,
+		CreatedAt:    goal.CreatedAt,
+		UpdatedAt:    goal.UpdatedAt,
+	}
+	if p := goal.Progress; p != nil {
+		response.Progress = &dto.GoalProgressResponse{
+			Saved:           p.Saved,
+			Remaining:       p.Remaining,
+			Percent:         p.Percent,
+			Contributions:   p.Contributions,
+			Achieved:        p.Achieved,
+			MonthlyRequired: p.MonthlyRequired,
+			ProjectedDate:   p.ProjectedDate,
+			OnTrack:         p.OnTrack,
+		}
+	}
+	return response
+}
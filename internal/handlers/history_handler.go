@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// HistoryHandler обрабатывает HTTP запросы истории изменений транзакций
+type HistoryHandler struct {
+	txService service.TransactionService
+}
+
+// NewHistoryHandler создаёт новый HistoryHandler
+func NewHistoryHandler(txService service.TransactionService) *HistoryHandler {
+	return &HistoryHandler{
+		txService: txService,
+	}
+}
+
+// GetHistory
+// @Summary История изменений транзакции
+// @Description Прежние версии транзакции, последние первыми. Версия сохраняется при каждом изменении транзакции
+// @Tags transactions
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Success 200 {object} dto.TransactionHistoryResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/history [get]
+func (h *HistoryHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	revisions, err := h.txService.GetHistory(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := dto.TransactionHistoryResponse{
+		Revisions: make([]*dto.TransactionRevisionResponse, len(revisions)),
+	}
+	for i, rev := range revisions {
+		response.Revisions[i] = &dto.TransactionRevisionResponse{
+			Revision:    rev.Revision,
+			Transaction: toTransactionResponse(rev.Transaction),
+			ReplacedAt:  rev.ReplacedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Revert
+// @Summary Откатить транзакцию к прежней версии
+// @Description Заменяет данные транзакции данными версии. Текущее состояние сохраняется в истории новой версией.
+// @Description Метки и разбивка не версионируются и не меняются
+// @Tags transactions
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param revision path int true "Номер версии"
+// @Success 200 {object} dto.TransactionResponse
+// @Header 200 {string} ETag "Новая версия транзакции"
+// @Failure 400 {object} apperror.Problem "Некорректный номер версии"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Транзакция или версия не найдена"
+// @Failure 409 {object} apperror.Problem "Сумма разбитой транзакции меняется только вместе с разбивкой"
+// @Failure 412 {object} apperror.Problem "Транзакция изменена другим клиентом"
+// @Router /api/v1/transactions/{id}/revert/{revision} [post]
+func (h *HistoryHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || revision < 1 {
+		writeError(w, r, errInvalidRevision)
+		return
+	}
+
+	tx, err := h.txService.Revert(r.Context(), userID, id, revision)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, tx)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTransactionResponse(tx))
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// InsightsHandler обрабатывает HTTP запросы для подсказок о расходах
+type InsightsHandler struct {
+	anomalyService service.AnomalyService
+}
+
+// NewInsightsHandler создаёт новый InsightsHandler
+func NewInsightsHandler(anomalyService service.AnomalyService) *InsightsHandler {
+	return &InsightsHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+// GetAnomalies
+// @Summary Необычные траты
+// @Description Транзакции с суммой намного выше обычной для продавца или категории, крупные первые траты у продавцов
+// @Description и всплески расходов категории за месяц. Новые сначала, скрытые по умолчанию не возвращаются
+// @Tags insights
+// @Produce json
+// @Param Accept-Language header string false "Язык объяснений (ru, en)"
+// @Param kind query string false "Вид: category_amount, merchant_amount, new_merchant, category_spike"
+// @Param include_dismissed query bool false "Включать скрытые" default(false)
+// @Param limit query int false "Лимит" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {array} dto.AnomalyResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/insights/anomalies [get]
+func (h *InsightsHandler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	filter := model.AnomalyFilter{
+		UserID: userID,
+		Limit:  20,
+	}
+
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		k := model.AnomalyKind(kind)
+		if !k.IsValid() {
+			writeError(w, r, apperror.Validation(apperror.FieldWithParams("kind", "not_allowed",
+				map[string]any{"allowed": "category_amount, merchant_amount, new_merchant, category_spike"})))
+			return
+		}
+		filter.Kind = &k
+	}
+
+	if include := r.URL.Query().Get("include_dismissed"); include != "" {
+		value, err := strconv.ParseBool(include)
+		if err != nil {
+			writeError(w, r, apperror.Validation(apperror.Field("include_dismissed", "invalid_format")))
+			return
+		}
+		filter.IncludeDismissed = value
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+	}
+
+	anomalies, err := h.anomalyService.GetAll(r.Context(), filter, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.AnomalyResponse, len(anomalies))
+	for i, anomaly := range anomalies {
+		response[i] = toAnomalyResponse(r.Context(), anomaly)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DismissAnomaly
+// @Summary Скрыть необычную трату
+// @Description Повторно та же аномалия не создаётся
+// @Tags insights
+// @Produce json
+// @Param id path string true "ID аномалии"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/insights/anomalies/{id}/dismiss [post]
+func (h *InsightsHandler) DismissAnomaly(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.anomalyService.Dismiss(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "anomaly_dismissed")})
+}
+
+func toAnomalyResponse(ctx context.Context, anomaly *model.Anomaly) *dto.AnomalyResponse {
+	response := &dto.AnomalyResponse{
+		ID:            anomaly.ID,
+		Kind:          string(anomaly.Kind),
+		TransactionID: anomaly.TransactionID,
+		CategoryID:    anomaly.CategoryID,
+		MerchantID:    anomaly.MerchantID,
+		Amount:        anomaly.Amount,
+		Baseline:      anomaly.Baseline,
+		Ratio:         anomaly.Ratio,
+		Currency:      anomaly.Currency,
+		Explanation:   service.AnomalyExplanation(ctx, anomaly),
+		DismissedAt:   anomaly.DismissedAt,
+		CreatedAt:     anomaly.CreatedAt,
+	}
+	if anomaly.Period != nil {
+		period := anomaly.Period.Format("2006-01")
+		response.Period = &period
+	}
+	return response
+}
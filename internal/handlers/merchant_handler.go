@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// MerchantHandler обрабатывает HTTP запросы для продавцов
+type MerchantHandler struct {
+	merchantService service.MerchantService
+}
+
+// NewMerchantHandler создаёт новый MerchantHandler
+func NewMerchantHandler(merchantService service.MerchantService) *MerchantHandler {
+	return &MerchantHandler{
+		merchantService: merchantService,
+	}
+}
+
+// GetAll
+// @Summary Получить продавцов
+// @Description Продавцы пользователя вместе с привязанными к ним нормализованными описаниями
+// @Tags merchants
+// @Produce json
+// @Success 200 {array} dto.MerchantResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/merchants [get]
+func (h *MerchantHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	merchants, err := h.merchantService.GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.MerchantResponse, len(merchants))
+	for i, m := range merchants {
+		response[i] = toMerchantResponse(m)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetByID
+// @Summary Получить продавца по ID
+// @Tags merchants
+// @Produce json
+// @Param id path string true "ID продавца"
+// @Success 200 {object} dto.MerchantResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/merchants/{id} [get]
+func (h *MerchantHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	m, err := h.merchantService.GetByID(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toMerchantResponse(m))
+}
+
+// Update
+// @Summary Переименовать продавца
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Param id path string true "ID продавца"
+// @Param request body dto.UpdateMerchantRequest true "Название"
+// @Success 200 {object} dto.MerchantResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/merchants/{id} [put]
+func (h *MerchantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateMerchantRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	m, err := h.merchantService.Rename(r.Context(), userID, chi.URLParam(r, "id"), req.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toMerchantResponse(m))
+}
+
+// Delete
+// @Summary Удалить продавца
+// @Description Удаление продавца; его транзакции остаются без продавца до следующей привязки
+// @Tags merchants
+// @Produce json
+// @Param id path string true "ID продавца"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/merchants/{id} [delete]
+func (h *MerchantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.merchantService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "merchant_deleted")})
+}
+
+// AddAlias
+// @Summary Привязать описание к продавцу
+// @Description Описание нормализуется так же, как описания транзакций: новые транзакции
+// @Description с похожим описанием будут привязаны к этому продавцу
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Param id path string true "ID продавца"
+// @Param request body dto.AddMerchantAliasRequest true "Описание"
+// @Success 200 {object} dto.MerchantResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Описание привязано к другому продавцу"
+// @Router /api/v1/merchants/{id}/aliases [post]
+func (h *MerchantHandler) AddAlias(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.AddMerchantAliasRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	m, err := h.merchantService.AddAlias(r.Context(), userID, chi.URLParam(r, "id"), req.Alias)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toMerchantResponse(m))
+}
+
+// RemoveAlias
+// @Summary Отвязать описание от продавца
+// @Tags merchants
+// @Produce json
+// @Param id path string true "ID продавца"
+// @Param alias path string true "Описание или его нормализованный ключ"
+// @Success 200 {object} dto.MerchantResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/merchants/{id}/aliases/{alias} [delete]
+func (h *MerchantHandler) RemoveAlias(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	alias, err := url.PathUnescape(chi.URLParam(r, "alias"))
+	if err != nil {
+		writeError(w, r, apperror.Validation(apperror.Field("alias", "invalid_format")))
+		return
+	}
+
+	m, err := h.merchantService.RemoveAlias(r.Context(), userID, chi.URLParam(r, "id"), alias)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toMerchantResponse(m))
+}
+
+// Merge
+// @Summary Объединить продавцов
+// @Description Описания и транзакции продавцов из source_ids переходят к продавцу id, сами продавцы удаляются
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Param id path string true "ID продавца, который остаётся"
+// @Param request body dto.MergeMerchantsRequest true "Объединяемые продавцы"
+// @Success 200 {object} dto.MerchantResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/merchants/{id}/merge [post]
+func (h *MerchantHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.MergeMerchantsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	m, err := h.merchantService.Merge(r.Context(), userID, chi.URLParam(r, "id"), req.SourceIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toMerchantResponse(m))
+}
+
+// Match
+// @Summary Привязать транзакции к продавцам
+// @Description Находит продавцов для транзакций, у которых продавца ещё нет, и создаёт недостающих.
+// @Description Нужен для транзакций, созданных до появления продавцов или отвязанных при удалении продавца
+// @Tags merchants
+// @Produce json
+// @Success 200 {object} dto.MerchantMatchResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/merchants/match [post]
+func (h *MerchantHandler) Match(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	matched, err := h.merchantService.Match(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.MerchantMatchResponse{Matched: matched})
+}
+
+func toMerchantResponse(m *model.Merchant) *dto.MerchantResponse {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return &dto.MerchantResponse{
+		ID:      m.ID,
+		Name:    m.Name,
+		Aliases: aliases,
+	}
+}
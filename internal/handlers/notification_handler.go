@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// NotificationHandler обрабатывает HTTP запросы для настроек уведомлений
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler создаёт новый NotificationHandler
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetChannels
+// @Summary Каналы уведомлений
+// @Description Все каналы: доступен ли канал на сервере и адрес пользователя, если канал настроен
+// @Tags notifications
+// @Produce json
+// @Success 200 {array} dto.NotificationChannelResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/notifications/channels [get]
+func (h *NotificationHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	targets, err := h.notificationService.GetTargets(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	available := make(map[model.NotificationChannel]bool)
+	for _, channel := range h.notificationService.AvailableChannels() {
+		available[channel] = true
+	}
+	configured := make(map[model.NotificationChannel]*model.NotificationTarget, len(targets))
+	for _, target := range targets {
+		configured[target.Channel] = target
+	}
+
+	response := make([]*dto.NotificationChannelResponse, len(model.NotificationChannels))
+	for i, channel := range model.NotificationChannels {
+		response[i] = &dto.NotificationChannelResponse{
+			Channel:   string(channel),
+			Available: available[channel],
+		}
+		if target := configured[channel]; target != nil {
+			response[i].Address = &target.Address
+			response[i].Locale = &target.Locale
+			response[i].UpdatedAt = &target.UpdatedAt
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetChannel
+// @Summary Настроить канал уведомлений
+// @Description Сохраняет адрес: email, HTTPS-адрес вебхука или chat_id Telegram.
+// @Description Уведомления в канал приходят на языке запроса
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "Язык уведомлений (ru, en)"
+// @Param channel path string true "Канал: email, webhook, telegram"
+// @Param request body dto.NotificationChannelRequest true "Адрес"
+// @Success 200 {object} dto.NotificationChannelResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 404 {object} apperror.Problem "Неизвестный канал"
+// @Failure 409 {object} apperror.Problem "Канал недоступен на сервере"
+// @Router /api/v1/notifications/channels/{channel} [put]
+func (h *NotificationHandler) SetChannel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.NotificationChannelRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	channel := model.NotificationChannel(chi.URLParam(r, "channel"))
+	target, err := h.notificationService.SetTarget(r.Context(), userID, channel, req.Address, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&dto.NotificationChannelResponse{
+		Channel:   string(target.Channel),
+		Available: true,
+		Address:   &target.Address,
+		Locale:    &target.Locale,
+		UpdatedAt: &target.UpdatedAt,
+	})
+}
+
+// DeleteChannel
+// @Summary Отключить канал уведомлений
+// @Tags notifications
+// @Produce json
+// @Param channel path string true "Канал: email, webhook, telegram"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 404 {object} apperror.Problem "Канал не настроен"
+// @Router /api/v1/notifications/channels/{channel} [delete]
+func (h *NotificationHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	channel := model.NotificationChannel(chi.URLParam(r, "channel"))
+	if err := h.notificationService.DeleteTarget(r.Context(), userID, channel); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "notification_target_deleted")})
+}
+
+// GetPreferences
+// @Summary Настройки событий
+// @Description Для каждой пары событие — канал: отправляется ли событие в канал. По умолчанию включено
+// @Tags notifications
+// @Produce json
+// @Success 200 {array} dto.NotificationPreferenceDTO
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationPreferences(prefs))
+}
+
+// SetPreferences
+// @Summary Изменить настройки событий
+// @Description Не указанные пары событие — канал не меняются. Возвращает все настройки
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body dto.NotificationPreferencesRequest true "Настройки"
+// @Success 200 {array} dto.NotificationPreferenceDTO
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/notifications/preferences [put]
+func (h *NotificationHandler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.NotificationPreferencesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	prefs := make([]*model.NotificationPreference, len(req.Preferences))
+	for i, p := range req.Preferences {
+		prefs[i] = &model.NotificationPreference{
+			Event:   model.NotificationEvent(p.Event),
+			Channel: model.NotificationChannel(p.Channel),
+			Enabled: p.Enabled,
+		}
+	}
+
+	saved, err := h.notificationService.SetPreferences(r.Context(), userID, prefs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toNotificationPreferences(saved))
+}
+
+func toNotificationPreferences(prefs []*model.NotificationPreference) []dto.NotificationPreferenceDTO {
+	response := make([]dto.NotificationPreferenceDTO, len(prefs))
+	for i, pref := range prefs {
+		response[i] = dto.NotificationPreferenceDTO{
+			Event:   string(pref.Event),
+			Channel: string(pref.Channel),
+			Enabled: pref.Enabled,
+		}
+	}
+	return response
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// maxReceiptImageSize максимальный размер фотографии чека
+const maxReceiptImageSize = 10 << 20
+
+// ReceiptHandler обрабатывает импорт транзакций по QR-кодам кассовых чеков
+type ReceiptHandler struct {
+	receiptService service.ReceiptService
+}
+
+// NewReceiptHandler создаёт новый ReceiptHandler
+func NewReceiptHandler(receiptService service.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptService: receiptService,
+	}
+}
+
+// Import
+// @Summary Создать транзакцию по QR-коду чека
+// @Description Принимает строку из QR-кода кассового чека ФНС (t=...&s=...&fn=...&i=...&fp=...&n=...).
+// @Description Сумма, дата и время берутся из чека, время трактуется в поясе timezone (по умолчанию Europe/Moscow).
+// @Description Повторный импорт того же чека отклоняется
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Param request body dto.ImportReceiptRequest true "Строка QR-кода"
+// @Success 201 {object} dto.ImportReceiptResponse
+// @Failure 400 {object} apperror.Problem "Неверный запрос или ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 409 {object} apperror.Problem "Чек уже импортирован"
+// @Router /api/v1/transactions/receipt [post]
+func (h *ReceiptHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.ImportReceiptRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params := &service.ReceiptImport{QR: req.QR}
+	if req.Timezone != nil {
+		params.Timezone = *req.Timezone
+	}
+	if req.Description != nil {
+		params.Description = *req.Description
+	}
+
+	receipt, err := h.receiptService.Import(r.Context(), userID, params)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReceipt(w, receipt)
+}
+
+// ImportImage
+// @Summary Создать транзакцию по фотографии чека
+// @Description Распознаёт QR-код на изображении (JPEG, PNG, GIF) из поля file формы multipart/form-data
+// @Description и создаёт транзакцию так же, как импорт по строке QR-кода
+// @Tags receipts
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Фотография чека"
+// @Param timezone query string false "Часовой пояс времени в чеке (по умолчанию Europe/Moscow)"
+// @Param description query string false "Описание транзакции"
+// @Success 201 {object} dto.ImportReceiptResponse
+// @Failure 400 {object} apperror.Problem "QR-код не найден, изображение повреждено или ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 409 {object} apperror.Problem "Чек уже импортирован"
+// @Failure 413 {object} apperror.Problem "Файл слишком большой"
+// @Failure 415 {object} apperror.Problem "Ожидается multipart/form-data"
+// @Router /api/v1/transactions/receipt/image [post]
+func (h *ReceiptHandler) ImportImage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		writeError(w, r, errMultipartRequired)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxReceiptImageSize+multipartOverhead)
+
+	_, data, err := readMultipartFile(r, "file", maxReceiptImageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	params := dto.ImportReceiptImageParams{
+		Timezone:    query.Get("timezone"),
+		Description: query.Get("description"),
+	}
+	if err := validateRequest(&params); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	receipt, err := h.receiptService.ImportImage(r.Context(), userID, data, &service.ReceiptImport{
+		Timezone:    params.Timezone,
+		Description: params.Description,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeReceipt(w, receipt)
+}
+
+func writeReceipt(w http.ResponseWriter, receipt *model.FiscalReceipt) {
+	setETag(w, receipt.Transaction)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&dto.ImportReceiptResponse{
+		Transaction: toTransactionResponse(receipt.Transaction),
+		Receipt: &dto.FiscalReceiptResponse{
+			FN:        receipt.FN,
+			FD:        receipt.FD,
+			FP:        receipt.FP,
+			Operation: receipt.Operation,
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/pkg/validator"
+)
+
+// decodeJSON читает тело запроса и проверяет его по тегам validate.
+// Возвращает ошибку apperror со всеми нарушениями сразу
+func decodeJSON(r *http.Request, dst any) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return errInvalidBody
+	}
+	return validateRequest(dst)
+}
+
+// validateRequest проверяет уже разобранный запрос
+func validateRequest(req any) error {
+	err := dto.Validate(req)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.Errors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fields := make([]apperror.FieldError, len(verrs))
+	for i, fe := range verrs {
+		fields[i] = apperror.FieldWithParams(fe.Field, fe.Code, fe.Params)
+	}
+	return apperror.Validation(fields...)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// ReviewHandler обрабатывает HTTP запросы очереди проверки транзакций
+type ReviewHandler struct {
+	txService service.TransactionService
+}
+
+// NewReviewHandler создаёт новый ReviewHandler
+func NewReviewHandler(txService service.TransactionService) *ReviewHandler {
+	return &ReviewHandler{
+		txService: txService,
+	}
+}
+
+// GetQueue
+// @Summary Очередь проверки транзакций
+// @Description Неподтверждённые транзакции с предложенными категориями: из автокатегоризации или из истории подтверждённых транзакций с тем же описанием
+// @Tags review
+// @Produce json
+// @Param Accept-Language header string false "Язык названий (ru, en)"
+// @Param workspace_id query string false "ID рабочего пространства; без него в очереди личные транзакции"
+// @Param sort query string false "Порядок: date или amount" default(date)
+// @Param limit query int false "Лимит" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} dto.ReviewQueueResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа к рабочему пространству"
+// @Router /api/v1/transactions/review [get]
+func (h *ReviewHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := model.ReviewFilter{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Sort:        model.ReviewSortDate,
+		Limit:       20,
+	}
+
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		switch model.ReviewSort(sort) {
+		case model.ReviewSortDate, model.ReviewSortAmount:
+			filter.Sort = model.ReviewSort(sort)
+		default:
+			writeError(w, r, apperror.Validation(apperror.FieldWithParams("sort", "not_allowed", map[string]any{"allowed": "date, amount"})))
+			return
+		}
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+	}
+
+	items, err := h.txService.GetReviewQueue(r.Context(), filter, i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	counts, err := h.txService.GetReviewCounts(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := dto.ReviewQueueResponse{
+		Items:  make([]*dto.ReviewItemResponse, len(items)),
+		Total:  counts.Unconfirmed,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for i, item := range items {
+		response.Items[i] = &dto.ReviewItemResponse{
+			Transaction:         toTransactionResponse(item.Transaction),
+			SuggestedCategoryID: item.SuggestedCategoryID,
+		}
+		if item.SuggestedCategory != nil {
+			response.Items[i].SuggestedCategory = &item.SuggestedCategory.Name
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetCounts
+// @Summary Счётчики очереди проверки
+// @Description Количество неподтверждённых транзакций и транзакций без категории для бейджа в интерфейсе
+// @Tags review
+// @Produce json
+// @Param workspace_id query string false "ID рабочего пространства; без него считаются личные транзакции"
+// @Success 200 {object} dto.ReviewCountsResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа к рабочему пространству"
+// @Router /api/v1/transactions/review/count [get]
+func (h *ReviewHandler) GetCounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	counts, err := h.txService.GetReviewCounts(r.Context(), userID, workspaceID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.ReviewCountsResponse{
+		Unconfirmed:   counts.Unconfirmed,
+		Uncategorized: counts.Uncategorized,
+	})
+}
+
+// Review
+// @Summary Принять или отклонить категорию
+// @Description accept подтверждает текущую или предложенную категорию (или переданную category_id), reject назначает category_id. В обоих случаях транзакция становится подтверждённой
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param request body dto.ReviewTransactionRequest true "Решение"
+// @Success 200 {object} dto.TransactionResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/review [post]
+func (h *ReviewHandler) Review(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	var req dto.ReviewTransactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	action := model.ReviewAction(req.Action)
+	if action == model.ReviewReject && req.CategoryID == nil {
+		writeError(w, r, apperror.Validation(apperror.Field("category_id", "required")))
+		return
+	}
+
+	tx, err := h.txService.Review(r.Context(), userID, id, action, req.CategoryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, tx)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTransactionResponse(tx))
+}
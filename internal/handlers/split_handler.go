@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// SplitHandler обрабатывает HTTP запросы разбивки транзакций
+type SplitHandler struct {
+	splitService service.SplitService
+}
+
+// NewSplitHandler создаёт новый SplitHandler
+func NewSplitHandler(splitService service.SplitService) *SplitHandler {
+	return &SplitHandler{
+		splitService: splitService,
+	}
+}
+
+// Get
+// @Summary Получить разбивку транзакции
+// @Description Строки разбивки транзакции по категориям. Пустой список — транзакция не разбита
+// @Tags splits
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Success 200 {object} dto.SplitsResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/splits [get]
+func (h *SplitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	splits, err := h.splitService.Get(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toSplitsResponse(id, splits))
+}
+
+// Create
+// @Summary Разбить транзакцию по категориям
+// @Description Создание строк разбивки. Сумма строк должна совпадать с суммой транзакции с точностью до копейки
+// @Tags splits
+// @Accept json
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param request body dto.SplitTransactionRequest true "Строки разбивки"
+// @Success 201 {object} dto.SplitsResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Транзакция уже разбита"
+// @Router /api/v1/transactions/{id}/splits [post]
+func (h *SplitHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.save(w, r, false)
+}
+
+// Replace
+// @Summary Заменить разбивку транзакции
+// @Description Полная замена строк разбивки. Сумма строк должна совпадать с суммой транзакции с точностью до копейки
+// @Tags splits
+// @Accept json
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param request body dto.SplitTransactionRequest true "Строки разбивки"
+// @Success 200 {object} dto.SplitsResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/splits [put]
+func (h *SplitHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	h.save(w, r, true)
+}
+
+// Delete
+// @Summary Удалить разбивку транзакции
+// @Description Транзакция снова относится к одной категории
+// @Tags splits
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/splits [delete]
+func (h *SplitHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	if err := h.splitService.Delete(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "splits_deleted")})
+}
+
+func (h *SplitHandler) save(w http.ResponseWriter, r *http.Request, replace bool) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	var req dto.SplitTransactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	splits := make([]*model.TransactionSplit, len(req.Splits))
+	for i, line := range req.Splits {
+		splits[i] = &model.TransactionSplit{
+			CategoryID: line.CategoryID,
+			Amount:     line.Amount,
+			Note:       line.Note,
+		}
+	}
+
+	var err error
+	if replace {
+		splits, err = h.splitService.Replace(r.Context(), userID, id, splits)
+	} else {
+		splits, err = h.splitService.Create(r.Context(), userID, id, splits)
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !replace {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(toSplitsResponse(id, splits))
+}
+
+func toSplitsResponse(transactionID string, splits []*model.TransactionSplit) *dto.SplitsResponse {
+	response := &dto.SplitsResponse{
+		TransactionID: transactionID,
+		Splits:        make([]*dto.SplitLineResponse, len(splits)),
+	}
+	for i, split := range splits {
+		response.Splits[i] = &dto.SplitLineResponse{
+			ID:         split.ID,
+			CategoryID: split.CategoryID,
+			Amount:     split.Amount,
+			Note:       split.Note,
+			CreatedAt:  split.CreatedAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// streamWriteTimeout срок каждой записи в поток. Общий WriteTimeout сервера
+// ограничивает весь ответ, поэтому поток заменяет его сроком на отдельную запись
+const streamWriteTimeout = 15 * time.Second
+
+// StreamHandler отдаёт события пользователя в реальном времени по Server-Sent Events
+type StreamHandler struct {
+	streamService service.StreamService
+	heartbeat     time.Duration
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+// NewStreamHandler создаёт новый StreamHandler. heartbeat — период комментариев,
+// которые не дают прокси закрыть соединение без событий
+func NewStreamHandler(streamService service.StreamService, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+		heartbeat:     heartbeat,
+		done:          make(chan struct{}),
+	}
+}
+
+// Close завершает открытые потоки, чтобы остановка сервера не ждала отключения клиентов
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Stream
+// @Summary Поток обновлений
+// @Description Server-Sent Events с изменениями данных пользователя: transaction.created, transaction.updated,
+// @Description transaction.deleted (data — транзакция в JSON) и import.progress (этап импорта чека:
+// @Description import_id, source, stage, transaction_id, error). Каждое событие имеет id. При переподключении
+// @Description с заголовком Last-Event-ID сначала приходят пропущенные события (хранятся 10 минут). Если часть
+// @Description пропущенного уже недоступна, приходит событие reset — данные нужно перечитать.
+// @Description Соединение поддерживается комментариями каждые STREAM_HEARTBEAT_INTERVAL
+// @Tags stream
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID последнего полученного события"
+// @Success 200 {string} string "Поток событий"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/stream [get]
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := extendWriteDeadline(rc); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub := h.streamService.Open(userID, r.Header.Get("Last-Event-ID"))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// nginx не должен буферизовать поток
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if sub.Reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range sub.Replay {
+		if err := writeStreamEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case e, ok := <-sub.Events:
+			// Клиент отстал: при переподключении он получит пропущенное по Last-Event-ID
+			if !ok {
+				return
+			}
+			if extendWriteDeadline(rc) != nil || writeStreamEvent(w, e) != nil {
+				return
+			}
+		case <-ticker.C:
+			if extendWriteDeadline(rc) != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// extendWriteDeadline продлевает срок записи. Без поддержки сроков у ResponseWriter
+// ограничения нет и продлевать нечего
+func extendWriteDeadline(rc *http.ResponseController) error {
+	err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+func writeStreamEvent(w io.Writer, e *model.StreamEvent) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Name, data)
+	return err
+}
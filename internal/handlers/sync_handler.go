@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// SyncHandler обрабатывает HTTP запросы синхронизации мобильных клиентов
+type SyncHandler struct {
+	syncService service.SyncService
+}
+
+// NewSyncHandler создаёт новый SyncHandler
+func NewSyncHandler(syncService service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// Pull
+// @Summary Получить изменения для синхронизации
+// @Description Созданные, изменённые и удалённые транзакции, правила и категории после токена since.
+// @Description Без since возвращаются все данные без удалённых. Токен из ответа передаётся в следующий запрос;
+// @Description запись может прийти повторно, клиент применяет изменения как upsert по ID
+// @Tags sync
+// @Produce json
+// @Param Accept-Language header string false "Язык названий категорий (ru, en)"
+// @Param since query string false "Токен из предыдущего ответа"
+// @Success 200 {object} dto.SyncPullResponse
+// @Failure 400 {object} apperror.Problem "Некорректный токен"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/sync [get]
+func (h *SyncHandler) Pull(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	changes, err := h.syncService.Pull(r.Context(), userID, r.URL.Query().Get("since"), i18n.LocaleFromContext(r.Context()))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := &dto.SyncPullResponse{
+		Token: changes.Token,
+		Changes: dto.SyncChanges{
+			Transactions: dto.SyncTransactionChanges{
+				Created: toTransactionResponses(changes.Transactions.Created),
+				Updated: toTransactionResponses(changes.Transactions.Updated),
+				Deleted: nonNil(changes.Transactions.Deleted),
+			},
+			Rules: dto.SyncRuleChanges{
+				Created: toSyncRuleResponses(changes.Rules.Created),
+				Updated: toSyncRuleResponses(changes.Rules.Updated),
+				Deleted: nonNil(changes.Rules.Deleted),
+			},
+			Categories: dto.SyncCategoryChanges{
+				Created: toCategoryResponses(changes.Categories.Created),
+				Updated: toCategoryResponses(changes.Categories.Updated),
+				Deleted: nonNil(changes.Categories.Deleted),
+			},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Push
+// @Summary Отправить офлайн-изменения
+// @Description Транзакции, созданные, изменённые и удалённые клиентом без сети, с ID, сгенерированными клиентом.
+// @Description Изменения применяются по порядку, каждое отдельно. Изменение версии, которая на сервере уже новее
+// @Description base_updated_at, не применяется: возвращается статус conflict с серверной версией, клиент сливает её и отправляет снова
+// @Tags sync
+// @Accept json
+// @Produce json
+// @Param request body dto.SyncPushRequest true "Изменения"
+// @Success 200 {object} dto.SyncPushResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/sync [post]
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.SyncPushRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	changes, indexes, err := toSyncChanges(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	results, err := h.syncService.Push(r.Context(), userID, changes)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := &dto.SyncPushResponse{Results: make([]*dto.SyncItemResponse, len(results))}
+	for i, result := range results {
+		item := &dto.SyncItemResponse{
+			Op:     string(result.Operation),
+			Index:  indexes[i],
+			ID:     result.ID,
+			Status: string(result.Status),
+		}
+		if result.Transaction != nil {
+			item.Transaction = toTransactionResponse(result.Transaction)
+		}
+		if result.Err != nil {
+			code := apperror.From(result.Err).Code
+			item.Error = &dto.BulkItemError{Code: code, Detail: i18n.T(r.Context(), code)}
+		}
+
+		switch result.Status {
+		case model.SyncApplied:
+			response.Applied++
+		case model.SyncConflict:
+			response.Conflicts++
+		default:
+			response.Rejected++
+		}
+		response.Results[i] = item
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// toSyncChanges проверяет ID и версии изменений и переводит их в модель.
+// Возвращает также индексы изменений в их списках запроса
+func toSyncChanges(req *dto.SyncPushRequest) ([]*model.SyncChange, []int, error) {
+	var (
+		fields  []apperror.FieldError
+		changes []*model.SyncChange
+		indexes []int
+	)
+
+	add := func(op model.SyncOperation, field string, i int, id string, base *time.Time, tx *model.Transaction) {
+		prefix := "transactions." + field + "[" + strconv.Itoa(i) + "]."
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			fields = append(fields, apperror.Field(prefix+"id", "invalid_format"))
+			return
+		}
+		if op == model.SyncUpdate && base == nil {
+			fields = append(fields, apperror.Field(prefix+"base_updated_at", "required"))
+			return
+		}
+
+		tx.ID = parsed.String()
+		changes = append(changes, &model.SyncChange{Operation: op, Transaction: tx, BaseUpdatedAt: base})
+		indexes = append(indexes, i)
+	}
+
+	for i := range req.Transactions.Created {
+		item := &req.Transactions.Created[i]
+		add(model.SyncCreate, "created", i, item.ID, item.BaseUpdatedAt, newSyncTransaction(item))
+	}
+	for i := range req.Transactions.Updated {
+		item := &req.Transactions.Updated[i]
+		add(model.SyncUpdate, "updated", i, item.ID, item.BaseUpdatedAt, newSyncTransaction(item))
+	}
+	for i, item := range req.Transactions.Deleted {
+		add(model.SyncDelete, "deleted", i, item.ID, item.BaseUpdatedAt, &model.Transaction{})
+	}
+
+	total := len(req.Transactions.Created) + len(req.Transactions.Updated) + len(req.Transactions.Deleted)
+	if total > model.MaxSyncPushItems {
+		fields = append(fields, apperror.FieldWithParams("transactions", "too_many", map[string]any{"max": model.MaxSyncPushItems}))
+	}
+	if len(fields) > 0 {
+		return nil, nil, apperror.Validation(fields...)
+	}
+
+	return changes, indexes, nil
+}
+
+// newSyncTransaction переводит проверенную транзакцию клиента в модель
+func newSyncTransaction(req *dto.SyncTransactionRequest) *model.Transaction {
+	// Дата уже проверена валидатором
+	date, _ := time.Parse(time.RFC3339, req.Date)
+
+	return &model.Transaction{
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		Date:        date,
+		PlaceName:   req.PlaceName,
+		PlaceLat:    req.PlaceLat,
+		PlaceLon:    req.PlaceLon,
+		CategoryID:  req.CategoryID,
+		IsConfirmed: req.IsConfirmed,
+	}
+}
+
+func toSyncRuleResponses(rules []*model.UserCategoryRule) []*dto.SyncRuleResponse {
+	responses := make([]*dto.SyncRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = &dto.SyncRuleResponse{
+			ID:          rule.ID,
+			WorkspaceID: rule.WorkspaceID,
+			Keyword:     rule.Keyword,
+			CategoryID:  rule.CategoryID,
+			CreatedAt:   rule.CreatedAt,
+		}
+	}
+	return responses
+}
+
+func toCategoryResponses(categories []*model.Category) []dto.CategoryResponse {
+	responses := make([]dto.CategoryResponse, len(categories))
+	for i, cat := range categories {
+		responses[i] = toCategoryResponse(cat)
+	}
+	return responses
+}
+
+// nonNil возвращает пустой слайс вместо nil, чтобы в JSON был [] а не null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// TagHandler обрабатывает HTTP запросы для меток
+type TagHandler struct {
+	tagService service.TagService
+}
+
+// NewTagHandler создаёт новый TagHandler
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// GetAll
+// @Summary Получить метки
+// @Description Получение всех меток пользователя
+// @Tags tags
+// @Produce json
+// @Success 200 {array} dto.TagResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/tags [get]
+func (h *TagHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	tags, err := h.tagService.GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTagResponses(tags))
+}
+
+// GetByID
+// @Summary Получить метку по ID
+// @Tags tags
+// @Produce json
+// @Param id path string true "ID метки"
+// @Success 200 {object} dto.TagResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/tags/{id} [get]
+func (h *TagHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	tag, err := h.tagService.GetByID(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTagResponse(tag))
+}
+
+// Create
+// @Summary Создать метку
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTagRequest true "Данные метки"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 409 {object} apperror.Problem "Метка уже существует"
+// @Router /api/v1/tags [post]
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.CreateTagRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tag := &model.Tag{
+		Name:  strings.TrimSpace(req.Name),
+		Color: req.Color,
+	}
+
+	created, err := h.tagService.Create(r.Context(), userID, tag)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toTagResponse(created))
+}
+
+// Update
+// @Summary Обновить метку
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "ID метки"
+// @Param request body dto.UpdateTagRequest true "Данные метки"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Метка уже существует"
+// @Router /api/v1/tags/{id} [put]
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tag := &model.Tag{
+		ID:    chi.URLParam(r, "id"),
+		Name:  strings.TrimSpace(req.Name),
+		Color: req.Color,
+	}
+
+	updated, err := h.tagService.Update(r.Context(), userID, tag)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTagResponse(updated))
+}
+
+// Delete
+// @Summary Удалить метку
+// @Description Удаление метки; с транзакций она снимается автоматически
+// @Tags tags
+// @Produce json
+// @Param id path string true "ID метки"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/tags/{id} [delete]
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.tagService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "tag_deleted")})
+}
+
+// Bulk
+// @Summary Массово поставить или снять метки
+// @Description Каждая метка из tag_ids ставится (tag) или снимается (untag) с каждой транзакции из transaction_ids.
+// @Description Транзакции, которые пользователь не может изменять, и несуществующие пропускаются и перечисляются в skipped
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body dto.TagBulkRequest true "Метки и транзакции"
+// @Success 200 {object} dto.TagBulkResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/tags/bulk [post]
+func (h *TagHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.TagBulkRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.tagService.Apply(r.Context(), userID, model.TagAction(req.Action), req.TagIDs, req.TransactionIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	skipped := result.Skipped
+	if skipped == nil {
+		skipped = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.TagBulkResponse{Affected: result.Affected, Skipped: skipped})
+}
+
+// SetTransactionTags
+// @Summary Заменить метки транзакции
+// @Description Заменяются только метки текущего пользователя, метки других участников пространства остаются
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param request body dto.SetTransactionTagsRequest true "Метки"
+// @Success 200 {array} dto.TagResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/transactions/{id}/tags [put]
+func (h *TagHandler) SetTransactionTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	var req dto.SetTransactionTagsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tags, err := h.tagService.SetTransactionTags(r.Context(), userID, id, req.TagIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTagResponses(tags))
+}
+
+// parseTagFilter проверяет и очищает от повторов ID меток из параметра tag_id
+func parseTagFilter(values []string) ([]string, error) {
+	seen := make(map[string]bool, len(values))
+	var ids []string
+	for _, value := range values {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return nil, apperror.Validation(apperror.Field("tag_id", "invalid_format"))
+		}
+		id := parsed.String()
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func toTagResponse(tag *model.Tag) *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:    tag.ID,
+		Name:  tag.Name,
+		Color: tag.Color,
+	}
+}
+
+func toTagResponses(tags []*model.Tag) []*dto.TagResponse {
+	responses := make([]*dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = toTagResponse(tag)
+	}
+	return responses
+}
@@ -1,16 +1,18 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
-	"errors"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
+	"github.com/gibbon/finace-dashboard/internal/apperror"
 	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
 	"github.com/gibbon/finace-dashboard/internal/middleware"
 	"github.com/gibbon/finace-dashboard/internal/service"
@@ -36,56 +38,30 @@ func NewTransactionHandler(txService service.TransactionService) *TransactionHan
 // @Produce json
 // @Param request body dto.CreateTransactionRequest true "Данные транзакции"
 // @Success 201 {object} dto.TransactionResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 401 {object} map[string]string "Неавторизован"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
 // @Router /api/v1/transactions [post]
 func (h *TransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	var req dto.CreateTransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	// Валидация
-	if req.Amount <= 0 {
-		http.Error(w, `{"error": "amount must be positive"}`, http.StatusBadRequest)
-		return
-	}
-
-	if req.Description == "" {
-		http.Error(w, `{"error": "description is required"}`, http.StatusBadRequest)
-		return
-	}
-
-	date, err := time.Parse(time.RFC3339, req.Date)
-	if err != nil {
-		http.Error(w, `{"error": "invalid date format, use RFC3339"}`, http.StatusBadRequest)
-		return
-	}
-
-	tx := &model.Transaction{
-		Amount:      req.Amount,
-		Currency:    req.Currency,
-		Description: req.Description,
-		Date:        date,
-		PlaceName:   req.PlaceName,
-		PlaceLat:    req.PlaceLat,
-		PlaceLon:    req.PlaceLon,
-	}
-
-	created, err := h.txService.Create(r.Context(), userID, tx)
+	created, err := h.txService.Create(r.Context(), userID, newTransaction(&req))
 	if err != nil {
-		http.Error(w, `{"error": "failed to create transaction"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	response := h.toTransactionResponse(created)
+	response := toTransactionResponse(created)
+	setETag(w, created)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
@@ -97,38 +73,40 @@ func (h *TransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags transactions
 // @Produce json
 // @Param id path string true "ID транзакции"
+// @Param If-None-Match header string false "ETag закэшированной версии"
 // @Success 200 {object} dto.TransactionResponse
-// @Failure 401 {object} map[string]string "Неавторизован"
-// @Failure 404 {object} map[string]string "Не найдено"
+// @Success 304 "Не изменилась"
+// @Header 200 {string} ETag "Версия транзакции"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
 // @Router /api/v1/transactions/{id} [get]
 func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, `{"error": "id is required"}`, http.StatusBadRequest)
+		writeError(w, r, errIDRequired)
 		return
 	}
 
 	tx, err := h.txService.GetByID(r.Context(), userID, id)
 	if err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			http.Error(w, `{"error": "transaction not found"}`, http.StatusNotFound)
-			return
-		}
-		if errors.Is(err, service.ErrUnauthorized) {
-			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
-			return
-		}
-		http.Error(w, `{"error": "failed to get transaction"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	response := h.toTransactionResponse(tx)
+	setETag(w, tx)
+	if inm := r.Header.Get("If-None-Match"); inm != "" && weakETagMatches(inm, transactionETag(tx)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response := toTransactionResponse(tx)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -139,17 +117,25 @@ func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Tags transactions
 // @Produce json
 // @Param category_id query int false "ID категории"
+// @Param tag_id query []string false "ID метки; при нескольких значениях нужны все метки" collectionFormat(multi)
+// @Param merchant_id query string false "ID продавца"
+// @Param workspace_id query string false "ID рабочего пространства; без него возвращаются личные транзакции"
 // @Param from_date query string false "Дата от (RFC3339)"
 // @Param to_date query string false "Дата до (RFC3339)"
+// @Param bbox query string false "Область min_lon,min_lat,max_lon,max_lat"
+// @Param lat query number false "Широта центра поиска рядом"
+// @Param lon query number false "Долгота центра поиска рядом"
+// @Param radius query number false "Радиус поиска в метрах" default(1000)
 // @Param limit query int false "Лимит" default(20)
 // @Param offset query int false "Смещение" default(0)
 // @Success 200 {object} dto.TransactionsListResponse
-// @Failure 401 {object} map[string]string "Неавторизован"
+// @Failure 400 {object} apperror.Problem "Ошибка валидации"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
 // @Router /api/v1/transactions [get]
 func (h *TransactionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
@@ -166,6 +152,37 @@ func (h *TransactionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if tagIDs := r.URL.Query()["tag_id"]; len(tagIDs) > 0 {
+		ids, err := parseTagFilter(tagIDs)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		filter.TagIDs = ids
+	}
+
+	if merchantID := r.URL.Query().Get("merchant_id"); merchantID != "" {
+		parsed, err := uuid.Parse(merchantID)
+		if err != nil {
+			writeError(w, r, apperror.Validation(apperror.Field("merchant_id", "invalid_format")))
+			return
+		}
+		id := parsed.String()
+		filter.MerchantID = &id
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.WorkspaceID = workspaceID
+
+	if err := parseGeoFilter(r, &filter); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
 	if fromDate := r.URL.Query().Get("from_date"); fromDate != "" {
 		if date, err := time.Parse(time.RFC3339, fromDate); err == nil {
 			filter.FromDate = &date
@@ -192,17 +209,18 @@ func (h *TransactionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 	transactions, err := h.txService.GetByUserID(r.Context(), filter)
 	if err != nil {
-		http.Error(w, `{"error": "failed to get transactions"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	total, err := h.txService.(interface{ GetTotalCount(context.Context, string) (int64, error) }).GetTotalCount(r.Context(), userID)
+	total, err := h.txService.GetTotalCount(r.Context(), filter)
 	if err != nil {
-		total = int64(len(transactions))
+		writeError(w, r, err)
+		return
 	}
 
 	response := dto.TransactionsListResponse{
-		Transactions: h.toTransactionResponses(transactions),
+		Transactions: toTransactionResponses(transactions),
 		Total:        total,
 		Limit:        filter.Limit,
 		Offset:       filter.Offset,
@@ -214,42 +232,134 @@ func (h *TransactionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 // Update
 // @Summary Обновить транзакцию
-// @Description Обновление данных транзакции
+// @Description Полная замена данных транзакции. Если передан If-Match, изменение применяется только к указанной версии
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Param id path string true "ID транзакции"
+// @Param If-Match header string false "ETag версии, которую изменяет клиент"
 // @Param request body dto.UpdateTransactionRequest true "Данные транзакции"
 // @Success 200 {object} dto.TransactionResponse
-// @Failure 400 {object} map[string]string "Некорректные данные"
-// @Failure 401 {object} map[string]string "Неавторизован"
-// @Failure 404 {object} map[string]string "Не найдено"
+// @Header 200 {string} ETag "Новая версия транзакции"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 412 {object} apperror.Problem "Транзакция изменена другим клиентом"
 // @Router /api/v1/transactions/{id} [put]
 func (h *TransactionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, `{"error": "id is required"}`, http.StatusBadRequest)
+		writeError(w, r, errIDRequired)
 		return
 	}
 
 	var req dto.UpdateTransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// If-Match необязателен для PUT, но если передан, фиксирует версию
+	var expected *time.Time
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		current, err := h.txService.GetByID(r.Context(), userID, id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if !etagMatches(ifMatch, transactionETag(current)) {
+			writeError(w, r, service.ErrPreconditionFailed)
+			return
+		}
+		expected = &current.UpdatedAt
+	}
+
+	h.saveUpdate(w, r, userID, id, &req, expected)
+}
+
+// Patch
+// @Summary Частично обновить транзакцию
+// @Description Частичное обновление по JSON Merge Patch (RFC 7396): отсутствующие поля не меняются, null удаляет необязательное значение.
+// @Description Заголовок If-Match обязателен: при несовпадении версии возвращается 412, и клиент должен перечитать транзакцию
+// @Tags transactions
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Param If-Match header string true "ETag версии, которую изменяет клиент"
+// @Param request body dto.PatchTransactionRequest true "Изменяемые поля"
+// @Success 200 {object} dto.TransactionResponse
+// @Header 200 {string} ETag "Новая версия транзакции"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 412 {object} apperror.Problem "Транзакция изменена другим клиентом"
+// @Failure 415 {object} apperror.Problem "Неподдерживаемый Content-Type"
+// @Failure 428 {object} apperror.Problem "Не передан If-Match"
+// @Router /api/v1/transactions/{id} [patch]
+func (h *TransactionHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
 		return
 	}
 
-	date, err := time.Parse(time.RFC3339, req.Date)
+	if !isMergePatch(r) {
+		writeError(w, r, errUnsupportedMedia)
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		writeError(w, r, errIfMatchRequired)
+		return
+	}
+
+	var patch dto.PatchTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, r, errInvalidBody)
+		return
+	}
+
+	current, err := h.txService.GetByID(r.Context(), userID, id)
 	if err != nil {
-		http.Error(w, `{"error": "invalid date format, use RFC3339"}`, http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+	if !etagMatches(ifMatch, transactionETag(current)) {
+		writeError(w, r, service.ErrPreconditionFailed)
 		return
 	}
 
+	// Патч накладывается на текущее состояние, результат проверяется как полный PUT
+	req := toUpdateRequest(current)
+	patch.ApplyTo(req)
+	if err := validateRequest(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.saveUpdate(w, r, userID, id, req, &current.UpdatedAt)
+}
+
+// saveUpdate сохраняет полное представление транзакции и отдаёт новую версию
+func (h *TransactionHandler) saveUpdate(w http.ResponseWriter, r *http.Request, userID, id string, req *dto.UpdateTransactionRequest, expected *time.Time) {
+	// Дата уже проверена валидатором
+	date, _ := time.Parse(time.RFC3339, req.Date)
+
 	tx := &model.Transaction{
 		ID:          id,
 		Amount:      req.Amount,
@@ -263,66 +373,206 @@ func (h *TransactionHandler) Update(w http.ResponseWriter, r *http.Request) {
 		IsConfirmed: req.IsConfirmed,
 	}
 
-	updated, err := h.txService.Update(r.Context(), userID, tx)
+	updated, err := h.txService.Update(r.Context(), userID, tx, expected)
 	if err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			http.Error(w, `{"error": "transaction not found"}`, http.StatusNotFound)
-			return
+		writeError(w, r, err)
+		return
+	}
+
+	response := toTransactionResponse(updated)
+	setETag(w, updated)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Bulk
+// @Summary Массовые операции с транзакциями
+// @Description Создание, смена категории, подтверждение и удаление многих транзакций одним запросом.
+// @Description Все элементы, прошедшие проверку владельца, применяются в одной транзакции БД; результат возвращается по каждому элементу
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkRequest true "Операции"
+// @Success 200 {object} dto.BulkResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/transactions/bulk [post]
+func (h *TransactionHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.BulkRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ops, err := toBulkOperations(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	results, err := h.txService.Bulk(r.Context(), userID, ops)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := &dto.BulkResponse{Results: make([]*dto.BulkItemResponse, 0, len(results))}
+	for _, result := range results {
+		item := &dto.BulkItemResponse{
+			Operation: result.Operation,
+			Op:        string(result.Type),
+			Index:     result.Index,
+			ID:        result.ID,
+			Status:    "ok",
 		}
-		if errors.Is(err, service.ErrUnauthorized) {
-			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
-			return
+		if result.Err != nil {
+			code := apperror.From(result.Err).Code
+			item.Status = "error"
+			item.Error = &dto.BulkItemError{Code: code, Detail: i18n.T(r.Context(), code)}
+			response.Failed++
+		} else {
+			response.Succeeded++
 		}
-		http.Error(w, `{"error": "failed to update transaction"}`, http.StatusInternalServerError)
-		return
+		response.Results = append(response.Results, item)
 	}
 
-	response := h.toTransactionResponse(updated)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// toBulkOperations проверяет согласованность операций и переводит их в модель
+func toBulkOperations(req *dto.BulkRequest) ([]*model.BulkOperation, error) {
+	var (
+		fields []apperror.FieldError
+		total  int
+	)
+
+	ops := make([]*model.BulkOperation, len(req.Operations))
+	for i, opReq := range req.Operations {
+		prefix := "operations[" + strconv.Itoa(i) + "]."
+		op := &model.BulkOperation{
+			Type:       model.BulkOperationType(opReq.Op),
+			IDs:        opReq.IDs,
+			CategoryID: opReq.CategoryID,
+		}
+
+		switch op.Type {
+		case model.BulkCreate:
+			if len(opReq.Transactions) == 0 {
+				fields = append(fields, apperror.Field(prefix+"transactions", "required"))
+			}
+			for j := range opReq.Transactions {
+				op.Transactions = append(op.Transactions, newTransaction(&opReq.Transactions[j]))
+			}
+			total += len(opReq.Transactions)
+		default:
+			if len(opReq.IDs) == 0 {
+				fields = append(fields, apperror.Field(prefix+"ids", "required"))
+			}
+			if op.Type == model.BulkUpdateCategory && opReq.CategoryID == nil {
+				fields = append(fields, apperror.Field(prefix+"category_id", "required"))
+			}
+			total += len(opReq.IDs)
+		}
+		ops[i] = op
+	}
+
+	if total > model.MaxBulkItems {
+		fields = append(fields, apperror.FieldWithParams("operations", "too_many", map[string]any{"max": model.MaxBulkItems}))
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields...)
+	}
+
+	return ops, nil
+}
+
+// newTransaction переводит проверенный запрос на создание в модель
+func newTransaction(req *dto.CreateTransactionRequest) *model.Transaction {
+	// Дата уже проверена валидатором
+	date, _ := time.Parse(time.RFC3339, req.Date)
+
+	currency := req.Currency
+	if currency == "" {
+		currency = string(model.CurrencyRUB)
+	}
+
+	return &model.Transaction{
+		Amount:      req.Amount,
+		Currency:    currency,
+		Description: req.Description,
+		Date:        date,
+		PlaceName:   req.PlaceName,
+		PlaceLat:    req.PlaceLat,
+		PlaceLon:    req.PlaceLon,
+		WorkspaceID: req.WorkspaceID,
+	}
+}
+
+// isMergePatch проверяет Content-Type запроса PATCH
+func isMergePatch(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/merge-patch+json" || mediaType == "application/json"
+}
+
+// toUpdateRequest переводит транзакцию в полное представление для PUT
+func toUpdateRequest(tx *model.Transaction) *dto.UpdateTransactionRequest {
+	return &dto.UpdateTransactionRequest{
+		Amount:      tx.Amount,
+		Currency:    tx.Currency,
+		Description: tx.Description,
+		Date:        tx.Date.Format(time.RFC3339Nano),
+		PlaceName:   tx.PlaceName,
+		PlaceLat:    tx.PlaceLat,
+		PlaceLon:    tx.PlaceLon,
+		CategoryID:  tx.CategoryID,
+		IsConfirmed: tx.IsConfirmed,
+	}
+}
+
 // Delete
 // @Summary Удалить транзакцию
-// @Description Удаление транзакции по идентификатору
+// @Description Перемещает транзакцию в корзину. Её можно восстановить, пока не истёк срок хранения корзины
 // @Tags transactions
 // @Produce json
 // @Param id path string true "ID транзакции"
 // @Success 200 {object} map[string]string "Успешно"
-// @Failure 401 {object} map[string]string "Неавторизован"
-// @Failure 404 {object} map[string]string "Не найдено"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
 // @Router /api/v1/transactions/{id} [delete]
 func (h *TransactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, errUnauthenticated)
 		return
 	}
 
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		http.Error(w, `{"error": "id is required"}`, http.StatusBadRequest)
+		writeError(w, r, errIDRequired)
 		return
 	}
 
 	if err := h.txService.Delete(r.Context(), userID, id); err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			http.Error(w, `{"error": "transaction not found"}`, http.StatusNotFound)
-			return
-		}
-		if errors.Is(err, service.ErrUnauthorized) {
-			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
-			return
-		}
-		http.Error(w, `{"error": "failed to delete transaction"}`, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "transaction deleted successfully"})
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "transaction_deleted")})
 }
 
-func (h *TransactionHandler) toTransactionResponse(tx *model.Transaction) *dto.TransactionResponse {
+func toTransactionResponse(tx *model.Transaction) *dto.TransactionResponse {
 	response := &dto.TransactionResponse{
 		ID:          tx.ID,
 		Amount:      tx.Amount,
@@ -333,17 +583,20 @@ func (h *TransactionHandler) toTransactionResponse(tx *model.Transaction) *dto.T
 		PlaceLat:    tx.PlaceLat,
 		PlaceLon:    tx.PlaceLon,
 		CategoryID:  tx.CategoryID,
+		MerchantID:  tx.MerchantID,
+		WorkspaceID: tx.WorkspaceID,
 		IsConfirmed: tx.IsConfirmed,
+		Tags:        toTagResponses(tx.Tags),
 		CreatedAt:   tx.CreatedAt,
 		UpdatedAt:   tx.UpdatedAt,
 	}
 	return response
 }
 
-func (h *TransactionHandler) toTransactionResponses(txs []*model.Transaction) []*dto.TransactionResponse {
+func toTransactionResponses(txs []*model.Transaction) []*dto.TransactionResponse {
 	responses := make([]*dto.TransactionResponse, len(txs))
 	for i, tx := range txs {
-		responses[i] = h.toTransactionResponse(tx)
+		responses[i] = toTransactionResponse(tx)
 	}
 	return responses
 }
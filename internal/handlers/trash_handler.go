@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// TrashHandler обрабатывает HTTP запросы корзины удалённых транзакций
+type TrashHandler struct {
+	trashService service.TrashService
+}
+
+// NewTrashHandler создаёт новый TrashHandler
+func NewTrashHandler(trashService service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetAll
+// @Summary Корзина транзакций
+// @Description Удалённые транзакции, последние удалённые первыми. После purge_at транзакция удаляется окончательно
+// @Tags transactions
+// @Produce json
+// @Param workspace_id query string false "ID рабочего пространства; без него возвращаются личные транзакции"
+// @Param limit query int false "Лимит" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} dto.TrashListResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа к рабочему пространству"
+// @Router /api/v1/transactions/trash [get]
+func (h *TrashHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaceID, err := parseWorkspaceID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	limit, offset := 20, 0
+
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	items, total, err := h.trashService.GetAll(r.Context(), userID, workspaceID, limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := dto.TrashListResponse{
+		Items:  make([]*dto.TrashItemResponse, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for i, item := range items {
+		response.Items[i] = &dto.TrashItemResponse{
+			Transaction: toTransactionResponse(item.Transaction),
+			DeletedAt:   *item.DeletedAt,
+			PurgeAt:     item.PurgeAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Restore
+// @Summary Восстановить транзакцию из корзины
+// @Description Возвращает удалённую транзакцию вместе с разбивкой, метками и вложениями
+// @Tags transactions
+// @Produce json
+// @Param id path string true "ID транзакции"
+// @Success 200 {object} dto.TransactionResponse
+// @Header 200 {string} ETag "Версия транзакции"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено или не в корзине"
+// @Router /api/v1/transactions/{id}/restore [post]
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, r, errIDRequired)
+		return
+	}
+
+	tx, err := h.trashService.Restore(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, tx)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toTransactionResponse(tx))
+}
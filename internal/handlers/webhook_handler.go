@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// WebhookHandler обрабатывает HTTP запросы для исходящих вебхуков
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler создаёт новый WebhookHandler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetAll
+// @Summary Список вебхуков
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} dto.WebhookResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	hooks, err := h.webhookService.GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.WebhookResponse, len(hooks))
+	for i, hook := range hooks {
+		response[i] = toWebhookResponse(hook)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Create
+// @Summary Создать вебхук
+// @Description На адрес отправляются POST-запросы с событиями транзакций. Тело подписывается HMAC-SHA256
+// @Description ключом из ответа: заголовок X-Webhook-Signature содержит sha256=<hex>. Ключ показывается только один раз
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body dto.WebhookRequest true "Адрес и события: transaction.created, transaction.updated, transaction.deleted"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hook, err := h.webhookService.Create(r.Context(), userID, toWebhookModel(&req))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := toWebhookResponse(hook)
+	response.Secret = hook.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Update
+// @Summary Изменить вебхук
+// @Description Заменяет адрес, события и активность. Ключ подписи не меняется
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Param request body dto.WebhookRequest true "Адрес и события"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hook := toWebhookModel(&req)
+	hook.ID = chi.URLParam(r, "id")
+	updated, err := h.webhookService.Update(r.Context(), userID, hook)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWebhookResponse(updated))
+}
+
+// Delete
+// @Summary Удалить вебхук
+// @Description Удаляет вебхук вместе с журналом доставок
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "webhook_deleted")})
+}
+
+// GetDeliveries
+// @Summary Журнал доставок вебхука
+// @Description Попытки доставки событий, новые сначала. Журнал хранится 30 дней
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Param status query string false "Состояние: pending, succeeded, failed"
+// @Param limit query int false "Лимит" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	filter := model.WebhookDeliveryFilter{
+		WebhookID: chi.URLParam(r, "id"),
+		Limit:     20,
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := model.WebhookDeliveryStatus(status)
+		if !s.IsValid() {
+			writeError(w, r, apperror.Validation(apperror.FieldWithParams("status", "not_allowed",
+				map[string]any{"allowed": "pending, succeeded, failed"})))
+			return
+		}
+		filter.Status = &s
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(r.Context(), userID, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		response[i] = toWebhookDeliveryResponse(d)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Redeliver
+// @Summary Повторить доставку
+// @Description Ставит в очередь новую доставку с тем же телом и ID события. Ожидающую доставку повторить нельзя
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "ID вебхука"
+// @Param deliveryID path string true "ID доставки"
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Доставка ещё выполняется"
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), userID, chi.URLParam(r, "id"), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(toWebhookDeliveryResponse(delivery))
+}
+
+func toWebhookModel(req *dto.WebhookRequest) *model.Webhook {
+	hook := &model.Webhook{
+		URL:    req.URL,
+		Active: req.Active == nil || *req.Active,
+		Events: make([]model.WebhookEvent, len(req.Events)),
+	}
+	for i, event := range req.Events {
+		hook.Events[i] = model.WebhookEvent(event)
+	}
+	return hook
+}
+
+func toWebhookResponse(hook *model.Webhook) *dto.WebhookResponse {
+	events := make([]string, len(hook.Events))
+	for i, event := range hook.Events {
+		events[i] = string(event)
+	}
+	return &dto.WebhookResponse{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    events,
+		Active:    hook.Active,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(d *model.WebhookDelivery) *dto.WebhookDeliveryResponse {
+	response := &dto.WebhookDeliveryResponse{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		Event:          string(d.Event),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		ResponseBody:   d.ResponseBody,
+		DurationMs:     d.DurationMs,
+		LastError:      d.LastError,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	// Срок следующей попытки имеет смысл только для ожидающей доставки
+	if d.Status == model.WebhookDeliveryPending {
+		response.NextAttemptAt = &d.NextAttemptAt
+	}
+	return response
+}
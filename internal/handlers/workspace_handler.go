@@ -0,0 +1,463 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/dto"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/internal/middleware"
+	"github.com/gibbon/finace-dashboard/internal/service"
+)
+
+// WorkspaceHandler обрабатывает HTTP запросы для рабочих пространств
+type WorkspaceHandler struct {
+	workspaceService service.WorkspaceService
+}
+
+// NewWorkspaceHandler создаёт новый WorkspaceHandler
+func NewWorkspaceHandler(workspaceService service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: workspaceService,
+	}
+}
+
+// GetAll
+// @Summary Список рабочих пространств
+// @Description Пространства, в которых состоит пользователь, с его ролью
+// @Tags workspaces
+// @Produce json
+// @Success 200 {array} dto.WorkspaceResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/workspaces [get]
+func (h *WorkspaceHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspaces, err := h.workspaceService.GetAll(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.WorkspaceResponse, len(workspaces))
+	for i, workspace := range workspaces {
+		response[i] = toWorkspaceResponse(workspace)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Create
+// @Summary Создать рабочее пространство
+// @Description Создатель становится владельцем пространства
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param request body dto.WorkspaceRequest true "Название пространства"
+// @Success 201 {object} dto.WorkspaceResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Router /api/v1/workspaces [post]
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.WorkspaceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.Create(r.Context(), userID, &model.Workspace{Name: req.Name})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace))
+}
+
+// GetByID
+// @Summary Получить рабочее пространство
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id} [get]
+func (h *WorkspaceHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	workspace, err := h.workspaceService.GetByID(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace))
+}
+
+// Update
+// @Summary Переименовать рабочее пространство
+// @Description Доступно владельцу пространства
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Param request body dto.WorkspaceRequest true "Новое название"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id} [put]
+func (h *WorkspaceHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.WorkspaceRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	workspace := &model.Workspace{ID: chi.URLParam(r, "id"), Name: req.Name}
+	updated, err := h.workspaceService.Update(r.Context(), userID, workspace)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceResponse(updated))
+}
+
+// Delete
+// @Summary Удалить рабочее пространство
+// @Description Доступно владельцу. Транзакции пространства становятся личными транзакциями их авторов, правила удаляются
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id} [delete]
+func (h *WorkspaceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.workspaceService.Delete(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "workspace_deleted")})
+}
+
+// GetMembers
+// @Summary Участники рабочего пространства
+// @Description Владелец первым
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Success 200 {array} dto.WorkspaceMemberResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id}/members [get]
+func (h *WorkspaceHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	members, err := h.workspaceService.GetMembers(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = &dto.WorkspaceMemberResponse{
+			UserID:    member.UserID,
+			Email:     member.Email,
+			Role:      string(member.Role),
+			CreatedAt: member.CreatedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateMember
+// @Summary Изменить роль участника
+// @Description Доступно владельцу. Роль владельца не меняется
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Param userID path string true "ID участника"
+// @Param request body dto.UpdateWorkspaceMemberRequest true "Роль: editor или viewer"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Участник — владелец пространства"
+// @Router /api/v1/workspaces/{id}/members/{userID} [put]
+func (h *WorkspaceHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.UpdateWorkspaceMemberRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := h.workspaceService.UpdateMember(r.Context(), userID, chi.URLParam(r, "id"), chi.URLParam(r, "userID"), model.WorkspaceRole(req.Role))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "workspace_member_updated")})
+}
+
+// RemoveMember
+// @Summary Исключить участника
+// @Description Владелец исключает участников, участник может выйти сам. Владелец выйти не может
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Param userID path string true "ID участника"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Участник — владелец пространства"
+// @Router /api/v1/workspaces/{id}/members/{userID} [delete]
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(r.Context(), userID, chi.URLParam(r, "id"), chi.URLParam(r, "userID")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "workspace_member_removed")})
+}
+
+// Invite
+// @Summary Пригласить в рабочее пространство
+// @Description Доступно владельцу. Приглашение действует 7 дней, принять его может пользователь с указанным email.
+// @Description Токен показывается только один раз и отправляется на email, если на сервере настроена почта
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Param request body dto.WorkspaceInvitationRequest true "Email и роль: editor или viewer"
+// @Success 201 {object} dto.WorkspaceInvitationResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id}/invitations [post]
+func (h *WorkspaceHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.WorkspaceInvitationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	invitation, token, err := h.workspaceService.Invite(r.Context(), userID, chi.URLParam(r, "id"), req.Email, model.WorkspaceRole(req.Role))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := toWorkspaceInvitationResponse(invitation)
+	response.Token = token
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetInvitations
+// @Summary Приглашения рабочего пространства
+// @Description Непринятые приглашения, последние первыми. Доступно владельцу
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Success 200 {array} dto.WorkspaceInvitationResponse
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id}/invitations [get]
+func (h *WorkspaceHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	invitations, err := h.workspaceService.GetInvitations(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := make([]*dto.WorkspaceInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = toWorkspaceInvitationResponse(invitation)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RevokeInvitation
+// @Summary Отозвать приглашение
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "ID пространства"
+// @Param invitationID path string true "ID приглашения"
+// @Success 200 {object} map[string]string "Успешно"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Нет доступа"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Router /api/v1/workspaces/{id}/invitations/{invitationID} [delete]
+func (h *WorkspaceHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.workspaceService.RevokeInvitation(r.Context(), userID, chi.URLParam(r, "id"), chi.URLParam(r, "invitationID")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": i18n.T(r.Context(), "workspace_invitation_revoked")})
+}
+
+// AcceptInvitation
+// @Summary Принять приглашение
+// @Description Пользователь, чей email совпадает с адресом приглашения, становится участником пространства
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptWorkspaceInvitationRequest true "Токен приглашения"
+// @Success 200 {object} dto.WorkspaceResponse
+// @Failure 400 {object} apperror.Problem "Некорректные данные"
+// @Failure 401 {object} apperror.Problem "Неавторизован"
+// @Failure 403 {object} apperror.Problem "Приглашение выдано на другой email"
+// @Failure 404 {object} apperror.Problem "Не найдено"
+// @Failure 409 {object} apperror.Problem "Приглашение истекло или пользователь уже участник"
+// @Router /api/v1/workspaces/invitations/accept [post]
+func (h *WorkspaceHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	var req dto.AcceptWorkspaceInvitationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	workspace, err := h.workspaceService.AcceptInvitation(r.Context(), userID, req.Token)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toWorkspaceResponse(workspace))
+}
+
+// parseWorkspaceID читает необязательный параметр workspace_id. Без него запрос относится к личным данным
+func parseWorkspaceID(r *http.Request) (*string, error) {
+	value := r.URL.Query().Get("workspace_id")
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil, apperror.Validation(apperror.Field("workspace_id", "invalid_format"))
+	}
+	id := parsed.String()
+	return &id, nil
+}
+
+func toWorkspaceResponse(workspace *model.Workspace) *dto.WorkspaceResponse {
+	return &dto.WorkspaceResponse{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		Role:      string(workspace.Role),
+		CreatedAt: workspace.CreatedAt,
+		UpdatedAt: workspace.UpdatedAt,
+	}
+}
+
+func toWorkspaceInvitationResponse(invitation *model.WorkspaceInvitation) *dto.WorkspaceInvitationResponse {
+	return &dto.WorkspaceInvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Catalog хранит сообщения по локалям: locale -> key -> message
+type Catalog map[string]map[string]string
+
+// Message возвращает сообщение для локали.
+// Если перевода нет, используется английский вариант, а затем сам ключ
+func (c Catalog) Message(locale, key string) string {
+	if msg, ok := c[locale][key]; ok {
+		return msg
+	}
+	if msg, ok := c[LocaleEN][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// T возвращает сообщение из каталога приложения для локали из контекста
+func T(ctx context.Context, key string) string {
+	return messages.Message(LocaleFromContext(ctx), key)
+}
+
+// Tf возвращает сообщение с подставленными параметрами вида {name}
+func Tf(ctx context.Context, key string, params map[string]any) string {
+	message := T(ctx, key)
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", fmt.Sprint(value))
+	}
+	return message
+}
@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые локали
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+
+	// DefaultLocale используется, если клиент не указал поддерживаемый язык
+	DefaultLocale = LocaleRU
+)
+
+var supportedLocales = map[string]bool{
+	LocaleRU: true,
+	LocaleEN: true,
+}
+
+type contextKey struct{}
+
+// WithLocale сохраняет локаль в контексте
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// LocaleFromContext извлекает локаль из контекста
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Negotiate выбирает локаль по заголовку Accept-Language с учётом q-весов
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		tag := part
+		q := 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = strings.TrimSpace(part[:i])
+			for _, param := range strings.Split(part[i+1:], ";") {
+				param = strings.TrimSpace(param)
+				if strings.HasPrefix(param, "q=") {
+					if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+						q = v
+					}
+				}
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: strings.ToLower(tag), q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return DefaultLocale
+		}
+		primary := c.tag
+		if i := strings.IndexAny(primary, "-_"); i >= 0 {
+			primary = primary[:i]
+		}
+		if supportedLocales[primary] {
+			return primary
+		}
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", DefaultLocale},
+		{"en", LocaleEN},
+		{"en-US,en;q=0.9", LocaleEN},
+		{"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", LocaleRU},
+		{"de-DE,de;q=0.9,en;q=0.5", LocaleEN},
+		{"ru;q=0.3,en;q=0.8", LocaleEN},
+		{"en;q=0,ru", LocaleRU},
+		{"fr", DefaultLocale},
+		{"*", DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %s, expected %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	ctx := WithLocale(context.Background(), LocaleEN)
+	if msg := T(ctx, "transaction_not_found"); msg != "transaction not found" {
+		t.Errorf("Expected english message, got %s", msg)
+	}
+
+	ctx = WithLocale(context.Background(), LocaleRU)
+	if msg := T(ctx, "transaction_not_found"); msg != "транзакция не найдена" {
+		t.Errorf("Expected russian message, got %s", msg)
+	}
+
+	if msg := T(ctx, "unknown_key"); msg != "unknown_key" {
+		t.Errorf("Expected key as fallback, got %s", msg)
+	}
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range messages[LocaleEN] {
+		if _, ok := messages[LocaleRU][key]; !ok {
+			t.Errorf("Missing russian translation for %s", key)
+		}
+	}
+	for key := range messages[LocaleRU] {
+		if _, ok := messages[LocaleEN][key]; !ok {
+			t.Errorf("Missing english translation for %s", key)
+		}
+	}
+}
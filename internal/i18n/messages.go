@@ -0,0 +1,294 @@
+package i18n
+
+// messages каталог сообщений API.
+// Ключи совпадают с кодами ошибок apperror, ключи validation.* описывают ошибки полей
+var messages = Catalog{
+	LocaleEN: {
+		// Общие
+		"internal_error":       "internal server error",
+		"not_implemented":      "not implemented",
+		"invalid_request_body": "invalid request body",
+		"id_required":          "id is required",
+		"validation_failed":    "request validation failed",
+		"forbidden":            "access denied",
+
+		// Аутентификация
+		"unauthorized":                 "unauthorized",
+		"missing_authorization_header": "missing authorization header",
+		"invalid_authorization_format": "invalid authorization format",
+		"invalid_token":                "invalid or expired token",
+		"invalid_refresh_token":        "invalid refresh token",
+		"invalid_credentials":          "invalid credentials",
+		"user_already_exists":          "user already exists",
+		"logged_out":                   "logged out successfully",
+
+		// Транзакции
+		"transaction_not_found":          "transaction not found",
+		"transaction_deleted":            "transaction moved to trash",
+		"transaction_not_in_trash":       "transaction is not in trash",
+		"transaction_revision_not_found": "transaction revision not found",
+		"no_category_suggestion":         "no category to accept, specify category_id",
+		"splits_already_exist":           "transaction is already split, use PUT to replace lines",
+		"transaction_has_splits":         "transaction is split, update split lines to change the amount",
+		"splits_deleted":                 "split lines deleted successfully",
+
+		// Условные запросы
+		"precondition_failed":    "resource was modified, reload it and retry",
+		"precondition_required":  "If-Match header is required",
+		"unsupported_media_type": "unsupported Content-Type, use application/merge-patch+json",
+
+		// Категории
+		"category_not_found":      "category not found",
+		"invalid_category":        "category not found",
+		"invalid_category_id":     "invalid category id",
+		"invalid_revision":        "invalid revision number",
+		"invalid_parent_category": "invalid parent category",
+		"invalid_reassign_target": "invalid reassign_to category",
+		"category_has_children":   "category has subcategories",
+		"category_in_use":         "category is in use, specify reassign_to",
+		"category_deleted":        "category deleted successfully",
+
+		// Метки
+		"tag_not_found":  "tag not found",
+		"tag_name_taken": "tag with this name already exists",
+		"invalid_tag":    "tag not found",
+		"tag_deleted":    "tag deleted successfully",
+
+		// Вложения
+		"attachment_not_found":        "attachment not found",
+		"thumbnail_not_found":         "attachment has no thumbnail",
+		"attachment_empty":            "attachment file is empty",
+		"attachment_too_large":        "attachment file is too large",
+		"unsupported_attachment_type": "unsupported file type, allowed: JPEG, PNG, GIF, WebP, PDF",
+		"multipart_required":          "request must be multipart/form-data",
+		"file_required":               "file field is required",
+		"attachment_deleted":          "attachment deleted successfully",
+
+		// Кассовые чеки
+		"receipt_already_imported": "this receipt has already been imported",
+		"receipt_qr_not_found":     "no receipt QR code found in the image",
+		"invalid_receipt_image":    "receipt image cannot be decoded, allowed: JPEG, PNG, GIF",
+
+		// Продавцы
+		"merchant_not_found":       "merchant not found",
+		"merchant_alias_not_found": "merchant alias not found",
+		"merchant_alias_taken":     "this description is already linked to another merchant",
+		"merchant_deleted":         "merchant deleted successfully",
+
+		// Цели накопления
+		"goal_not_found":   "goal not found",
+		"goal_tag_missing": "goal has no tag, link a tag to record contributions",
+		"goal_deleted":     "goal deleted successfully",
+
+		// Необычные траты
+		"anomaly_not_found":        "anomaly not found",
+		"anomaly_dismissed":        "anomaly dismissed",
+		"anomaly.category_amount":  "{amount} {currency} is {ratio}× the usual {baseline} {currency} in {category}",
+		"anomaly.merchant_amount":  "{amount} {currency} at {merchant} is {ratio}× the usual {baseline} {currency} there",
+		"anomaly.new_merchant":     "First purchase at {merchant}: {amount} {currency} is {ratio}× your typical {baseline} {currency}",
+		"anomaly.category_spike":   "{category} spending in {month} is {amount} {currency}, {ratio}× the average {baseline} {currency} of previous months",
+		"anomaly.unknown_category": "this category",
+		"anomaly.unknown_merchant": "a new merchant",
+
+		// Уведомления
+		"notification_target_not_found":    "notification channel is not set up",
+		"notification_channel_unavailable": "this notification channel is not available on the server",
+		"notification_target_deleted":      "notification channel removed",
+		"notification.anomaly_detected":    "Unusual spending",
+
+		// Вебхуки
+		"webhook_not_found":          "webhook not found",
+		"webhook_delivery_not_found": "webhook delivery not found",
+		"webhook_delivery_pending":   "delivery is still in progress, wait for it to finish",
+		"webhook_deleted":            "webhook deleted successfully",
+
+		// Рабочие пространства
+		"workspace_not_found":                 "workspace not found",
+		"workspace_member_not_found":          "workspace member not found",
+		"workspace_member_exists":             "user is already a member of this workspace",
+		"workspace_owner_immutable":           "workspace owner cannot be changed or removed",
+		"workspace_invitation_not_found":      "invitation not found or already accepted",
+		"workspace_invitation_expired":        "invitation has expired",
+		"workspace_invitation_email_mismatch": "invitation was sent to a different email",
+		"workspace_deleted":                   "workspace deleted successfully",
+		"workspace_member_updated":            "member role updated",
+		"workspace_member_removed":            "member removed from workspace",
+		"workspace_invitation_revoked":        "invitation revoked",
+
+		// Синхронизация
+		"sync_exists":   "transaction with this id already exists on the server",
+		"sync_modified": "transaction was changed on the server after base_updated_at, merge the server version and retry",
+		"sync_deleted":  "transaction was deleted on the server",
+
+		// Правила категоризации
+		"rule_not_found": "rule not found",
+		"rule_deleted":   "rule deleted successfully",
+
+		// Ошибки полей
+		"validation.required":             "is required",
+		"validation.must_be_positive":     "must be positive",
+		"validation.too_short":            "must be at least {min} characters",
+		"validation.too_long":             "must be at most {max} characters",
+		"validation.invalid_format":       "has invalid format",
+		"validation.invalid_date":         "must be a date in RFC3339 format",
+		"validation.date_out_of_range":    "must be between 1970 and one year from now",
+		"validation.invalid_email":        "must be a valid email address",
+		"validation.weak_password":        "must contain letters and digits",
+		"validation.too_many":             "must contain at most {max} items",
+		"validation.too_few":              "must contain at least {min} items",
+		"validation.split_sum_mismatch":   "split amounts must add up to {expected}, got {actual}",
+		"validation.invalid_category":     "must be an existing category available to you",
+		"validation.must_be_greater":      "must be greater than {min}",
+		"validation.too_large":            "must be less than {max}",
+		"validation.out_of_range":         "must be between {min} and {max}",
+		"validation.not_allowed":          "must be one of: {allowed}",
+		"validation.required_with":        "is required when {field} is set",
+		"validation.unsupported_currency": "must be a supported currency code (RUB, USD, EUR)",
+		"validation.missing_fiscal_param": "receipt parameter {param} is missing",
+		"validation.invalid_fiscal_param": "receipt parameter {param} is invalid",
+	},
+	LocaleRU: {
+		// Общие
+		"internal_error":       "внутренняя ошибка сервера",
+		"not_implemented":      "не реализовано",
+		"invalid_request_body": "некорректное тело запроса",
+		"id_required":          "не указан id",
+		"validation_failed":    "ошибка валидации запроса",
+		"forbidden":            "доступ запрещён",
+
+		// Аутентификация
+		"unauthorized":                 "требуется авторизация",
+		"missing_authorization_header": "отсутствует заголовок Authorization",
+		"invalid_authorization_format": "неверный формат заголовка Authorization",
+		"invalid_token":                "недействительный или просроченный токен",
+		"invalid_refresh_token":        "недействительный refresh токен",
+		"invalid_credentials":          "неверный email или пароль",
+		"user_already_exists":          "пользователь уже существует",
+		"logged_out":                   "выход выполнен успешно",
+
+		// Транзакции
+		"transaction_not_found":          "транзакция не найдена",
+		"transaction_deleted":            "транзакция перемещена в корзину",
+		"transaction_not_in_trash":       "транзакции нет в корзине",
+		"transaction_revision_not_found": "версия транзакции не найдена",
+		"no_category_suggestion":         "нет категории для подтверждения, укажите category_id",
+		"splits_already_exist":           "транзакция уже разбита, используйте PUT для замены строк",
+		"transaction_has_splits":         "транзакция разбита, чтобы изменить сумму, обновите строки разбивки",
+		"splits_deleted":                 "разбивка удалена",
+
+		// Условные запросы
+		"precondition_failed":    "ресурс был изменён, загрузите его заново и повторите запрос",
+		"precondition_required":  "требуется заголовок If-Match",
+		"unsupported_media_type": "неподдерживаемый Content-Type, используйте application/merge-patch+json",
+
+		// Категории
+		"category_not_found":      "категория не найдена",
+		"invalid_category":        "категория не найдена",
+		"invalid_category_id":     "некорректный id категории",
+		"invalid_revision":        "некорректный номер версии",
+		"invalid_parent_category": "некорректная родительская категория",
+		"invalid_reassign_target": "некорректная категория для переназначения",
+		"category_has_children":   "у категории есть подкатегории",
+		"category_in_use":         "категория используется, укажите reassign_to",
+		"category_deleted":        "категория удалена",
+
+		// Метки
+		"tag_not_found":  "метка не найдена",
+		"tag_name_taken": "метка с таким названием уже существует",
+		"invalid_tag":    "метка не найдена",
+		"tag_deleted":    "метка удалена",
+
+		// Вложения
+		"attachment_not_found":        "вложение не найдено",
+		"thumbnail_not_found":         "у вложения нет миниатюры",
+		"attachment_empty":            "файл вложения пуст",
+		"attachment_too_large":        "файл вложения слишком большой",
+		"unsupported_attachment_type": "неподдерживаемый тип файла, допустимы JPEG, PNG, GIF, WebP, PDF",
+		"multipart_required":          "запрос должен быть в формате multipart/form-data",
+		"file_required":               "не передано поле file",
+		"attachment_deleted":          "вложение удалено",
+
+		// Кассовые чеки
+		"receipt_already_imported": "этот чек уже импортирован",
+		"receipt_qr_not_found":     "на изображении не найден QR-код чека",
+		"invalid_receipt_image":    "не удалось прочитать изображение чека, допустимы JPEG, PNG, GIF",
+
+		// Продавцы
+		"merchant_not_found":       "продавец не найден",
+		"merchant_alias_not_found": "описание не привязано к продавцу",
+		"merchant_alias_taken":     "это описание уже привязано к другому продавцу",
+		"merchant_deleted":         "продавец удалён",
+
+		// Цели накопления
+		"goal_not_found":   "цель не найдена",
+		"goal_tag_missing": "у цели нет метки, привяжите метку, чтобы записывать взносы",
+		"goal_deleted":     "цель удалена",
+
+		// Необычные траты
+		"anomaly_not_found":        "аномалия не найдена",
+		"anomaly_dismissed":        "аномалия скрыта",
+		"anomaly.category_amount":  "{amount} {currency} — в {ratio} раза больше обычных {baseline} {currency} в категории «{category}»",
+		"anomaly.merchant_amount":  "{amount} {currency} у продавца {merchant} — в {ratio} раза больше обычных {baseline} {currency}",
+		"anomaly.new_merchant":     "Первая покупка у продавца {merchant}: {amount} {currency} — в {ratio} раза больше ваших обычных {baseline} {currency}",
+		"anomaly.category_spike":   "Расходы в категории «{category}» за {month} — {amount} {currency}, в {ratio} раза больше среднего {baseline} {currency} за прошлые месяцы",
+		"anomaly.unknown_category": "без названия",
+		"anomaly.unknown_merchant": "без названия",
+
+		// Уведомления
+		"notification_target_not_found":    "канал уведомлений не настроен",
+		"notification_channel_unavailable": "этот канал уведомлений недоступен на сервере",
+		"notification_target_deleted":      "канал уведомлений отключён",
+		"notification.anomaly_detected":    "Необычная трата",
+
+		// Вебхуки
+		"webhook_not_found":          "вебхук не найден",
+		"webhook_delivery_not_found": "доставка вебхука не найдена",
+		"webhook_delivery_pending":   "доставка ещё выполняется, дождитесь её завершения",
+		"webhook_deleted":            "вебхук удалён",
+
+		// Рабочие пространства
+		"workspace_not_found":                 "рабочее пространство не найдено",
+		"workspace_member_not_found":          "участник пространства не найден",
+		"workspace_member_exists":             "пользователь уже состоит в этом пространстве",
+		"workspace_owner_immutable":           "владельца пространства нельзя изменить или исключить",
+		"workspace_invitation_not_found":      "приглашение не найдено или уже принято",
+		"workspace_invitation_expired":        "срок действия приглашения истёк",
+		"workspace_invitation_email_mismatch": "приглашение отправлено на другой email",
+		"workspace_deleted":                   "рабочее пространство удалено",
+		"workspace_member_updated":            "роль участника изменена",
+		"workspace_member_removed":            "участник исключён из пространства",
+		"workspace_invitation_revoked":        "приглашение отозвано",
+
+		// Синхронизация
+		"sync_exists":   "транзакция с таким id уже есть на сервере",
+		"sync_modified": "транзакция изменена на сервере после base_updated_at, объедините с серверной версией и отправьте снова",
+		"sync_deleted":  "транзакция удалена на сервере",
+
+		// Правила категоризации
+		"rule_not_found": "правило не найдено",
+		"rule_deleted":   "правило удалено",
+
+		// Ошибки полей
+		"validation.required":             "обязательное поле",
+		"validation.must_be_positive":     "должно быть положительным",
+		"validation.too_short":            "должно содержать не менее {min} символов",
+		"validation.too_long":             "должно содержать не более {max} символов",
+		"validation.invalid_format":       "имеет неверный формат",
+		"validation.invalid_date":         "должно быть датой в формате RFC3339",
+		"validation.date_out_of_range":    "должно быть не раньше 1970 года и не позже чем через год",
+		"validation.invalid_email":        "должно быть корректным email",
+		"validation.weak_password":        "должно содержать буквы и цифры",
+		"validation.too_many":             "должно содержать не более {max} элементов",
+		"validation.too_few":              "должно содержать не менее {min} элементов",
+		"validation.split_sum_mismatch":   "сумма строк должна быть равна {expected}, получено {actual}",
+		"validation.invalid_category":     "должно быть существующей категорией, доступной вам",
+		"validation.must_be_greater":      "должно быть больше {min}",
+		"validation.too_large":            "должно быть меньше {max}",
+		"validation.out_of_range":         "должно быть в диапазоне от {min} до {max}",
+		"validation.not_allowed":          "должно быть одним из: {allowed}",
+		"validation.required_with":        "обязательно, если указано поле {field}",
+		"validation.unsupported_currency": "должно быть поддерживаемой валютой (RUB, USD, EUR)",
+		"validation.missing_fiscal_param": "в чеке нет параметра {param}",
+		"validation.invalid_fiscal_param": "неверное значение параметра чека {param}",
+	},
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/gibbon/finace-dashboard/internal/audit"
+)
+
+// AuditMiddleware сохраняет в контексте пользователя, ID запроса и IP клиента для журнала аудита.
+// Подключается после AuthMiddleware; IP берётся из RemoteAddr, который уже обработал middleware.RealIP
+func AuditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := GetUserIDFromContext(r.Context())
+
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		ctx := audit.WithRequest(r.Context(), audit.Request{
+			ActorID:   userID,
+			RequestID: chimiddleware.GetReqID(r.Context()),
+			ClientIP:  ip,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gibbon/finace-dashboard/internal/apperror"
 	"github.com/gibbon/finace-dashboard/pkg/jwt"
 )
 
@@ -15,6 +16,12 @@ const (
 	EmailKey  contextKey = "email"
 )
 
+var (
+	errMissingAuthHeader = apperror.New(apperror.KindUnauthorized, "missing_authorization_header")
+	errInvalidAuthFormat = apperror.New(apperror.KindUnauthorized, "invalid_authorization_format")
+	errInvalidToken      = apperror.New(apperror.KindUnauthorized, "invalid_token")
+)
+
 // Проверяет JWT токен
 type AuthMiddleware struct {
 	jwtManager *jwt.Manager
@@ -30,21 +37,21 @@ func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, `{"error": "missing authorization header"}`, http.StatusUnauthorized)
+			apperror.Write(w, r, errMissingAuthHeader)
 			return
 		}
 
 		// Формат "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, `{"error": "invalid authorization format"}`, http.StatusUnauthorized)
+			apperror.Write(w, r, errInvalidAuthFormat)
 			return
 		}
 
 		token := parts[1]
 		claims, err := m.jwtManager.ValidateAccessToken(token)
 		if err != nil {
-			http.Error(w, `{"error": "invalid or expired token"}`, http.StatusUnauthorized)
+			apperror.Write(w, r, errInvalidToken)
 			return
 		}
 		//Помещаем данные юзера в контекст
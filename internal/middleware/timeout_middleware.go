@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Timeout ограничивает время обработки запроса, кроме потоковых маршрутов streamPaths,
+// которые держат соединение открытым, пока клиент не отключится
+func Timeout(timeout time.Duration, streamPaths ...string) func(http.Handler) http.Handler {
+	withTimeout := chimiddleware.Timeout(timeout)
+	return func(next http.Handler) http.Handler {
+		limited := withTimeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
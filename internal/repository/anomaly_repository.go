@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// anomalySelect выбирает аномалии вместе с названиями категории и продавца.
+// Для системной категории вместо названия возвращается ключ перевода
+const anomalySelect = `
+	SELECT a.id, a.user_id, a.kind, a.transaction_id, a.category_id, a.merchant_id, a.period,
+	       a.amount, a.baseline, a.ratio, a.currency,
+	       COALESCE(c.translation_key, c.name), m.name,
+	       a.dismissed_at, a.created_at
+	FROM anomalies a
+	LEFT JOIN categories c ON c.id = a.category_id
+	LEFT JOIN merchants m ON m.id = a.merchant_id
+`
+
+type postgresAnomalyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAnomalyRepository(pool *pgxpool.Pool) repository.AnomalyRepository {
+	return &postgresAnomalyRepository{pool: pool}
+}
+
+func (r *postgresAnomalyRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanAnomaly(row pgx.Row) (*model.Anomaly, error) {
+	anomaly := &model.Anomaly{}
+	err := row.Scan(
+		&anomaly.ID,
+		&anomaly.UserID,
+		&anomaly.Kind,
+		&anomaly.TransactionID,
+		&anomaly.CategoryID,
+		&anomaly.MerchantID,
+		&anomaly.Period,
+		&anomaly.Amount,
+		&anomaly.Baseline,
+		&anomaly.Ratio,
+		&anomaly.Currency,
+		&anomaly.CategoryName,
+		&anomaly.MerchantName,
+		&anomaly.DismissedAt,
+		&anomaly.CreatedAt,
+	)
+	return anomaly, err
+}
+
+func (r *postgresAnomalyRepository) Create(ctx context.Context, anomaly *model.Anomaly) (bool, error) {
+	// Уникальные индексы не дают сохранить одну аномалию дважды: по транзакции и виду
+	// или по категории, валюте и месяцу для всплесков
+	query := `
+		INSERT INTO anomalies (
+			id, user_id, kind, transaction_id, category_id, merchant_id, period,
+			amount, baseline, ratio, currency
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT DO NOTHING
+		RETURNING created_at
+	`
+
+	anomaly.ID = uuid.New().String()
+
+	err := r.db(ctx).QueryRow(ctx, query,
+		anomaly.ID,
+		anomaly.UserID,
+		anomaly.Kind,
+		anomaly.TransactionID,
+		anomaly.CategoryID,
+		anomaly.MerchantID,
+		anomaly.Period,
+		anomaly.Amount,
+		anomaly.Baseline,
+		anomaly.Ratio,
+		anomaly.Currency,
+	).Scan(&anomaly.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *postgresAnomalyRepository) GetByUserID(ctx context.Context, filter model.AnomalyFilter) ([]*model.Anomaly, error) {
+	// Аномалии удалённых транзакций не показываются
+	query := anomalySelect + `
+		WHERE a.user_id = $1
+		  AND NOT EXISTS (SELECT 1 FROM transactions t WHERE t.id = a.transaction_id AND t.deleted_at IS NOT NULL)
+	`
+	args := []interface{}{filter.UserID}
+
+	if filter.Kind != nil {
+		args = append(args, *filter.Kind)
+		query += " AND a.kind = $" + strconv.Itoa(len(args))
+	}
+
+	if !filter.IncludeDismissed {
+		query += " AND a.dismissed_at IS NULL"
+	}
+
+	query += " ORDER BY a.created_at DESC, a.id"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*model.Anomaly
+	for rows.Next() {
+		anomaly, err := scanAnomaly(rows)
+		if err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	return anomalies, rows.Err()
+}
+
+func (r *postgresAnomalyRepository) GetByID(ctx context.Context, id string) (*model.Anomaly, error) {
+	anomaly, err := scanAnomaly(r.db(ctx).QueryRow(ctx, anomalySelect+` WHERE a.id = $1`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return anomaly, nil
+}
+
+func (r *postgresAnomalyRepository) Dismiss(ctx context.Context, id string) error {
+	query := `UPDATE anomalies SET dismissed_at = COALESCE(dismissed_at, CURRENT_TIMESTAMP) WHERE id = $1`
+
+	tag, err := r.db(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresAnomalyRepository) GetAmounts(ctx context.Context, sample model.AmountSample) ([]float64, error) {
+	scope, scopeArg := workspaceScope(sample.UserID, sample.WorkspaceID, "")
+	query := `
+		SELECT amount FROM transactions
+		WHERE ` + scope + ` AND deleted_at IS NULL AND currency = $2 AND date >= $3
+	`
+	args := []interface{}{scopeArg, sample.Currency, sample.Since}
+
+	if sample.CategoryID != nil {
+		args = append(args, *sample.CategoryID)
+		query += " AND category_id = $" + strconv.Itoa(len(args))
+	}
+
+	if sample.MerchantID != nil {
+		args = append(args, *sample.MerchantID)
+		query += " AND merchant_id = $" + strconv.Itoa(len(args))
+	}
+
+	if sample.ExcludeID != "" {
+		args = append(args, sample.ExcludeID)
+		query += " AND id <> $" + strconv.Itoa(len(args))
+	}
+
+	query += " ORDER BY date DESC"
+
+	if sample.Limit > 0 {
+		args = append(args, sample.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var amounts []float64
+	for rows.Next() {
+		var amount float64
+		if err := rows.Scan(&amount); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount)
+	}
+
+	return amounts, rows.Err()
+}
+
+func (r *postgresAnomalyRepository) SumCategoryByMonth(ctx context.Context, userID string, workspaceID *string, categoryID int, currency string, from, to time.Time) ([]*model.MonthTotal, error) {
+	scope, scopeArg := workspaceScope(userID, workspaceID, "t.")
+	// Разбитая транзакция учитывается по строкам разбивки, как в аналитике по категориям
+	query := `
+		SELECT date_trunc('month', t.date AT TIME ZONE 'UTC'), SUM(COALESCE(s.amount, t.amount))
+		FROM transactions t
+		LEFT JOIN transaction_splits s ON s.transaction_id = t.id
+		WHERE ` + scope + ` AND t.deleted_at IS NULL AND COALESCE(s.category_id, t.category_id) = $2
+		  AND t.currency = $3 AND t.date >= $4 AND t.date < $5
+		GROUP BY 1 ORDER BY 1
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, scopeArg, categoryID, currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.MonthTotal
+	for rows.Next() {
+		total := &model.MonthTotal{}
+		if err := rows.Scan(&total.Month, &total.Total); err != nil {
+			return nil, err
+		}
+		total.Month = time.Date(total.Month.Year(), total.Month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+func (r *postgresAnomalyRepository) GetCreatedSince(ctx context.Context, since time.Time) ([]*model.Transaction, error) {
+	query := `
+		SELECT id, user_id, amount, currency, description, date, category_id, merchant_id, created_at, updated_at, workspace_id
+		FROM transactions
+		WHERE created_at >= $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []*model.Transaction
+	for rows.Next() {
+		tx := &model.Transaction{}
+		if err := rows.Scan(
+			&tx.ID,
+			&tx.UserID,
+			&tx.Amount,
+			&tx.Currency,
+			&tx.Description,
+			&tx.Date,
+			&tx.CategoryID,
+			&tx.MerchantID,
+			&tx.CreatedAt,
+			&tx.UpdatedAt,
+			&tx.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const attachmentColumns = `id, transaction_id, user_id, file_name, content_type, size, storage_key, thumbnail_key, created_at`
+
+type postgresAttachmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAttachmentRepository(pool *pgxpool.Pool) repository.AttachmentRepository {
+	return &postgresAttachmentRepository{pool: pool}
+}
+
+func (r *postgresAttachmentRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanAttachment(row pgx.Row) (*model.Attachment, error) {
+	attachment := &model.Attachment{}
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.TransactionID,
+		&attachment.UserID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.ThumbnailKey,
+		&attachment.CreatedAt,
+	)
+	return attachment, err
+}
+
+func (r *postgresAttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, transaction_id, user_id, file_name, content_type, size, storage_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		attachment.ID,
+		attachment.TransactionID,
+		attachment.UserID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.ThumbnailKey,
+	).Scan(&attachment.CreatedAt)
+}
+
+func (r *postgresAttachmentRepository) GetByID(ctx context.Context, id string) (*model.Attachment, error) {
+	// Вложения транзакций в корзине недоступны до восстановления
+	query := `
+		SELECT ` + attachmentColumns + ` FROM attachments
+		WHERE id = $1 AND transaction_id IN (SELECT id FROM transactions WHERE deleted_at IS NULL)
+	`
+
+	attachment, err := scanAttachment(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (r *postgresAttachmentRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE transaction_id = $1 ORDER BY created_at`
+
+	rows, err := r.db(ctx).Query(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*model.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *postgresAttachmentRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	return err
+}
+
+func (r *postgresAttachmentRepository) GetBlobDeletions(ctx context.Context, limit int) ([]*model.BlobDeletion, error) {
+	query := `SELECT id, storage_key FROM blob_deletions ORDER BY id LIMIT $1`
+
+	rows, err := r.db(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deletions []*model.BlobDeletion
+	for rows.Next() {
+		deletion := &model.BlobDeletion{}
+		if err := rows.Scan(&deletion.ID, &deletion.StorageKey); err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, deletion)
+	}
+
+	return deletions, rows.Err()
+}
+
+func (r *postgresAttachmentRepository) CompleteBlobDeletions(ctx context.Context, ids []int64) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM blob_deletions WHERE id = ANY($1)`, ids)
+	return err
+}
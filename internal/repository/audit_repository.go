@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresAuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAuditRepository(pool *pgxpool.Pool) repository.AuditRepository {
+	return &postgresAuditRepository{pool: pool}
+}
+
+func (r *postgresAuditRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *postgresAuditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (user_id, workspace_id, actor_id, entity, entity_id, action, before, after, request_id, client_ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		entry.UserID,
+		entry.WorkspaceID,
+		entry.ActorID,
+		entry.Entity,
+		entry.EntityID,
+		entry.Action,
+		entry.Before,
+		entry.After,
+		entry.RequestID,
+		entry.ClientIP,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *postgresAuditRepository) GetByUserID(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "")
+	query := `
+		SELECT id, user_id, workspace_id, actor_id, entity, entity_id, action, before, after, request_id, client_ip, created_at
+		FROM audit_log
+		WHERE ` + scope + `
+	`
+	args := []interface{}{scopeArg}
+
+	if filter.Entity != nil {
+		args = append(args, *filter.Entity)
+		query += " AND entity = $" + strconv.Itoa(len(args))
+	}
+
+	if filter.EntityID != nil {
+		args = append(args, *filter.EntityID)
+		query += " AND entity_id = $" + strconv.Itoa(len(args))
+	}
+
+	query += " ORDER BY id DESC"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.AuditEntry
+	for rows.Next() {
+		entry := &model.AuditEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.WorkspaceID,
+			&entry.ActorID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.Before,
+			&entry.After,
+			&entry.RequestID,
+			&entry.ClientIP,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -2,15 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
 	"github.com/gibbon/finace-dashboard/internal/domain/repository"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var ErrCategoryInUse = errors.New("category is in use")
+
+const categoryColumns = `id, user_id, parent_id, name, translation_key, icon, color, is_default, created_at, updated_at`
+
 type postgresCategoryRepository struct {
 	pool *pgxpool.Pool
 }
@@ -19,47 +25,196 @@ func NewPostgresCategoryRepository(pool *pgxpool.Pool) repository.CategoryReposi
 	return &postgresCategoryRepository{pool: pool}
 }
 
-func (r *postgresCategoryRepository) GetAll(ctx context.Context) ([]*model.Category, error) {
-	query := `SELECT id, name, is_default, created_at FROM categories ORDER BY name`
+func (r *postgresCategoryRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *postgresCategoryRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE user_id IS NULL OR user_id = $1 ORDER BY name`
+	return r.queryCategories(ctx, query, userID)
+}
+
+func (r *postgresCategoryRepository) GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.Category, error) {
+	// Категории бывших участников остаются у транзакций, поэтому берутся все категории их владельцев —
+	// вместе с родительскими для свёртки подкатегорий
+	query := `
+		SELECT ` + categoryColumns + ` FROM categories
+		WHERE user_id IS NULL
+		   OR user_id IN (SELECT user_id FROM workspace_members WHERE workspace_id = $1)
+		   OR user_id IN (
+		       SELECT c.user_id FROM transactions t JOIN categories c ON c.id = t.category_id
+		       WHERE t.workspace_id = $1
+		   )
+		ORDER BY name
+	`
+	return r.queryCategories(ctx, query, workspaceID)
+}
+
+func (r *postgresCategoryRepository) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	cat, err := scanCategory(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return cat, nil
+}
+
+func (r *postgresCategoryRepository) GetDefault(ctx context.Context) ([]*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE is_default = true ORDER BY name`
+	return r.queryCategories(ctx, query)
+}
+
+func (r *postgresCategoryRepository) GetTranslations(ctx context.Context, locale string) (map[string]string, error) {
+	query := `SELECT translation_key, name FROM category_translations WHERE locale = $1`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.db(ctx).Query(ctx, query, locale)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var categories []*model.Category
+	translations := make(map[string]string)
 	for rows.Next() {
-		cat := &model.Category{}
-		err := rows.Scan(&cat.ID, &cat.Name, &cat.IsDefault, &cat.CreatedAt)
-		if err != nil {
+		var key, name string
+		if err := rows.Scan(&key, &name); err != nil {
 			return nil, err
 		}
-		categories = append(categories, cat)
+		translations[key] = name
 	}
 
-	return categories, nil
+	return translations, rows.Err()
 }
 
-func (r *postgresCategoryRepository) GetByID(ctx context.Context, id int) (*model.Category, error) {
-	query := `SELECT id, name, is_default, created_at FROM categories WHERE id = $1`
+func (r *postgresCategoryRepository) GetChildren(ctx context.Context, parentID int) ([]*model.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE parent_id = $1 ORDER BY name`
+	return r.queryCategories(ctx, query, parentID)
+}
 
-	cat := &model.Category{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(&cat.ID, &cat.Name, &cat.IsDefault, &cat.CreatedAt)
+func (r *postgresCategoryRepository) Create(ctx context.Context, category *model.Category) error {
+	query := `
+		INSERT INTO categories (user_id, parent_id, name, icon, color, is_default)
+		VALUES ($1, $2, $3, $4, $5, false)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		category.UserID,
+		category.ParentID,
+		category.Name,
+		category.Icon,
+		category.Color,
+	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
+}
+
+func (r *postgresCategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	query := `
+		UPDATE categories
+		SET parent_id = $2, name = $3, icon = $4, color = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		category.ID,
+		category.ParentID,
+		category.Name,
+		category.Icon,
+		category.Color,
+	).Scan(&category.UpdatedAt)
+}
+
+func (r *postgresCategoryRepository) GetWorkspaceIDs(ctx context.Context, id int) ([]string, error) {
+	// Удалённые транзакции тоже переназначаются, поэтому учитываются
+	query := `
+		SELECT workspace_id FROM transactions WHERE category_id = $1 AND workspace_id IS NOT NULL
+		UNION
+		SELECT t.workspace_id FROM transaction_splits s
+		JOIN transactions t ON t.id = s.transaction_id
+		WHERE s.category_id = $1 AND t.workspace_id IS NOT NULL
+		UNION
+		SELECT workspace_id FROM user_category_rules WHERE category_id = $1 AND workspace_id IS NOT NULL
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, id)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, ErrUserNotFound
-		}
 		return nil, err
 	}
+	defer rows.Close()
 
-	return cat, nil
+	var workspaceIDs []string
+	for rows.Next() {
+		var workspaceID string
+		if err := rows.Scan(&workspaceID); err != nil {
+			return nil, err
+		}
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	return workspaceIDs, rows.Err()
 }
 
-func (r *postgresCategoryRepository) GetDefault(ctx context.Context) ([]*model.Category, error) {
-	query := `SELECT id, name, is_default, created_at FROM categories WHERE is_default = true ORDER BY name`
+func (r *postgresCategoryRepository) Delete(ctx context.Context, id int, reassignTo *int) error {
+	dbTx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Rollback(ctx)
+
+	// Транзакции, у которых меняется категория, сохраняют прежнее состояние версией
+	if reassignTo != nil {
+		reassigned := withRevisions(`category_id = $1`) + `
+			UPDATE transactions SET category_id = $2
+			WHERE id IN (SELECT transaction_id FROM previous)
+		`
+		if _, err := dbTx.Exec(ctx, reassigned, id, *reassignTo); err != nil {
+			return err
+		}
+		if _, err := dbTx.Exec(ctx, `UPDATE user_category_rules SET category_id = $2 WHERE category_id = $1`, id, *reassignTo); err != nil {
+			return err
+		}
+		if _, err := dbTx.Exec(ctx, `UPDATE transaction_splits SET category_id = $2 WHERE category_id = $1`, id, *reassignTo); err != nil {
+			return err
+		}
+	}
+
+	// Удалённые транзакции не мешают удалению категории: они теряют категорию и разбивку целиком,
+	// чтобы сумма строк разбивки не разошлась с суммой транзакции
+	deleted := `
+		DELETE FROM transaction_splits
+		WHERE transaction_id IN (
+			SELECT s.transaction_id FROM transaction_splits s
+			JOIN transactions t ON t.id = s.transaction_id
+			WHERE s.category_id = $1 AND t.deleted_at IS NOT NULL
+		)
+	`
+	if _, err := dbTx.Exec(ctx, deleted, id); err != nil {
+		return err
+	}
+	uncategorized := withRevisions(`category_id = $1 AND deleted_at IS NOT NULL`) + `
+		UPDATE transactions SET category_id = NULL
+		WHERE id IN (SELECT transaction_id FROM previous)
+	`
+	if _, err := dbTx.Exec(ctx, uncategorized, id); err != nil {
+		return err
+	}
 
-	rows, err := r.pool.Query(ctx, query)
+	if _, err := dbTx.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCategoryInUse
+		}
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+func (r *postgresCategoryRepository) queryCategories(ctx context.Context, query string, args ...interface{}) ([]*model.Category, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -67,15 +222,34 @@ func (r *postgresCategoryRepository) GetDefault(ctx context.Context) ([]*model.C
 
 	var categories []*model.Category
 	for rows.Next() {
-		cat := &model.Category{}
-		err := rows.Scan(&cat.ID, &cat.Name, &cat.IsDefault, &cat.CreatedAt)
+		cat, err := scanCategory(rows)
 		if err != nil {
 			return nil, err
 		}
 		categories = append(categories, cat)
 	}
 
-	return categories, nil
+	return categories, rows.Err()
+}
+
+func scanCategory(row pgx.Row) (*model.Category, error) {
+	cat := &model.Category{}
+	err := row.Scan(
+		&cat.ID,
+		&cat.UserID,
+		&cat.ParentID,
+		&cat.Name,
+		&cat.TranslationKey,
+		&cat.Icon,
+		&cat.Color,
+		&cat.IsDefault,
+		&cat.CreatedAt,
+		&cat.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return cat, nil
 }
 
 type postgresUserCategoryRuleRepository struct {
@@ -86,30 +260,58 @@ func NewPostgresUserCategoryRuleRepository(pool *pgxpool.Pool) repository.UserCa
 	return &postgresUserCategoryRuleRepository{pool: pool}
 }
 
+func (r *postgresUserCategoryRuleRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
 func (r *postgresUserCategoryRuleRepository) Create(ctx context.Context, rule *model.UserCategoryRule) error {
 	query := `
-		INSERT INTO user_category_rules (id, user_id, keyword, category_id, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO user_category_rules (id, user_id, keyword, category_id, created_at, workspace_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	rule.ID = uuid.New().String()
 	rule.CreatedAt = time.Now()
 
-	_, err := r.pool.Exec(ctx, query,
+	_, err := r.db(ctx).Exec(ctx, query,
 		rule.ID,
 		rule.UserID,
 		rule.Keyword,
 		rule.CategoryID,
 		rule.CreatedAt,
+		rule.WorkspaceID,
 	)
 
 	return err
 }
 
+func (r *postgresUserCategoryRuleRepository) GetByID(ctx context.Context, id string) (*model.UserCategoryRule, error) {
+	query := `SELECT id, user_id, keyword, category_id, created_at, workspace_id FROM user_category_rules WHERE id = $1`
+
+	rule := &model.UserCategoryRule{}
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(&rule.ID, &rule.UserID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt, &rule.WorkspaceID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
 func (r *postgresUserCategoryRuleRepository) GetByUserID(ctx context.Context, userID string) ([]*model.UserCategoryRule, error) {
-	query := `SELECT id, user_id, keyword, category_id, created_at FROM user_category_rules WHERE user_id = $1`
+	query := `SELECT id, user_id, keyword, category_id, created_at, workspace_id FROM user_category_rules WHERE user_id = $1 AND workspace_id IS NULL`
+	return r.queryRules(ctx, query, userID)
+}
+
+func (r *postgresUserCategoryRuleRepository) GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.UserCategoryRule, error) {
+	query := `SELECT id, user_id, keyword, category_id, created_at, workspace_id FROM user_category_rules WHERE workspace_id = $1`
+	return r.queryRules(ctx, query, workspaceID)
+}
 
-	rows, err := r.pool.Query(ctx, query, userID)
+func (r *postgresUserCategoryRuleRepository) queryRules(ctx context.Context, query string, args ...interface{}) ([]*model.UserCategoryRule, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +320,7 @@ func (r *postgresUserCategoryRuleRepository) GetByUserID(ctx context.Context, us
 	var rules []*model.UserCategoryRule
 	for rows.Next() {
 		rule := &model.UserCategoryRule{}
-		err := rows.Scan(&rule.ID, &rule.UserID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt)
+		err := rows.Scan(&rule.ID, &rule.UserID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt, &rule.WorkspaceID)
 		if err != nil {
 			return nil, err
 		}
@@ -129,13 +331,13 @@ func (r *postgresUserCategoryRuleRepository) GetByUserID(ctx context.Context, us
 }
 
 func (r *postgresUserCategoryRuleRepository) GetByKeyword(ctx context.Context, userID, keyword string) (*model.UserCategoryRule, error) {
-	query := `SELECT id, user_id, keyword, category_id, created_at FROM user_category_rules WHERE user_id = $1 AND keyword = $2`
+	query := `SELECT id, user_id, keyword, category_id, created_at, workspace_id FROM user_category_rules WHERE user_id = $1 AND workspace_id IS NULL AND keyword = $2`
 
 	rule := &model.UserCategoryRule{}
-	err := r.pool.QueryRow(ctx, query, userID, keyword).Scan(&rule.ID, &rule.UserID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt)
+	err := r.db(ctx).QueryRow(ctx, query, userID, keyword).Scan(&rule.ID, &rule.UserID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt, &rule.WorkspaceID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, ErrUserNotFound
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
@@ -145,7 +347,7 @@ func (r *postgresUserCategoryRuleRepository) GetByKeyword(ctx context.Context, u
 
 func (r *postgresUserCategoryRuleRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM user_category_rules WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	_, err := r.db(ctx).Exec(ctx, query, id)
 	return err
 }
 
@@ -156,6 +358,6 @@ func (r *postgresUserCategoryRuleRepository) Update(ctx context.Context, rule *m
 		WHERE id = $1
 	`
 
-	_, err := r.pool.Exec(ctx, query, rule.ID, rule.Keyword, rule.CategoryID)
+	_, err := r.db(ctx).Exec(ctx, query, rule.ID, rule.Keyword, rule.CategoryID)
 	return err
 }
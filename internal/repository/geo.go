@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+// earthRadius средний радиус Земли в метрах
+const earthRadius = 6371008.8
+
+// appendGeoFilter добавляет к запросу условия Bounds и Near фильтра.
+// prefix — псевдоним таблицы transactions вместе с точкой или пустая строка
+func appendGeoFilter(query string, args []interface{}, filter model.TransactionFilter, prefix string) (string, []interface{}) {
+	if filter.Bounds == nil && filter.Near == nil {
+		return query, args
+	}
+
+	lat, lon := prefix+"place_lat", prefix+"place_lon"
+	query += " AND " + lat + " IS NOT NULL AND " + lon + " IS NOT NULL"
+
+	if filter.Bounds != nil {
+		query, args = appendBounds(query, args, *filter.Bounds, lat, lon)
+	}
+
+	if c := filter.Near; c != nil {
+		// Прямоугольник вокруг окружности отсекает большую часть строк до вычисления расстояния
+		query, args = appendBounds(query, args, circleBounds(*c), lat, lon)
+
+		args = append(args, c.Lat, c.Lon, c.Radius/earthRadius)
+		pLat, pLon, pAngle := "$"+strconv.Itoa(len(args)-2), "$"+strconv.Itoa(len(args)-1), "$"+strconv.Itoa(len(args))
+		// Формула гаверсинусов: угловое расстояние между точками не больше радиуса
+		query += ` AND 2 * asin(sqrt(least(1,
+			power(sin(radians(` + lat + `::float8 - ` + pLat + `::float8) / 2), 2) +
+			cos(radians(` + pLat + `::float8)) * cos(radians(` + lat + `::float8)) *
+			power(sin(radians(` + lon + `::float8 - ` + pLon + `::float8) / 2), 2)
+		))) <= ` + pAngle + `::float8`
+	}
+
+	return query, args
+}
+
+// appendBounds добавляет условие попадания координат в прямоугольник
+func appendBounds(query string, args []interface{}, b model.GeoBounds, lat, lon string) (string, []interface{}) {
+	args = append(args, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon)
+	n := len(args)
+	query += " AND " + lat + " BETWEEN $" + strconv.Itoa(n-3) + " AND $" + strconv.Itoa(n-2)
+
+	op := " AND "
+	if b.MinLon > b.MaxLon {
+		op = " OR "
+	}
+	query += " AND (" + lon + " >= $" + strconv.Itoa(n-1) + op + lon + " <= $" + strconv.Itoa(n) + ")"
+	return query, args
+}
+
+// circleBounds возвращает прямоугольник, описанный вокруг окружности.
+// Если окружность накрывает полюс, ограничение по долготе снимается
+func circleBounds(c model.GeoCircle) model.GeoBounds {
+	angle := c.Radius / earthRadius
+	latDelta := angle * 180 / math.Pi
+
+	b := model.GeoBounds{
+		MinLat: c.Lat - latDelta,
+		MaxLat: c.Lat + latDelta,
+		MinLon: -180,
+		MaxLon: 180,
+	}
+	if b.MinLat <= -90 || b.MaxLat >= 90 || angle >= math.Pi/2 {
+		b.MinLat = math.Max(b.MinLat, -90)
+		b.MaxLat = math.Min(b.MaxLat, 90)
+		return b
+	}
+
+	lonDelta := math.Asin(math.Sin(angle)/math.Cos(c.Lat*math.Pi/180)) * 180 / math.Pi
+	b.MinLon, b.MaxLon = c.Lon-lonDelta, c.Lon+lonDelta
+	if b.MinLon < -180 {
+		b.MinLon += 360
+	}
+	if b.MaxLon > 180 {
+		b.MaxLon -= 360
+	}
+	return b
+}
+
+func (r *postgresTransactionRepository) SumByPlace(ctx context.Context, filter model.PlaceFilter) ([]*model.PlaceTotal, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, nil, "")
+	args := []interface{}{scopeArg}
+
+	// Места группируются по названию без учёта регистра и координатам, округлённым до ~100 м,
+	// чтобы разные магазины одной сети оставались отдельными точками
+	groupBy := "lower(trim(place_name)), round(place_lat, 3), round(place_lon, 3)"
+	cell := "NULL::float8, NULL::float8"
+	name := "MIN(place_name)"
+	if filter.GroupBy == model.PlaceGroupingGrid {
+		args = append(args, filter.CellSize)
+		size := "$" + strconv.Itoa(len(args)) + "::float8"
+		groupBy = "1, 2"
+		cell = "floor(place_lat::float8 / " + size + "), floor(place_lon::float8 / " + size + ")"
+		name = "NULL::text"
+	}
+
+	query := `
+		SELECT ` + cell + `, ` + name + `,
+		       AVG(place_lat)::float8, AVG(place_lon)::float8,
+		       SUM(amount), COUNT(*)
+		FROM transactions
+		WHERE ` + scope + ` AND deleted_at IS NULL
+		  AND place_lat IS NOT NULL AND place_lon IS NOT NULL
+	`
+
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		query += " AND category_id = $" + strconv.Itoa(len(args))
+	}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND date <= $" + strconv.Itoa(len(args))
+	}
+
+	query, args = appendGeoFilter(query, args, filter.TransactionFilter, "")
+
+	query += " GROUP BY " + groupBy + " ORDER BY SUM(amount) DESC"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.PlaceTotal
+	for rows.Next() {
+		var cellLat, cellLon *float64
+		total := &model.PlaceTotal{}
+		if err := rows.Scan(&cellLat, &cellLon, &total.Name, &total.Lat, &total.Lon, &total.Total, &total.Count); err != nil {
+			return nil, err
+		}
+		if cellLat != nil && cellLon != nil {
+			total.Cell = &model.GeoBounds{
+				MinLat: *cellLat * filter.CellSize,
+				MinLon: *cellLon * filter.CellSize,
+				MaxLat: (*cellLat + 1) * filter.CellSize,
+				MaxLon: (*cellLon + 1) * filter.CellSize,
+			}
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestCircleBounds(t *testing.T) {
+	// 1 км вокруг Москвы: ~0.009° по широте и ~0.016° по долготе
+	b := circleBounds(model.GeoCircle{Lat: 55.75, Lon: 37.62, Radius: 1000})
+	if math.Abs(b.MaxLat-b.MinLat-0.01799) > 1e-4 || math.Abs(b.MaxLon-b.MinLon-0.03196) > 1e-4 {
+		t.Errorf("Unexpected bounds %+v", b)
+	}
+
+	// Окружность у 180-го меридиана переходит на другую сторону
+	b = circleBounds(model.GeoCircle{Lat: 65, Lon: 179.99, Radius: 5000})
+	if b.MinLon <= b.MaxLon || b.MaxLon > -179.8 {
+		t.Errorf("Expected bounds across antimeridian, got %+v", b)
+	}
+
+	// Окружность, накрывающая полюс, не ограничивает долготу
+	b = circleBounds(model.GeoCircle{Lat: 89.9, Lon: 10, Radius: 50000})
+	if b.MaxLat != 90 || b.MinLon != -180 || b.MaxLon != 180 {
+		t.Errorf("Expected polar bounds, got %+v", b)
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const goalColumns = `id, user_id, name, target_amount, currency, deadline, tag_id, created_at, updated_at`
+
+type postgresGoalRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresGoalRepository(pool *pgxpool.Pool) repository.GoalRepository {
+	return &postgresGoalRepository{pool: pool}
+}
+
+func (r *postgresGoalRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanGoal(row pgx.Row) (*model.Goal, error) {
+	goal := &model.Goal{}
+	err := row.Scan(
+		&goal.ID,
+		&goal.UserID,
+		&goal.Name,
+		&goal.TargetAmount,
+		&goal.Currency,
+		&goal.Deadline,
+		&goal.TagID,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+	return goal, err
+}
+
+func (r *postgresGoalRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals WHERE user_id = $1 ORDER BY deadline NULLS LAST, created_at`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []*model.Goal
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, rows.Err()
+}
+
+func (r *postgresGoalRepository) GetByID(ctx context.Context, id string) (*model.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals WHERE id = $1`
+
+	goal, err := scanGoal(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+func (r *postgresGoalRepository) Create(ctx context.Context, goal *model.Goal) error {
+	query := `
+		INSERT INTO goals (id, user_id, name, target_amount, currency, deadline, tag_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	goal.ID = uuid.New().String()
+
+	return r.db(ctx).QueryRow(ctx, query,
+		goal.ID,
+		goal.UserID,
+		goal.Name,
+		goal.TargetAmount,
+		goal.Currency,
+		goal.Deadline,
+		goal.TagID,
+	).Scan(&goal.CreatedAt, &goal.UpdatedAt)
+}
+
+func (r *postgresGoalRepository) Update(ctx context.Context, goal *model.Goal) error {
+	query := `
+		UPDATE goals
+		SET name = $2, target_amount = $3, currency = $4, deadline = $5, tag_id = $6
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db(ctx).QueryRow(ctx, query,
+		goal.ID,
+		goal.Name,
+		goal.TargetAmount,
+		goal.Currency,
+		goal.Deadline,
+		goal.TagID,
+	).Scan(&goal.CreatedAt, &goal.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *postgresGoalRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM goals WHERE id = $1`, id)
+	return err
+}
+
+func (r *postgresGoalRepository) SumContributions(ctx context.Context, goalIDs []string) (map[string]*model.GoalContributions, error) {
+	// Взносы — личные транзакции владельца цели с её меткой и в её валюте
+	query := `
+		SELECT g.id, SUM(t.amount), COUNT(*), MIN(t.date)
+		FROM goals g
+		JOIN transaction_tags tt ON tt.tag_id = g.tag_id
+		JOIN transactions t ON t.id = tt.transaction_id
+		WHERE g.id = ANY($1::uuid[]) AND t.user_id = g.user_id AND t.workspace_id IS NULL
+		  AND t.deleted_at IS NULL AND t.currency = g.currency
+		GROUP BY g.id
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, goalIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributions := make(map[string]*model.GoalContributions, len(goalIDs))
+	for rows.Next() {
+		var id string
+		c := &model.GoalContributions{}
+		if err := rows.Scan(&id, &c.Total, &c.Count, &c.FirstDate); err != nil {
+			return nil, err
+		}
+		contributions[id] = c
+	}
+
+	return contributions, rows.Err()
+}
@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrMerchantAliasTaken ключ уже привязан к другому продавцу пользователя
+var ErrMerchantAliasTaken = errors.New("merchant alias already taken")
+
+// merchantSelect выбирает продавцов вместе с отсортированными ключами
+const merchantSelect = `
+	SELECT m.id, m.user_id, m.name, m.created_at, m.updated_at,
+	       COALESCE(array_agg(a.key ORDER BY a.key) FILTER (WHERE a.key IS NOT NULL), '{}')
+	FROM merchants m
+	LEFT JOIN merchant_aliases a ON a.merchant_id = m.id
+`
+
+type postgresMerchantRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresMerchantRepository(pool *pgxpool.Pool) repository.MerchantRepository {
+	return &postgresMerchantRepository{pool: pool}
+}
+
+func (r *postgresMerchantRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanMerchant(row pgx.Row) (*model.Merchant, error) {
+	merchant := &model.Merchant{}
+	err := row.Scan(
+		&merchant.ID,
+		&merchant.UserID,
+		&merchant.Name,
+		&merchant.CreatedAt,
+		&merchant.UpdatedAt,
+		&merchant.Aliases,
+	)
+	return merchant, err
+}
+
+func (r *postgresMerchantRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Merchant, error) {
+	query := merchantSelect + ` WHERE m.user_id = $1 GROUP BY m.id ORDER BY m.name`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merchants []*model.Merchant
+	for rows.Next() {
+		merchant, err := scanMerchant(rows)
+		if err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, merchant)
+	}
+
+	return merchants, rows.Err()
+}
+
+func (r *postgresMerchantRepository) GetByID(ctx context.Context, id string) (*model.Merchant, error) {
+	query := merchantSelect + ` WHERE m.id = $1 GROUP BY m.id`
+
+	merchant, err := scanMerchant(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return merchant, nil
+}
+
+func (r *postgresMerchantRepository) Update(ctx context.Context, merchant *model.Merchant) error {
+	query := `UPDATE merchants SET name = $2 WHERE id = $1 RETURNING created_at, updated_at`
+
+	err := r.db(ctx).QueryRow(ctx, query, merchant.ID, merchant.Name).Scan(&merchant.CreatedAt, &merchant.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *postgresMerchantRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM merchants WHERE id = $1`, id)
+	return err
+}
+
+func (r *postgresMerchantRepository) Resolve(ctx context.Context, userID string, names map[string]string) (map[string]string, error) {
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+
+	ids, err := r.lookupAliases(ctx, userID, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var newKeys, newNames, newIDs []string
+	for _, key := range keys {
+		if _, ok := ids[key]; !ok {
+			newKeys = append(newKeys, key)
+			newNames = append(newNames, names[key])
+			newIDs = append(newIDs, uuid.New().String())
+		}
+	}
+	if len(newKeys) == 0 {
+		return ids, nil
+	}
+
+	// Продавец и его ключ создаются одной командой. Ключ, который успел занять
+	// параллельный запрос, не вставляется, а созданный для него продавец удаляется ниже
+	query := `
+		WITH input AS (
+			SELECT * FROM unnest($2::text[], $3::text[], $4::uuid[]) AS i(key, name, id)
+		), created AS (
+			INSERT INTO merchants (id, user_id, name)
+			SELECT id, $1, name FROM input
+			RETURNING id
+		)
+		INSERT INTO merchant_aliases (user_id, key, merchant_id)
+		SELECT $1, i.key, c.id FROM input i JOIN created c ON c.id = i.id
+		ON CONFLICT (user_id, key) DO NOTHING
+		RETURNING key, merchant_id
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID, newKeys, newNames, newIDs)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids[key] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var lostKeys, orphans []string
+	for i, key := range newKeys {
+		if _, ok := ids[key]; !ok {
+			lostKeys = append(lostKeys, key)
+			orphans = append(orphans, newIDs[i])
+		}
+	}
+	if len(lostKeys) == 0 {
+		return ids, nil
+	}
+
+	if _, err := r.db(ctx).Exec(ctx, `DELETE FROM merchants WHERE id = ANY($1::uuid[])`, orphans); err != nil {
+		return nil, err
+	}
+	existing, err := r.lookupAliases(ctx, userID, lostKeys)
+	if err != nil {
+		return nil, err
+	}
+	for key, id := range existing {
+		ids[key] = id
+	}
+	return ids, nil
+}
+
+// lookupAliases возвращает ID продавцов по уже привязанным ключам
+func (r *postgresMerchantRepository) lookupAliases(ctx context.Context, userID string, keys []string) (map[string]string, error) {
+	query := `SELECT key, merchant_id FROM merchant_aliases WHERE user_id = $1 AND key = ANY($2::text[])`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string, len(keys))
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, err
+		}
+		ids[key] = id
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *postgresMerchantRepository) AddAlias(ctx context.Context, userID, merchantID, key string) error {
+	query := `INSERT INTO merchant_aliases (user_id, key, merchant_id) VALUES ($1, $2, $3)`
+
+	_, err := r.db(ctx).Exec(ctx, query, userID, key, merchantID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrMerchantAliasTaken
+	}
+	return err
+}
+
+func (r *postgresMerchantRepository) DeleteAlias(ctx context.Context, merchantID, key string) error {
+	tag, err := r.db(ctx).Exec(ctx, `DELETE FROM merchant_aliases WHERE merchant_id = $1 AND key = $2`, merchantID, key)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresMerchantRepository) Merge(ctx context.Context, targetID string, sourceIDs []string) error {
+	// Транзакции, у которых меняется продавец, сохраняют прежнее состояние версией
+	queries := []string{
+		`UPDATE merchant_aliases SET merchant_id = $1 WHERE merchant_id = ANY($2::uuid[])`,
+		withRevisions(`merchant_id = ANY($2::uuid[])`) + `
+		UPDATE transactions SET merchant_id = $1 WHERE id IN (SELECT transaction_id FROM previous)`,
+		`DELETE FROM merchants WHERE id = ANY($2::uuid[])`,
+	}
+	for _, query := range queries {
+		if _, err := r.db(ctx).Exec(ctx, query, targetID, sourceIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresMerchantRepository) GetUnmatchedDescriptions(ctx context.Context, userID string) ([]string, error) {
+	scope, scopeArg := workspaceScope(userID, nil, "")
+	query := `SELECT DISTINCT description FROM transactions WHERE ` + scope + ` AND deleted_at IS NULL AND merchant_id IS NULL`
+
+	rows, err := r.db(ctx).Query(ctx, query, scopeArg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var descriptions []string
+	for rows.Next() {
+		var description string
+		if err := rows.Scan(&description); err != nil {
+			return nil, err
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	return descriptions, rows.Err()
+}
+
+func (r *postgresMerchantRepository) AssignByDescription(ctx context.Context, userID string, merchantIDs map[string]string) (int64, error) {
+	descriptions := make([]string, 0, len(merchantIDs))
+	ids := make([]string, 0, len(merchantIDs))
+	for description, id := range merchantIDs {
+		descriptions = append(descriptions, description)
+		ids = append(ids, id)
+	}
+
+	scope, scopeArg := workspaceScope(userID, nil, "")
+	query := withRevisions(scope+` AND deleted_at IS NULL AND merchant_id IS NULL AND description = ANY($2::text[])`) + `
+		UPDATE transactions t SET merchant_id = v.merchant_id
+		FROM unnest($2::text[], $3::uuid[]) AS v(description, merchant_id)
+		WHERE t.id IN (SELECT transaction_id FROM previous) AND t.description = v.description
+	`
+
+	tag, err := r.db(ctx).Exec(ctx, query, scopeArg, descriptions, ids)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *postgresMerchantRepository) SumByMerchant(ctx context.Context, filter model.TransactionFilter) ([]*model.MerchantTotal, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "t.")
+	query := `
+		SELECT t.merchant_id, COALESCE(m.name, ''), SUM(t.amount), COUNT(*)
+		FROM transactions t
+		LEFT JOIN merchants m ON m.id = t.merchant_id
+		WHERE ` + scope + ` AND t.deleted_at IS NULL
+	`
+
+	args := []interface{}{scopeArg}
+
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		query += " AND t.category_id = $" + strconv.Itoa(len(args))
+	}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND t.date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND t.date <= $" + strconv.Itoa(len(args))
+	}
+
+	// Транзакции без продавца идут последней группой
+	query += " GROUP BY t.merchant_id, m.name ORDER BY t.merchant_id IS NULL, SUM(t.amount) DESC"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.MerchantTotal
+	for rows.Next() {
+		total := &model.MerchantTotal{}
+		if err := rows.Scan(&total.MerchantID, &total.Name, &total.Total, &total.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
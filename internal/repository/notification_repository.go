@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const notificationColumns = `id, user_id, event, channel, address, subject, text, data, status, attempts, next_attempt_at, last_error, created_at, sent_at`
+
+type postgresNotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresNotificationRepository(pool *pgxpool.Pool) repository.NotificationRepository {
+	return &postgresNotificationRepository{pool: pool}
+}
+
+func (r *postgresNotificationRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *postgresNotificationRepository) GetTargets(ctx context.Context, userID string) ([]*model.NotificationTarget, error) {
+	query := `
+		SELECT user_id, channel, address, locale, created_at, updated_at
+		FROM notification_targets WHERE user_id = $1 ORDER BY channel
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []*model.NotificationTarget
+	for rows.Next() {
+		target := &model.NotificationTarget{}
+		if err := rows.Scan(&target.UserID, &target.Channel, &target.Address, &target.Locale, &target.CreatedAt, &target.UpdatedAt); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, rows.Err()
+}
+
+func (r *postgresNotificationRepository) UpsertTarget(ctx context.Context, target *model.NotificationTarget) error {
+	query := `
+		INSERT INTO notification_targets (user_id, channel, address, locale)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, channel) DO UPDATE
+		SET address = EXCLUDED.address, locale = EXCLUDED.locale
+		RETURNING created_at, updated_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query, target.UserID, target.Channel, target.Address, target.Locale).
+		Scan(&target.CreatedAt, &target.UpdatedAt)
+}
+
+func (r *postgresNotificationRepository) DeleteTarget(ctx context.Context, userID string, channel model.NotificationChannel) error {
+	tag, err := r.db(ctx).Exec(ctx, `DELETE FROM notification_targets WHERE user_id = $1 AND channel = $2`, userID, channel)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresNotificationRepository) GetPreferences(ctx context.Context, userID string) ([]*model.NotificationPreference, error) {
+	query := `SELECT event, channel, enabled FROM notification_preferences WHERE user_id = $1`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prefs []*model.NotificationPreference
+	for rows.Next() {
+		pref := &model.NotificationPreference{}
+		if err := rows.Scan(&pref.Event, &pref.Channel, &pref.Enabled); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, pref)
+	}
+
+	return prefs, rows.Err()
+}
+
+func (r *postgresNotificationRepository) SetPreferences(ctx context.Context, userID string, prefs []*model.NotificationPreference) error {
+	events := make([]string, len(prefs))
+	channels := make([]string, len(prefs))
+	enabled := make([]bool, len(prefs))
+	for i, pref := range prefs {
+		events[i] = string(pref.Event)
+		channels[i] = string(pref.Channel)
+		enabled[i] = pref.Enabled
+	}
+
+	query := `
+		INSERT INTO notification_preferences (user_id, event, channel, enabled)
+		SELECT $1, p.event, p.channel, p.enabled
+		FROM unnest($2::text[], $3::text[], $4::bool[]) AS p(event, channel, enabled)
+		ON CONFLICT (user_id, event, channel) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, userID, events, channels, enabled)
+	return err
+}
+
+func (r *postgresNotificationRepository) Enqueue(ctx context.Context, notifications []*model.Notification) error {
+	query := `
+		INSERT INTO notification_outbox (user_id, event, channel, address, subject, text, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, attempts, next_attempt_at, created_at
+	`
+
+	for _, n := range notifications {
+		err := r.db(ctx).QueryRow(ctx, query, n.UserID, n.Event, n.Channel, n.Address, n.Subject, n.Text, n.Data).
+			Scan(&n.ID, &n.Status, &n.Attempts, &n.NextAttemptAt, &n.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresNotificationRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.Notification, error) {
+	// SKIP LOCKED: строки, которые уже забирает другой экземпляр, пропускаются без ожидания
+	query := `
+		UPDATE notification_outbox SET next_attempt_at = CURRENT_TIMESTAMP + $2::interval
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationColumns
+
+	rows, err := r.db(ctx).Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*model.Notification
+	for rows.Next() {
+		n := &model.Notification{}
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Event,
+			&n.Channel,
+			&n.Address,
+			&n.Subject,
+			&n.Text,
+			&n.Data,
+			&n.Status,
+			&n.Attempts,
+			&n.NextAttemptAt,
+			&n.LastError,
+			&n.CreatedAt,
+			&n.SentAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *postgresNotificationRepository) MarkSent(ctx context.Context, id string) error {
+	query := `
+		UPDATE notification_outbox
+		SET status = 'sent', attempts = attempts + 1, sent_at = CURRENT_TIMESTAMP, last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id)
+	return err
+}
+
+func (r *postgresNotificationRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt *time.Time) error {
+	query := `
+		UPDATE notification_outbox
+		SET attempts = attempts + 1, last_error = $2,
+		    status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE status END,
+		    next_attempt_at = COALESCE($3, next_attempt_at)
+		WHERE id = $1
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id, lastError, nextAttemptAt)
+	return err
+}
+
+func (r *postgresNotificationRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM notification_outbox WHERE status IN ('sent', 'failed') AND created_at < $1`
+
+	tag, err := r.db(ctx).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
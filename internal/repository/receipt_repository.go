@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrReceiptExists чек с такими фискальными реквизитами уже импортирован
+var ErrReceiptExists = errors.New("fiscal receipt already exists")
+
+type postgresFiscalReceiptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresFiscalReceiptRepository(pool *pgxpool.Pool) repository.FiscalReceiptRepository {
+	return &postgresFiscalReceiptRepository{pool: pool}
+}
+
+func (r *postgresFiscalReceiptRepository) Create(ctx context.Context, receipt *model.FiscalReceipt) error {
+	query := `
+		INSERT INTO fiscal_receipts (id, user_id, transaction_id, fn, fd, fp, operation, raw)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	receipt.ID = uuid.New().String()
+
+	err := conn(ctx, r.pool).QueryRow(ctx, query,
+		receipt.ID,
+		receipt.UserID,
+		receipt.TransactionID,
+		receipt.FN,
+		receipt.FD,
+		receipt.FP,
+		receipt.Operation,
+		receipt.Raw,
+	).Scan(&receipt.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrReceiptExists
+	}
+	return err
+}
+
+func (r *postgresFiscalReceiptRepository) GetByFiscalID(ctx context.Context, userID, fn, fd, fp string) (*model.FiscalReceipt, error) {
+	query := `
+		SELECT id, user_id, transaction_id, fn, fd, fp, operation, raw, created_at
+		FROM fiscal_receipts
+		WHERE user_id = $1 AND fn = $2 AND fd = $3 AND fp = $4
+	`
+
+	receipt := &model.FiscalReceipt{}
+	err := conn(ctx, r.pool).QueryRow(ctx, query, userID, fn, fd, fp).Scan(
+		&receipt.ID,
+		&receipt.UserID,
+		&receipt.TransactionID,
+		&receipt.FN,
+		&receipt.FD,
+		&receipt.FP,
+		&receipt.Operation,
+		&receipt.Raw,
+		&receipt.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return receipt, nil
+}
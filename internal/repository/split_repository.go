@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresTransactionSplitRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTransactionSplitRepository(pool *pgxpool.Pool) repository.TransactionSplitRepository {
+	return &postgresTransactionSplitRepository{pool: pool}
+}
+
+func (r *postgresTransactionSplitRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.TransactionSplit, error) {
+	query := `
+		SELECT id, transaction_id, category_id, amount, note, position, created_at
+		FROM transaction_splits
+		WHERE transaction_id = $1
+		ORDER BY position
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []*model.TransactionSplit
+	for rows.Next() {
+		split := &model.TransactionSplit{}
+		err := rows.Scan(
+			&split.ID,
+			&split.TransactionID,
+			&split.CategoryID,
+			&split.Amount,
+			&split.Note,
+			&split.Position,
+			&split.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+
+	return splits, rows.Err()
+}
+
+func (r *postgresTransactionSplitRepository) Replace(ctx context.Context, transactionID string, splits []*model.TransactionSplit) error {
+	db := conn(ctx, r.pool)
+
+	if _, err := db.Exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, transactionID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO transaction_splits (id, transaction_id, category_id, amount, note, position)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	for i, split := range splits {
+		split.ID = uuid.New().String()
+		split.TransactionID = transactionID
+		split.Position = i
+		err := db.QueryRow(ctx, query,
+			split.ID,
+			split.TransactionID,
+			split.CategoryID,
+			split.Amount,
+			split.Note,
+			split.Position,
+		).Scan(&split.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *postgresTransactionSplitRepository) DeleteByTransactionID(ctx context.Context, transactionID string) error {
+	_, err := conn(ctx, r.pool).Exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, transactionID)
+	return err
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Сущности в таблице следов удаления sync_tombstones
+const (
+	tombstoneTransaction = "transaction"
+	tombstoneRule        = "rule"
+	tombstoneCategory    = "category"
+)
+
+type postgresSyncRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSyncRepository(pool *pgxpool.Pool) repository.SyncRepository {
+	return &postgresSyncRepository{pool: pool}
+}
+
+func (r *postgresSyncRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *postgresSyncRepository) CurrentToken(ctx context.Context) (int64, error) {
+	// Транзакции с номером меньше xmin снимка завершены, более поздние попадут в следующую выборку
+	var token int64
+	err := r.db(ctx).QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`).Scan(&token)
+	return token, err
+}
+
+func (r *postgresSyncRepository) GetTransactionChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.Transaction, string], error) {
+	// Синхронизируются личные транзакции, данные пространств клиент получает через их API
+	scope, scopeArg := workspaceScope(userID, nil, "")
+	query := `
+		SELECT id, user_id, workspace_id, amount, currency, description, date,
+		       place_name, place_lat, place_lon, category_id, is_confirmed, merchant_id,
+		       created_at, updated_at, deleted_at, sync_created_xid >= $2
+		FROM transactions
+		WHERE ` + scope + ` AND sync_xid >= $2
+	`
+	// Первой синхронизации следы удаления не нужны
+	if since == 0 {
+		query += " AND deleted_at IS NULL"
+	}
+	query += " ORDER BY date DESC"
+
+	rows, err := r.db(ctx).Query(ctx, query, scopeArg, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := &model.SyncSet[*model.Transaction, string]{}
+	for rows.Next() {
+		tx := &model.Transaction{}
+		var created bool
+		err := rows.Scan(
+			&tx.ID,
+			&tx.UserID,
+			&tx.WorkspaceID,
+			&tx.Amount,
+			&tx.Currency,
+			&tx.Description,
+			&tx.Date,
+			&tx.PlaceName,
+			&tx.PlaceLat,
+			&tx.PlaceLon,
+			&tx.CategoryID,
+			&tx.IsConfirmed,
+			&tx.MerchantID,
+			&tx.CreatedAt,
+			&tx.UpdatedAt,
+			&tx.DeletedAt,
+			&created,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tx.DeletedAt != nil:
+			changes.Deleted = append(changes.Deleted, tx.ID)
+		case created:
+			changes.Created = append(changes.Created, tx)
+		default:
+			changes.Updated = append(changes.Updated, tx)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Транзакции, удалённые из корзины окончательно, остаются только в следах
+	purged, err := r.tombstones(ctx, tombstoneTransaction, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	changes.Deleted = append(changes.Deleted, purged...)
+	return changes, nil
+}
+
+func (r *postgresSyncRepository) GetRuleChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.UserCategoryRule, string], error) {
+	scope, scopeArg := workspaceScope(userID, nil, "")
+	query := `
+		SELECT id, user_id, workspace_id, keyword, category_id, created_at, sync_created_xid >= $2
+		FROM user_category_rules
+		WHERE ` + scope + ` AND sync_xid >= $2
+		ORDER BY created_at
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, scopeArg, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := &model.SyncSet[*model.UserCategoryRule, string]{}
+	for rows.Next() {
+		rule := &model.UserCategoryRule{}
+		var created bool
+		if err := rows.Scan(&rule.ID, &rule.UserID, &rule.WorkspaceID, &rule.Keyword, &rule.CategoryID, &rule.CreatedAt, &created); err != nil {
+			return nil, err
+		}
+		if created {
+			changes.Created = append(changes.Created, rule)
+		} else {
+			changes.Updated = append(changes.Updated, rule)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if changes.Deleted, err = r.tombstones(ctx, tombstoneRule, userID, since); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (r *postgresSyncRepository) GetCategoryChanges(ctx context.Context, userID string, since int64) (*model.SyncSet[*model.Category, int], error) {
+	query := `
+		SELECT ` + categoryColumns + `, sync_created_xid >= $2
+		FROM categories
+		WHERE (user_id IS NULL OR user_id = $1) AND sync_xid >= $2
+		ORDER BY name
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := &model.SyncSet[*model.Category, int]{}
+	for rows.Next() {
+		cat := &model.Category{}
+		var created bool
+		err := rows.Scan(
+			&cat.ID,
+			&cat.UserID,
+			&cat.ParentID,
+			&cat.Name,
+			&cat.TranslationKey,
+			&cat.Icon,
+			&cat.Color,
+			&cat.IsDefault,
+			&cat.CreatedAt,
+			&cat.UpdatedAt,
+			&created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			changes.Created = append(changes.Created, cat)
+		} else {
+			changes.Updated = append(changes.Updated, cat)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids, err := r.tombstones(ctx, tombstoneCategory, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		categoryID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, err
+		}
+		changes.Deleted = append(changes.Deleted, categoryID)
+	}
+	return changes, nil
+}
+
+// tombstones возвращает ID удалённых записей сущности, видимых пользователю.
+// Следы системных категорий (user_id IS NULL) видны всем
+func (r *postgresSyncRepository) tombstones(ctx context.Context, entity, userID string, since int64) ([]string, error) {
+	if since == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT DISTINCT entity_id
+		FROM sync_tombstones
+		WHERE entity = $1 AND (user_id = $2 OR user_id IS NULL) AND sync_xid >= $3
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, entity, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
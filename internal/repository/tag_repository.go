@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrTagNameTaken у пользователя уже есть метка с таким названием
+var ErrTagNameTaken = errors.New("tag name already taken")
+
+const tagColumns = `id, user_id, name, color, created_at, updated_at`
+
+type postgresTagRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTagRepository(pool *pgxpool.Pool) repository.TagRepository {
+	return &postgresTagRepository{pool: pool}
+}
+
+func (r *postgresTagRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanTag(row pgx.Row) (*model.Tag, error) {
+	tag := &model.Tag{}
+	err := row.Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.Color,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	return tag, err
+}
+
+func (r *postgresTagRepository) queryTags(ctx context.Context, query string, args ...interface{}) ([]*model.Tag, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*model.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *postgresTagRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE user_id = $1 ORDER BY name`
+	return r.queryTags(ctx, query, userID)
+}
+
+func (r *postgresTagRepository) GetByID(ctx context.Context, id string) (*model.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE id = $1`
+
+	tag, err := scanTag(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (r *postgresTagRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Tag, error) {
+	query := `SELECT ` + tagColumns + ` FROM tags WHERE id = ANY($1::uuid[])`
+	return r.queryTags(ctx, query, ids)
+}
+
+func (r *postgresTagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	query := `
+		INSERT INTO tags (id, user_id, name, color)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at
+	`
+
+	tag.ID = uuid.New().String()
+
+	err := r.db(ctx).QueryRow(ctx, query, tag.ID, tag.UserID, tag.Name, tag.Color).Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	return mapTagError(err)
+}
+
+func (r *postgresTagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	query := `
+		UPDATE tags SET name = $2, color = $3
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db(ctx).QueryRow(ctx, query, tag.ID, tag.Name, tag.Color).Scan(&tag.CreatedAt, &tag.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	return mapTagError(err)
+}
+
+func (r *postgresTagRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	return err
+}
+
+func (r *postgresTagRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*model.Tag, error) {
+	query := `
+		SELECT tt.transaction_id, t.id, t.user_id, t.name, t.color, t.created_at, t.updated_at
+		FROM transaction_tags tt
+		JOIN tags t ON t.id = tt.tag_id
+		WHERE tt.transaction_id = ANY($1::uuid[])
+		ORDER BY t.name
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]*model.Tag)
+	for rows.Next() {
+		var transactionID string
+		tag := &model.Tag{}
+		err := rows.Scan(
+			&transactionID,
+			&tag.ID,
+			&tag.UserID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result[transactionID] = append(result[transactionID], tag)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *postgresTagRepository) Attach(ctx context.Context, tagIDs, transactionIDs []string) (int64, error) {
+	query := `
+		INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT tx_id, tag_id
+		FROM unnest($1::uuid[]) AS tx_id CROSS JOIN unnest($2::uuid[]) AS tag_id
+		ON CONFLICT DO NOTHING
+	`
+
+	tag, err := r.db(ctx).Exec(ctx, query, transactionIDs, tagIDs)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *postgresTagRepository) Detach(ctx context.Context, tagIDs, transactionIDs []string) (int64, error) {
+	query := `DELETE FROM transaction_tags WHERE transaction_id = ANY($1::uuid[]) AND tag_id = ANY($2::uuid[])`
+
+	tag, err := r.db(ctx).Exec(ctx, query, transactionIDs, tagIDs)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *postgresTagRepository) ReplaceForTransaction(ctx context.Context, userID, transactionID string, tagIDs []string) error {
+	query := `
+		DELETE FROM transaction_tags tt
+		USING tags g
+		WHERE g.id = tt.tag_id AND tt.transaction_id = $1 AND g.user_id = $2
+	`
+	if _, err := r.db(ctx).Exec(ctx, query, transactionID, userID); err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	_, err := r.Attach(ctx, tagIDs, []string{transactionID})
+	return err
+}
+
+func (r *postgresTagRepository) SumByTag(ctx context.Context, filter model.TransactionFilter) ([]*model.TagTotal, error) {
+	// Метки всегда личные, транзакции отбираются так же, как в остальной аналитике
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "t.")
+	query := `
+		SELECT g.id, g.name, g.color, SUM(t.amount), COUNT(*)
+		FROM transaction_tags tt
+		JOIN tags g ON g.id = tt.tag_id
+		JOIN transactions t ON t.id = tt.transaction_id
+		WHERE ` + scope + ` AND g.user_id = $2 AND t.deleted_at IS NULL
+	`
+
+	args := []interface{}{scopeArg, filter.UserID}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND t.date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND t.date <= $" + strconv.Itoa(len(args))
+	}
+
+	query += " GROUP BY g.id, g.name, g.color ORDER BY SUM(t.amount) DESC"
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.TagTotal
+	for rows.Next() {
+		total := &model.TagTotal{}
+		if err := rows.Scan(&total.TagID, &total.Name, &total.Color, &total.Total, &total.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// mapTagError переводит нарушение уникальности названия в ErrTagNameTaken
+func mapTagError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrTagNameTaken
+	}
+	return err
+}
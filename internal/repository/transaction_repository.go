@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
@@ -10,6 +12,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrVersionConflict транзакция была изменена после чтения (не совпал updated_at)
+var ErrVersionConflict = errors.New("version conflict")
+
 type postgresTransactionRepository struct {
 	pool *pgxpool.Pool
 }
@@ -18,16 +23,23 @@ func NewPostgresTransactionRepository(pool *pgxpool.Pool) repository.Transaction
 	return &postgresTransactionRepository{pool: pool}
 }
 
+// db возвращает соединение с учётом транзакции из контекста
+func (r *postgresTransactionRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
 func (r *postgresTransactionRepository) Create(ctx context.Context, tx *model.Transaction) error {
 	query := `
 		INSERT INTO transactions (
 			id, user_id, amount, currency, description, date,
 			place_name, place_lat, place_lon, category_id, is_confirmed,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			created_at, updated_at, merchant_id, workspace_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING created_at, updated_at
 	`
 
-	_, err := r.pool.Exec(ctx, query,
+	// Время возвращается из БД, чтобы версия (updated_at) совпадала с хранимой с точностью до микросекунд
+	return r.db(ctx).QueryRow(ctx, query,
 		tx.ID,
 		tx.UserID,
 		tx.Amount,
@@ -41,22 +53,22 @@ func (r *postgresTransactionRepository) Create(ctx context.Context, tx *model.Tr
 		tx.IsConfirmed,
 		tx.CreatedAt,
 		tx.UpdatedAt,
-	)
-
-	return err
+		tx.MerchantID,
+		tx.WorkspaceID,
+	).Scan(&tx.CreatedAt, &tx.UpdatedAt)
 }
 
 func (r *postgresTransactionRepository) GetByID(ctx context.Context, id string) (*model.Transaction, error) {
 	query := `
 		SELECT id, user_id, amount, currency, description, date,
-		       place_name, place_lat, place_lon, category_id, is_confirmed,
-		       created_at, updated_at
+		       place_name, place_lat, place_lon, category_id, is_confirmed, merchant_id,
+		       created_at, updated_at, workspace_id
 		FROM transactions
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	tx := &model.Transaction{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
 		&tx.ID,
 		&tx.UserID,
 		&tx.Amount,
@@ -68,13 +80,15 @@ func (r *postgresTransactionRepository) GetByID(ctx context.Context, id string)
 		&tx.PlaceLon,
 		&tx.CategoryID,
 		&tx.IsConfirmed,
+		&tx.MerchantID,
 		&tx.CreatedAt,
 		&tx.UpdatedAt,
+		&tx.WorkspaceID,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, ErrUserNotFound
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
@@ -83,49 +97,27 @@ func (r *postgresTransactionRepository) GetByID(ctx context.Context, id string)
 }
 
 func (r *postgresTransactionRepository) GetByUserID(ctx context.Context, filter model.TransactionFilter) ([]*model.Transaction, error) {
+	where, args := transactionFilterWhere(filter)
 	query := `
 		SELECT id, user_id, amount, currency, description, date,
-		       place_name, place_lat, place_lon, category_id, is_confirmed,
-		       created_at, updated_at
+		       place_name, place_lat, place_lon, category_id, is_confirmed, merchant_id,
+		       created_at, updated_at, workspace_id
 		FROM transactions
-		WHERE user_id = $1
-	`
-
-	args := []interface{}{filter.UserID}
-	argIndex := 2
-
-	if filter.CategoryID != nil {
-		query += " AND category_id = $" + string(rune('0'+argIndex))
-		args = append(args, *filter.CategoryID)
-		argIndex++
-	}
-
-	if filter.FromDate != nil {
-		query += " AND date >= $" + string(rune('0'+argIndex))
-		args = append(args, *filter.FromDate)
-		argIndex++
-	}
-
-	if filter.ToDate != nil {
-		query += " AND date <= $" + string(rune('0'+argIndex))
-		args = append(args, *filter.ToDate)
-		argIndex++
-	}
+	` + where
 
 	query += " ORDER BY date DESC"
 
 	if filter.Limit > 0 {
-		query += " LIMIT $" + string(rune('0'+argIndex))
 		args = append(args, filter.Limit)
-		argIndex++
+		query += " LIMIT $" + strconv.Itoa(len(args))
 	}
 
 	if filter.Offset > 0 {
-		query += " OFFSET $" + string(rune('0'+argIndex))
 		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
 	}
 
-	rows, err := r.pool.Query(ctx, query, args...)
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +138,10 @@ func (r *postgresTransactionRepository) GetByUserID(ctx context.Context, filter
 			&tx.PlaceLon,
 			&tx.CategoryID,
 			&tx.IsConfirmed,
+			&tx.MerchantID,
 			&tx.CreatedAt,
 			&tx.UpdatedAt,
+			&tx.WorkspaceID,
 		)
 		if err != nil {
 			return nil, err
@@ -158,18 +152,80 @@ func (r *postgresTransactionRepository) GetByUserID(ctx context.Context, filter
 	return transactions, nil
 }
 
-func (r *postgresTransactionRepository) Update(ctx context.Context, tx *model.Transaction) error {
-	query := `
+// transactionFilterWhere возвращает условие WHERE списка транзакций и его аргументы.
+// Одно условие используется и для страницы списка, и для общего количества
+func transactionFilterWhere(filter model.TransactionFilter) (string, []interface{}) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "")
+	query := " WHERE deleted_at IS NULL AND " + scope
+
+	args := []interface{}{scopeArg}
+
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		query += " AND category_id = $" + strconv.Itoa(len(args))
+	}
+
+	if filter.MerchantID != nil {
+		args = append(args, *filter.MerchantID)
+		query += " AND merchant_id = $" + strconv.Itoa(len(args))
+	}
+
+	if len(filter.TagIDs) > 0 {
+		// Транзакция должна иметь все перечисленные метки
+		args = append(args, filter.TagIDs, len(filter.TagIDs))
+		query += ` AND id IN (
+			SELECT transaction_id FROM transaction_tags
+			WHERE tag_id = ANY($` + strconv.Itoa(len(args)-1) + `::uuid[])
+			GROUP BY transaction_id
+			HAVING COUNT(*) = $` + strconv.Itoa(len(args)) + `)`
+	}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND date <= $" + strconv.Itoa(len(args))
+	}
+
+	return appendGeoFilter(query, args, filter, "")
+}
+
+// withRevisions возвращает CTE previous, сохраняющее версией текущее состояние транзакций,
+// отобранных условием condition. Изменяющий запрос должен затрагивать только строки
+// previous: FOR UPDATE блокирует их, поэтому в версию попадает именно то состояние,
+// которое заменяет изменение
+func withRevisions(condition string) string {
+	return `
+		WITH previous AS (
+			INSERT INTO transaction_revisions (
+				transaction_id, amount, currency, description, date,
+				place_name, place_lat, place_lon, category_id, merchant_id, is_confirmed, updated_at
+			)
+			SELECT id, amount, currency, description, date,
+			       place_name, place_lat, place_lon, category_id, merchant_id, is_confirmed, updated_at
+			FROM transactions
+			WHERE ` + condition + `
+			FOR UPDATE
+			RETURNING transaction_id
+		)`
+}
+
+func (r *postgresTransactionRepository) Update(ctx context.Context, tx *model.Transaction, expectedUpdatedAt *time.Time) error {
+	// Прежнее состояние сохраняется версией в той же команде.
+	// updated_at выставляет триггер, новое значение возвращается через RETURNING
+	query := withRevisions(`id = $1 AND deleted_at IS NULL AND ($11::timestamptz IS NULL OR updated_at = $11)`) + `
 		UPDATE transactions
 		SET amount = $2, currency = $3, description = $4, date = $5,
 		    place_name = $6, place_lat = $7, place_lon = $8,
-		    category_id = $9, is_confirmed = $10, updated_at = $11
-		WHERE id = $1
+		    category_id = $9, is_confirmed = $10, merchant_id = $12
+		WHERE id = (SELECT transaction_id FROM previous)
+		RETURNING created_at, updated_at
 	`
 
-	tx.UpdatedAt = time.Now()
-
-	_, err := r.pool.Exec(ctx, query,
+	err := r.db(ctx).QueryRow(ctx, query,
 		tx.ID,
 		tx.Amount,
 		tx.Currency,
@@ -180,21 +236,527 @@ func (r *postgresTransactionRepository) Update(ctx context.Context, tx *model.Tr
 		tx.PlaceLon,
 		tx.CategoryID,
 		tx.IsConfirmed,
-		tx.UpdatedAt,
-	)
+		expectedUpdatedAt,
+		tx.MerchantID,
+	).Scan(&tx.CreatedAt, &tx.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		if expectedUpdatedAt != nil {
+			return ErrVersionConflict
+		}
+		return ErrNotFound
+	}
 
 	return err
 }
 
+func (r *postgresTransactionRepository) Touch(ctx context.Context, tx *model.Transaction) error {
+	// Поля транзакции не меняются, поэтому версия не сохраняется, а updated_at и sync_xid выставляют триггеры
+	query := `UPDATE transactions SET updated_at = updated_at WHERE id = $1 AND deleted_at IS NULL RETURNING updated_at`
+
+	err := r.db(ctx).QueryRow(ctx, query, tx.ID).Scan(&tx.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// transactionRevisionsQuery выбирает версии транзакции $1. Номер версии не хранится,
+// а считается по порядку добавления: версии только добавляются, поэтому номера не меняются
+const transactionRevisionsQuery = `
+	SELECT revision, transaction_id, amount, currency, description, date,
+	       place_name, place_lat, place_lon, category_id, merchant_id, is_confirmed,
+	       updated_at, replaced_at
+	FROM (
+		SELECT ROW_NUMBER() OVER (ORDER BY id) AS revision, *
+		FROM transaction_revisions
+		WHERE transaction_id = $1
+	) r
+`
+
+func scanTransactionRevision(row pgx.Row) (*model.TransactionRevision, error) {
+	rev := &model.TransactionRevision{Transaction: &model.Transaction{}}
+	err := row.Scan(
+		&rev.Revision,
+		&rev.ID,
+		&rev.Amount,
+		&rev.Currency,
+		&rev.Description,
+		&rev.Date,
+		&rev.PlaceName,
+		&rev.PlaceLat,
+		&rev.PlaceLon,
+		&rev.CategoryID,
+		&rev.MerchantID,
+		&rev.IsConfirmed,
+		&rev.UpdatedAt,
+		&rev.ReplacedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (r *postgresTransactionRepository) GetRevisions(ctx context.Context, id string) ([]*model.TransactionRevision, error) {
+	rows, err := r.db(ctx).Query(ctx, transactionRevisionsQuery+" ORDER BY revision DESC", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.TransactionRevision
+	for rows.Next() {
+		rev, err := scanTransactionRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (r *postgresTransactionRepository) GetRevision(ctx context.Context, id string, revision int) (*model.TransactionRevision, error) {
+	rev, err := scanTransactionRevision(r.db(ctx).QueryRow(ctx, transactionRevisionsQuery+" WHERE revision = $2", id, revision))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return rev, err
+}
+
 func (r *postgresTransactionRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM transactions WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
+	// Строка остаётся следом удаления, чтобы клиенты синхронизации узнали о нём.
+	// Состояние на момент удаления сохраняется версией
+	query := withRevisions(`id = $1 AND deleted_at IS NULL`) + `
+		UPDATE transactions SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT transaction_id FROM previous)
+	`
+	_, err := r.db(ctx).Exec(ctx, query, id)
 	return err
 }
 
-func (r *postgresTransactionRepository) GetTotalCount(ctx context.Context, userID string) (int64, error) {
-	query := `SELECT COUNT(*) FROM transactions WHERE user_id = $1`
+func (r *postgresTransactionRepository) GetTotalCount(ctx context.Context, filter model.TransactionFilter) (int64, error) {
+	where, args := transactionFilterWhere(filter)
+	query := `SELECT COUNT(*) FROM transactions` + where
 	var count int64
-	err := r.pool.QueryRow(ctx, query, userID).Scan(&count)
+	err := r.db(ctx).QueryRow(ctx, query, args...).Scan(&count)
 	return count, err
 }
+
+// workspaceScope возвращает условие выборки строк пространства workspaceID или, без него,
+// личных строк пользователя и аргумент условия, который должен стать $1. Подходит для любой
+// таблицы с колонками user_id и workspace_id: транзакций, правил, журнала аудита.
+// prefix — псевдоним таблицы вместе с точкой или пустая строка
+func workspaceScope(userID string, workspaceID *string, prefix string) (string, interface{}) {
+	if workspaceID != nil {
+		return prefix + "workspace_id = $1", *workspaceID
+	}
+	return prefix + "user_id = $1 AND " + prefix + "workspace_id IS NULL", userID
+}
+
+func (r *postgresTransactionRepository) SumByCategory(ctx context.Context, filter model.TransactionFilter) ([]*model.CategoryTotal, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "t.")
+	// Разбитая транзакция учитывается по строкам разбивки, остальные — по своей категории
+	query := `
+		SELECT COALESCE(s.category_id, t.category_id) AS category_id,
+		       SUM(COALESCE(s.amount, t.amount)),
+		       COUNT(DISTINCT t.id)
+		FROM transactions t
+		LEFT JOIN transaction_splits s ON s.transaction_id = t.id
+		WHERE t.deleted_at IS NULL AND ` + scope + `
+	`
+
+	args := []interface{}{scopeArg}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND t.date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND t.date <= $" + strconv.Itoa(len(args))
+	}
+
+	query += " GROUP BY COALESCE(s.category_id, t.category_id)"
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.CategoryTotal
+	for rows.Next() {
+		total := &model.CategoryTotal{}
+		if err := rows.Scan(&total.CategoryID, &total.Total, &total.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+func (r *postgresTransactionRepository) SumByDay(ctx context.Context, filter model.TransactionFilter) ([]*model.DailyTotal, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "t.")
+	// Разбитая транзакция учитывается по строкам разбивки, как в SumByCategory
+	query := `
+		SELECT (t.date AT TIME ZONE 'UTC')::date,
+		       COALESCE(s.category_id, t.category_id),
+		       SUM(COALESCE(s.amount, t.amount))
+		FROM transactions t
+		LEFT JOIN transaction_splits s ON s.transaction_id = t.id
+		WHERE t.deleted_at IS NULL AND ` + scope + `
+	`
+
+	args := []interface{}{scopeArg}
+
+	if filter.FromDate != nil {
+		args = append(args, *filter.FromDate)
+		query += " AND t.date >= $" + strconv.Itoa(len(args))
+	}
+
+	if filter.ToDate != nil {
+		args = append(args, *filter.ToDate)
+		query += " AND t.date <= $" + strconv.Itoa(len(args))
+	}
+
+	query += " GROUP BY 1, 2 ORDER BY 1"
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*model.DailyTotal
+	for rows.Next() {
+		total := &model.DailyTotal{}
+		if err := rows.Scan(&total.Date, &total.CategoryID, &total.Total); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+func (r *postgresTransactionRepository) CreateMany(ctx context.Context, txs []*model.Transaction) error {
+	rows := make([][]any, len(txs))
+	for i, tx := range txs {
+		rows[i] = []any{
+			tx.ID, tx.UserID, tx.Amount, tx.Currency, tx.Description, tx.Date,
+			tx.PlaceName, tx.PlaceLat, tx.PlaceLon, tx.CategoryID, tx.IsConfirmed,
+			tx.CreatedAt, tx.UpdatedAt, tx.MerchantID, tx.WorkspaceID,
+		}
+	}
+
+	_, err := r.db(ctx).CopyFrom(ctx,
+		pgx.Identifier{"transactions"},
+		[]string{
+			"id", "user_id", "amount", "currency", "description", "date",
+			"place_name", "place_lat", "place_lon", "category_id", "is_confirmed",
+			"created_at", "updated_at", "merchant_id", "workspace_id",
+		},
+		pgx.CopyFromRows(rows),
+	)
+	return err
+}
+
+func (r *postgresTransactionRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Transaction, error) {
+	query := `
+		SELECT id, user_id, amount, currency, description, date,
+		       place_name, place_lat, place_lon, category_id, is_confirmed, merchant_id,
+		       created_at, updated_at, workspace_id
+		FROM transactions
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*model.Transaction
+	for rows.Next() {
+		tx := &model.Transaction{}
+		err := rows.Scan(
+			&tx.ID,
+			&tx.UserID,
+			&tx.Amount,
+			&tx.Currency,
+			&tx.Description,
+			&tx.Date,
+			&tx.PlaceName,
+			&tx.PlaceLat,
+			&tx.PlaceLon,
+			&tx.CategoryID,
+			&tx.IsConfirmed,
+			&tx.MerchantID,
+			&tx.CreatedAt,
+			&tx.UpdatedAt,
+			&tx.WorkspaceID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *postgresTransactionRepository) GetByIDsWithDeleted(ctx context.Context, ids []string) ([]*model.Transaction, error) {
+	query := `
+		SELECT ` + deletedTransactionColumns + `
+		FROM transactions
+		WHERE id = ANY($1::uuid[])
+	`
+	return r.queryWithDeleted(ctx, query, ids)
+}
+
+func (r *postgresTransactionRepository) GetDeleted(ctx context.Context, userID string, workspaceID *string, limit, offset int) ([]*model.Transaction, error) {
+	scope, scopeArg := workspaceScope(userID, workspaceID, "")
+	query := `
+		SELECT ` + deletedTransactionColumns + `
+		FROM transactions
+		WHERE ` + scope + ` AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+	return r.queryWithDeleted(ctx, query, scopeArg, limit, offset)
+}
+
+func (r *postgresTransactionRepository) CountDeleted(ctx context.Context, userID string, workspaceID *string) (int64, error) {
+	scope, scopeArg := workspaceScope(userID, workspaceID, "")
+	query := `SELECT COUNT(*) FROM transactions WHERE ` + scope + ` AND deleted_at IS NOT NULL`
+	var count int64
+	err := r.db(ctx).QueryRow(ctx, query, scopeArg).Scan(&count)
+	return count, err
+}
+
+func (r *postgresTransactionRepository) Restore(ctx context.Context, id string) error {
+	query := `UPDATE transactions SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	tag, err := r.db(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresTransactionRepository) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	// Разбивка, метки и вложения удаляются каскадно, файлы вложений ставятся в очередь удаления триггером
+	query := `
+		DELETE FROM transactions
+		WHERE id IN (
+			SELECT id FROM transactions
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+		)
+	`
+	tag, err := r.db(ctx).Exec(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// deletedTransactionColumns колонки транзакции вместе с отметкой удаления
+const deletedTransactionColumns = `id, user_id, amount, currency, description, date,
+		       place_name, place_lat, place_lon, category_id, is_confirmed, merchant_id,
+		       created_at, updated_at, deleted_at, workspace_id`
+
+// queryWithDeleted выполняет запрос по колонкам deletedTransactionColumns
+func (r *postgresTransactionRepository) queryWithDeleted(ctx context.Context, query string, args ...any) ([]*model.Transaction, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*model.Transaction
+	for rows.Next() {
+		tx := &model.Transaction{}
+		err := rows.Scan(
+			&tx.ID,
+			&tx.UserID,
+			&tx.Amount,
+			&tx.Currency,
+			&tx.Description,
+			&tx.Date,
+			&tx.PlaceName,
+			&tx.PlaceLat,
+			&tx.PlaceLon,
+			&tx.CategoryID,
+			&tx.IsConfirmed,
+			&tx.MerchantID,
+			&tx.CreatedAt,
+			&tx.UpdatedAt,
+			&tx.DeletedAt,
+			&tx.WorkspaceID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, rows.Err()
+}
+
+// Массовые изменения, как и Update, сохраняют прежнее состояние каждой транзакции версией
+
+func (r *postgresTransactionRepository) SetCategory(ctx context.Context, ids []string, categoryID *int) ([]string, error) {
+	query := withRevisions(`id = ANY($1::uuid[]) AND deleted_at IS NULL`) + `
+		UPDATE transactions SET category_id = $2
+		WHERE id IN (SELECT transaction_id FROM previous)
+		RETURNING id
+	`
+	return r.collectIDs(ctx, query, ids, categoryID)
+}
+
+func (r *postgresTransactionRepository) Confirm(ctx context.Context, ids []string) ([]string, error) {
+	query := withRevisions(`id = ANY($1::uuid[]) AND deleted_at IS NULL`) + `
+		UPDATE transactions SET is_confirmed = true
+		WHERE id IN (SELECT transaction_id FROM previous)
+		RETURNING id
+	`
+	return r.collectIDs(ctx, query, ids)
+}
+
+func (r *postgresTransactionRepository) DeleteMany(ctx context.Context, ids []string) ([]string, error) {
+	query := withRevisions(`id = ANY($1::uuid[]) AND deleted_at IS NULL`) + `
+		UPDATE transactions SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT transaction_id FROM previous)
+		RETURNING id
+	`
+	return r.collectIDs(ctx, query, ids)
+}
+
+// collectIDs выполняет запрос с RETURNING id и возвращает затронутые идентификаторы
+func (r *postgresTransactionRepository) collectIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// historySuggestion подзапрос самой частой подтверждённой категории для описания транзакции t.
+// История берётся из тех же транзакций, что и workspaceScope: пространства t или личных автора t
+const historySuggestion = `
+	SELECT h.category_id
+	FROM transactions h
+	WHERE h.workspace_id IS NOT DISTINCT FROM t.workspace_id AND (t.workspace_id IS NOT NULL OR h.user_id = t.user_id)
+	  AND h.deleted_at IS NULL AND h.is_confirmed AND h.category_id IS NOT NULL
+	  AND lower(h.description) = lower(t.description)
+	GROUP BY h.category_id
+	ORDER BY COUNT(*) DESC, MAX(h.date) DESC
+	LIMIT 1
+`
+
+func (r *postgresTransactionRepository) GetForReview(ctx context.Context, filter model.ReviewFilter) ([]*model.ReviewItem, error) {
+	scope, scopeArg := workspaceScope(filter.UserID, filter.WorkspaceID, "t.")
+	query := `
+		SELECT t.id, t.user_id, t.amount, t.currency, t.description, t.date,
+		       t.place_name, t.place_lat, t.place_lon, t.category_id, t.is_confirmed, t.merchant_id,
+		       t.created_at, t.updated_at, t.workspace_id,
+		       COALESCE(t.category_id, (` + historySuggestion + `))
+		FROM transactions t
+		WHERE ` + scope + ` AND t.deleted_at IS NULL AND NOT t.is_confirmed
+	`
+
+	if filter.Sort == model.ReviewSortAmount {
+		query += " ORDER BY t.amount DESC, t.date DESC"
+	} else {
+		query += " ORDER BY t.date DESC"
+	}
+
+	args := []interface{}{scopeArg}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.ReviewItem
+	for rows.Next() {
+		tx := &model.Transaction{}
+		item := &model.ReviewItem{Transaction: tx}
+		err := rows.Scan(
+			&tx.ID,
+			&tx.UserID,
+			&tx.Amount,
+			&tx.Currency,
+			&tx.Description,
+			&tx.Date,
+			&tx.PlaceName,
+			&tx.PlaceLat,
+			&tx.PlaceLon,
+			&tx.CategoryID,
+			&tx.IsConfirmed,
+			&tx.MerchantID,
+			&tx.CreatedAt,
+			&tx.UpdatedAt,
+			&tx.WorkspaceID,
+			&item.SuggestedCategoryID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *postgresTransactionRepository) CountForReview(ctx context.Context, userID string, workspaceID *string) (*model.ReviewCounts, error) {
+	scope, scopeArg := workspaceScope(userID, workspaceID, "")
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE category_id IS NULL)
+		FROM transactions
+		WHERE ` + scope + ` AND deleted_at IS NULL AND NOT is_confirmed
+	`
+
+	counts := &model.ReviewCounts{}
+	err := r.db(ctx).QueryRow(ctx, query, scopeArg).Scan(&counts.Unconfirmed, &counts.Uncategorized)
+	return counts, err
+}
+
+func (r *postgresTransactionRepository) SuggestCategory(ctx context.Context, tx *model.Transaction) (*int, error) {
+	query := `
+		SELECT (` + historySuggestion + `)
+		FROM (SELECT $1::uuid AS user_id, $2::uuid AS workspace_id, $3::text AS description) t
+	`
+
+	var categoryID *int
+	err := r.db(ctx).QueryRow(ctx, query, tx.UserID, tx.WorkspaceID, tx.Description).Scan(&categoryID)
+	return categoryID, err
+}
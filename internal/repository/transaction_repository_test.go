@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestTransactionFilterWhere(t *testing.T) {
+	merchantID := "merchant-1"
+	where, args := transactionFilterWhere(model.TransactionFilter{
+		UserID:     "user-1",
+		MerchantID: &merchantID,
+		TagIDs:     []string{"tag-1", "tag-2"},
+		Bounds:     &model.GeoBounds{MinLat: 55, MaxLat: 56, MinLon: 37, MaxLon: 38},
+		Limit:      20,
+		Offset:     40,
+	})
+
+	// Все фильтры списка попадают в условие, а страница — нет: по нему же считается total
+	for _, part := range []string{"user_id = $1", "merchant_id = $2", "tag_id = ANY($3::uuid[])", "HAVING COUNT(*) = $4", "place_lat BETWEEN $5 AND $6"} {
+		if !strings.Contains(where, part) {
+			t.Errorf("условие не содержит %q: %s", part, where)
+		}
+	}
+	if strings.Contains(where, "LIMIT") || strings.Contains(where, "OFFSET") {
+		t.Errorf("условие содержит страницу: %s", where)
+	}
+	if len(args) != 8 || !strings.Contains(where, "$"+strconv.Itoa(len(args))) {
+		t.Errorf("аргументов %d, условие %s", len(args), where)
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrNotFound     = errors.New("not found")
+)
 
 type postgresUserRepository struct {
 	pool *pgxpool.Pool
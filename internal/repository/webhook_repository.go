@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	webhookColumns  = `id, user_id, url, secret, events, active, created_at, updated_at`
+	deliveryColumns = `id, webhook_id, user_id, event_id, event, payload, status, attempts, next_attempt_at,
+		response_status, response_body, duration_ms, last_error, redelivery_of, created_at, delivered_at`
+)
+
+type postgresWebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWebhookRepository(pool *pgxpool.Pool) repository.WebhookRepository {
+	return &postgresWebhookRepository{pool: pool}
+}
+
+func (r *postgresWebhookRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanWebhook(row pgx.Row) (*model.Webhook, error) {
+	webhook := &model.Webhook{}
+	var events []string
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Secret,
+		&events,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	webhook.Events = make([]model.WebhookEvent, len(events))
+	for i, event := range events {
+		webhook.Events[i] = model.WebhookEvent(event)
+	}
+	return webhook, err
+}
+
+func webhookEvents(events []model.WebhookEvent) []string {
+	values := make([]string, len(events))
+	for i, event := range events {
+		values[i] = string(event)
+	}
+	return values
+}
+
+func (r *postgresWebhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]*model.Webhook, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *postgresWebhookRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY created_at`
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+func (r *postgresWebhookRepository) GetSubscribed(ctx context.Context, userID string, event model.WebhookEvent) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 AND active AND $2 = ANY(events)`
+	return r.queryWebhooks(ctx, query, userID, string(event))
+}
+
+func (r *postgresWebhookRepository) GetByID(ctx context.Context, id string) (*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	webhook, err := scanWebhook(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (r *postgresWebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, webhookEvents(webhook.Events), webhook.Active,
+	).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func (r *postgresWebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		UPDATE webhooks SET url = $2, events = $3, active = $4
+		WHERE id = $1
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db(ctx).QueryRow(ctx, query, webhook.ID, webhook.URL, webhookEvents(webhook.Events), webhook.Active).
+		Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *postgresWebhookRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+func scanDelivery(row pgx.Row) (*model.WebhookDelivery, error) {
+	d := &model.WebhookDelivery{}
+	err := row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.UserID,
+		&d.EventID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.ResponseStatus,
+		&d.ResponseBody,
+		&d.DurationMs,
+		&d.LastError,
+		&d.RedeliveryOf,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	return d, err
+}
+
+func (r *postgresWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]*model.WebhookDelivery, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *postgresWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, user_id, event_id, event, payload, redelivery_of)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, attempts, next_attempt_at, created_at
+	`
+
+	for _, d := range deliveries {
+		err := r.db(ctx).QueryRow(ctx, query, d.WebhookID, d.UserID, d.EventID, d.Event, d.Payload, d.RedeliveryOf).
+			Scan(&d.ID, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresWebhookRepository) GetDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = $1`
+	args := []interface{}{filter.WebhookID}
+
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		query += " AND status = $" + strconv.Itoa(len(args))
+	}
+
+	query += " ORDER BY created_at DESC, id"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	return r.queryDeliveries(ctx, query, args...)
+}
+
+func (r *postgresWebhookRepository) GetDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	d, err := scanDelivery(r.db(ctx).QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (r *postgresWebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	// SKIP LOCKED: строки, которые уже забирает другой экземпляр, пропускаются без ожидания
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP + $2::interval
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	return r.queryDeliveries(ctx, query, limit, lease)
+}
+
+func (r *postgresWebhookRepository) RecordAttempt(ctx context.Context, d *model.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, response_status = $5,
+		    response_body = $6, duration_ms = $7, last_error = $8, delivered_at = $9
+		WHERE id = $1
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus,
+		d.ResponseBody, d.DurationMs, d.LastError, d.DeliveredAt,
+	)
+	return err
+}
+
+func (r *postgresWebhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+
+	tag, err := r.db(ctx).Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
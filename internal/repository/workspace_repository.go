@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrMemberExists пользователь уже состоит в рабочем пространстве
+var ErrMemberExists = errors.New("workspace member already exists")
+
+const workspaceInvitationColumns = `id, workspace_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at`
+
+type postgresWorkspaceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresWorkspaceRepository(pool *pgxpool.Pool) repository.WorkspaceRepository {
+	return &postgresWorkspaceRepository{pool: pool}
+}
+
+func (r *postgresWorkspaceRepository) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func scanWorkspace(row pgx.Row) (*model.Workspace, error) {
+	workspace := &model.Workspace{}
+	err := row.Scan(
+		&workspace.ID,
+		&workspace.Name,
+		&workspace.Role,
+		&workspace.CreatedAt,
+		&workspace.UpdatedAt,
+	)
+	return workspace, err
+}
+
+func scanWorkspaceInvitation(row pgx.Row) (*model.WorkspaceInvitation, error) {
+	invitation := &model.WorkspaceInvitation{}
+	var invitedBy *string
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.WorkspaceID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.CreatedAt,
+	)
+	// Пригласивший мог удалить аккаунт
+	if invitedBy != nil {
+		invitation.InvitedBy = *invitedBy
+	}
+	return invitation, err
+}
+
+func (r *postgresWorkspaceRepository) Create(ctx context.Context, workspace *model.Workspace) error {
+	query := `
+		INSERT INTO workspaces (id, name)
+		VALUES ($1, $2)
+		RETURNING created_at, updated_at
+	`
+
+	workspace.ID = uuid.New().String()
+
+	return r.db(ctx).QueryRow(ctx, query, workspace.ID, workspace.Name).Scan(&workspace.CreatedAt, &workspace.UpdatedAt)
+}
+
+func (r *postgresWorkspaceRepository) GetByID(ctx context.Context, id, userID string) (*model.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, m.role, w.created_at, w.updated_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id AND m.user_id = $2
+		WHERE w.id = $1
+	`
+
+	workspace, err := scanWorkspace(r.db(ctx).QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+func (r *postgresWorkspaceRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Workspace, error) {
+	query := `
+		SELECT w.id, w.name, m.role, w.created_at, w.updated_at
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.name, w.id
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*model.Workspace
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	return workspaces, rows.Err()
+}
+
+func (r *postgresWorkspaceRepository) Update(ctx context.Context, workspace *model.Workspace) error {
+	query := `UPDATE workspaces SET name = $2 WHERE id = $1 RETURNING created_at, updated_at`
+
+	err := r.db(ctx).QueryRow(ctx, query, workspace.ID, workspace.Name).Scan(&workspace.CreatedAt, &workspace.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *postgresWorkspaceRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM workspaces WHERE id = $1`
+	_, err := r.db(ctx).Exec(ctx, query, id)
+	return err
+}
+
+func (r *postgresWorkspaceRepository) GetMemberRole(ctx context.Context, workspaceID, userID string) (model.WorkspaceRole, error) {
+	query := `SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+	var role model.WorkspaceRole
+	err := r.db(ctx).QueryRow(ctx, query, workspaceID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return role, err
+}
+
+func (r *postgresWorkspaceRepository) GetMembers(ctx context.Context, workspaceID string) ([]*model.WorkspaceMember, error) {
+	query := `
+		SELECT m.workspace_id, m.user_id, u.email, m.role, m.created_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.role = 'owner' DESC, m.created_at, m.user_id
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*model.WorkspaceMember
+	for rows.Next() {
+		member := &model.WorkspaceMember{}
+		if err := rows.Scan(&member.WorkspaceID, &member.UserID, &member.Email, &member.Role, &member.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *postgresWorkspaceRepository) AddMember(ctx context.Context, member *model.WorkspaceMember) error {
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+		RETURNING created_at
+	`
+
+	err := r.db(ctx).QueryRow(ctx, query, member.WorkspaceID, member.UserID, member.Role).Scan(&member.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrMemberExists
+	}
+	return err
+}
+
+func (r *postgresWorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.WorkspaceRole) error {
+	query := `UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`
+
+	tag, err := r.db(ctx).Exec(ctx, query, workspaceID, userID, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	query := `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+	tag, err := r.db(ctx).Exec(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresWorkspaceRepository) CreateInvitation(ctx context.Context, invitation *model.WorkspaceInvitation) error {
+	query := `
+		INSERT INTO workspace_invitations (id, workspace_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	invitation.ID = uuid.New().String()
+
+	return r.db(ctx).QueryRow(ctx, query,
+		invitation.ID,
+		invitation.WorkspaceID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	).Scan(&invitation.CreatedAt)
+}
+
+func (r *postgresWorkspaceRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*model.WorkspaceInvitation, error) {
+	query := `SELECT ` + workspaceInvitationColumns + ` FROM workspace_invitations WHERE token_hash = $1`
+
+	invitation, err := scanWorkspaceInvitation(r.db(ctx).QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (r *postgresWorkspaceRepository) GetPendingInvitations(ctx context.Context, workspaceID string) ([]*model.WorkspaceInvitation, error) {
+	query := `
+		SELECT ` + workspaceInvitationColumns + `
+		FROM workspace_invitations
+		WHERE workspace_id = $1 AND accepted_at IS NULL
+		ORDER BY created_at DESC, id
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*model.WorkspaceInvitation
+	for rows.Next() {
+		invitation, err := scanWorkspaceInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (r *postgresWorkspaceRepository) AcceptInvitation(ctx context.Context, id string) error {
+	query := `UPDATE workspace_invitations SET accepted_at = CURRENT_TIMESTAMP WHERE id = $1 AND accepted_at IS NULL`
+
+	tag, err := r.db(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresWorkspaceRepository) DeleteInvitation(ctx context.Context, workspaceID, id string) error {
+	query := `DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND accepted_at IS NULL`
+
+	tag, err := r.db(ctx).Exec(ctx, query, id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+)
+
+type AnalyticsService interface {
+	// Возвращает расходы по категориям верхнего уровня, подкатегории сворачиваются в родителя.
+	// С filter.WorkspaceID считаются транзакции рабочего пространства
+	SpendingByCategory(ctx context.Context, filter model.TransactionFilter, locale string) ([]*model.CategorySpending, error)
+
+	// Возвращает суммы транзакций по меткам. Транзакция с несколькими метками учитывается в каждой
+	SpendingByTag(ctx context.Context, filter model.TransactionFilter) ([]*model.TagTotal, error)
+
+	// Возвращает суммы транзакций по продавцам, транзакции без продавца идут последней группой
+	SpendingByMerchant(ctx context.Context, filter model.TransactionFilter) ([]*model.MerchantTotal, error)
+
+	// Возвращает суммы транзакций с координатами по местам или ячейкам сетки, крупные группы первыми
+	SpendingByPlace(ctx context.Context, filter model.PlaceFilter) ([]*model.PlaceTotal, error)
+
+	// Прогнозирует доходы, расходы по категориям и изменение баланса на months месяцев
+	// начиная со следующего календарного месяца
+	Forecast(ctx context.Context, userID string, months int, locale string) (*model.Forecast, error)
+}
+
+const (
+	// DefaultPlaceCellSize сторона ячейки сетки по умолчанию, около 1 км по широте
+	DefaultPlaceCellSize = 0.01
+	// maxPlaceGroups ограничивает число точек на карте расходов
+	maxPlaceGroups = 1000
+)
+
+type analyticsServiceImpl struct {
+	txRepo       repository.TransactionRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	merchantRepo repository.MerchantRepository
+	policy       AccessPolicy
+	now          func() time.Time
+}
+
+func NewAnalyticsService(
+	txRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	merchantRepo repository.MerchantRepository,
+	policy AccessPolicy,
+) AnalyticsService {
+	return &analyticsServiceImpl{
+		txRepo:       txRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		merchantRepo: merchantRepo,
+		policy:       policy,
+		now:          time.Now,
+	}
+}
+
+func (s *analyticsServiceImpl) SpendingByTag(ctx context.Context, filter model.TransactionFilter) ([]*model.TagTotal, error) {
+	return s.tagRepo.SumByTag(ctx, filter)
+}
+
+func (s *analyticsServiceImpl) SpendingByMerchant(ctx context.Context, filter model.TransactionFilter) ([]*model.MerchantTotal, error) {
+	return s.merchantRepo.SumByMerchant(ctx, filter)
+}
+
+func (s *analyticsServiceImpl) SpendingByPlace(ctx context.Context, filter model.PlaceFilter) ([]*model.PlaceTotal, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = model.PlaceGroupingPlace
+	}
+	if filter.GroupBy == model.PlaceGroupingGrid && filter.CellSize <= 0 {
+		filter.CellSize = DefaultPlaceCellSize
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPlaceGroups {
+		filter.Limit = maxPlaceGroups
+	}
+	return s.txRepo.SumByPlace(ctx, filter)
+}
+
+func (s *analyticsServiceImpl) Forecast(ctx context.Context, userID string, months int, locale string) (*model.Forecast, error) {
+	months = min(max(months, 1), MaxForecastMonths)
+
+	now := s.now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	historyFrom := thisMonth.AddDate(0, -forecastHistoryMonths, 0)
+	// Текущий месяц ещё не закончился и в историю не входит
+	historyTo := thisMonth.Add(-time.Microsecond)
+
+	totals, err := s.txRepo.SumByDay(ctx, model.TransactionFilter{UserID: userID, FromDate: &historyFrom, ToDate: &historyTo})
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, s.categoryRepo, locale, categories...); err != nil {
+		return nil, err
+	}
+
+	return buildForecast(totals, categories, historyFrom, thisMonth, thisMonth.AddDate(0, 1, 0), months), nil
+}
+
+func (s *analyticsServiceImpl) SpendingByCategory(ctx context.Context, filter model.TransactionFilter, locale string) ([]*model.CategorySpending, error) {
+	if filter.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, filter.UserID, *filter.WorkspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+
+	totals, err := s.txRepo.SumByCategory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// В пространстве транзакции участников отнесены и к их личным категориям
+	categories, err := visibleCategories(ctx, s.categoryRepo, filter.UserID, filter.WorkspaceID, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return rollupCategoryTotals(totals, categories), nil
+}
+
+// rollupCategoryTotals сворачивает суммы подкатегорий в категории верхнего уровня.
+// Транзакции без категории попадают в отдельную группу с CategoryID == nil
+func rollupCategoryTotals(totals []*model.CategoryTotal, categories []*model.Category) []*model.CategorySpending {
+	byID := make(map[int]*model.Category, len(categories))
+	for _, cat := range categories {
+		byID[cat.ID] = cat
+	}
+
+	roots := make(map[int]*model.CategorySpending)
+	var uncategorized *model.CategorySpending
+
+	for _, total := range totals {
+		var cat *model.Category
+		if total.CategoryID != nil {
+			cat = byID[*total.CategoryID]
+		}
+
+		if cat == nil {
+			if uncategorized == nil {
+				uncategorized = &model.CategorySpending{}
+			}
+			uncategorized.Total += total.Total
+			uncategorized.Count += total.Count
+			continue
+		}
+
+		rootCat := cat
+		if cat.ParentID != nil {
+			if parent, ok := byID[*cat.ParentID]; ok {
+				rootCat = parent
+			}
+		}
+
+		root, ok := roots[rootCat.ID]
+		if !ok {
+			id := rootCat.ID
+			root = &model.CategorySpending{CategoryID: &id, Name: rootCat.Name}
+			roots[rootCat.ID] = root
+		}
+		root.Total += total.Total
+		root.Count += total.Count
+
+		if rootCat != cat {
+			id := cat.ID
+			root.Subcategories = append(root.Subcategories, &model.CategorySpending{
+				CategoryID: &id,
+				Name:       cat.Name,
+				Total:      total.Total,
+				Count:      total.Count,
+			})
+		}
+	}
+
+	result := make([]*model.CategorySpending, 0, len(roots)+1)
+	for _, root := range roots {
+		sortSpending(root.Subcategories)
+		result = append(result, root)
+	}
+	sortSpending(result)
+
+	if uncategorized != nil {
+		result = append(result, uncategorized)
+	}
+
+	return result
+}
+
+// sortSpending сортирует по убыванию суммы, при равенстве по названию
+func sortSpending(items []*model.CategorySpending) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestRollupCategoryTotals(t *testing.T) {
+	userID := "user-1"
+	categories := []*model.Category{
+		{ID: 1, Name: "Транспорт", IsDefault: true},
+		{ID: 2, Name: "Продукты", IsDefault: true},
+		{ID: 100, Name: "Такси", UserID: &userID, ParentID: intPtr(1)},
+		{ID: 101, Name: "Метро", UserID: &userID, ParentID: intPtr(1)},
+	}
+	totals := []*model.CategoryTotal{
+		{CategoryID: intPtr(1), Total: 100, Count: 1},
+		{CategoryID: intPtr(100), Total: 500, Count: 2},
+		{CategoryID: intPtr(101), Total: 50, Count: 1},
+		{CategoryID: intPtr(2), Total: 300, Count: 3},
+		{CategoryID: nil, Total: 70, Count: 1},
+	}
+
+	result := rollupCategoryTotals(totals, categories)
+
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(result))
+	}
+
+	transport := result[0]
+	if transport.Name != "Транспорт" {
+		t.Fatalf("Expected Транспорт first, got %s", transport.Name)
+	}
+	if transport.Total != 650 {
+		t.Errorf("Expected Транспорт total 650, got %v", transport.Total)
+	}
+	if transport.Count != 4 {
+		t.Errorf("Expected Транспорт count 4, got %d", transport.Count)
+	}
+	if len(transport.Subcategories) != 2 || transport.Subcategories[0].Name != "Такси" {
+		t.Errorf("Expected subcategories Такси and Метро, got %+v", transport.Subcategories)
+	}
+
+	if result[1].Name != "Продукты" || result[1].Total != 300 {
+		t.Errorf("Expected Продукты with 300, got %s with %v", result[1].Name, result[1].Total)
+	}
+
+	if result[2].CategoryID != nil || result[2].Total != 70 {
+		t.Errorf("Expected uncategorized group last with 70, got %+v", result[2])
+	}
+}
+
+// placeTxRepository запоминает фильтр карты расходов
+type placeTxRepository struct {
+	mockTransactionRepository
+	filter model.PlaceFilter
+}
+
+func (m *placeTxRepository) SumByPlace(ctx context.Context, filter model.PlaceFilter) ([]*model.PlaceTotal, error) {
+	m.filter = filter
+	return nil, nil
+}
+
+func TestAnalyticsService_SpendingByPlace(t *testing.T) {
+	txRepo := &placeTxRepository{}
+	svc := NewAnalyticsService(txRepo, &mockCategoryRepository{}, &mockTagRepository{}, &mockMerchantRepository{}, NewAccessPolicy(&mockWorkspaceRepository{}))
+	ctx := context.Background()
+
+	if _, err := svc.SpendingByPlace(ctx, model.PlaceFilter{}); err != nil {
+		t.Fatalf("SpendingByPlace: %v", err)
+	}
+	if txRepo.filter.GroupBy != model.PlaceGroupingPlace || txRepo.filter.Limit != maxPlaceGroups {
+		t.Errorf("Unexpected defaults %+v", txRepo.filter)
+	}
+
+	svc.SpendingByPlace(ctx, model.PlaceFilter{GroupBy: model.PlaceGroupingGrid, TransactionFilter: model.TransactionFilter{Limit: 50}})
+	if txRepo.filter.CellSize != DefaultPlaceCellSize || txRepo.filter.Limit != 50 {
+		t.Errorf("Unexpected grid filter %+v", txRepo.filter)
+	}
+}
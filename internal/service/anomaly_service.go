@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+	"github.com/google/uuid"
+)
+
+var ErrAnomalyNotFound = apperror.New(apperror.KindNotFound, "anomaly_not_found")
+
+const (
+	// anomalySampleSize сколько последних транзакций берётся для оценки обычной суммы
+	anomalySampleSize = 200
+	// minAnomalySamples меньше этого числа транзакций обычную сумму не оценить
+	minAnomalySamples = 5
+	// minNewMerchantSamples сколько транзакций пользователя нужно, чтобы оценить крупную трату у нового продавца
+	minNewMerchantSamples = 10
+	// anomalyRatio во сколько раз сумма должна превышать медиану
+	anomalyRatio = 3.0
+	// anomalyRobustZ на сколько масштабированных медианных отклонений сумма должна превышать медиану
+	anomalyRobustZ = 5.0
+	// spikeRatio во сколько раз расходы категории за месяц должны превышать среднее
+	spikeRatio = 1.5
+	// spikeMonths за сколько предыдущих месяцев считается среднее, minSpikeMonths — сколько из них с расходами
+	spikeMonths    = 3
+	minSpikeMonths = 2
+)
+
+type AnomalyService interface {
+	// Проверяет транзакцию и сохраняет найденные аномалии. Возвращает только новые
+	Check(ctx context.Context, tx *model.Transaction) ([]*model.Anomaly, error)
+
+	// Проверяет транзакции всех пользователей, созданные начиная с since.
+	// Возвращает число новых аномалий
+	Scan(ctx context.Context, since time.Time) (int, error)
+
+	// Подписывается на создание транзакций: новая транзакция проверяется в фоне после фиксации
+	Subscribe(bus *eventbus.Bus)
+
+	// Периодически проверяет новые транзакции, пока не отменён ctx.
+	// Нужен для транзакций, событие о которых потерялось, например при перезапуске сервера
+	RunDetection(ctx context.Context, interval time.Duration)
+
+	// Возвращает аномалии пользователя с названиями категорий на языке locale
+	GetAll(ctx context.Context, filter model.AnomalyFilter, locale string) ([]*model.Anomaly, error)
+
+	// Отмечает аномалию просмотренной
+	Dismiss(ctx context.Context, userID, id string) error
+}
+
+type anomalyServiceImpl struct {
+	anomalyRepo   repository.AnomalyRepository
+	categoryRepo  repository.CategoryRepository
+	notifications NotificationService
+	transactor    repository.Transactor
+}
+
+func NewAnomalyService(
+	anomalyRepo repository.AnomalyRepository,
+	categoryRepo repository.CategoryRepository,
+	notifications NotificationService,
+	transactor repository.Transactor,
+) AnomalyService {
+	return &anomalyServiceImpl{
+		anomalyRepo:   anomalyRepo,
+		categoryRepo:  categoryRepo,
+		notifications: notifications,
+		transactor:    transactor,
+	}
+}
+
+func (s *anomalyServiceImpl) Check(ctx context.Context, tx *model.Transaction) ([]*model.Anomaly, error) {
+	candidates, err := s.detect(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Уведомление ставится в очередь вместе с аномалией, чтобы не потерять ни одно из них
+	var created []*model.Anomaly
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		created = nil
+		for _, anomaly := range candidates {
+			ok, err := s.anomalyRepo.Create(ctx, anomaly)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			created = append(created, anomaly)
+
+			id := anomaly.ID
+			err = s.notifications.Notify(ctx, anomaly.UserID, model.NotificationAnomalyDetected, func(ctx context.Context, locale string) (*model.NotificationContent, error) {
+				return s.anomalyNotification(ctx, id, locale)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// anomalyNotification уведомление о новой аномалии на языке locale
+func (s *anomalyServiceImpl) anomalyNotification(ctx context.Context, id, locale string) (*model.NotificationContent, error) {
+	// Аномалия перечитывается ради названий категории и продавца
+	anomaly, err := s.anomalyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translateCategories(ctx, locale, anomaly); err != nil {
+		return nil, err
+	}
+
+	return &model.NotificationContent{
+		Subject: i18n.T(ctx, "notification.anomaly_detected"),
+		Text:    AnomalyExplanation(ctx, anomaly),
+		Data: anomalyNotificationData{
+			ID:            anomaly.ID,
+			Kind:          anomaly.Kind,
+			TransactionID: anomaly.TransactionID,
+			CategoryID:    anomaly.CategoryID,
+			MerchantID:    anomaly.MerchantID,
+			Amount:        anomaly.Amount,
+			Baseline:      anomaly.Baseline,
+			Ratio:         anomaly.Ratio,
+			Currency:      anomaly.Currency,
+		},
+	}, nil
+}
+
+// anomalyNotificationData данные аномалии в вебхуке
+type anomalyNotificationData struct {
+	ID            string            `json:"id"`
+	Kind          model.AnomalyKind `json:"kind"`
+	TransactionID *string           `json:"transaction_id,omitempty"`
+	CategoryID    *int              `json:"category_id,omitempty"`
+	MerchantID    *string           `json:"merchant_id,omitempty"`
+	Amount        float64           `json:"amount"`
+	Baseline      float64           `json:"baseline"`
+	Ratio         float64           `json:"ratio"`
+	Currency      string            `json:"currency"`
+}
+
+// detect ищет аномалии транзакции: крупную сумму для продавца или категории,
+// крупную первую трату у продавца и всплеск расходов категории за месяц транзакции
+func (s *anomalyServiceImpl) detect(ctx context.Context, tx *model.Transaction) ([]*model.Anomaly, error) {
+	var found []*model.Anomaly
+	yearAgo := tx.Date.AddDate(-1, 0, 0)
+	// Транзакция сравнивается с транзакциями того же пространства или личными транзакциями автора
+	sample := model.AmountSample{UserID: tx.UserID, WorkspaceID: tx.WorkspaceID, Currency: tx.Currency, ExcludeID: tx.ID, Limit: anomalySampleSize}
+
+	flagged := false
+	if tx.MerchantID != nil {
+		merchantSample := sample
+		merchantSample.MerchantID = tx.MerchantID
+		amounts, err := s.anomalyRepo.GetAmounts(ctx, merchantSample)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(amounts) == 0 {
+			userSample := sample
+			userSample.Since = yearAgo
+			amounts, err := s.anomalyRepo.GetAmounts(ctx, userSample)
+			if err != nil {
+				return nil, err
+			}
+			if len(amounts) >= minNewMerchantSamples {
+				if typical := median(amounts); typical > 0 && tx.Amount >= anomalyRatio*typical {
+					found = append(found, transactionAnomaly(tx, model.AnomalyNewMerchant, typical))
+					flagged = true
+				}
+			}
+		} else if typical, ok := unusualAmount(tx.Amount, amounts); ok {
+			found = append(found, transactionAnomaly(tx, model.AnomalyMerchantAmount, typical))
+			flagged = true
+		}
+	}
+
+	if tx.CategoryID == nil {
+		return found, nil
+	}
+
+	if !flagged {
+		categorySample := sample
+		categorySample.CategoryID = tx.CategoryID
+		categorySample.Since = yearAgo
+		amounts, err := s.anomalyRepo.GetAmounts(ctx, categorySample)
+		if err != nil {
+			return nil, err
+		}
+		if typical, ok := unusualAmount(tx.Amount, amounts); ok {
+			found = append(found, transactionAnomaly(tx, model.AnomalyCategoryAmount, typical))
+		}
+	}
+
+	date := tx.Date.UTC()
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	totals, err := s.anomalyRepo.SumCategoryByMonth(ctx, tx.UserID, tx.WorkspaceID, *tx.CategoryID, tx.Currency, month.AddDate(0, -spikeMonths, 0), month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+	if current, average, ok := categorySpike(totals, month); ok {
+		categoryID := *tx.CategoryID
+		found = append(found, &model.Anomaly{
+			UserID:     tx.UserID,
+			Kind:       model.AnomalyCategorySpike,
+			CategoryID: &categoryID,
+			Period:     &month,
+			Amount:     roundCents(current),
+			Baseline:   roundCents(average),
+			Ratio:      anomalyRatioOf(current, average),
+			Currency:   tx.Currency,
+		})
+	}
+
+	return found, nil
+}
+
+func (s *anomalyServiceImpl) Subscribe(bus *eventbus.Bus) {
+	bus.SubscribeAsync(event.TransactionCreatedName, func(ctx context.Context, e eventbus.Event) error {
+		tx := e.(event.TransactionCreated).Transaction
+		if _, err := s.Check(ctx, tx); err != nil {
+			return fmt.Errorf("anomalies: check of transaction %s: %w", tx.ID, err)
+		}
+		return nil
+	})
+}
+
+func (s *anomalyServiceImpl) Scan(ctx context.Context, since time.Time) (int, error) {
+	txs, err := s.anomalyRepo.GetCreatedSince(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, tx := range txs {
+		anomalies, err := s.Check(ctx, tx)
+		created += len(anomalies)
+		if err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+func (s *anomalyServiceImpl) RunDetection(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now().Add(-interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			started := time.Now()
+			if _, err := s.Scan(ctx, since); err != nil {
+				if ctx.Err() == nil {
+					log.Printf("anomalies: scan failed: %v", err)
+				}
+				continue
+			}
+			since = started
+		}
+	}
+}
+
+func (s *anomalyServiceImpl) GetAll(ctx context.Context, filter model.AnomalyFilter, locale string) ([]*model.Anomaly, error) {
+	anomalies, err := s.anomalyRepo.GetByUserID(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translateCategories(ctx, locale, anomalies...); err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+// translateCategories переводит названия системных категорий: для них репозиторий возвращает ключ перевода
+func (s *anomalyServiceImpl) translateCategories(ctx context.Context, locale string, anomalies ...*model.Anomaly) error {
+	translations, err := s.categoryRepo.GetTranslations(ctx, locale)
+	if err != nil {
+		return err
+	}
+	for _, anomaly := range anomalies {
+		if anomaly.CategoryName == nil {
+			continue
+		}
+		if name, ok := translations[*anomaly.CategoryName]; ok {
+			anomaly.CategoryName = &name
+		}
+	}
+	return nil
+}
+
+func (s *anomalyServiceImpl) Dismiss(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrAnomalyNotFound
+	}
+
+	anomaly, err := s.anomalyRepo.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrAnomalyNotFound)
+	}
+	if anomaly.UserID != userID {
+		return ErrForbidden
+	}
+
+	return notFound(s.anomalyRepo.Dismiss(ctx, id), ErrAnomalyNotFound)
+}
+
+// AnomalyExplanation объясняет аномалию на языке из контекста. Удалённые категория
+// и продавец заменяются нейтральным названием
+func AnomalyExplanation(ctx context.Context, anomaly *model.Anomaly) string {
+	params := map[string]any{
+		"amount":   strconv.FormatFloat(anomaly.Amount, 'f', 2, 64),
+		"baseline": strconv.FormatFloat(anomaly.Baseline, 'f', 2, 64),
+		"ratio":    strconv.FormatFloat(anomaly.Ratio, 'f', -1, 64),
+		"currency": anomaly.Currency,
+		"category": i18n.T(ctx, "anomaly.unknown_category"),
+		"merchant": i18n.T(ctx, "anomaly.unknown_merchant"),
+	}
+	if anomaly.CategoryName != nil {
+		params["category"] = *anomaly.CategoryName
+	}
+	if anomaly.MerchantName != nil {
+		params["merchant"] = *anomaly.MerchantName
+	}
+	if anomaly.Period != nil {
+		params["month"] = anomaly.Period.Format("2006-01")
+	}
+	return i18n.Tf(ctx, "anomaly."+string(anomaly.Kind), params)
+}
+
+func transactionAnomaly(tx *model.Transaction, kind model.AnomalyKind, typical float64) *model.Anomaly {
+	id := tx.ID
+	return &model.Anomaly{
+		UserID:        tx.UserID,
+		Kind:          kind,
+		TransactionID: &id,
+		CategoryID:    tx.CategoryID,
+		MerchantID:    tx.MerchantID,
+		Amount:        tx.Amount,
+		Baseline:      roundCents(typical),
+		Ratio:         anomalyRatioOf(tx.Amount, typical),
+		Currency:      tx.Currency,
+	}
+}
+
+// unusualAmount сравнивает сумму с медианой прошлых сумм. Сумма необычна, если она
+// в anomalyRatio раз больше медианы и выше неё на anomalyRobustZ масштабированных
+// медианных абсолютных отклонений (устойчивый аналог трёх сигм)
+func unusualAmount(amount float64, history []float64) (float64, bool) {
+	if len(history) < minAnomalySamples {
+		return 0, false
+	}
+
+	typical := median(history)
+	if typical <= 0 || amount < anomalyRatio*typical {
+		return typical, false
+	}
+
+	deviations := make([]float64, len(history))
+	for i, v := range history {
+		deviations[i] = math.Abs(v - typical)
+	}
+	// 1.4826 приводит MAD к стандартному отклонению для нормального распределения
+	scale := 1.4826 * median(deviations)
+	if scale > 0 && (amount-typical)/scale < anomalyRobustZ {
+		return typical, false
+	}
+	return typical, true
+}
+
+// categorySpike сравнивает расходы за month со средним за spikeMonths предыдущих месяцев.
+// Месяцы без расходов входят в среднее нулями, но их не должно быть больше допустимого
+func categorySpike(totals []*model.MonthTotal, month time.Time) (float64, float64, bool) {
+	var current, previous float64
+	active := 0
+	for _, total := range totals {
+		switch {
+		case total.Month.Equal(month):
+			current = total.Total
+		case total.Month.Before(month) && total.Total > 0:
+			previous += total.Total
+			active++
+		}
+	}
+
+	if active < minSpikeMonths {
+		return current, 0, false
+	}
+	average := previous / spikeMonths
+	return current, average, current >= spikeRatio*average
+}
+
+func anomalyRatioOf(amount, baseline float64) float64 {
+	if baseline <= 0 {
+		return 0
+	}
+	return math.Round(amount/baseline*100) / 100
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
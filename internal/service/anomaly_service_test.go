@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+)
+
+// stubAnomalyRepository отдаёт заранее заданные суммы и сохраняет аномалии в память
+type stubAnomalyRepository struct {
+	repository.AnomalyRepository
+	merchantAmounts []float64
+	categoryAmounts []float64
+	userAmounts     []float64
+	months          []*model.MonthTotal
+	created         []*model.Anomaly
+}
+
+func (m *stubAnomalyRepository) GetAmounts(ctx context.Context, sample model.AmountSample) ([]float64, error) {
+	switch {
+	case sample.MerchantID != nil:
+		return m.merchantAmounts, nil
+	case sample.CategoryID != nil:
+		return m.categoryAmounts, nil
+	}
+	return m.userAmounts, nil
+}
+
+func (m *stubAnomalyRepository) SumCategoryByMonth(ctx context.Context, userID string, workspaceID *string, categoryID int, currency string, from, to time.Time) ([]*model.MonthTotal, error) {
+	return m.months, nil
+}
+
+func (m *stubAnomalyRepository) Create(ctx context.Context, anomaly *model.Anomaly) (bool, error) {
+	m.created = append(m.created, anomaly)
+	return true, nil
+}
+
+// mockNotificationService запоминает события, о которых просили уведомить
+type mockNotificationService struct {
+	NotificationService
+	events []model.NotificationEvent
+}
+
+func (m *mockNotificationService) Notify(ctx context.Context, userID string, event model.NotificationEvent, render NotificationRenderer) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func TestUnusualAmount(t *testing.T) {
+	history := []float64{500, 450, 600, 520, 480, 550}
+
+	if typical, ok := unusualAmount(5000, history); !ok || typical != 510 {
+		t.Errorf("Expected 5000 to be unusual against median 510, got %v %v", typical, ok)
+	}
+	if _, ok := unusualAmount(1400, history); ok {
+		t.Error("Expected amount below 3x median to be usual")
+	}
+	if _, ok := unusualAmount(5000, history[:4]); ok {
+		t.Error("Expected too short history to be ignored")
+	}
+
+	// Разброс истории большой: втрое выше медианы, но в пределах отклонений
+	wide := []float64{100, 200, 300, 400, 500, 600, 700}
+	if _, ok := unusualAmount(1300, wide); ok {
+		t.Error("Expected amount within the usual spread to be usual")
+	}
+}
+
+func TestCategorySpike(t *testing.T) {
+	month := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	totals := []*model.MonthTotal{
+		{Month: month.AddDate(0, -3, 0), Total: 10000},
+		{Month: month.AddDate(0, -1, 0), Total: 14000},
+		{Month: month, Total: 12000},
+	}
+
+	// Среднее с пропущенным месяцем: (10000 + 0 + 14000) / 3 = 8000
+	current, average, ok := categorySpike(totals, month)
+	if !ok || current != 12000 || average != 8000 {
+		t.Errorf("Expected spike 12000 over 8000, got %v over %v (%v)", current, average, ok)
+	}
+
+	if _, _, ok := categorySpike(totals[1:], month); ok {
+		t.Error("Expected no spike with a single month of history")
+	}
+}
+
+func TestAnomalyService_Check(t *testing.T) {
+	merchantID := "merchant-1"
+	tx := &model.Transaction{
+		ID: ownTxID, UserID: "user-1", Amount: 20000, Currency: "RUB",
+		Date: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), CategoryID: intPtr(1), MerchantID: &merchantID,
+	}
+
+	// Первый платёж продавцу, в шесть раз больше обычного
+	repo := &stubAnomalyRepository{userAmounts: []float64{3000, 2500, 4000, 3500, 3000, 2800, 3200, 3300, 3100, 2900}}
+	notifications := &mockNotificationService{}
+	anomalies, err := NewAnomalyService(repo, &mockCategoryRepository{}, notifications, &mockTransactor{}).Check(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(notifications.events) != 1 || notifications.events[0] != model.NotificationAnomalyDetected {
+		t.Errorf("Expected one anomaly notification, got %v", notifications.events)
+	}
+	if len(anomalies) != 1 || anomalies[0].Kind != model.AnomalyNewMerchant || anomalies[0].Baseline != 3050 || anomalies[0].Ratio != 6.56 {
+		t.Fatalf("Expected a new merchant anomaly, got %+v", anomalies)
+	}
+
+	// Известный продавец: крупная сумма отмечается один раз, по продавцу, и отдельно всплеск категории
+	repo = &stubAnomalyRepository{
+		merchantAmounts: []float64{1000, 1200, 900, 1100, 1000},
+		categoryAmounts: []float64{1000, 1200, 900, 1100, 1000},
+		months: []*model.MonthTotal{
+			{Month: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), Total: 5000},
+			{Month: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Total: 4000},
+			{Month: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Total: 25000},
+		},
+	}
+	anomalies, err = NewAnomalyService(repo, &mockCategoryRepository{}, &mockNotificationService{}, &mockTransactor{}).Check(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(anomalies) != 2 || anomalies[0].Kind != model.AnomalyMerchantAmount || anomalies[1].Kind != model.AnomalyCategorySpike {
+		t.Fatalf("Expected merchant amount and category spike, got %+v", anomalies)
+	}
+	if spike := anomalies[1]; spike.TransactionID != nil || !spike.Period.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || spike.Baseline != 3000 {
+		t.Errorf("Unexpected category spike %+v", spike)
+	}
+}
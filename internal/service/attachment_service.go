@@ -0,0 +1,315 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/blobstore"
+	"github.com/gibbon/finace-dashboard/pkg/thumbnail"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentNotFound = apperror.New(apperror.KindNotFound, "attachment_not_found")
+	ErrThumbnailNotFound  = apperror.New(apperror.KindNotFound, "thumbnail_not_found")
+	ErrAttachmentEmpty    = apperror.New(apperror.KindBadRequest, "attachment_empty")
+	ErrAttachmentTooLarge = apperror.New(apperror.KindPayloadTooLarge, "attachment_too_large")
+	ErrAttachmentType     = apperror.New(apperror.KindUnsupportedMediaType, "unsupported_attachment_type")
+)
+
+// attachmentExtensions допустимые типы вложений и расширения файлов в хранилище.
+// Тип определяется по содержимому, а не по заголовку клиента
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+const (
+	// thumbnailSide размер большей стороны миниатюры в пикселях
+	thumbnailSide = 256
+	// blobCleanupBatch сколько объектов удаляется из хранилища за один проход очистки
+	blobCleanupBatch = 100
+	// maxFileNameLength соответствует длине колонки attachments.file_name
+	maxFileNameLength = 255
+)
+
+// Конфигурация сервиса вложений
+type AttachmentServiceConfig struct {
+	MaxSize int64
+}
+
+type AttachmentService interface {
+	// Сохраняет файл в хранилище и прикрепляет его к транзакции. Для изображений строится миниатюра
+	Upload(ctx context.Context, userID, transactionID, fileName string, data []byte) (*model.Attachment, error)
+
+	// Возвращает вложения транзакции
+	GetByTransactionID(ctx context.Context, userID, transactionID string) ([]*model.Attachment, error)
+
+	// Открывает содержимое вложения или его миниатюры. Вызывающий обязан закрыть поток
+	Open(ctx context.Context, userID, id string, thumb bool) (*model.Attachment, io.ReadCloser, error)
+
+	// Удаляет вложение, файлы удаляются из хранилища асинхронно
+	Delete(ctx context.Context, userID, id string) error
+
+	// Удаляет из хранилища файлы удалённых вложений и возвращает их количество
+	PurgeBlobs(ctx context.Context) (int, error)
+
+	// Периодически вызывает PurgeBlobs до отмены контекста
+	RunBlobCleanup(ctx context.Context, interval time.Duration)
+
+	// Максимальный размер вложения в байтах
+	MaxSize() int64
+}
+
+type attachmentServiceImpl struct {
+	attachmentRepo repository.AttachmentRepository
+	txRepo         repository.TransactionRepository
+	store          blobstore.BlobStore
+	config         AttachmentServiceConfig
+	policy         AccessPolicy
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	txRepo repository.TransactionRepository,
+	store blobstore.BlobStore,
+	config AttachmentServiceConfig,
+	policy AccessPolicy,
+) AttachmentService {
+	return &attachmentServiceImpl{
+		attachmentRepo: attachmentRepo,
+		txRepo:         txRepo,
+		store:          store,
+		config:         config,
+		policy:         policy,
+	}
+}
+
+func (s *attachmentServiceImpl) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+func (s *attachmentServiceImpl) Upload(ctx context.Context, userID, transactionID, fileName string, data []byte) (*model.Attachment, error) {
+	if err := s.authorizeTransaction(ctx, userID, transactionID, model.PermissionWrite); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if int64(len(data)) > s.config.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	ext, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, ErrAttachmentType
+	}
+
+	attachment := &model.Attachment{
+		ID:            uuid.New().String(),
+		TransactionID: transactionID,
+		UserID:        userID,
+		FileName:      cleanFileName(fileName, ext),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+	}
+	prefix := "attachments/" + userID + "/" + attachment.ID
+	attachment.StorageKey = prefix + ext
+
+	if err := s.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	// Миниатюра необязательна: WebP и повреждённые изображения хранятся без неё
+	if strings.HasPrefix(contentType, "image/") {
+		if thumb, err := thumbnail.Generate(data, thumbnailSide); err == nil {
+			key := prefix + "_thumb.jpg"
+			if err := s.store.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), thumbnail.ContentType); err != nil {
+				s.discardBlobs(attachment.StorageKey)
+				return nil, err
+			}
+			attachment.ThumbnailKey = &key
+		}
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		keys := []string{attachment.StorageKey}
+		if attachment.ThumbnailKey != nil {
+			keys = append(keys, *attachment.ThumbnailKey)
+		}
+		s.discardBlobs(keys...)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentServiceImpl) GetByTransactionID(ctx context.Context, userID, transactionID string) ([]*model.Attachment, error) {
+	if err := s.authorizeTransaction(ctx, userID, transactionID, model.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.GetByTransactionID(ctx, transactionID)
+}
+
+func (s *attachmentServiceImpl) Open(ctx context.Context, userID, id string, thumb bool) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.get(ctx, userID, id, model.PermissionRead)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.StorageKey
+	if thumb {
+		if attachment.ThumbnailKey == nil {
+			return nil, nil, ErrThumbnailNotFound
+		}
+		key = *attachment.ThumbnailKey
+	}
+
+	content, err := s.store.Get(ctx, key)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.get(ctx, userID, id, model.PermissionWrite); err != nil {
+		return err
+	}
+	return s.attachmentRepo.Delete(ctx, id)
+}
+
+func (s *attachmentServiceImpl) PurgeBlobs(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		deletions, err := s.attachmentRepo.GetBlobDeletions(ctx, blobCleanupBatch)
+		if err != nil || len(deletions) == 0 {
+			return purged, err
+		}
+
+		done := make([]int64, 0, len(deletions))
+		for _, deletion := range deletions {
+			// Ошибка хранилища не прерывает очистку: объект останется в очереди до следующего прохода
+			if err := s.store.Delete(ctx, deletion.StorageKey); err != nil && !errors.Is(err, blobstore.ErrInvalidKey) {
+				log.Printf("attachments: failed to delete blob %s: %v", deletion.StorageKey, err)
+				continue
+			}
+			done = append(done, deletion.ID)
+		}
+
+		if len(done) == 0 {
+			return purged, nil
+		}
+		if err := s.attachmentRepo.CompleteBlobDeletions(ctx, done); err != nil {
+			return purged, err
+		}
+		purged += len(done)
+
+		if len(deletions) < blobCleanupBatch {
+			return purged, nil
+		}
+	}
+}
+
+func (s *attachmentServiceImpl) RunBlobCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeBlobs(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("attachments: blob cleanup failed: %v", err)
+			}
+		}
+	}
+}
+
+// get загружает вложение и проверяет право пользователя на его транзакцию
+func (s *attachmentServiceImpl) get(ctx context.Context, userID, id string, perm model.Permission) (*model.Attachment, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAttachmentNotFound
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrAttachmentNotFound)
+	}
+
+	tx, err := s.txRepo.GetByID(ctx, attachment.TransactionID)
+	switch {
+	case err == nil:
+		err = s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, perm)
+	case errors.Is(err, repo.ErrNotFound):
+		// Транзакция в корзине: её вложения доступны только автору
+		err = s.policy.Authorize(ctx, userID, attachment.UserID, nil, perm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *attachmentServiceImpl) authorizeTransaction(ctx context.Context, userID, transactionID string, perm model.Permission) error {
+	if _, err := uuid.Parse(transactionID); err != nil {
+		return ErrTransactionNotFound
+	}
+
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return notFound(err, ErrTransactionNotFound)
+	}
+	return s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, perm)
+}
+
+// discardBlobs удаляет уже записанные файлы, если вложение не удалось сохранить.
+// Контекст запроса может быть отменён, поэтому используется собственный
+func (s *attachmentServiceImpl) discardBlobs(keys ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("attachments: failed to discard blob %s: %v", key, err)
+		}
+	}
+}
+
+// cleanFileName оставляет только имя файла без пути и ограничивает длину.
+// Пустое имя заменяется на "attachment" с расширением по типу содержимого
+func cleanFileName(name, ext string) string {
+	name = strings.ToValidUTF8(strings.ReplaceAll(name, "\\", "/"), "_")
+	name = strings.TrimSpace(path.Base(name))
+	if name == "" || name == "." || name == "/" {
+		return "attachment" + ext
+	}
+
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
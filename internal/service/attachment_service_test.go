@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/gibbon/finace-dashboard/pkg/blobstore"
+)
+
+type stubAttachmentRepository struct {
+	repository.AttachmentRepository
+	attachments map[string]*model.Attachment
+	deletions   []*model.BlobDeletion
+	createErr   error
+}
+
+func (m *stubAttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.attachments[attachment.ID] = attachment
+	return nil
+}
+
+func (m *stubAttachmentRepository) GetByID(ctx context.Context, id string) (*model.Attachment, error) {
+	if attachment, ok := m.attachments[id]; ok {
+		return attachment, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// Delete повторяет триггер БД: ключи файлов попадают в очередь удаления
+func (m *stubAttachmentRepository) Delete(ctx context.Context, id string) error {
+	attachment := m.attachments[id]
+	delete(m.attachments, id)
+	m.deletions = append(m.deletions, &model.BlobDeletion{ID: int64(len(m.deletions) + 1), StorageKey: attachment.StorageKey})
+	if attachment.ThumbnailKey != nil {
+		m.deletions = append(m.deletions, &model.BlobDeletion{ID: int64(len(m.deletions) + 1), StorageKey: *attachment.ThumbnailKey})
+	}
+	return nil
+}
+
+func (m *stubAttachmentRepository) GetBlobDeletions(ctx context.Context, limit int) ([]*model.BlobDeletion, error) {
+	return m.deletions, nil
+}
+
+func (m *stubAttachmentRepository) CompleteBlobDeletions(ctx context.Context, ids []int64) error {
+	m.deletions = nil
+	return nil
+}
+
+func newTestAttachmentService(t *testing.T) (AttachmentService, *stubAttachmentRepository, string) {
+	dir := t.TempDir()
+	store, err := blobstore.NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachmentRepo := &stubAttachmentRepository{attachments: make(map[string]*model.Attachment)}
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-1"},
+		&model.Transaction{ID: otherTxID, UserID: "user-2"},
+	)
+	svc := NewAttachmentService(attachmentRepo, txRepo, store, AttachmentServiceConfig{MaxSize: 1 << 20}, NewAccessPolicy(&mockWorkspaceRepository{}))
+	return svc, attachmentRepo, dir
+}
+
+func testPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func countFiles(t *testing.T, dir string) int {
+	count := 0
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	svc, attachmentRepo, dir := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	photo, err := svc.Upload(ctx, "user-1", ownTxID, `C:\photos\receipt.png`, testPNG(t))
+	if err != nil {
+		t.Fatalf("Upload returned error: %v", err)
+	}
+	if photo.ContentType != "image/png" || photo.FileName != "receipt.png" {
+		t.Errorf("Unexpected attachment metadata: %+v", photo)
+	}
+	if photo.ThumbnailKey == nil {
+		t.Fatal("Expected thumbnail for image")
+	}
+
+	_, content, err := svc.Open(ctx, "user-1", photo.ID, true)
+	if err != nil {
+		t.Fatalf("Open thumbnail: %v", err)
+	}
+	thumb, _ := io.ReadAll(content)
+	content.Close()
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(thumb)); err != nil || cfg.Width != 256 || cfg.Height != 192 {
+		t.Errorf("Expected 256x192 thumbnail, got %+v (%v)", cfg, err)
+	}
+
+	pdf, err := svc.Upload(ctx, "user-1", ownTxID, "check.pdf", []byte("%PDF-1.7\n1 0 obj\n"))
+	if err != nil {
+		t.Fatalf("Upload pdf: %v", err)
+	}
+	if pdf.ThumbnailKey != nil {
+		t.Error("PDF must not have a thumbnail")
+	}
+	if _, _, err := svc.Open(ctx, "user-1", pdf.ID, true); !errors.Is(err, ErrThumbnailNotFound) {
+		t.Errorf("Expected ErrThumbnailNotFound, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		txID   string
+		data   []byte
+		want   error
+	}{
+		{"foreign transaction", "user-1", otherTxID, testPNG(t), ErrForbidden},
+		{"missing transaction", "user-1", goneTxID, testPNG(t), ErrTransactionNotFound},
+		{"empty file", "user-1", ownTxID, nil, ErrAttachmentEmpty},
+		{"too large", "user-1", ownTxID, make([]byte, 1<<20+1), ErrAttachmentTooLarge},
+		{"disallowed type", "user-1", ownTxID, []byte("<html><script>alert(1)</script>"), ErrAttachmentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Upload(ctx, tt.userID, tt.txID, "file", tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, _, err := svc.Open(ctx, "user-2", photo.ID, false); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for another user, got %v", err)
+	}
+
+	// Файлы удаляются из хранилища только очисткой
+	if err := svc.Delete(ctx, "user-1", photo.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if countFiles(t, dir) != 3 {
+		t.Errorf("Expected files to stay until cleanup, got %d", countFiles(t, dir))
+	}
+	purged, err := svc.PurgeBlobs(ctx)
+	if err != nil || purged != 2 {
+		t.Errorf("Expected 2 purged blobs, got %d (%v)", purged, err)
+	}
+	if countFiles(t, dir) != 1 {
+		t.Errorf("Expected only the PDF to remain, got %d files", countFiles(t, dir))
+	}
+
+	// Если метаданные не сохранились, записанные файлы удаляются сразу
+	attachmentRepo.createErr = errors.New("db is down")
+	if _, err := svc.Upload(ctx, "user-1", ownTxID, "receipt.png", testPNG(t)); err == nil {
+		t.Fatal("Expected error when repository fails")
+	}
+	if countFiles(t, dir) != 1 {
+		t.Errorf("Expected orphaned files to be discarded, got %d files", countFiles(t, dir))
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/audit"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+)
+
+type AuditService interface {
+	// Подписывает журнал на изменения транзакций, правил, категорий и настроек.
+	// Запись добавляется в той же транзакции БД, что и изменение
+	Subscribe(bus *eventbus.Bus)
+
+	// Возвращает личные записи журнала пользователя или записи рабочего пространства,
+	// последние первыми. Журнал пространства доступен его участникам
+	GetAll(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
+
+type auditServiceImpl struct {
+	auditRepo repository.AuditRepository
+	policy    AccessPolicy
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, policy AccessPolicy) AuditService {
+	return &auditServiceImpl{
+		auditRepo: auditRepo,
+		policy:    policy,
+	}
+}
+
+func (s *auditServiceImpl) GetAll(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	if filter.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, filter.UserID, *filter.WorkspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+	return s.auditRepo.GetByUserID(ctx, filter)
+}
+
+func (s *auditServiceImpl) Subscribe(bus *eventbus.Bus) {
+	for _, name := range []string{
+		event.TransactionCreatedName,
+		event.TransactionUpdatedName,
+		event.TransactionDeletedName,
+		event.RuleCreatedName,
+		event.RuleDeletedName,
+		event.CategoryCreatedName,
+		event.CategoryUpdatedName,
+		event.CategoryDeletedName,
+		event.SettingsUpdatedName,
+	} {
+		bus.Subscribe(name, s.handleEvent)
+	}
+}
+
+func (s *auditServiceImpl) handleEvent(ctx context.Context, e eventbus.Event) error {
+	switch e := e.(type) {
+	case event.TransactionCreated:
+		return s.record(ctx, e.UserID(), e.Transaction.WorkspaceID, model.AuditTransaction, e.Transaction.ID, nil, toTransactionPayload(e.Transaction))
+	case event.TransactionUpdated:
+		return s.record(ctx, e.UserID(), e.Transaction.WorkspaceID, model.AuditTransaction, e.Transaction.ID, toTransactionPayload(e.Previous), toTransactionPayload(e.Transaction))
+	case event.TransactionDeleted:
+		return s.record(ctx, e.UserID(), e.Transaction.WorkspaceID, model.AuditTransaction, e.Transaction.ID, toTransactionPayload(e.Transaction), nil)
+	case event.RuleCreated:
+		return s.record(ctx, e.UserID(), e.Rule.WorkspaceID, model.AuditRule, e.Rule.ID, nil, toRuleAudit(e.Rule))
+	case event.RuleDeleted:
+		return s.record(ctx, e.UserID(), e.Rule.WorkspaceID, model.AuditRule, e.Rule.ID, toRuleAudit(e.Rule), nil)
+	case event.CategoryCreated:
+		return s.record(ctx, e.UserID(), nil, model.AuditCategory, strconv.Itoa(e.Category.ID), nil, toCategoryAudit(e.Category))
+	case event.CategoryUpdated:
+		return s.record(ctx, e.UserID(), nil, model.AuditCategory, strconv.Itoa(e.Category.ID), toCategoryAudit(e.Previous), toCategoryAudit(e.Category))
+	case event.CategoryDeleted:
+		return s.record(ctx, e.UserID(), nil, model.AuditCategory, strconv.Itoa(e.Category.ID), toCategoryAudit(e.Category), nil)
+	case event.SettingsUpdated:
+		return s.record(ctx, e.UserID(), nil, model.AuditSettings, e.User, toSettingsAudit(e.Previous), toSettingsAudit(e.Settings))
+	}
+	return nil
+}
+
+// record добавляет запись об изменении сущности автора userID, для сущностей рабочего
+// пространства — с его workspaceID. before и after —
+// состояния сущности до и после изменения, nil при создании и удалении соответственно.
+// В запись попадают только изменённые поля; изменение без различий не записывается
+func (s *auditServiceImpl) record(ctx context.Context, userID string, workspaceID *string, entity model.AuditEntity, entityID string, before, after any) error {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.AuditEntry{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Entity:      entity,
+		EntityID:    entityID,
+	}
+	switch {
+	case beforeFields == nil:
+		entry.Action, entry.After = model.AuditCreate, afterFields
+	case afterFields == nil:
+		entry.Action, entry.Before = model.AuditDelete, beforeFields
+	default:
+		entry.Action = model.AuditUpdate
+		entry.Before, entry.After = auditDiff(beforeFields, afterFields)
+		if len(entry.After) == 0 {
+			return nil
+		}
+	}
+
+	req := audit.RequestFromContext(ctx)
+	entry.ActorID = optionalString(req.ActorID)
+	entry.RequestID = optionalString(req.RequestID)
+	entry.ClientIP = optionalString(req.ClientIP)
+
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// auditIgnoredFields служебные поля, которые меняются при каждом изменении и не пишутся в журнал
+var auditIgnoredFields = []string{"id", "created_at", "updated_at"}
+
+// auditFields переводит состояние сущности в поля JSON. Для nil возвращает nil
+func auditFields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, key := range auditIgnoredFields {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+// auditDiff оставляет только поля, значения которых различаются. Отсутствующее поле равно null
+func auditDiff(before, after map[string]any) (map[string]any, map[string]any) {
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+
+	for key, value := range before {
+		if !reflect.DeepEqual(value, after[key]) {
+			changedBefore[key] = value
+			changedAfter[key] = after[key]
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changedBefore[key] = nil
+			changedAfter[key] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ruleAudit правило категоризации в журнале аудита
+type ruleAudit struct {
+	Keyword    string `json:"keyword"`
+	CategoryID int    `json:"category_id"`
+}
+
+func toRuleAudit(rule *model.UserCategoryRule) *ruleAudit {
+	return &ruleAudit{Keyword: rule.Keyword, CategoryID: rule.CategoryID}
+}
+
+// categoryAudit категория в журнале аудита
+type categoryAudit struct {
+	Name     string  `json:"name"`
+	ParentID *int    `json:"parent_id"`
+	Icon     *string `json:"icon"`
+	Color    *string `json:"color"`
+}
+
+func toCategoryAudit(category *model.Category) *categoryAudit {
+	return &categoryAudit{
+		Name:     category.Name,
+		ParentID: category.ParentID,
+		Icon:     category.Icon,
+		Color:    category.Color,
+	}
+}
+
+// toSettingsAudit раскладывает настройки в плоские поля, чтобы в журнал попали
+// только изменённые адреса и настройки уведомлений
+func toSettingsAudit(settings *model.UserSettings) map[string]any {
+	if settings == nil {
+		return nil
+	}
+
+	fields := make(map[string]any)
+	for _, target := range settings.NotificationTargets {
+		prefix := "notification_targets." + string(target.Channel)
+		fields[prefix+".address"] = target.Address
+		fields[prefix+".locale"] = target.Locale
+	}
+	for _, pref := range settings.NotificationPreferences {
+		fields["notification_preferences."+string(pref.Event)+"."+string(pref.Channel)] = pref.Enabled
+	}
+	return fields
+}
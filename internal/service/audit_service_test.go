@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/audit"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+)
+
+type mockAuditRepository struct {
+	entries []*model.AuditEntry
+}
+
+func (m *mockAuditRepository) Create(ctx context.Context, entry *model.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockAuditRepository) GetByUserID(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	return m.entries, nil
+}
+
+func TestAuditService_Subscribe(t *testing.T) {
+	repo := &mockAuditRepository{}
+	bus := eventbus.New(eventbus.Config{})
+	NewAuditService(repo, NewAccessPolicy(&mockWorkspaceRepository{})).Subscribe(bus)
+
+	ctx := audit.WithRequest(context.Background(), audit.Request{
+		ActorID:   "user-1",
+		RequestID: "req-1",
+		ClientIP:  "10.0.0.1",
+	})
+
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := &model.Transaction{ID: ownTxID, UserID: "user-1", Amount: 100, Currency: "RUB", Description: "Такси", Date: date}
+	after := &model.Transaction{ID: ownTxID, UserID: "user-1", Amount: 250, Currency: "RUB", Description: "Такси", Date: date, UpdatedAt: date.Add(time.Hour)}
+
+	err := bus.Publish(ctx,
+		event.NewTransactionCreated(before),
+		event.NewTransactionUpdated(after, before),
+		event.NewTransactionUpdated(after, after),
+		event.NewTransactionDeleted(after),
+	)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(repo.entries) != 3 {
+		t.Fatalf("записей = %d, ожидалось 3: изменение без различий не записывается", len(repo.entries))
+	}
+
+	wantActions := []model.AuditAction{model.AuditCreate, model.AuditUpdate, model.AuditDelete}
+	for i, entry := range repo.entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entries[%d].Action = %q, ожидалось %q", i, entry.Action, wantActions[i])
+		}
+		if entry.Entity != model.AuditTransaction || entry.EntityID != ownTxID || entry.UserID != "user-1" {
+			t.Errorf("entries[%d] = %s/%s пользователя %s", i, entry.Entity, entry.EntityID, entry.UserID)
+		}
+		if entry.ActorID == nil || *entry.ActorID != "user-1" ||
+			entry.RequestID == nil || *entry.RequestID != "req-1" ||
+			entry.ClientIP == nil || *entry.ClientIP != "10.0.0.1" {
+			t.Errorf("entries[%d] без данных запроса", i)
+		}
+	}
+
+	update := repo.entries[1]
+	if !reflect.DeepEqual(update.Before, map[string]any{"amount": 100.0}) {
+		t.Errorf("Before = %v, ожидалась только сумма", update.Before)
+	}
+	if !reflect.DeepEqual(update.After, map[string]any{"amount": 250.0}) {
+		t.Errorf("After = %v, ожидалась только сумма", update.After)
+	}
+
+	if repo.entries[0].Before != nil || repo.entries[0].After["description"] != "Такси" {
+		t.Errorf("создание: Before = %v, After = %v", repo.entries[0].Before, repo.entries[0].After)
+	}
+	if repo.entries[2].After != nil || repo.entries[2].Before["amount"] != 250.0 {
+		t.Errorf("удаление: Before = %v, After = %v", repo.entries[2].Before, repo.entries[2].After)
+	}
+}
+
+func TestAuditService_Workspace(t *testing.T) {
+	const workspaceID = "66666666-6666-6666-6666-666666666666"
+	repo := &mockAuditRepository{}
+	workspaceRepo := &mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {"owner": model.WorkspaceOwner, "viewer": model.WorkspaceViewer},
+	}}
+	svc := NewAuditService(repo, NewAccessPolicy(workspaceRepo))
+	bus := eventbus.New(eventbus.Config{})
+	svc.Subscribe(bus)
+
+	ctx := context.Background()
+	workspace := workspaceID
+	tx := &model.Transaction{ID: ownTxID, UserID: "owner", WorkspaceID: &workspace, Amount: 100, Currency: "RUB"}
+	if err := bus.Publish(ctx, event.NewTransactionCreated(tx)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(repo.entries) != 1 || repo.entries[0].WorkspaceID == nil || *repo.entries[0].WorkspaceID != workspaceID {
+		t.Fatalf("запись транзакции пространства без workspace_id: %+v", repo.entries)
+	}
+
+	// Журнал пространства читает любой участник, посторонний получает отказ
+	if _, err := svc.GetAll(ctx, model.AuditFilter{UserID: "viewer", WorkspaceID: &workspace}); err != nil {
+		t.Errorf("viewer GetAll() error = %v", err)
+	}
+	if _, err := svc.GetAll(ctx, model.AuditFilter{UserID: "stranger", WorkspaceID: &workspace}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("stranger GetAll() error = %v, ожидалось ErrForbidden", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/gibbon/finace-dashboard/internal/apperror"
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
 	"github.com/gibbon/finace-dashboard/internal/domain/repository"
 	domainService "github.com/gibbon/finace-dashboard/internal/domain/service"
@@ -15,13 +16,21 @@ import (
 )
 
 var (
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUnauthorized       = errors.New("unauthorized")
+	ErrUserAlreadyExists  = apperror.New(apperror.KindConflict, "user_already_exists")
+	ErrInvalidCredentials = apperror.New(apperror.KindUnauthorized, "invalid_credentials")
+	ErrForbidden          = apperror.New(apperror.KindForbidden, "forbidden")
 )
 
 var ErrUserNotFound = repo.ErrUserNotFound
 
+// notFound заменяет ErrNotFound репозитория на доменную ошибку с кодом
+func notFound(err error, domainErr error) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return domainErr
+	}
+	return err
+}
+
 // Конфигурация для сервиса
 type AuthServiceConfig struct {
 	JWTSecret     string
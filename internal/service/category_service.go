@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+)
+
+var (
+	ErrCategoryNotFound    = apperror.New(apperror.KindNotFound, "category_not_found")
+	ErrCategoryInUse       = apperror.New(apperror.KindConflict, "category_in_use")
+	ErrCategoryHasChildren = apperror.New(apperror.KindConflict, "category_has_children")
+	ErrInvalidParent       = apperror.New(apperror.KindBadRequest, "invalid_parent_category")
+	ErrInvalidReassign     = apperror.New(apperror.KindBadRequest, "invalid_reassign_target")
+)
+
+type CategoryService interface {
+	// Возвращает системные категории и категории пользователя с названиями на языке locale
+	GetAll(ctx context.Context, userID, locale string) ([]*model.Category, error)
+
+	// Возвращает категорию по ID, если она доступна пользователю
+	GetByID(ctx context.Context, userID string, id int, locale string) (*model.Category, error)
+
+	// Создаёт пользовательскую категорию
+	Create(ctx context.Context, userID string, category *model.Category) (*model.Category, error)
+
+	// Обновляет пользовательскую категорию
+	Update(ctx context.Context, userID string, category *model.Category) (*model.Category, error)
+
+	// Удаляет пользовательскую категорию, переназначая транзакции и правила на reassignTo
+	Delete(ctx context.Context, userID string, id int, reassignTo *int) error
+}
+
+type categoryServiceImpl struct {
+	categoryRepo repository.CategoryRepository
+	events       EventPublisher
+	transactor   repository.Transactor
+	policy       AccessPolicy
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository, events EventPublisher, transactor repository.Transactor, policy AccessPolicy) CategoryService {
+	return &categoryServiceImpl{
+		categoryRepo: categoryRepo,
+		events:       events,
+		transactor:   transactor,
+		policy:       policy,
+	}
+}
+
+func (s *categoryServiceImpl) GetAll(ctx context.Context, userID, locale string) ([]*model.Category, error) {
+	categories, err := s.categoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localizeCategories(ctx, s.categoryRepo, locale, categories...); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *categoryServiceImpl) GetByID(ctx context.Context, userID string, id int, locale string) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrCategoryNotFound)
+	}
+
+	if !category.IsVisibleTo(userID) {
+		return nil, ErrForbidden
+	}
+
+	if err := localizeCategories(ctx, s.categoryRepo, locale, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryServiceImpl) Create(ctx context.Context, userID string, category *model.Category) (*model.Category, error) {
+	if err := s.checkParent(ctx, userID, category); err != nil {
+		return nil, err
+	}
+
+	category.UserID = &userID
+	category.IsDefault = false
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Create(ctx, category); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewCategoryCreated(category))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryServiceImpl) Update(ctx context.Context, userID string, category *model.Category) (*model.Category, error) {
+	existing, err := s.categoryRepo.GetByID(ctx, category.ID)
+	if err != nil {
+		return nil, notFound(err, ErrCategoryNotFound)
+	}
+
+	// Системные категории изменять нельзя
+	if !existing.IsOwnedBy(userID) {
+		return nil, ErrForbidden
+	}
+
+	if category.ParentID != nil {
+		// Категория с подкатегориями не может сама стать подкатегорией
+		children, err := s.categoryRepo.GetChildren(ctx, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(children) > 0 {
+			return nil, ErrInvalidParent
+		}
+	}
+
+	if err := s.checkParent(ctx, userID, category); err != nil {
+		return nil, err
+	}
+
+	category.UserID = existing.UserID
+	category.CreatedAt = existing.CreatedAt
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewCategoryUpdated(category, existing))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryServiceImpl) Delete(ctx context.Context, userID string, id int, reassignTo *int) error {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrCategoryNotFound)
+	}
+
+	if !category.IsOwnedBy(userID) {
+		return ErrForbidden
+	}
+
+	children, err := s.categoryRepo.GetChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return ErrCategoryHasChildren
+	}
+
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return ErrInvalidReassign
+		}
+		target, err := s.categoryRepo.GetByID(ctx, *reassignTo)
+		if err != nil {
+			return notFound(err, ErrInvalidReassign)
+		}
+		if !target.IsVisibleTo(userID) {
+			return ErrInvalidReassign
+		}
+
+		// Категорией могут пользоваться пространства: цель должна быть доступна каждому из них,
+		// иначе транзакции участников перейдут в чужую им категорию
+		workspaceIDs, err := s.categoryRepo.GetWorkspaceIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, workspaceID := range workspaceIDs {
+			if err := categoryUsable(ctx, s.policy, userID, &workspaceID, target); err != nil {
+				if errors.Is(err, ErrInvalidCategory) {
+					return ErrInvalidReassign
+				}
+				return err
+			}
+		}
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Delete(ctx, id, reassignTo); err != nil {
+			if errors.Is(err, repo.ErrCategoryInUse) {
+				return ErrCategoryInUse
+			}
+			return err
+		}
+		return s.events.Publish(ctx, event.NewCategoryDeleted(category, reassignTo))
+	})
+}
+
+// checkParent проверяет, что родительская категория доступна пользователю
+// и сама является категорией верхнего уровня (дерево не глубже двух уровней)
+func (s *categoryServiceImpl) checkParent(ctx context.Context, userID string, category *model.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+
+	if *category.ParentID == category.ID {
+		return ErrInvalidParent
+	}
+
+	parent, err := s.categoryRepo.GetByID(ctx, *category.ParentID)
+	if err != nil {
+		return notFound(err, ErrInvalidParent)
+	}
+
+	if !parent.IsVisibleTo(userID) || parent.ParentID != nil {
+		return ErrInvalidParent
+	}
+
+	return nil
+}
+
+// localizeCategories подставляет переводы названий категорий с ключом перевода.
+// Если перевода для локали нет, остаётся исходное название
+func localizeCategories(ctx context.Context, categoryRepo repository.CategoryRepository, locale string, categories ...*model.Category) error {
+	translations, err := categoryRepo.GetTranslations(ctx, locale)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		if category.TranslationKey == nil {
+			continue
+		}
+		if name, ok := translations[*category.TranslationKey]; ok {
+			category.Name = name
+		}
+	}
+
+	return nil
+}
+
+// visibleCategories возвращает локализованные категории, доступные транзакциям пользователя
+// или пространства workspaceID
+func visibleCategories(ctx context.Context, categoryRepo repository.CategoryRepository, userID string, workspaceID *string, locale string) ([]*model.Category, error) {
+	var (
+		categories []*model.Category
+		err        error
+	)
+	if workspaceID != nil {
+		categories, err = categoryRepo.GetByWorkspaceID(ctx, *workspaceID)
+	} else {
+		categories, err = categoryRepo.GetByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localizeCategories(ctx, categoryRepo, locale, categories...); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// categoryUsable проверяет доступность категории: личным данным — системные и собственные
+// категории пользователя, пространству — системные и категории его участников
+func categoryUsable(ctx context.Context, policy AccessPolicy, userID string, workspaceID *string, category *model.Category) error {
+	if workspaceID == nil || category.UserID == nil {
+		if !category.IsVisibleTo(userID) {
+			return ErrInvalidCategory
+		}
+		return nil
+	}
+
+	err := policy.AuthorizeWorkspace(ctx, *category.UserID, *workspaceID, model.PermissionRead)
+	if errors.Is(err, ErrForbidden) {
+		return ErrInvalidCategory
+	}
+	return err
+}
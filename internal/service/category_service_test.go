@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestCategoryService_DeleteWorkspaceReassign(t *testing.T) {
+	member, other := "user-1", "user-2"
+	workspaceID := "ws-1"
+	categoryRepo := &mockCategoryRepository{
+		categories: map[int]*model.Category{
+			1:  {ID: 1, Name: "Продукты", IsDefault: true},
+			10: {ID: 10, Name: "Дача", UserID: &member},
+			11: {ID: 11, Name: "Хобби", UserID: &member},
+			12: {ID: 12, Name: "Сад", UserID: &member},
+		},
+		workspaces: map[int][]string{10: {workspaceID}, 12: {workspaceID}},
+	}
+	policy := NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {other: model.WorkspaceOwner},
+	}})
+	svc := NewCategoryService(categoryRepo, &mockEventPublisher{}, &mockTransactor{}, policy)
+	ctx := context.Background()
+
+	// Автор вышел из пространства: его личная категория недоступна остальным участникам
+	if err := svc.Delete(ctx, member, 10, intPtr(11)); !errors.Is(err, ErrInvalidReassign) {
+		t.Errorf("Delete() с личной целью error = %v, ожидалось ErrInvalidReassign", err)
+	}
+	if categoryRepo.categories[10] == nil {
+		t.Fatal("категория удалена, хотя цель переназначения отклонена")
+	}
+
+	// Системная категория доступна любому пространству
+	if err := svc.Delete(ctx, member, 10, intPtr(1)); err != nil {
+		t.Errorf("Delete() с системной целью error = %v", err)
+	}
+
+	// Участник пространства может переназначить на свою категорию
+	policy = NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {other: model.WorkspaceOwner, member: model.WorkspaceEditor},
+	}})
+	svc = NewCategoryService(categoryRepo, &mockEventPublisher{}, &mockTransactor{}, policy)
+	if err := svc.Delete(ctx, member, 12, intPtr(11)); err != nil {
+		t.Errorf("Delete() участником с личной целью error = %v", err)
+	}
+}
+
+func TestCategoryService_Parent(t *testing.T) {
+	userID, other := "user-1", "user-2"
+	categoryRepo := &mockCategoryRepository{categories: map[int]*model.Category{
+		1:  {ID: 1, Name: "Продукты", IsDefault: true},
+		10: {ID: 10, Name: "Дом", UserID: &userID},
+		11: {ID: 11, Name: "Ремонт", UserID: &userID, ParentID: intPtr(10)},
+		12: {ID: 12, Name: "Хобби", UserID: &userID},
+		20: {ID: 20, Name: "Чужая", UserID: &other},
+	}}
+	svc := NewCategoryService(categoryRepo, &mockEventPublisher{}, &mockTransactor{}, NewAccessPolicy(&mockWorkspaceRepository{}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		update   bool
+		category *model.Category
+		wantErr  error
+	}{
+		{"подкатегория системной категории", false, &model.Category{Name: "Фрукты", ParentID: intPtr(1)}, nil},
+		{"третий уровень вложенности", false, &model.Category{Name: "Краска", ParentID: intPtr(11)}, ErrInvalidParent},
+		{"несуществующий родитель", false, &model.Category{Name: "Краска", ParentID: intPtr(99)}, ErrInvalidParent},
+		{"чужой родитель", false, &model.Category{Name: "Краска", ParentID: intPtr(20)}, ErrInvalidParent},
+		{"категория сама себе родитель", true, &model.Category{ID: 12, Name: "Хобби", ParentID: intPtr(12)}, ErrInvalidParent},
+		{"категория с подкатегориями становится подкатегорией", true, &model.Category{ID: 10, Name: "Дом", ParentID: intPtr(12)}, ErrInvalidParent},
+		{"перенос в другого родителя", true, &model.Category{ID: 11, Name: "Ремонт", ParentID: intPtr(12)}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.update {
+				_, err = svc.Update(ctx, userID, tt.category)
+			} else {
+				_, err = svc.Create(ctx, userID, tt.category)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCategoryService_Delete(t *testing.T) {
+	userID, other := "user-1", "user-2"
+	categoryRepo := &mockCategoryRepository{
+		categories: map[int]*model.Category{
+			1:  {ID: 1, Name: "Продукты", IsDefault: true},
+			10: {ID: 10, Name: "Дом", UserID: &userID},
+			11: {ID: 11, Name: "Ремонт", UserID: &userID, ParentID: intPtr(10)},
+			12: {ID: 12, Name: "Хобби", UserID: &userID},
+			20: {ID: 20, Name: "Чужая", UserID: &other},
+		},
+		inUse: map[int]bool{12: true},
+	}
+	events := &mockEventPublisher{}
+	svc := NewCategoryService(categoryRepo, events, &mockTransactor{}, NewAccessPolicy(&mockWorkspaceRepository{}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		id         int
+		reassignTo *int
+		wantErr    error
+	}{
+		{"системная категория", 1, nil, ErrForbidden},
+		{"чужая категория", 20, nil, ErrForbidden},
+		{"несуществующая категория", 99, nil, ErrCategoryNotFound},
+		{"категория с подкатегориями", 10, nil, ErrCategoryHasChildren},
+		{"переназначение на саму себя", 12, intPtr(12), ErrInvalidReassign},
+		{"переназначение на несуществующую категорию", 12, intPtr(99), ErrInvalidReassign},
+		{"переназначение на чужую категорию", 12, intPtr(20), ErrInvalidReassign},
+		{"категория с транзакциями без переназначения", 12, nil, ErrCategoryInUse},
+		{"переназначение на системную категорию", 12, intPtr(1), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.Delete(ctx, userID, tt.id, tt.reassignTo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if _, ok := categoryRepo.categories[tt.id]; ok && err == nil {
+				t.Errorf("Expected category %d to be deleted", tt.id)
+			}
+		})
+	}
+
+	// Событие публикуется только для успешного удаления
+	if len(events.events) != 1 || events.events[0].EventName() != event.CategoryDeletedName {
+		t.Errorf("Expected single %s event, got %v", event.CategoryDeletedName, events.events)
+	}
+	if categoryRepo.categories[10] == nil || categoryRepo.categories[20] == nil {
+		t.Error("Rejected categories must not be deleted")
+	}
+}
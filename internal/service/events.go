@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+)
+
+// EventPublisher публикует доменные события из internal/domain/event.
+// Публикация внутри транзакции БД: синхронные подписчики работают в ней же,
+// а асинхронные получат события только после фиксации
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...eventbus.Event) error
+}
+
+// transactionPayload транзакция в событиях для внешних получателей: вебхуков и потока обновлений.
+// Для transaction.deleted — состояние до удаления. Splits передаются в событиях изменения разбивки
+type transactionPayload struct {
+	ID          string          `json:"id"`
+	Amount      float64         `json:"amount"`
+	Currency    string          `json:"currency"`
+	Description string          `json:"description"`
+	Date        time.Time       `json:"date"`
+	PlaceName   *string         `json:"place_name,omitempty"`
+	PlaceLat    *float64        `json:"place_lat,omitempty"`
+	PlaceLon    *float64        `json:"place_lon,omitempty"`
+	CategoryID  *int            `json:"category_id,omitempty"`
+	MerchantID  *string         `json:"merchant_id,omitempty"`
+	IsConfirmed bool            `json:"is_confirmed"`
+	Splits      []*splitPayload `json:"splits,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// splitPayload строка разбивки транзакции в событиях
+type splitPayload struct {
+	CategoryID int     `json:"category_id"`
+	Amount     float64 `json:"amount"`
+	Note       *string `json:"note,omitempty"`
+}
+
+func toTransactionPayload(tx *model.Transaction) *transactionPayload {
+	var splits []*splitPayload
+	for _, split := range tx.Splits {
+		splits = append(splits, &splitPayload{CategoryID: split.CategoryID, Amount: split.Amount, Note: split.Note})
+	}
+	return &transactionPayload{
+		ID:          tx.ID,
+		Amount:      tx.Amount,
+		Currency:    tx.Currency,
+		Description: tx.Description,
+		Date:        tx.Date,
+		PlaceName:   tx.PlaceName,
+		PlaceLat:    tx.PlaceLat,
+		PlaceLon:    tx.PlaceLon,
+		CategoryID:  tx.CategoryID,
+		MerchantID:  tx.MerchantID,
+		IsConfirmed: tx.IsConfirmed,
+		Splits:      splits,
+		CreatedAt:   tx.CreatedAt,
+		UpdatedAt:   tx.UpdatedAt,
+	}
+}
+
+// importPayload этап импорта в потоке обновлений
+type importPayload struct {
+	ImportID      string  `json:"import_id"`
+	Source        string  `json:"source"`
+	Stage         string  `json:"stage"`
+	TransactionID *string `json:"transaction_id,omitempty"`
+	Error         *string `json:"error,omitempty"`
+}
+
+func toImportPayload(e event.ImportProgress) *importPayload {
+	return &importPayload{
+		ImportID:      e.ImportID,
+		Source:        e.Source,
+		Stage:         e.Stage,
+		TransactionID: e.TransactionID,
+		Error:         e.Error,
+	}
+}
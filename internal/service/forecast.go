@@ -0,0 +1,286 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+const (
+	// forecastHistoryMonths сколько полных месяцев истории используется для прогноза
+	forecastHistoryMonths = 24
+	// seasonalMinMonths сезонный прогноз строится по двум полным годам истории,
+	// иначе используется скользящее среднее
+	seasonalMinMonths = 24
+	// movingAverageWindow окно скользящего среднего в месяцах
+	movingAverageWindow = 3
+	// errorWindow за сколько последних месяцев оценивается ошибка прогноза
+	errorWindow = 12
+	// forecastConfidence уровень доверительного интервала и соответствующий квантиль
+	// нормального распределения
+	forecastConfidence = 0.8
+	forecastZ          = 1.2816
+	// MaxForecastMonths максимальный горизонт прогноза
+	MaxForecastMonths = 12
+	// incomeTranslationKey ключ системной категории доходов
+	incomeTranslationKey = "category.income"
+)
+
+// categorySeries помесячная история категории верхнего уровня
+type categorySeries struct {
+	category *model.Category
+	income   bool
+	months   []float64
+}
+
+// monthForecast прогноз категории: значение на каждый месяц горизонта и средняя ошибка
+type monthForecast struct {
+	method model.ForecastMethod
+	values []float64
+	sigma  float64
+}
+
+// buildForecast строит прогноз на months календарных месяцев начиная с from
+// по дневным суммам истории за месяцы с historyFrom до historyTo (не включая его).
+// Подкатегории сворачиваются в категории верхнего уровня, доходами считается
+// системная категория «Доходы». Все даты — начала месяцев в UTC
+func buildForecast(totals []*model.DailyTotal, categories []*model.Category, historyFrom, historyTo, from time.Time, months int) *model.Forecast {
+	byID := make(map[int]*model.Category, len(categories))
+	for _, cat := range categories {
+		byID[cat.ID] = cat
+	}
+
+	historyLen := monthIndex(historyFrom, historyTo)
+	first := historyLen
+	for _, total := range totals {
+		first = min(first, max(monthIndex(historyFrom, total.Date), 0))
+	}
+	n := historyLen - first
+
+	// Ключ 0 — транзакции без категории: ID категорий начинаются с 1
+	series := make(map[int]*categorySeries)
+	// Доля суммы по дням месяца отдельно для доходов и расходов
+	var dayWeights [2][31]float64
+
+	for _, total := range totals {
+		i := monthIndex(historyFrom, total.Date) - first
+		if i < 0 || i >= n {
+			continue
+		}
+
+		var root *model.Category
+		if total.CategoryID != nil {
+			root = rootCategory(byID, *total.CategoryID)
+		}
+		key := 0
+		if root != nil {
+			key = root.ID
+		}
+
+		s, ok := series[key]
+		if !ok {
+			s = &categorySeries{category: root, income: isIncomeCategory(root), months: make([]float64, n)}
+			series[key] = s
+		}
+		s.months[i] += total.Total
+
+		kind := 0
+		if s.income {
+			kind = 1
+		}
+		dayWeights[kind][total.Date.Day()-1] += total.Total
+	}
+
+	forecast := &model.Forecast{
+		From:          from,
+		To:            from.AddDate(0, months, -1),
+		Confidence:    forecastConfidence,
+		HistoryMonths: n,
+	}
+
+	predictions := make(map[int]*monthForecast, len(series))
+	for key, s := range series {
+		predictions[key] = predictSeries(s.months, months)
+	}
+
+	var balance, variance float64
+	for h := 0; h < months; h++ {
+		month := &model.MonthForecast{Month: from.AddDate(0, h, 0)}
+		var income, expense, incomeVar, expenseVar float64
+
+		for key, s := range series {
+			p := predictions[key]
+			value := p.values[h]
+			item := &model.CategoryForecast{
+				Income: s.income,
+				Method: p.method,
+				Amount: band(value, p.sigma),
+			}
+			if s.category != nil {
+				id := s.category.ID
+				item.CategoryID, item.Name = &id, s.category.Name
+			}
+			month.Categories = append(month.Categories, item)
+
+			if s.income {
+				income += value
+				incomeVar += p.sigma * p.sigma
+			} else {
+				expense += value
+				expenseVar += p.sigma * p.sigma
+			}
+		}
+		sortCategoryForecasts(month.Categories)
+		month.Income = band(income, math.Sqrt(incomeVar))
+		month.Expense = band(expense, math.Sqrt(expenseVar))
+		forecast.Months = append(forecast.Months, month)
+
+		// Баланс распределяется по дням месяца пропорционально истории
+		days := daysIn(month.Month)
+		incomeShare := dayShares(dayWeights[1], days)
+		expenseShare := dayShares(dayWeights[0], days)
+		for d := 0; d < days; d++ {
+			balance += income*incomeShare[d] - expense*expenseShare[d]
+			dayVariance := variance + (incomeVar+expenseVar)*float64(d+1)/float64(days)
+			forecast.Days = append(forecast.Days, &model.DayForecast{
+				Date:    month.Month.AddDate(0, 0, d),
+				Balance: balanceBand(balance, math.Sqrt(dayVariance)),
+			})
+		}
+		variance += incomeVar + expenseVar
+	}
+
+	return forecast
+}
+
+// predictSeries прогнозирует помесячный ряд на horizon месяцев. При двух годах истории
+// используется сезонный наивный метод (значение того же месяца год назад), иначе —
+// скользящее среднее. Ошибка — среднеквадратичная ошибка метода на последних месяцах истории
+func predictSeries(history []float64, horizon int) *monthForecast {
+	n := len(history)
+	p := &monthForecast{values: make([]float64, horizon)}
+
+	var residuals []float64
+	if n >= seasonalMinMonths {
+		p.method = model.ForecastSeasonalNaive
+		for h := range p.values {
+			p.values[h] = history[n-12+h%12]
+		}
+		for i := n - errorWindow; i < n; i++ {
+			residuals = append(residuals, history[i]-history[i-12])
+		}
+	} else {
+		p.method = model.ForecastMovingAverage
+		value := mean(history[max(n-movingAverageWindow, 0):])
+		for h := range p.values {
+			p.values[h] = value
+		}
+		for i := max(n-errorWindow, 1); i < n; i++ {
+			residuals = append(residuals, history[i]-mean(history[max(i-movingAverageWindow, 0):i]))
+		}
+	}
+
+	var sum float64
+	for _, r := range residuals {
+		sum += r * r
+	}
+	if len(residuals) > 0 {
+		p.sigma = math.Sqrt(sum / float64(len(residuals)))
+	}
+	return p
+}
+
+// rootCategory возвращает категорию верхнего уровня для категории id
+func rootCategory(byID map[int]*model.Category, id int) *model.Category {
+	cat, ok := byID[id]
+	if !ok {
+		return nil
+	}
+	if cat.ParentID != nil {
+		if parent, ok := byID[*cat.ParentID]; ok {
+			return parent
+		}
+	}
+	return cat
+}
+
+func isIncomeCategory(cat *model.Category) bool {
+	return cat != nil && cat.TranslationKey != nil && *cat.TranslationKey == incomeTranslationKey
+}
+
+// dayShares нормирует веса дней месяца. Дни, которых нет в коротком месяце,
+// переносятся на его последний день. Без истории сумма делится поровну
+func dayShares(weights [31]float64, days int) []float64 {
+	shares := make([]float64, days)
+	var total float64
+	for d, w := range weights {
+		shares[min(d, days-1)] += w
+		total += w
+	}
+	for d := range shares {
+		if total > 0 {
+			shares[d] /= total
+		} else {
+			shares[d] = 1 / float64(days)
+		}
+	}
+	return shares
+}
+
+// sortCategoryForecasts сортирует доходы перед расходами, затем по убыванию суммы
+func sortCategoryForecasts(items []*model.CategoryForecast) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Income != items[j].Income {
+			return items[i].Income
+		}
+		if items[i].Amount.Value != items[j].Amount.Value {
+			return items[i].Amount.Value > items[j].Amount.Value
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// band возвращает неотрицательную сумму с доверительным интервалом
+func band(value, sigma float64) model.ForecastValue {
+	return model.ForecastValue{
+		Value: roundCents(value),
+		Lower: roundCents(math.Max(value-forecastZ*sigma, 0)),
+		Upper: roundCents(value + forecastZ*sigma),
+	}
+}
+
+// balanceBand возвращает изменение баланса с доверительным интервалом, оно может быть отрицательным
+func balanceBand(value, sigma float64) model.ForecastValue {
+	return model.ForecastValue{
+		Value: roundCents(value),
+		Lower: roundCents(value - forecastZ*sigma),
+		Upper: roundCents(value + forecastZ*sigma),
+	}
+}
+
+// monthIndex номер месяца t относительно месяца from
+func monthIndex(from, t time.Time) int {
+	t = t.UTC()
+	return (t.Year()-from.Year())*12 + int(t.Month()) - int(from.Month())
+}
+
+func daysIn(month time.Time) int {
+	return month.AddDate(0, 1, -1).Day()
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func roundCents(amount float64) float64 {
+	return fromCents(model.Cents(amount))
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestPredictSeries(t *testing.T) {
+	// Два года истории: сезонный наивный прогноз повторяет прошлый год
+	history := make([]float64, 24)
+	for i := range history {
+		history[i] = float64(100 + i%12)
+	}
+	p := predictSeries(history, 3)
+	if p.method != model.ForecastSeasonalNaive {
+		t.Fatalf("Expected seasonal naive method, got %s", p.method)
+	}
+	if p.values[0] != 100 || p.values[2] != 102 || p.sigma != 0 {
+		t.Errorf("Expected repeated season without error, got %v ± %v", p.values, p.sigma)
+	}
+
+	// Короткая история: среднее за последние три месяца
+	p = predictSeries([]float64{10, 20, 30, 40}, 2)
+	if p.method != model.ForecastMovingAverage || p.values[0] != 30 || p.values[1] != 30 {
+		t.Errorf("Expected moving average 30, got %s %v", p.method, p.values)
+	}
+	// Ошибки одношаговых прогнозов: 20-10, 30-15, 40-20
+	if want := math.Sqrt((100.0 + 225 + 400) / 3); math.Abs(p.sigma-want) > 1e-9 {
+		t.Errorf("Expected sigma %v, got %v", want, p.sigma)
+	}
+}
+
+func TestBuildForecast(t *testing.T) {
+	historyFrom := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	historyTo := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	income := "category.income"
+	categories := []*model.Category{
+		{ID: 1, Name: "Продукты"},
+		{ID: 12, Name: "Доходы", TranslationKey: &income},
+		{ID: 100, Name: "Премия", ParentID: intPtr(12)},
+	}
+
+	// Три последних месяца: зарплата 5-го числа, продукты 20-го
+	var totals []*model.DailyTotal
+	for _, month := range []time.Month{time.July, time.August, time.September} {
+		totals = append(totals,
+			&model.DailyTotal{Date: time.Date(2026, month, 5, 0, 0, 0, 0, time.UTC), CategoryID: intPtr(12), Total: 90000},
+			&model.DailyTotal{Date: time.Date(2026, month, 20, 0, 0, 0, 0, time.UTC), CategoryID: intPtr(1), Total: 30000},
+		)
+	}
+	totals = append(totals, &model.DailyTotal{Date: time.Date(2026, time.September, 5, 0, 0, 0, 0, time.UTC), CategoryID: intPtr(100), Total: 30000})
+
+	f := buildForecast(totals, categories, historyFrom, historyTo, from, 2)
+
+	if f.HistoryMonths != 3 {
+		t.Errorf("Expected history from July to September, got %d months", f.HistoryMonths)
+	}
+	if len(f.Months) != 2 || len(f.Days) != 30+31 {
+		t.Fatalf("Expected 2 months and 61 days, got %d and %d", len(f.Months), len(f.Days))
+	}
+	if !f.To.Equal(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected end of forecast %v", f.To)
+	}
+
+	// Премия сворачивается в доходы: среднее (90000 + 90000 + 120000) / 3
+	month := f.Months[0]
+	if month.Income.Value != 100000 || month.Expense.Value != 30000 {
+		t.Errorf("Expected income 100000 and expense 30000, got %+v / %+v", month.Income, month.Expense)
+	}
+	if month.Income.Lower >= month.Income.Value || month.Income.Upper <= month.Income.Value {
+		t.Errorf("Expected income band around the value, got %+v", month.Income)
+	}
+	if len(month.Categories) != 2 || !month.Categories[0].Income || month.Categories[0].Name != "Доходы" {
+		t.Errorf("Expected income category first with subcategory rolled up, got %+v", month.Categories)
+	}
+
+	// Доход приходит 5-го, расход 20-го
+	if day := f.Days[3]; day.Balance.Value != 0 {
+		t.Errorf("Expected no change before the 5th, got %+v", day.Balance)
+	}
+	if day := f.Days[4]; day.Balance.Value != 100000 {
+		t.Errorf("Expected income on the 5th, got %+v", day.Balance)
+	}
+	if day := f.Days[29]; day.Balance.Value != 70000 {
+		t.Errorf("Expected 70000 at the end of November, got %+v", day.Balance)
+	}
+	if last := f.Days[len(f.Days)-1]; last.Balance.Value != 140000 || last.Balance.Upper-last.Balance.Lower <= f.Days[29].Balance.Upper-f.Days[29].Balance.Lower {
+		t.Errorf("Expected balance 140000 with a wider band at the end, got %+v", last.Balance)
+	}
+}
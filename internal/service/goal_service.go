@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGoalNotFound   = apperror.New(apperror.KindNotFound, "goal_not_found")
+	ErrGoalTagMissing = apperror.New(apperror.KindConflict, "goal_tag_missing")
+)
+
+const (
+	// daysPerMonth средняя длина месяца для расчёта ежемесячного взноса
+	daysPerMonth = 365.25 / 12
+	// minPacePeriod темп взносов считается не меньше чем за месяц,
+	// чтобы первый взнос не давал прогноз «цель будет достигнута через неделю»
+	minPacePeriod = 30 * 24 * time.Hour
+	// maxProjection прогноз дальше этого срока не возвращается
+	maxProjection = 100 * 365 * 24 * time.Hour
+)
+
+type GoalService interface {
+	// Возвращает цели пользователя с прогрессом
+	GetAll(ctx context.Context, userID string) ([]*model.Goal, error)
+
+	// Возвращает цель по ID с прогрессом
+	GetByID(ctx context.Context, userID, id string) (*model.Goal, error)
+
+	// Создаёт цель
+	Create(ctx context.Context, userID string, goal *model.Goal) (*model.Goal, error)
+
+	// Обновляет цель
+	Update(ctx context.Context, userID string, goal *model.Goal) (*model.Goal, error)
+
+	// Удаляет цель, транзакции-взносы и метка остаются
+	Delete(ctx context.Context, userID, id string) error
+
+	// Записывает взнос: создаёт транзакцию в валюте цели и ставит на неё метку цели
+	Contribute(ctx context.Context, userID, id string, tx *model.Transaction) (*model.Transaction, error)
+}
+
+type goalServiceImpl struct {
+	goalRepo   repository.GoalRepository
+	tagRepo    repository.TagRepository
+	txService  TransactionService
+	transactor repository.Transactor
+	now        func() time.Time
+}
+
+func NewGoalService(
+	goalRepo repository.GoalRepository,
+	tagRepo repository.TagRepository,
+	txService TransactionService,
+	transactor repository.Transactor,
+) GoalService {
+	return &goalServiceImpl{
+		goalRepo:   goalRepo,
+		tagRepo:    tagRepo,
+		txService:  txService,
+		transactor: transactor,
+		now:        time.Now,
+	}
+}
+
+func (s *goalServiceImpl) GetAll(ctx context.Context, userID string) ([]*model.Goal, error) {
+	goals, err := s.goalRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadProgress(ctx, goals...); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (s *goalServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Goal, error) {
+	goal, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadProgress(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *goalServiceImpl) Create(ctx context.Context, userID string, goal *model.Goal) (*model.Goal, error) {
+	goal.UserID = userID
+	if err := s.checkTag(ctx, userID, goal.TagID); err != nil {
+		return nil, err
+	}
+
+	if err := s.goalRepo.Create(ctx, goal); err != nil {
+		return nil, err
+	}
+	if err := s.loadProgress(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *goalServiceImpl) Update(ctx context.Context, userID string, goal *model.Goal) (*model.Goal, error) {
+	existing, err := s.get(ctx, userID, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTag(ctx, userID, goal.TagID); err != nil {
+		return nil, err
+	}
+
+	goal.UserID = existing.UserID
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, notFound(err, ErrGoalNotFound)
+	}
+	if err := s.loadProgress(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *goalServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.goalRepo.Delete(ctx, id)
+}
+
+func (s *goalServiceImpl) Contribute(ctx context.Context, userID, id string, tx *model.Transaction) (*model.Transaction, error) {
+	goal, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if goal.TagID == nil {
+		return nil, ErrGoalTagMissing
+	}
+
+	if tx.Currency == "" {
+		tx.Currency = goal.Currency
+	}
+	if tx.Currency != goal.Currency {
+		return nil, apperror.Validation(apperror.FieldWithParams("currency", "not_allowed", map[string]any{"allowed": goal.Currency}))
+	}
+	if tx.Description == "" {
+		tx.Description = goal.Name
+	}
+
+	tag, err := s.tagRepo.GetByID(ctx, *goal.TagID)
+	if err != nil {
+		return nil, notFound(err, ErrGoalTagMissing)
+	}
+
+	var created *model.Transaction
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.txService.Create(ctx, userID, tx)
+		if err != nil {
+			return err
+		}
+		_, err = s.tagRepo.Attach(ctx, []string{tag.ID}, []string{created.ID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created.Tags = []*model.Tag{tag}
+	return created, nil
+}
+
+// loadProgress рассчитывает прогресс целей по взносам, взносы читаются одним запросом
+func (s *goalServiceImpl) loadProgress(ctx context.Context, goals ...*model.Goal) error {
+	if len(goals) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(goals))
+	for i, goal := range goals {
+		ids[i] = goal.ID
+	}
+	contributions, err := s.goalRepo.SumContributions(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, goal := range goals {
+		goal.Progress = goalProgress(goal, contributions[goal.ID], now)
+	}
+	return nil
+}
+
+func (s *goalServiceImpl) get(ctx context.Context, userID, id string) (*model.Goal, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrGoalNotFound
+	}
+
+	goal, err := s.goalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrGoalNotFound)
+	}
+	if goal.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return goal, nil
+}
+
+// checkTag проверяет, что метка цели существует и принадлежит пользователю
+func (s *goalServiceImpl) checkTag(ctx context.Context, userID string, tagID *string) error {
+	if tagID == nil {
+		return nil
+	}
+	if _, err := uuid.Parse(*tagID); err != nil {
+		return ErrInvalidTag
+	}
+
+	tag, err := s.tagRepo.GetByID(ctx, *tagID)
+	if err != nil {
+		return notFound(err, ErrInvalidTag)
+	}
+	if tag.UserID != userID {
+		return ErrInvalidTag
+	}
+	return nil
+}
+
+// goalProgress рассчитывает прогресс цели на момент now.
+// Темп взносов — накопленная сумма, делённая на время с начала накопления:
+// с создания цели или с первого взноса, если он был раньше
+func goalProgress(goal *model.Goal, c *model.GoalContributions, now time.Time) *model.GoalProgress {
+	if c == nil {
+		c = &model.GoalContributions{}
+	}
+
+	target := model.Cents(goal.TargetAmount)
+	saved := model.Cents(c.Total)
+	p := &model.GoalProgress{
+		Saved:         fromCents(saved),
+		Remaining:     fromCents(max(target-saved, 0)),
+		Contributions: c.Count,
+		Achieved:      saved >= target,
+	}
+	if target > 0 {
+		p.Percent = math.Min(math.Round(float64(saved)*10000/float64(target))/100, 100)
+	}
+
+	if p.Achieved {
+		if goal.Deadline != nil {
+			zero, onTrack := 0.0, true
+			p.MonthlyRequired, p.OnTrack = &zero, &onTrack
+		}
+		return p
+	}
+
+	if saved > 0 {
+		start := goal.CreatedAt
+		if c.FirstDate != nil && c.FirstDate.Before(start) {
+			start = *c.FirstDate
+		}
+		elapsed := max(now.Sub(start), minPacePeriod)
+
+		// Время до цели при текущем темпе: elapsed * remaining / saved
+		eta := float64(elapsed) * float64(target-saved) / float64(saved)
+		if eta < float64(maxProjection) {
+			projected := now.Add(time.Duration(eta))
+			p.ProjectedDate = &projected
+		}
+	}
+
+	if goal.Deadline != nil {
+		months := math.Max(goal.Deadline.Sub(now).Hours()/24/daysPerMonth, 1)
+		monthly := fromCents(int64(math.Ceil(float64(target-saved) / months)))
+		onTrack := p.ProjectedDate != nil && !p.ProjectedDate.After(*goal.Deadline)
+		p.MonthlyRequired, p.OnTrack = &monthly, &onTrack
+	}
+
+	return p
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
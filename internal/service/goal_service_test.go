@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestGoalProgress(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	month := time.Duration(daysPerMonth * 24 * float64(time.Hour))
+	deadline := now.Add(6 * month)
+
+	// 25 000 из 100 000 за три месяца: при том же темпе ещё девять месяцев
+	goal := &model.Goal{TargetAmount: 100000, Deadline: &deadline, CreatedAt: now.Add(-3 * month)}
+	p := goalProgress(goal, &model.GoalContributions{Total: 25000, Count: 3}, now)
+
+	if p.Saved != 25000 || p.Remaining != 75000 || p.Percent != 25 || p.Achieved {
+		t.Errorf("Unexpected totals: %+v", p)
+	}
+	if p.MonthlyRequired == nil || *p.MonthlyRequired != 12500 {
+		t.Errorf("Expected 12500 per month, got %v", p.MonthlyRequired)
+	}
+	if want := now.Add(9 * month); p.ProjectedDate == nil || p.ProjectedDate.Sub(want).Abs() > time.Second {
+		t.Errorf("Expected projection %v, got %v", want, p.ProjectedDate)
+	}
+	if p.OnTrack == nil || *p.OnTrack {
+		t.Errorf("Expected goal to be behind schedule, got %v", p.OnTrack)
+	}
+
+	// Первый взнос до создания цели удлиняет период темпа
+	early := now.Add(-6 * month)
+	p = goalProgress(goal, &model.GoalContributions{Total: 50000, Count: 6, FirstDate: &early}, now)
+	if p.ProjectedDate == nil || !p.ProjectedDate.Equal(deadline) || !*p.OnTrack {
+		t.Errorf("Expected projection at the deadline, got %v", p.ProjectedDate)
+	}
+
+	// Достигнутая цель
+	p = goalProgress(goal, &model.GoalContributions{Total: 120000, Count: 2}, now)
+	if !p.Achieved || p.Remaining != 0 || p.Percent != 100 || p.ProjectedDate != nil || *p.MonthlyRequired != 0 {
+		t.Errorf("Expected achieved goal, got %+v", p)
+	}
+
+	// Без взносов и с прошедшим сроком: откладывать нужно всё сразу, прогноза нет
+	past := now.Add(-month)
+	goal = &model.Goal{TargetAmount: 1000.5, Deadline: &past, CreatedAt: now.Add(-2 * month)}
+	p = goalProgress(goal, nil, now)
+	if *p.MonthlyRequired != 1000.5 || p.ProjectedDate != nil || *p.OnTrack {
+		t.Errorf("Unexpected progress for overdue goal: %+v", p)
+	}
+
+	// Без срока ежемесячный взнос не считается
+	goal = &model.Goal{TargetAmount: 1000, CreatedAt: now}
+	if p = goalProgress(goal, &model.GoalContributions{Total: 100, Count: 1}, now); p.MonthlyRequired != nil || p.OnTrack != nil {
+		t.Errorf("Expected no schedule without deadline, got %+v", p)
+	}
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/merchant"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMerchantNotFound      = apperror.New(apperror.KindNotFound, "merchant_not_found")
+	ErrMerchantAliasNotFound = apperror.New(apperror.KindNotFound, "merchant_alias_not_found")
+	ErrMerchantAliasTaken    = apperror.New(apperror.KindConflict, "merchant_alias_taken")
+)
+
+// maxMerchantText соответствует длине колонок merchants.name и merchant_aliases.key
+const maxMerchantText = 255
+
+type MerchantService interface {
+	// Возвращает продавцов пользователя
+	GetAll(ctx context.Context, userID string) ([]*model.Merchant, error)
+
+	// Возвращает продавца по ID
+	GetByID(ctx context.Context, userID, id string) (*model.Merchant, error)
+
+	// Переименовывает продавца
+	Rename(ctx context.Context, userID, id, name string) (*model.Merchant, error)
+
+	// Удаляет продавца, его транзакции остаются без продавца
+	Delete(ctx context.Context, userID, id string) error
+
+	// Привязывает к продавцу описание: новые транзакции с таким описанием попадут к нему
+	AddAlias(ctx context.Context, userID, id, alias string) (*model.Merchant, error)
+
+	// Отвязывает описание от продавца
+	RemoveAlias(ctx context.Context, userID, id, alias string) (*model.Merchant, error)
+
+	// Объединяет продавцов sourceIDs с продавцом id: ключи и транзакции переходят к нему
+	Merge(ctx context.Context, userID, id string, sourceIDs []string) (*model.Merchant, error)
+
+	// Привязывает к продавцам транзакции пользователя, у которых продавца ещё нет.
+	// Возвращает число привязанных транзакций
+	Match(ctx context.Context, userID string) (int64, error)
+}
+
+type merchantServiceImpl struct {
+	merchantRepo repository.MerchantRepository
+	transactor   repository.Transactor
+}
+
+func NewMerchantService(merchantRepo repository.MerchantRepository, transactor repository.Transactor) MerchantService {
+	return &merchantServiceImpl{
+		merchantRepo: merchantRepo,
+		transactor:   transactor,
+	}
+}
+
+func (s *merchantServiceImpl) GetAll(ctx context.Context, userID string) ([]*model.Merchant, error) {
+	return s.merchantRepo.GetByUserID(ctx, userID)
+}
+
+func (s *merchantServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Merchant, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrMerchantNotFound
+	}
+
+	m, err := s.merchantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrMerchantNotFound)
+	}
+	if m.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return m, nil
+}
+
+func (s *merchantServiceImpl) Rename(ctx context.Context, userID, id, name string) (*model.Merchant, error) {
+	m, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Name = strings.TrimSpace(name)
+	if err := s.merchantRepo.Update(ctx, m); err != nil {
+		return nil, notFound(err, ErrMerchantNotFound)
+	}
+	return m, nil
+}
+
+func (s *merchantServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.merchantRepo.Delete(ctx, id)
+}
+
+func (s *merchantServiceImpl) AddAlias(ctx context.Context, userID, id, alias string) (*model.Merchant, error) {
+	key, err := aliasKey(alias)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.merchantRepo.AddAlias(ctx, userID, id, key); err != nil {
+		if errors.Is(err, repo.ErrMerchantAliasTaken) {
+			return nil, ErrMerchantAliasTaken
+		}
+		return nil, err
+	}
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *merchantServiceImpl) RemoveAlias(ctx context.Context, userID, id, alias string) (*model.Merchant, error) {
+	key, err := aliasKey(alias)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.merchantRepo.DeleteAlias(ctx, id, key); err != nil {
+		return nil, notFound(err, ErrMerchantAliasNotFound)
+	}
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *merchantServiceImpl) Merge(ctx context.Context, userID, id string, sourceIDs []string) (*model.Merchant, error) {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{id: true}
+	var sources []string
+	for _, sourceID := range sourceIDs {
+		if parsed, err := uuid.Parse(sourceID); err == nil {
+			sourceID = parsed.String()
+		}
+		if seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+
+		if _, err := s.GetByID(ctx, userID, sourceID); err != nil {
+			return nil, err
+		}
+		sources = append(sources, sourceID)
+	}
+	if len(sources) == 0 {
+		return nil, apperror.Validation(apperror.Field("source_ids", "required"))
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.merchantRepo.Merge(ctx, id, sources)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, userID, id)
+}
+
+func (s *merchantServiceImpl) Match(ctx context.Context, userID string) (int64, error) {
+	descriptions, err := s.merchantRepo.GetUnmatchedDescriptions(ctx, userID)
+	if err != nil || len(descriptions) == 0 {
+		return 0, err
+	}
+
+	var matched int64
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		names := make(map[string]string)
+		keys := make(map[string]string, len(descriptions))
+		for _, description := range descriptions {
+			if key := merchantKey(description); key != "" {
+				keys[description] = key
+				names[key] = merchantName(description)
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		ids, err := s.merchantRepo.Resolve(ctx, userID, names)
+		if err != nil {
+			return err
+		}
+
+		assignments := make(map[string]string, len(keys))
+		for description, key := range keys {
+			assignments[description] = ids[key]
+		}
+		matched, err = s.merchantRepo.AssignByDescription(ctx, userID, assignments)
+		return err
+	})
+	return matched, err
+}
+
+// assignMerchants привязывает транзакции к продавцам по описанию, создавая недостающих продавцов.
+// Транзакции, в описании которых не нашлось названия, остаются без продавца
+func assignMerchants(ctx context.Context, merchantRepo repository.MerchantRepository, userID string, txs ...*model.Transaction) error {
+	names := make(map[string]string)
+	keys := make([]string, len(txs))
+	for i, tx := range txs {
+		tx.MerchantID = nil
+		keys[i] = merchantKey(tx.Description)
+		// Название нового продавца берётся из первого описания с этим ключом
+		if _, ok := names[keys[i]]; !ok && keys[i] != "" {
+			names[keys[i]] = merchantName(tx.Description)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	ids, err := merchantRepo.Resolve(ctx, userID, names)
+	if err != nil {
+		return err
+	}
+
+	for i, tx := range txs {
+		if id, ok := ids[keys[i]]; ok {
+			tx.MerchantID = &id
+		}
+	}
+	return nil
+}
+
+// aliasKey нормализует описание, которое пользователь привязывает к продавцу
+func aliasKey(alias string) (string, error) {
+	key := merchantKey(alias)
+	if key == "" {
+		return "", apperror.Validation(apperror.Field("alias", "invalid_format"))
+	}
+	return key, nil
+}
+
+func merchantKey(description string) string {
+	return truncateRunes(merchant.Key(description), maxMerchantText)
+}
+
+func merchantName(description string) string {
+	return truncateRunes(merchant.Name(description), maxMerchantText)
+}
+
+func truncateRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestAssignMerchants(t *testing.T) {
+	merchantRepo := &mockMerchantRepository{}
+	stale := "merchant-stale"
+	txs := []*model.Transaction{
+		{Description: "PYATEROCHKA 1234 MOSCOW RUS"},
+		{Description: "Пятёрочка 5678"},
+		{Description: "427601******1234 0042", MerchantID: &stale},
+	}
+
+	if err := assignMerchants(context.Background(), merchantRepo, "user-1", txs...); err != nil {
+		t.Fatalf("assignMerchants returned error: %v", err)
+	}
+
+	for _, tx := range txs[:2] {
+		if tx.MerchantID == nil || *tx.MerchantID != "merchant-pyaterochka" {
+			t.Errorf("%q: expected merchant-pyaterochka, got %v", tx.Description, tx.MerchantID)
+		}
+	}
+	if txs[2].MerchantID != nil {
+		t.Errorf("Expected description without a name to drop the merchant, got %v", *txs[2].MerchantID)
+	}
+	if len(merchantRepo.resolved) != 1 {
+		t.Errorf("Expected one merchant to resolve, got %v", merchantRepo.resolved)
+	}
+	if name := merchantRepo.resolved["pyaterochka"]; name != "Pyaterochka" {
+		t.Errorf("Expected name from the first description, got %q", name)
+	}
+}
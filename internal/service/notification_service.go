@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/pkg/notify"
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
+)
+
+var (
+	ErrNotificationTargetNotFound     = apperror.New(apperror.KindNotFound, "notification_target_not_found")
+	ErrNotificationChannelUnavailable = apperror.New(apperror.KindConflict, "notification_channel_unavailable")
+)
+
+const (
+	// notificationBatchSize сколько уведомлений отправляется за один запрос к очереди
+	notificationBatchSize = 50
+	// notificationLease на сколько откладывается уведомление, взятое на отправку.
+	// Если экземпляр упал во время отправки, уведомление снова станет доступно после lease
+	notificationLease = 5 * time.Minute
+	// maxNotificationAttempts после стольких неудачных попыток уведомление больше не отправляется
+	maxNotificationAttempts = 10
+	// Задержка перед повторной попыткой растёт вдвое с каждой попыткой от минуты до шести часов
+	notificationRetryBase = time.Minute
+	notificationRetryMax  = 6 * time.Hour
+	// notificationRetention сколько хранятся отправленные и окончательно не отправленные уведомления
+	notificationRetention = 30 * 24 * time.Hour
+	// maxNotificationAddress соответствует длине колонки notification_targets.address
+	maxNotificationAddress = 2048
+)
+
+// NotificationRenderer готовит текст уведомления на языке locale.
+// Контекст уже содержит эту локаль, поэтому i18n.T возвращает сообщения на нужном языке
+type NotificationRenderer func(ctx context.Context, locale string) (*model.NotificationContent, error)
+
+type NotificationService interface {
+	// Ставит уведомление о событии в очередь для всех каналов пользователя, в которых событие
+	// не выключено. Вызывается в транзакции изменения, о котором уведомляет
+	Notify(ctx context.Context, userID string, event model.NotificationEvent, render NotificationRenderer) error
+
+	// Возвращает каналы, для которых настроена отправка на сервере
+	AvailableChannels() []model.NotificationChannel
+
+	// Возвращает адреса пользователя в каналах
+	GetTargets(ctx context.Context, userID string) ([]*model.NotificationTarget, error)
+
+	// Сохраняет адрес пользователя в канале. Уведомления в канал пишутся на языке locale
+	SetTarget(ctx context.Context, userID string, channel model.NotificationChannel, address, locale string) (*model.NotificationTarget, error)
+
+	// Удаляет адрес пользователя в канале
+	DeleteTarget(ctx context.Context, userID string, channel model.NotificationChannel) error
+
+	// Возвращает настройки для всех пар событие — канал с учётом значений по умолчанию
+	GetPreferences(ctx context.Context, userID string) ([]*model.NotificationPreference, error)
+
+	// Сохраняет настройки событий и возвращает все настройки
+	SetPreferences(ctx context.Context, userID string, prefs []*model.NotificationPreference) ([]*model.NotificationPreference, error)
+
+	// Отправляет уведомления, срок отправки которых наступил. Возвращает число отправленных
+	Deliver(ctx context.Context) (int, error)
+
+	// Периодически отправляет уведомления из очереди и удаляет старые, пока не отменён ctx
+	RunDelivery(ctx context.Context, interval time.Duration)
+}
+
+type notificationServiceImpl struct {
+	notificationRepo repository.NotificationRepository
+	notifiers        map[model.NotificationChannel]notify.Notifier
+	events           EventPublisher
+	transactor       repository.Transactor
+	now              func() time.Time
+}
+
+// NewNotificationService создаёт сервис уведомлений. notifiers — отправители для каналов,
+// настроенных на сервере; в остальные каналы уведомления не ставятся
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	notifiers map[model.NotificationChannel]notify.Notifier,
+	events EventPublisher,
+	transactor repository.Transactor,
+) NotificationService {
+	return &notificationServiceImpl{
+		notificationRepo: notificationRepo,
+		notifiers:        notifiers,
+		events:           events,
+		transactor:       transactor,
+		now:              time.Now,
+	}
+}
+
+func (s *notificationServiceImpl) Notify(ctx context.Context, userID string, event model.NotificationEvent, render NotificationRenderer) error {
+	targets, err := s.notificationRepo.GetTargets(ctx, userID)
+	if err != nil || len(targets) == 0 {
+		return err
+	}
+
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	disabled := make(map[model.NotificationChannel]bool)
+	for _, pref := range stored {
+		if pref.Event == event && !pref.Enabled {
+			disabled[pref.Channel] = true
+		}
+	}
+
+	contents := make(map[string]*model.NotificationContent)
+	var notifications []*model.Notification
+	for _, target := range targets {
+		if s.notifiers[target.Channel] == nil || disabled[target.Channel] {
+			continue
+		}
+
+		content, ok := contents[target.Locale]
+		if !ok {
+			content, err = render(i18n.WithLocale(ctx, target.Locale), target.Locale)
+			if err != nil {
+				return err
+			}
+			contents[target.Locale] = content
+		}
+
+		var data []byte
+		if content.Data != nil {
+			if data, err = json.Marshal(content.Data); err != nil {
+				return err
+			}
+		}
+
+		notifications = append(notifications, &model.Notification{
+			UserID:  userID,
+			Event:   event,
+			Channel: target.Channel,
+			Address: target.Address,
+			Subject: content.Subject,
+			Text:    content.Text,
+			Data:    data,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return s.notificationRepo.Enqueue(ctx, notifications)
+}
+
+func (s *notificationServiceImpl) AvailableChannels() []model.NotificationChannel {
+	var channels []model.NotificationChannel
+	for _, channel := range model.NotificationChannels {
+		if s.notifiers[channel] != nil {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+func (s *notificationServiceImpl) GetTargets(ctx context.Context, userID string) ([]*model.NotificationTarget, error) {
+	return s.notificationRepo.GetTargets(ctx, userID)
+}
+
+func (s *notificationServiceImpl) SetTarget(ctx context.Context, userID string, channel model.NotificationChannel, address, locale string) (*model.NotificationTarget, error) {
+	if !channel.IsValid() {
+		return nil, ErrNotificationTargetNotFound
+	}
+	if s.notifiers[channel] == nil {
+		return nil, ErrNotificationChannelUnavailable
+	}
+	if err := validateNotificationAddress(channel, address); err != nil {
+		return nil, err
+	}
+
+	target := &model.NotificationTarget{
+		UserID:  userID,
+		Channel: channel,
+		Address: address,
+		Locale:  locale,
+	}
+	err := s.updateSettings(ctx, userID, func(ctx context.Context) error {
+		return s.notificationRepo.UpsertTarget(ctx, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (s *notificationServiceImpl) DeleteTarget(ctx context.Context, userID string, channel model.NotificationChannel) error {
+	if !channel.IsValid() {
+		return ErrNotificationTargetNotFound
+	}
+	return s.updateSettings(ctx, userID, func(ctx context.Context) error {
+		return notFound(s.notificationRepo.DeleteTarget(ctx, userID, channel), ErrNotificationTargetNotFound)
+	})
+}
+
+func (s *notificationServiceImpl) GetPreferences(ctx context.Context, userID string) ([]*model.NotificationPreference, error) {
+	stored, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		event   model.NotificationEvent
+		channel model.NotificationChannel
+	}
+	enabled := make(map[key]bool, len(stored))
+	for _, pref := range stored {
+		enabled[key{pref.Event, pref.Channel}] = pref.Enabled
+	}
+
+	prefs := make([]*model.NotificationPreference, 0, len(model.NotificationEvents)*len(model.NotificationChannels))
+	for _, event := range model.NotificationEvents {
+		for _, channel := range model.NotificationChannels {
+			value, ok := enabled[key{event, channel}]
+			prefs = append(prefs, &model.NotificationPreference{
+				Event:   event,
+				Channel: channel,
+				Enabled: value || !ok,
+			})
+		}
+	}
+	return prefs, nil
+}
+
+func (s *notificationServiceImpl) SetPreferences(ctx context.Context, userID string, prefs []*model.NotificationPreference) ([]*model.NotificationPreference, error) {
+	var fields []apperror.FieldError
+	for i, pref := range prefs {
+		prefix := "preferences[" + strconv.Itoa(i) + "]."
+		if !pref.Event.IsValid() {
+			fields = append(fields, apperror.Field(prefix+"event", "invalid_format"))
+		}
+		if !pref.Channel.IsValid() {
+			fields = append(fields, apperror.Field(prefix+"channel", "invalid_format"))
+		}
+	}
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields...)
+	}
+
+	if len(prefs) > 0 {
+		err := s.updateSettings(ctx, userID, func(ctx context.Context) error {
+			return s.notificationRepo.SetPreferences(ctx, userID, prefs)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// updateSettings применяет изменение настроек пользователя и публикует событие
+// с настройками до и после изменения в той же транзакции БД
+func (s *notificationServiceImpl) updateSettings(ctx context.Context, userID string, apply func(ctx context.Context) error) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		previous, err := s.settings(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := apply(ctx); err != nil {
+			return err
+		}
+		current, err := s.settings(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewSettingsUpdated(userID, current, previous))
+	})
+}
+
+// settings возвращает адреса и настройки уведомлений пользователя
+func (s *notificationServiceImpl) settings(ctx context.Context, userID string) (*model.UserSettings, error) {
+	targets, err := s.notificationRepo.GetTargets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserSettings{NotificationTargets: targets, NotificationPreferences: prefs}, nil
+}
+
+func (s *notificationServiceImpl) Deliver(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		batch, err := s.notificationRepo.ClaimDue(ctx, notificationBatchSize, notificationLease)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, n := range batch {
+			ok, err := s.deliver(ctx, n)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(batch) < notificationBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver отправляет одно уведомление и записывает результат попытки.
+// Ошибка возвращается только при сбое записи результата
+func (s *notificationServiceImpl) deliver(ctx context.Context, n *model.Notification) (bool, error) {
+	notifier := s.notifiers[n.Channel]
+	if notifier == nil {
+		// Канал отключили на сервере после постановки уведомления в очередь
+		return false, s.notificationRepo.MarkFailed(ctx, n.ID, "channel is not configured", nil)
+	}
+
+	err := notifier.Send(ctx, &notify.Message{
+		To:      n.Address,
+		Event:   string(n.Event),
+		Subject: n.Subject,
+		Text:    n.Text,
+		Data:    n.Data,
+	})
+	if err == nil {
+		return true, s.notificationRepo.MarkSent(ctx, n.ID)
+	}
+	if ctx.Err() != nil {
+		// Сервер останавливается: уведомление отправится после истечения lease
+		return false, ctx.Err()
+	}
+
+	attempt := n.Attempts + 1
+	var next *time.Time
+	if !notify.IsPermanent(err) && attempt < maxNotificationAttempts {
+		at := s.now().Add(notificationBackoff(attempt))
+		next = &at
+	}
+	log.Printf("notifications: %s delivery %s failed (attempt %d): %v", n.Channel, n.ID, attempt, err)
+	return false, s.notificationRepo.MarkFailed(ctx, n.ID, err.Error(), next)
+}
+
+func (s *notificationServiceImpl) RunDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Deliver(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("notifications: delivery failed: %v", err)
+			}
+			if _, err := s.notificationRepo.DeleteSentBefore(ctx, s.now().Add(-notificationRetention)); err != nil && ctx.Err() == nil {
+				log.Printf("notifications: cleanup failed: %v", err)
+			}
+		}
+	}
+}
+
+// notificationBackoff задержка перед попыткой attempt + 1
+func notificationBackoff(attempt int) time.Duration {
+	return retryDelay(attempt, notificationRetryBase, notificationRetryMax)
+}
+
+// validateNotificationAddress проверяет адрес получателя в канале
+func validateNotificationAddress(channel model.NotificationChannel, address string) error {
+	var err error
+	switch channel {
+	case model.NotificationEmail:
+		err = notify.ValidateEmail(address)
+	case model.NotificationWebhook:
+		err = webhook.ValidateURL(address)
+	case model.NotificationTelegram:
+		err = notify.ValidateChatID(address)
+	}
+	if err != nil {
+		return apperror.Validation(apperror.Field("address", "invalid_format"))
+	}
+	if len(address) > maxNotificationAddress {
+		return apperror.Validation(apperror.FieldWithParams("address", "too_long", map[string]any{"max": maxNotificationAddress}))
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/gibbon/finace-dashboard/internal/i18n"
+	"github.com/gibbon/finace-dashboard/pkg/notify"
+)
+
+// stubNotificationRepository хранит настройки и очередь уведомлений в памяти
+type stubNotificationRepository struct {
+	repository.NotificationRepository
+	targets  []*model.NotificationTarget
+	prefs    []*model.NotificationPreference
+	enqueued []*model.Notification
+	sent     []string
+	failed   map[string]*time.Time
+}
+
+func (m *stubNotificationRepository) GetTargets(ctx context.Context, userID string) ([]*model.NotificationTarget, error) {
+	return m.targets, nil
+}
+
+func (m *stubNotificationRepository) GetPreferences(ctx context.Context, userID string) ([]*model.NotificationPreference, error) {
+	return m.prefs, nil
+}
+
+func (m *stubNotificationRepository) Enqueue(ctx context.Context, notifications []*model.Notification) error {
+	m.enqueued = append(m.enqueued, notifications...)
+	return nil
+}
+
+func (m *stubNotificationRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.Notification, error) {
+	claimed := m.enqueued
+	m.enqueued = nil
+	return claimed, nil
+}
+
+func (m *stubNotificationRepository) MarkSent(ctx context.Context, id string) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *stubNotificationRepository) MarkFailed(ctx context.Context, id, lastError string, nextAttemptAt *time.Time) error {
+	if m.failed == nil {
+		m.failed = make(map[string]*time.Time)
+	}
+	m.failed[id] = nextAttemptAt
+	return nil
+}
+
+// stubNotifier возвращает ошибку, заданную для адреса
+type stubNotifier struct {
+	errs map[string]error
+}
+
+func (n *stubNotifier) Send(ctx context.Context, msg *notify.Message) error {
+	return n.errs[msg.To]
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	repo := &stubNotificationRepository{
+		targets: []*model.NotificationTarget{
+			{Channel: model.NotificationEmail, Address: "user@example.com", Locale: i18n.LocaleEN},
+			{Channel: model.NotificationWebhook, Address: "https://example.com/hook", Locale: i18n.LocaleRU},
+			{Channel: model.NotificationTelegram, Address: "42", Locale: i18n.LocaleEN},
+		},
+		prefs: []*model.NotificationPreference{
+			{Event: model.NotificationAnomalyDetected, Channel: model.NotificationTelegram, Enabled: false},
+		},
+	}
+	// Отправитель вебхуков на сервере не настроен
+	svc := NewNotificationService(repo, map[model.NotificationChannel]notify.Notifier{
+		model.NotificationEmail:    &stubNotifier{},
+		model.NotificationTelegram: &stubNotifier{},
+	}, &mockEventPublisher{}, &mockTransactor{})
+
+	renders := 0
+	err := svc.Notify(context.Background(), "user-1", model.NotificationAnomalyDetected, func(ctx context.Context, locale string) (*model.NotificationContent, error) {
+		renders++
+		return &model.NotificationContent{
+			Subject: i18n.T(ctx, "notification.anomaly_detected"),
+			Text:    locale,
+			Data:    map[string]string{"id": "a-1"},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	if len(repo.enqueued) != 1 || renders != 1 {
+		t.Fatalf("Expected a single email notification, got %d (renders %d)", len(repo.enqueued), renders)
+	}
+	n := repo.enqueued[0]
+	if n.Channel != model.NotificationEmail || n.Address != "user@example.com" || n.Subject != "Unusual spending" || string(n.Data) != `{"id":"a-1"}` {
+		t.Errorf("Unexpected notification %+v", n)
+	}
+}
+
+func TestNotificationService_Deliver(t *testing.T) {
+	repo := &stubNotificationRepository{
+		enqueued: []*model.Notification{
+			{ID: "ok", Channel: model.NotificationEmail, Address: "ok@example.com"},
+			{ID: "retry", Channel: model.NotificationEmail, Address: "retry@example.com", Attempts: 2},
+			{ID: "last", Channel: model.NotificationEmail, Address: "retry@example.com", Attempts: maxNotificationAttempts - 1},
+			{ID: "gone", Channel: model.NotificationEmail, Address: "gone@example.com"},
+			{ID: "off", Channel: model.NotificationTelegram, Address: "42"},
+		},
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc := NewNotificationService(repo, map[model.NotificationChannel]notify.Notifier{
+		model.NotificationEmail: &stubNotifier{errs: map[string]error{
+			"retry@example.com": errors.New("connection refused"),
+			"gone@example.com":  notify.Permanent(errors.New("550 no such user")),
+		}},
+	}, &mockEventPublisher{}, &mockTransactor{}).(*notificationServiceImpl)
+	svc.now = func() time.Time { return now }
+
+	sent, err := svc.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	if sent != 1 || len(repo.sent) != 1 || repo.sent[0] != "ok" {
+		t.Errorf("Expected only ok to be sent, got %d %v", sent, repo.sent)
+	}
+
+	if next := repo.failed["retry"]; next == nil || !next.Equal(now.Add(4*time.Minute)) {
+		t.Errorf("Expected third attempt in 4 minutes, got %v", next)
+	}
+	for _, id := range []string{"last", "gone", "off"} {
+		if next, ok := repo.failed[id]; !ok || next != nil {
+			t.Errorf("Expected %s to fail permanently, got %v", id, next)
+		}
+	}
+}
+
+func TestNotificationBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		5: 16 * time.Minute,
+		9: 256 * time.Minute,
+		// Задержка не растёт больше шести часов
+		10: 6 * time.Hour,
+		40: 6 * time.Hour,
+	}
+	for attempt, want := range cases {
+		if got := notificationBackoff(attempt); got != want {
+			t.Errorf("notificationBackoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+)
+
+// AccessPolicy проверяет права пользователя на данные с учётом рабочих пространств.
+// Личные данные доступны только их владельцу, данные пространства — его участникам
+// в соответствии с ролью
+type AccessPolicy interface {
+	// Authorize проверяет право perm на данные владельца ownerID, принадлежащие
+	// пространству workspaceID (nil — личные данные). Без права возвращает ErrForbidden
+	Authorize(ctx context.Context, userID, ownerID string, workspaceID *string, perm model.Permission) error
+
+	// AuthorizeWorkspace проверяет право perm в пространстве. Не участнику возвращает ErrForbidden
+	AuthorizeWorkspace(ctx context.Context, userID, workspaceID string, perm model.Permission) error
+}
+
+type accessPolicy struct {
+	workspaceRepo repository.WorkspaceRepository
+}
+
+func NewAccessPolicy(workspaceRepo repository.WorkspaceRepository) AccessPolicy {
+	return &accessPolicy{
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+func (p *accessPolicy) Authorize(ctx context.Context, userID, ownerID string, workspaceID *string, perm model.Permission) error {
+	if workspaceID != nil {
+		return p.AuthorizeWorkspace(ctx, userID, *workspaceID, perm)
+	}
+	if ownerID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (p *accessPolicy) AuthorizeWorkspace(ctx context.Context, userID, workspaceID string, perm model.Permission) error {
+	role, err := p.workspaceRepo.GetMemberRole(ctx, workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrForbidden
+		}
+		return err
+	}
+	if !role.Can(perm) {
+		return ErrForbidden
+	}
+	return nil
+}
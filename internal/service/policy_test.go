@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestAccessPolicy_Authorize(t *testing.T) {
+	const workspaceID = "66666666-6666-6666-6666-666666666666"
+	workspace := workspaceID
+	policy := NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {"owner": model.WorkspaceOwner, "editor": model.WorkspaceEditor, "viewer": model.WorkspaceViewer},
+	}})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		userID      string
+		ownerID     string
+		workspaceID *string
+		perm        model.Permission
+		wantErr     error
+	}{
+		{"личные данные владельца", "user-1", "user-1", nil, model.PermissionWrite, nil},
+		{"чужие личные данные", "user-2", "user-1", nil, model.PermissionRead, ErrForbidden},
+		{"owner пишет", "owner", "editor", &workspace, model.PermissionWrite, nil},
+		{"owner управляет", "owner", "owner", &workspace, model.PermissionManage, nil},
+		{"editor пишет в чужую транзакцию пространства", "editor", "owner", &workspace, model.PermissionWrite, nil},
+		{"editor не управляет", "editor", "editor", &workspace, model.PermissionManage, ErrForbidden},
+		{"viewer читает", "viewer", "owner", &workspace, model.PermissionRead, nil},
+		{"viewer не пишет", "viewer", "viewer", &workspace, model.PermissionWrite, ErrForbidden},
+		{"не участник не читает", "stranger", "owner", &workspace, model.PermissionRead, ErrForbidden},
+		{"автор не участник не пишет", "stranger", "stranger", &workspace, model.PermissionWrite, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(ctx, tt.userID, tt.ownerID, tt.workspaceID, tt.perm)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, ожидалось %v", err, tt.wantErr)
+			}
+			if tt.workspaceID == nil {
+				return
+			}
+			err = policy.AuthorizeWorkspace(ctx, tt.userID, *tt.workspaceID, tt.perm)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorizeWorkspace() error = %v, ожидалось %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccessPolicy_MembershipChanges(t *testing.T) {
+	const workspaceID = "66666666-6666-6666-6666-666666666666"
+	workspaceRepo := &mockWorkspaceInvitations{
+		mockWorkspaceRepository: &mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+			workspaceID: {"owner": model.WorkspaceOwner, "editor": model.WorkspaceEditor, "viewer": model.WorkspaceViewer},
+		}},
+	}
+	policy := NewAccessPolicy(workspaceRepo)
+	svc := NewWorkspaceService(workspaceRepo, newMockUserRepository(), nil, &mockTransactor{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		change  func() error
+		userID  string
+		perm    model.Permission
+		wantErr error
+	}{
+		{
+			name:    "viewer, повышенный до editor, пишет",
+			change:  func() error { return svc.UpdateMember(ctx, "owner", workspaceID, "viewer", model.WorkspaceEditor) },
+			userID:  "viewer",
+			perm:    model.PermissionWrite,
+			wantErr: nil,
+		},
+		{
+			name:    "исключённый участник не читает",
+			change:  func() error { return svc.RemoveMember(ctx, "owner", workspaceID, "editor") },
+			userID:  "editor",
+			perm:    model.PermissionRead,
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "роль владельца не меняется",
+			change: func() error {
+				if err := svc.UpdateMember(ctx, "owner", workspaceID, "owner", model.WorkspaceViewer); !errors.Is(err, ErrWorkspaceOwner) {
+					return fmt.Errorf("UpdateMember(owner) error = %v, ожидалось ErrWorkspaceOwner", err)
+				}
+				if err := svc.RemoveMember(ctx, "owner", workspaceID, "owner"); !errors.Is(err, ErrWorkspaceOwner) {
+					return fmt.Errorf("RemoveMember(owner) error = %v, ожидалось ErrWorkspaceOwner", err)
+				}
+				return nil
+			},
+			userID:  "owner",
+			perm:    model.PermissionManage,
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); err != nil {
+				t.Fatalf("изменение состава: %v", err)
+			}
+			if err := policy.AuthorizeWorkspace(ctx, tt.userID, workspaceID, tt.perm); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorizeWorkspace() error = %v, ожидалось %v", err, tt.wantErr)
+			}
+		})
+	}
+}
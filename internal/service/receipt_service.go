@@ -0,0 +1,275 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/audit"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/fiscal"
+	"github.com/gibbon/finace-dashboard/pkg/qrcode"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReceiptAlreadyImported = apperror.New(apperror.KindConflict, "receipt_already_imported")
+	ErrReceiptQRNotFound      = apperror.New(apperror.KindBadRequest, "receipt_qr_not_found")
+	ErrInvalidReceiptImage    = apperror.New(apperror.KindBadRequest, "invalid_receipt_image")
+)
+
+// DefaultReceiptTimezone часовой пояс чеков по умолчанию: время в QR-коде указано без пояса
+const DefaultReceiptTimezone = "Europe/Moscow"
+
+// maxReceiptImagePixels ограничивает размер распознаваемого изображения
+const maxReceiptImagePixels = 40_000_000
+
+// ReceiptImport параметры импорта чека
+type ReceiptImport struct {
+	QR          string
+	Timezone    string
+	Description string
+}
+
+// ReceiptService импортирует чеки. Этапы импорта публикуются событиями import.progress
+type ReceiptService interface {
+	// Создаёт транзакцию по строке QR-кода кассового чека
+	Import(ctx context.Context, userID string, req *ReceiptImport) (*model.FiscalReceipt, error)
+
+	// Распознаёт QR-код чека на изображении и создаёт транзакцию
+	ImportImage(ctx context.Context, userID string, data []byte, req *ReceiptImport) (*model.FiscalReceipt, error)
+}
+
+type receiptServiceImpl struct {
+	receiptRepo  repository.FiscalReceiptRepository
+	categoryRepo repository.CategoryRepository
+	txService    TransactionService
+	events       EventPublisher
+	transactor   repository.Transactor
+}
+
+func NewReceiptService(
+	receiptRepo repository.FiscalReceiptRepository,
+	categoryRepo repository.CategoryRepository,
+	txService TransactionService,
+	events EventPublisher,
+	transactor repository.Transactor,
+) ReceiptService {
+	return &receiptServiceImpl{
+		receiptRepo:  receiptRepo,
+		categoryRepo: categoryRepo,
+		txService:    txService,
+		events:       events,
+		transactor:   transactor,
+	}
+}
+
+func (s *receiptServiceImpl) Import(ctx context.Context, userID string, req *ReceiptImport) (*model.FiscalReceipt, error) {
+	progress := s.newProgress(ctx, userID)
+	if err := progress.publish(ctx, event.ImportStarted, nil); err != nil {
+		return nil, err
+	}
+	return s.importQR(ctx, progress, req)
+}
+
+func (s *receiptServiceImpl) ImportImage(ctx context.Context, userID string, data []byte, req *ReceiptImport) (*model.FiscalReceipt, error) {
+	progress := s.newProgress(ctx, userID)
+	if err := progress.publish(ctx, event.ImportStarted, nil); err != nil {
+		return nil, err
+	}
+
+	text, err := decodeReceiptQR(data)
+	if err != nil {
+		return nil, progress.fail(ctx, err)
+	}
+	if err := progress.publish(ctx, event.ImportRecognized, nil); err != nil {
+		return nil, err
+	}
+
+	return s.importQR(ctx, progress, &ReceiptImport{
+		QR:          text,
+		Timezone:    req.Timezone,
+		Description: req.Description,
+	})
+}
+
+// importQR создаёт транзакцию по строке QR-кода. Завершение импорта публикуется
+// в транзакции БД вместе с созданной транзакцией, ошибка — после её отмены
+func (s *receiptServiceImpl) importQR(ctx context.Context, progress *receiptProgress, req *ReceiptImport) (*model.FiscalReceipt, error) {
+	receipt, err := s.createReceipt(ctx, progress, req)
+	if err != nil {
+		return nil, progress.fail(ctx, err)
+	}
+	return receipt, nil
+}
+
+func (s *receiptServiceImpl) createReceipt(ctx context.Context, progress *receiptProgress, req *ReceiptImport) (*model.FiscalReceipt, error) {
+	userID := progress.userID
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = DefaultReceiptTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, apperror.Validation(apperror.Field("timezone", "invalid_format"))
+	}
+
+	parsed, err := fiscal.Parse(req.QR, loc)
+	if err != nil {
+		return nil, fiscalError(err)
+	}
+
+	if _, err := s.receiptRepo.GetByFiscalID(ctx, userID, parsed.FN, parsed.FD, parsed.FP); err == nil {
+		return nil, ErrReceiptAlreadyImported
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		description = receiptDescription(parsed)
+	}
+
+	// Возврат по чеку — поступление денег, а не трата: он относится к доходам
+	var categoryID *int
+	if parsed.Operation.IsInflow() {
+		if categoryID, err = s.incomeCategoryID(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	receipt := &model.FiscalReceipt{
+		UserID:    userID,
+		FN:        parsed.FN,
+		FD:        parsed.FD,
+		FP:        parsed.FP,
+		Operation: int(parsed.Operation),
+		Raw:       strings.TrimSpace(req.QR),
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx, err := s.txService.Create(ctx, userID, &model.Transaction{
+			Amount:      parsed.Amount,
+			Currency:    string(model.CurrencyRUB),
+			Description: description,
+			Date:        parsed.Time,
+			CategoryID:  categoryID,
+		})
+		if err != nil {
+			return err
+		}
+
+		receipt.TransactionID = tx.ID
+		receipt.Transaction = tx
+		if err := s.receiptRepo.Create(ctx, receipt); err != nil {
+			return err
+		}
+		return progress.publish(ctx, event.ImportCompleted, &tx.ID)
+	})
+	if errors.Is(err, repo.ErrReceiptExists) {
+		return nil, ErrReceiptAlreadyImported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// decodeReceiptQR распознаёт строку QR-кода чека на изображении
+func decodeReceiptQR(data []byte) (string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width*cfg.Height > maxReceiptImagePixels {
+		return "", ErrInvalidReceiptImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidReceiptImage
+	}
+
+	text, err := qrcode.Decode(img)
+	if err != nil {
+		return "", ErrReceiptQRNotFound
+	}
+	return text, nil
+}
+
+// receiptProgress публикует этапы одного импорта чека
+type receiptProgress struct {
+	events EventPublisher
+	userID string
+	id     string
+}
+
+// newProgress начинает импорт. Его ID — X-Request-Id запроса, чтобы клиент связал этапы
+// со своим запросом, а без заголовка — новый UUID
+func (s *receiptServiceImpl) newProgress(ctx context.Context, userID string) *receiptProgress {
+	id := audit.RequestFromContext(ctx).RequestID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return &receiptProgress{events: s.events, userID: userID, id: id}
+}
+
+func (p *receiptProgress) publish(ctx context.Context, stage string, transactionID *string) error {
+	e := event.NewImportProgress(p.userID, p.id, event.ImportSourceReceipt, stage)
+	e.TransactionID = transactionID
+	return p.events.Publish(ctx, e)
+}
+
+// fail публикует неудачу импорта с кодом ошибки и возвращает саму ошибку:
+// ошибка публикации не должна её заменить
+func (p *receiptProgress) fail(ctx context.Context, err error) error {
+	e := event.NewImportProgress(p.userID, p.id, event.ImportSourceReceipt, event.ImportFailed)
+	code := apperror.From(err).Code
+	e.Error = &code
+	p.events.Publish(ctx, e)
+	return err
+}
+
+// incomeCategoryID возвращает ID системной категории доходов
+func (s *receiptServiceImpl) incomeCategoryID(ctx context.Context) (*int, error) {
+	categories, err := s.categoryRepo.GetDefault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cat := range categories {
+		if isIncomeCategory(cat) {
+			return &cat.ID, nil
+		}
+	}
+	return nil, errors.New("income category is missing")
+}
+
+// fiscalError переводит ошибку разбора строки чека в ошибку валидации поля qr
+func fiscalError(err error) error {
+	var ferr *fiscal.Error
+	if !errors.As(err, &ferr) {
+		return err
+	}
+	code := "invalid_fiscal_param"
+	if ferr.Missing {
+		code = "missing_fiscal_param"
+	}
+	return apperror.Validation(apperror.FieldWithParams("qr", code, map[string]any{"param": ferr.Param}))
+}
+
+// receiptDescription описание транзакции по умолчанию: в QR-коде нет названия продавца
+func receiptDescription(receipt *fiscal.Receipt) string {
+	if receipt.Operation.IsInflow() {
+		return "Возврат по чеку №" + receipt.FD
+	}
+	return "Чек №" + receipt.FD
+}
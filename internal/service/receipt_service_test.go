@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+)
+
+type stubReceiptRepository struct {
+	receipts []*model.FiscalReceipt
+}
+
+func (m *stubReceiptRepository) Create(ctx context.Context, receipt *model.FiscalReceipt) error {
+	m.receipts = append(m.receipts, receipt)
+	return nil
+}
+
+func (m *stubReceiptRepository) GetByFiscalID(ctx context.Context, userID, fn, fd, fp string) (*model.FiscalReceipt, error) {
+	for _, receipt := range m.receipts {
+		if receipt.UserID == userID && receipt.FN == fn && receipt.FD == fd && receipt.FP == fp {
+			return receipt, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+// stubReceiptTxService создаёт транзакции без категоризации
+type stubReceiptTxService struct {
+	TransactionService
+	created []*model.Transaction
+}
+
+func (m *stubReceiptTxService) Create(ctx context.Context, userID string, tx *model.Transaction) (*model.Transaction, error) {
+	tx.ID = ownTxID
+	tx.UserID = userID
+	m.created = append(m.created, tx)
+	return tx, nil
+}
+
+// receiptCategories системные категории с категорией доходов
+func receiptCategories() *mockCategoryRepository {
+	income := incomeTranslationKey
+	return &mockCategoryRepository{categories: map[int]*model.Category{
+		1: {ID: 1, Name: "Продукты", IsDefault: true},
+		9: {ID: 9, Name: "Доходы", TranslationKey: &income, IsDefault: true},
+	}}
+}
+
+func TestReceiptService_Import(t *testing.T) {
+	receiptRepo := &stubReceiptRepository{}
+	txService := &stubReceiptTxService{}
+	events := &mockEventPublisher{}
+	svc := NewReceiptService(receiptRepo, receiptCategories(), txService, events, &mockTransactor{})
+	ctx := context.Background()
+	qr := "t=20240315T1842&s=1234.50&fn=7380440700347812&i=28461&fp=3287654321&n=1"
+
+	receipt, err := svc.Import(ctx, "user-1", &ReceiptImport{QR: qr})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	tx := receipt.Transaction
+	wantDate := time.Date(2024, 3, 15, 15, 42, 0, 0, time.UTC)
+	if tx.Amount != 1234.5 || tx.Currency != "RUB" || !tx.Date.Equal(wantDate) || tx.Description != "Чек №28461" {
+		t.Errorf("Unexpected transaction %+v", tx)
+	}
+	if receipt.TransactionID != ownTxID || receipt.FD != "28461" || receipt.Operation != 1 {
+		t.Errorf("Unexpected receipt %+v", receipt)
+	}
+
+	// Тот же чек не импортируется повторно, даже с другим описанием
+	if _, err := svc.Import(ctx, "user-1", &ReceiptImport{QR: qr, Description: "Продукты"}); !errors.Is(err, ErrReceiptAlreadyImported) {
+		t.Errorf("Expected ErrReceiptAlreadyImported, got %v", err)
+	}
+
+	// Этапы импорта публикуются для потока обновлений, этапы одного импорта связаны ImportID
+	var stages []string
+	for _, e := range events.events {
+		progress := e.(event.ImportProgress)
+		stages = append(stages, progress.Stage)
+		if progress.Source != event.ImportSourceReceipt || progress.User != "user-1" {
+			t.Errorf("Unexpected progress %+v", progress)
+		}
+	}
+	if want := []string{event.ImportStarted, event.ImportCompleted, event.ImportStarted, event.ImportFailed}; !reflect.DeepEqual(stages, want) {
+		t.Fatalf("Expected stages %v, got %v", want, stages)
+	}
+	completed, failed := events.events[1].(event.ImportProgress), events.events[3].(event.ImportProgress)
+	if completed.ImportID != events.events[0].(event.ImportProgress).ImportID || completed.TransactionID == nil || *completed.TransactionID != ownTxID {
+		t.Errorf("Unexpected completed progress %+v", completed)
+	}
+	if failed.Error == nil || *failed.Error != "receipt_already_imported" {
+		t.Errorf("Unexpected failed progress %+v", failed)
+	}
+	// Другой пользователь может импортировать свой экземпляр чека
+	if _, err := svc.Import(ctx, "user-2", &ReceiptImport{QR: qr, Timezone: "Asia/Yekaterinburg"}); err != nil {
+		t.Errorf("Import for another user: %v", err)
+	}
+	if len(txService.created) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(txService.created))
+	}
+	if got := txService.created[1].Date; !got.Equal(wantDate.Add(-2 * time.Hour)) {
+		t.Errorf("Expected date in Asia/Yekaterinburg, got %v", got)
+	}
+	if txService.created[0].CategoryID != nil {
+		t.Errorf("Expected purchase without preset category, got %v", *txService.created[0].CategoryID)
+	}
+
+	// Возврат прихода относится к доходам, а не к тратам
+	refund := "t=20240316T1000&s=500&fn=7380440700347812&i=28470&fp=1111111111&n=2"
+	receipt, err = svc.Import(ctx, "user-1", &ReceiptImport{QR: refund})
+	if err != nil {
+		t.Fatalf("Import refund: %v", err)
+	}
+	if id := receipt.Transaction.CategoryID; id == nil || *id != 9 {
+		t.Errorf("Expected refund in income category 9, got %v", id)
+	}
+}
+
+func TestReceiptService_ImportInvalid(t *testing.T) {
+	svc := NewReceiptService(&stubReceiptRepository{}, receiptCategories(), &stubReceiptTxService{}, &mockEventPublisher{}, &mockTransactor{})
+	ctx := context.Background()
+
+	tests := []struct {
+		req   ReceiptImport
+		field string
+		code  string
+	}{
+		{ReceiptImport{QR: "t=20240315T1842&s=1&fn=1&i=1&n=1"}, "qr", "missing_fiscal_param"},
+		{ReceiptImport{QR: "t=20240315T1842&s=abc&fn=1&i=1&fp=1&n=1"}, "qr", "invalid_fiscal_param"},
+		{ReceiptImport{QR: "t=20240315T1842&s=1&fn=1&i=1&fp=1&n=1", Timezone: "Mars/Olympus"}, "timezone", "invalid_format"},
+	}
+	for _, tt := range tests {
+		_, err := svc.Import(ctx, "user-1", &tt.req)
+		appErr := apperror.From(err)
+		if appErr.Kind != apperror.KindValidation || len(appErr.Fields) != 1 ||
+			appErr.Fields[0].Field != tt.field || appErr.Fields[0].Code != tt.code {
+			t.Errorf("%q: expected %s/%s, got %v", tt.req.QR, tt.field, tt.code, err)
+		}
+	}
+
+	if _, err := svc.ImportImage(ctx, "user-1", []byte("not an image"), &ReceiptImport{}); !errors.Is(err, ErrInvalidReceiptImage) {
+		t.Errorf("Expected ErrInvalidReceiptImage, got %v", err)
+	}
+}
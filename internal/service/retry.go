@@ -0,0 +1,12 @@
+package service
+
+import "time"
+
+// retryDelay задержка перед попыткой attempt + 1: base, удваиваемая с каждой попыткой, но не больше max
+func retryDelay(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+)
+
+type SplitService interface {
+	// Возвращает строки разбивки транзакции
+	Get(ctx context.Context, userID, transactionID string) ([]*model.TransactionSplit, error)
+
+	// Создаёт разбивку транзакции. Если транзакция уже разбита, возвращает ErrSplitsAlreadyExist
+	Create(ctx context.Context, userID, transactionID string, splits []*model.TransactionSplit) ([]*model.TransactionSplit, error)
+
+	// Заменяет разбивку транзакции целиком
+	Replace(ctx context.Context, userID, transactionID string, splits []*model.TransactionSplit) ([]*model.TransactionSplit, error)
+
+	// Удаляет разбивку, транзакция снова относится к одной категории
+	Delete(ctx context.Context, userID, transactionID string) error
+}
+
+var (
+	ErrSplitsAlreadyExist  = apperror.New(apperror.KindConflict, "splits_already_exist")
+	ErrTransactionHasSplit = apperror.New(apperror.KindConflict, "transaction_has_splits")
+)
+
+type splitServiceImpl struct {
+	txRepo       repository.TransactionRepository
+	splitRepo    repository.TransactionSplitRepository
+	categoryRepo repository.CategoryRepository
+	events       EventPublisher
+	transactor   repository.Transactor
+	policy       AccessPolicy
+}
+
+func NewSplitService(
+	txRepo repository.TransactionRepository,
+	splitRepo repository.TransactionSplitRepository,
+	categoryRepo repository.CategoryRepository,
+	events EventPublisher,
+	transactor repository.Transactor,
+	policy AccessPolicy,
+) SplitService {
+	return &splitServiceImpl{
+		txRepo:       txRepo,
+		splitRepo:    splitRepo,
+		categoryRepo: categoryRepo,
+		events:       events,
+		transactor:   transactor,
+		policy:       policy,
+	}
+}
+
+func (s *splitServiceImpl) Get(ctx context.Context, userID, transactionID string) ([]*model.TransactionSplit, error) {
+	if _, err := s.transaction(ctx, userID, transactionID, model.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.splitRepo.GetByTransactionID(ctx, transactionID)
+}
+
+func (s *splitServiceImpl) Create(ctx context.Context, userID, transactionID string, splits []*model.TransactionSplit) ([]*model.TransactionSplit, error) {
+	return s.save(ctx, userID, transactionID, splits, false)
+}
+
+func (s *splitServiceImpl) Replace(ctx context.Context, userID, transactionID string, splits []*model.TransactionSplit) ([]*model.TransactionSplit, error) {
+	return s.save(ctx, userID, transactionID, splits, true)
+}
+
+func (s *splitServiceImpl) Delete(ctx context.Context, userID, transactionID string) error {
+	tx, err := s.transaction(ctx, userID, transactionID, model.PermissionWrite)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.splitRepo.GetByTransactionID(ctx, transactionID)
+		if err != nil || len(existing) == 0 {
+			return err
+		}
+		if err := s.splitRepo.DeleteByTransactionID(ctx, transactionID); err != nil {
+			return err
+		}
+		return s.publishUpdated(ctx, tx, existing, nil)
+	})
+}
+
+func (s *splitServiceImpl) save(ctx context.Context, userID, transactionID string, splits []*model.TransactionSplit, replace bool) ([]*model.TransactionSplit, error) {
+	tx, err := s.transaction(ctx, userID, transactionID, model.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSplitsTotal(tx, splits); err != nil {
+		return nil, err
+	}
+
+	// Категории строк доступны так же, как категория самой транзакции
+	for i, split := range splits {
+		category, err := s.categoryRepo.GetByID(ctx, split.CategoryID)
+		if err == nil {
+			err = categoryUsable(ctx, s.policy, userID, tx.WorkspaceID, category)
+		}
+		if err != nil && !errors.Is(err, repo.ErrNotFound) && !errors.Is(err, ErrInvalidCategory) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, apperror.Validation(apperror.Field("splits["+strconv.Itoa(i)+"].category_id", "invalid_category"))
+		}
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.splitRepo.GetByTransactionID(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+		if !replace && len(existing) > 0 {
+			return ErrSplitsAlreadyExist
+		}
+		if err := s.splitRepo.Replace(ctx, transactionID, splits); err != nil {
+			return err
+		}
+		return s.publishUpdated(ctx, tx, existing, splits)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
+
+// publishUpdated отмечает изменение разбивки у транзакции и публикует transaction.updated,
+// чтобы журнал аудита, вебхуки, поток обновлений и синхронизация увидели новую разбивку
+func (s *splitServiceImpl) publishUpdated(ctx context.Context, tx *model.Transaction, before, after []*model.TransactionSplit) error {
+	previous := *tx
+	previous.Splits = before
+	tx.Splits = after
+	if err := s.txRepo.Touch(ctx, tx); err != nil {
+		return notFound(err, ErrTransactionNotFound)
+	}
+	return s.events.Publish(ctx, event.NewTransactionUpdated(tx, &previous))
+}
+
+// transaction загружает транзакцию и проверяет право пользователя на неё
+func (s *splitServiceImpl) transaction(ctx context.Context, userID, transactionID string, perm model.Permission) (*model.Transaction, error) {
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, notFound(err, ErrTransactionNotFound)
+	}
+	if err := s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, perm); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// checkSplitsTotal проверяет, что строки в сумме дают сумму транзакции (с точностью до копейки)
+func checkSplitsTotal(tx *model.Transaction, splits []*model.TransactionSplit) error {
+	expected := model.Cents(tx.Amount)
+	actual := model.SplitsTotal(splits)
+	if expected == actual {
+		return nil
+	}
+	return apperror.Validation(apperror.FieldWithParams("splits", "split_sum_mismatch", map[string]any{
+		"expected": formatCents(expected),
+		"actual":   formatCents(actual),
+	}))
+}
+
+// formatCents форматирует сумму в копейках для сообщений об ошибке
+func formatCents(cents int64) string {
+	return strconv.FormatFloat(float64(cents)/100, 'f', 2, 64)
+}
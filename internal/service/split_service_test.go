@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+const sharedSplitTxID = "66666666-6666-6666-6666-666666666666"
+
+func newSplitTestService() (SplitService, *mockSplitRepository, *mockEventPublisher) {
+	otherUser, member := "user-2", "user-3"
+	workspaceID := "ws-1"
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-1", Amount: 1000.10},
+		&model.Transaction{ID: otherTxID, UserID: "user-2", Amount: 100},
+		&model.Transaction{ID: sharedSplitTxID, UserID: "user-3", WorkspaceID: &workspaceID, Amount: 100},
+	)
+	categoryRepo := &mockCategoryRepository{categories: map[int]*model.Category{
+		1:  {ID: 1, Name: "Продукты", IsDefault: true},
+		6:  {ID: 6, Name: "Дом", IsDefault: true},
+		50: {ID: 50, Name: "Чужая", UserID: &otherUser},
+		51: {ID: 51, Name: "Дача", UserID: &member},
+	}}
+	policy := NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {"user-1": model.WorkspaceEditor, member: model.WorkspaceOwner},
+	}})
+	splitRepo := &mockSplitRepository{}
+	events := &mockEventPublisher{}
+	return NewSplitService(txRepo, splitRepo, categoryRepo, events, &mockTransactor{}, policy), splitRepo, events
+}
+
+func TestSplitService_Create(t *testing.T) {
+	svc, splitRepo, _ := newSplitTestService()
+	ctx := context.Background()
+
+	// Сумма float64 накапливает погрешность, поэтому сравнение идёт в копейках
+	splits := []*model.TransactionSplit{
+		{CategoryID: 1, Amount: 999.9},
+		{CategoryID: 6, Amount: 0.1},
+		{CategoryID: 6, Amount: 0.1},
+	}
+	if _, err := svc.Create(ctx, "user-1", ownTxID, splits); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if len(splitRepo.splits[ownTxID]) != 3 {
+		t.Fatalf("Expected 3 stored splits, got %d", len(splitRepo.splits[ownTxID]))
+	}
+
+	if _, err := svc.Create(ctx, "user-1", ownTxID, splits); !errors.Is(err, ErrSplitsAlreadyExist) {
+		t.Errorf("Expected ErrSplitsAlreadyExist, got %v", err)
+	}
+	if _, err := svc.Replace(ctx, "user-1", ownTxID, splits[:2]); err == nil {
+		t.Error("Expected sum mismatch error")
+	}
+}
+
+func TestSplitService_Validation(t *testing.T) {
+	svc, _, _ := newSplitTestService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		txID   string
+		splits []*model.TransactionSplit
+		code   string
+	}{
+		{
+			name:   "sum mismatch",
+			txID:   ownTxID,
+			splits: []*model.TransactionSplit{{CategoryID: 1, Amount: 500}, {CategoryID: 6, Amount: 500}},
+			code:   apperror.CodeValidationFailed,
+		},
+		{
+			name:   "foreign category",
+			txID:   ownTxID,
+			splits: []*model.TransactionSplit{{CategoryID: 1, Amount: 500.10}, {CategoryID: 50, Amount: 500}},
+			code:   apperror.CodeValidationFailed,
+		},
+		{
+			name:   "foreign transaction",
+			txID:   otherTxID,
+			splits: []*model.TransactionSplit{{CategoryID: 1, Amount: 50}, {CategoryID: 6, Amount: 50}},
+			code:   "forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Replace(ctx, "user-1", tt.txID, tt.splits)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if code := apperror.From(err).Code; code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestSplitService_Workspace(t *testing.T) {
+	svc, splitRepo, events := newSplitTestService()
+	ctx := context.Background()
+
+	// В пространстве доступна категория другого участника, но не личная категория постороннего
+	if _, err := svc.Create(ctx, "user-1", sharedSplitTxID, []*model.TransactionSplit{
+		{CategoryID: 51, Amount: 60},
+		{CategoryID: 50, Amount: 40},
+	}); apperror.From(err).Code != apperror.CodeValidationFailed {
+		t.Errorf("Create() с категорией постороннего error = %v, ожидалась ошибка валидации", err)
+	}
+	if _, err := svc.Create(ctx, "user-1", sharedSplitTxID, []*model.TransactionSplit{
+		{CategoryID: 51, Amount: 60},
+		{CategoryID: 1, Amount: 40},
+	}); err != nil {
+		t.Fatalf("Create() с категорией участника error = %v", err)
+	}
+
+	// Изменение разбивки публикуется как изменение транзакции
+	if err := svc.Delete(ctx, "user-1", sharedSplitTxID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(splitRepo.splits[sharedSplitTxID]) != 0 {
+		t.Error("разбивка не удалена")
+	}
+	if published := events.published(event.TransactionUpdatedName); len(published) != 2 {
+		t.Fatalf("опубликовано %d transaction.updated, ожидалось 2", len(published))
+	}
+	updated := events.events[1].(event.TransactionUpdated)
+	if len(updated.Previous.Splits) != 2 || len(updated.Transaction.Splits) != 0 {
+		t.Errorf("удаление разбивки: до %d строк, после %d", len(updated.Previous.Splits), len(updated.Transaction.Splits))
+	}
+}
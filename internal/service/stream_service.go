@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+)
+
+const (
+	// streamReplayWindow сколько хранятся события для повтора после переподключения
+	streamReplayWindow = 10 * time.Minute
+	// streamReplaySize сколько последних событий пользователя хранится для повтора
+	streamReplaySize = 200
+	// streamBufferSize сколько событий ждёт отправки клиенту. Отстающий клиент
+	// отключается и при переподключении получает пропущенное повтором
+	streamBufferSize = 64
+	// streamPruneInterval как часто удаляются пользователи без клиентов и свежих событий
+	streamPruneInterval = time.Minute
+)
+
+// StreamSubscription подписка клиента на события пользователя.
+// Events закрывается, если клиент отстал или подписка закрыта
+type StreamSubscription struct {
+	// Replay события, пропущенные после Last-Event-ID, старые первые
+	Replay []*model.StreamEvent
+	// Reset часть пропущенных событий уже не хранится: клиенту нужно перечитать данные
+	Reset  bool
+	Events <-chan *model.StreamEvent
+
+	close func()
+}
+
+// Close отписывает клиента
+func (s *StreamSubscription) Close() {
+	s.close()
+}
+
+type StreamService interface {
+	// Подписывает клиента на события пользователя. Если передан lastEventID,
+	// сначала возвращаются события после него
+	Open(userID, lastEventID string) *StreamSubscription
+
+	// Подписывается на события, которые передаются клиентам, на всех экземплярах сервиса
+	Subscribe(bus *eventbus.Bus)
+}
+
+type streamEntry struct {
+	event      *model.StreamEvent
+	receivedAt time.Time
+}
+
+type streamUser struct {
+	recent  []streamEntry
+	clients map[chan *model.StreamEvent]struct{}
+}
+
+type streamServiceImpl struct {
+	mu     sync.Mutex
+	users  map[string]*streamUser
+	pruned time.Time
+	now    func() time.Time
+}
+
+func NewStreamService() StreamService {
+	return &streamServiceImpl{
+		users: make(map[string]*streamUser),
+		now:   time.Now,
+	}
+}
+
+func (s *streamServiceImpl) Open(userID, lastEventID string) *StreamSubscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.user(userID)
+	events := make(chan *model.StreamEvent, streamBufferSize)
+	u.clients[events] = struct{}{}
+
+	sub := &StreamSubscription{
+		Events: events,
+		close: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := u.clients[events]; ok {
+				delete(u.clients, events)
+				close(events)
+			}
+		},
+	}
+
+	if lastEventID == "" {
+		return sub
+	}
+
+	// ID событий упорядочены по времени, поэтому пропущенные — все с большим ID
+	found := false
+	for _, entry := range u.recent {
+		switch {
+		case entry.event.ID == lastEventID:
+			found = true
+		case entry.event.ID > lastEventID:
+			sub.Replay = append(sub.Replay, entry.event)
+		}
+	}
+	sub.Reset = !found
+	return sub
+}
+
+// user возвращает состояние пользователя, создавая его при необходимости. Вызывается под s.mu
+func (s *streamServiceImpl) user(userID string) *streamUser {
+	u, ok := s.users[userID]
+	if !ok {
+		u = &streamUser{clients: make(map[chan *model.StreamEvent]struct{})}
+		s.users[userID] = u
+	}
+	return u
+}
+
+func (s *streamServiceImpl) Subscribe(bus *eventbus.Bus) {
+	bus.SubscribeBroadcast(event.TransactionCreatedName, s.handleEvent)
+	bus.SubscribeBroadcast(event.TransactionUpdatedName, s.handleEvent)
+	bus.SubscribeBroadcast(event.TransactionDeletedName, s.handleEvent)
+	bus.SubscribeBroadcast(event.ImportProgressName, s.handleEvent)
+}
+
+func (s *streamServiceImpl) handleEvent(ctx context.Context, e eventbus.Event) error {
+	var (
+		id string
+		tx *model.Transaction
+	)
+	switch e := e.(type) {
+	case event.TransactionCreated:
+		id, tx = e.ID, e.Transaction
+	case event.TransactionUpdated:
+		id, tx = e.ID, e.Transaction
+	case event.TransactionDeleted:
+		id, tx = e.ID, e.Transaction
+	case event.ImportProgress:
+		s.publish(e.User, &model.StreamEvent{ID: e.ID, Name: e.EventName(), Data: toImportPayload(e)})
+		return nil
+	default:
+		return nil
+	}
+
+	s.publish(tx.UserID, &model.StreamEvent{ID: id, Name: e.EventName(), Data: toTransactionPayload(tx)})
+	return nil
+}
+
+// publish сохраняет событие для повтора и передаёт его подключённым клиентам пользователя
+func (s *streamServiceImpl) publish(userID string, e *model.StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	u := s.user(userID)
+	u.recent = append(u.recent, streamEntry{event: e, receivedAt: now})
+	if len(u.recent) > streamReplaySize {
+		u.recent = u.recent[len(u.recent)-streamReplaySize:]
+	}
+	u.recent = freshEntries(u.recent, now)
+
+	for client := range u.clients {
+		select {
+		case client <- e:
+		default:
+			delete(u.clients, client)
+			close(client)
+		}
+	}
+
+	if now.Sub(s.pruned) >= streamPruneInterval {
+		s.prune(now)
+	}
+}
+
+// prune удаляет устаревшие события и пользователей без клиентов и событий. Вызывается под s.mu
+func (s *streamServiceImpl) prune(now time.Time) {
+	s.pruned = now
+	for userID, u := range s.users {
+		u.recent = freshEntries(u.recent, now)
+		if len(u.recent) == 0 && len(u.clients) == 0 {
+			delete(s.users, userID)
+		}
+	}
+}
+
+// freshEntries отбрасывает события старше streamReplayWindow
+func freshEntries(entries []streamEntry, now time.Time) []streamEntry {
+	i := 0
+	for i < len(entries) && now.Sub(entries[i].receivedAt) > streamReplayWindow {
+		i++
+	}
+	return entries[i:]
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestStreamService(t *testing.T) {
+	svc := NewStreamService().(*streamServiceImpl)
+	ctx := context.Background()
+
+	publish := func(userID string) event.TransactionCreated {
+		t.Helper()
+		e := event.NewTransactionCreated(&model.Transaction{ID: "tx-" + userID, UserID: userID})
+		if err := svc.handleEvent(ctx, e); err != nil {
+			t.Fatalf("handleEvent returned error: %v", err)
+		}
+		return e
+	}
+
+	live := svc.Open("user-1", "")
+	first := publish("user-1")
+	publish("user-2")
+	second := publish("user-1")
+
+	for _, want := range []string{first.ID, second.ID} {
+		select {
+		case e := <-live.Events:
+			if e.ID != want || e.Name != event.TransactionCreatedName {
+				t.Errorf("Expected %s, got %+v", want, e)
+			}
+		default:
+			t.Fatalf("Expected event %s for connected client", want)
+		}
+	}
+	if len(live.Events) != 0 {
+		t.Error("Client must not receive events of another user")
+	}
+	live.Close()
+	if _, ok := <-live.Events; ok {
+		t.Error("Expected events channel to be closed")
+	}
+
+	// Повтор после последнего полученного события
+	resumed := svc.Open("user-1", first.ID)
+	defer resumed.Close()
+	if resumed.Reset || len(resumed.Replay) != 1 || resumed.Replay[0].ID != second.ID {
+		t.Errorf("Expected replay of %s, got reset=%v replay=%v", second.ID, resumed.Reset, resumed.Replay)
+	}
+
+	// Неизвестный ID: пропущенное могло не сохраниться
+	unknown := svc.Open("user-1", "00000000-0000-7000-8000-000000000000")
+	defer unknown.Close()
+	if !unknown.Reset || len(unknown.Replay) != 2 {
+		t.Errorf("Expected reset with 2 replayed events, got reset=%v replay=%d", unknown.Reset, len(unknown.Replay))
+	}
+}
+
+func TestStreamService_SlowClient(t *testing.T) {
+	svc := NewStreamService().(*streamServiceImpl)
+	slow := svc.Open("user-1", "")
+	defer slow.Close()
+
+	for i := 0; i <= streamBufferSize; i++ {
+		svc.handleEvent(context.Background(), event.NewTransactionCreated(&model.Transaction{UserID: "user-1"}))
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != streamBufferSize {
+		t.Errorf("Expected slow client to be disconnected after %d events, got %d", streamBufferSize, received)
+	}
+}
+
+func TestStreamService_ReplayWindow(t *testing.T) {
+	svc := NewStreamService().(*streamServiceImpl)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	old := event.NewTransactionCreated(&model.Transaction{UserID: "user-1"})
+	svc.handleEvent(context.Background(), old)
+
+	now = now.Add(streamReplayWindow + time.Minute)
+	fresh := event.NewTransactionCreated(&model.Transaction{UserID: "user-1"})
+	svc.handleEvent(context.Background(), fresh)
+
+	sub := svc.Open("user-1", old.ID)
+	defer sub.Close()
+	if !sub.Reset || len(sub.Replay) != 1 || sub.Replay[0].ID != fresh.ID {
+		t.Errorf("Expected reset with only the fresh event, got reset=%v replay=%v", sub.Reset, sub.Replay)
+	}
+}
+
+func TestStreamService_ImportProgress(t *testing.T) {
+	svc := NewStreamService().(*streamServiceImpl)
+	sub := svc.Open("user-1", "")
+	defer sub.Close()
+
+	e := event.NewImportProgress("user-1", "import-1", event.ImportSourceReceipt, event.ImportStarted)
+	if err := svc.handleEvent(context.Background(), e); err != nil {
+		t.Fatalf("handleEvent returned error: %v", err)
+	}
+
+	select {
+	case got := <-sub.Events:
+		payload, ok := got.Data.(*importPayload)
+		if got.ID != e.ID || got.Name != event.ImportProgressName || !ok || payload.ImportID != "import-1" || payload.Stage != event.ImportStarted {
+			t.Errorf("Unexpected stream event %+v", got)
+		}
+	default:
+		t.Fatal("Expected import progress event")
+	}
+}
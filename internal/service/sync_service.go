@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+)
+
+var (
+	ErrSyncExists   = apperror.New(apperror.KindConflict, "sync_exists")
+	ErrSyncModified = apperror.New(apperror.KindConflict, "sync_modified")
+	ErrSyncDeleted  = apperror.New(apperror.KindConflict, "sync_deleted")
+)
+
+type SyncService interface {
+	// Возвращает изменения транзакций, правил и категорий после токена since.
+	// Пустой since означает первую синхронизацию: возвращаются все данные без удалённых
+	Pull(ctx context.Context, userID, since, locale string) (*model.SyncChanges, error)
+
+	// Применяет офлайн-изменения транзакций по порядку, каждое отдельно.
+	// При конфликте побеждает версия сервера, она возвращается в результате
+	Push(ctx context.Context, userID string, changes []*model.SyncChange) ([]*model.SyncResult, error)
+}
+
+type syncServiceImpl struct {
+	syncRepo     repository.SyncRepository
+	txRepo       repository.TransactionRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	txService    TransactionService
+	policy       AccessPolicy
+}
+
+func NewSyncService(
+	syncRepo repository.SyncRepository,
+	txRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	txService TransactionService,
+	policy AccessPolicy,
+) SyncService {
+	return &syncServiceImpl{
+		syncRepo:     syncRepo,
+		txRepo:       txRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		txService:    txService,
+		policy:       policy,
+	}
+}
+
+func (s *syncServiceImpl) Pull(ctx context.Context, userID, since, locale string) (*model.SyncChanges, error) {
+	var sinceToken int64
+	if since != "" {
+		token, err := strconv.ParseInt(since, 10, 64)
+		if err != nil || token <= 0 {
+			return nil, apperror.Validation(apperror.Field("since", "invalid_format"))
+		}
+		sinceToken = token
+	}
+
+	// Токен берётся до чтения изменений: всё, что зафиксируется позже, попадёт в следующую выборку
+	token, err := s.syncRepo.CurrentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &model.SyncChanges{Token: strconv.FormatInt(token, 10)}
+
+	if changes.Transactions, err = s.syncRepo.GetTransactionChanges(ctx, userID, sinceToken); err != nil {
+		return nil, err
+	}
+	if changes.Rules, err = s.syncRepo.GetRuleChanges(ctx, userID, sinceToken); err != nil {
+		return nil, err
+	}
+	if changes.Categories, err = s.syncRepo.GetCategoryChanges(ctx, userID, sinceToken); err != nil {
+		return nil, err
+	}
+
+	categories := append(changes.Categories.Created, changes.Categories.Updated...)
+	if err := localizeCategories(ctx, s.categoryRepo, locale, categories...); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, append(changes.Transactions.Created, changes.Transactions.Updated...)); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// loadTags заполняет метки транзакций одним запросом
+func (s *syncServiceImpl) loadTags(ctx context.Context, txs []*model.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.ID
+	}
+
+	tags, err := s.tagRepo.GetByTransactionIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		tx.Tags = tags[tx.ID]
+	}
+	return nil
+}
+
+func (s *syncServiceImpl) Push(ctx context.Context, userID string, changes []*model.SyncChange) ([]*model.SyncResult, error) {
+	ids := make([]string, len(changes))
+	for i, change := range changes {
+		ids[i] = change.Transaction.ID
+	}
+
+	// Удалённые транзакции тоже загружаются: их ID занят, а изменение удалённой — конфликт
+	existing := make(map[string]*model.Transaction, len(ids))
+	if len(ids) > 0 {
+		txs, err := s.txRepo.GetByIDsWithDeleted(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			existing[tx.ID] = tx
+		}
+	}
+
+	results := make([]*model.SyncResult, len(changes))
+	for i, change := range changes {
+		id := change.Transaction.ID
+		current, err := s.apply(ctx, userID, change, existing[id])
+
+		result := &model.SyncResult{Operation: change.Operation, ID: id, Status: model.SyncApplied, Transaction: current, Err: err}
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrSyncExists), errors.Is(err, ErrSyncModified), errors.Is(err, ErrSyncDeleted):
+			result.Status = model.SyncConflict
+		case apperror.From(err).Kind == apperror.KindInternal:
+			return nil, err
+		default:
+			result.Status = model.SyncRejected
+		}
+		results[i] = result
+
+		// Следующие изменения той же транзакции в запросе видят результат предыдущих
+		if err == nil {
+			existing[id] = syncState(change, current, existing[id])
+		}
+	}
+
+	return results, nil
+}
+
+// apply применяет одно изменение и возвращает актуальную версию транзакции на сервере
+func (s *syncServiceImpl) apply(ctx context.Context, userID string, change *model.SyncChange, existing *model.Transaction) (*model.Transaction, error) {
+	id := change.Transaction.ID
+
+	if existing != nil {
+		if err := s.policy.Authorize(ctx, userID, existing.UserID, existing.WorkspaceID, model.PermissionWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	switch change.Operation {
+	case model.SyncCreate:
+		if existing != nil {
+			if existing.DeletedAt != nil {
+				return nil, ErrSyncDeleted
+			}
+			return existing, ErrSyncExists
+		}
+		return s.txService.Create(ctx, userID, change.Transaction)
+
+	case model.SyncUpdate:
+		if existing == nil {
+			return nil, ErrTransactionNotFound
+		}
+		if existing.DeletedAt != nil {
+			return nil, ErrSyncDeleted
+		}
+
+		updated, err := s.txService.Update(ctx, userID, change.Transaction, change.BaseUpdatedAt)
+		switch {
+		case errors.Is(err, ErrPreconditionFailed):
+			current, err := s.txService.GetByID(ctx, userID, id)
+			if err != nil {
+				return nil, s.syncError(err)
+			}
+			return current, ErrSyncModified
+		case err != nil:
+			return nil, s.syncError(err)
+		}
+		return updated, nil
+
+	case model.SyncDelete:
+		// Повторное удаление и удаление неизвестной серверу транзакции ничего не меняют
+		if existing == nil || existing.DeletedAt != nil {
+			return nil, nil
+		}
+		if change.BaseUpdatedAt != nil && !existing.UpdatedAt.Equal(*change.BaseUpdatedAt) {
+			return existing, ErrSyncModified
+		}
+		if err := s.txService.Delete(ctx, userID, id); err != nil && !errors.Is(err, ErrTransactionNotFound) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return nil, apperror.Validation(apperror.Field("op", "invalid_format"))
+}
+
+// syncError переводит исчезновение транзакции во время применения в конфликт удаления
+func (s *syncServiceImpl) syncError(err error) error {
+	if errors.Is(err, ErrTransactionNotFound) {
+		return ErrSyncDeleted
+	}
+	return err
+}
+
+// syncState состояние транзакции после успешно применённого изменения
+func syncState(change *model.SyncChange, current, previous *model.Transaction) *model.Transaction {
+	if change.Operation != model.SyncDelete {
+		return current
+	}
+	if previous == nil {
+		return nil
+	}
+
+	deleted := *previous
+	now := time.Now()
+	deleted.DeletedAt = &now
+	return &deleted
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestSyncService_Push(t *testing.T) {
+	txService, txRepo := newBulkTestService(&mockTransactor{})
+	svc := NewSyncService(nil, txRepo, nil, &mockTagRepository{}, txService, NewAccessPolicy(&mockWorkspaceRepository{}))
+
+	const (
+		newID    = "55555555-5555-5555-5555-555555555555"
+		removeID = "66666666-6666-6666-6666-666666666666"
+	)
+	version := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	txRepo.txs[removeID] = &model.Transaction{ID: removeID, UserID: "user-1", Description: "Кофе", UpdatedAt: version}
+
+	stale := version.Add(-time.Hour)
+	var zero time.Time
+	changes := []*model.SyncChange{
+		{Operation: model.SyncCreate, Transaction: &model.Transaction{ID: newID, Amount: 300, Currency: "RUB", Description: "Рынок", CategoryID: intPtr(1), IsConfirmed: true}},
+		{Operation: model.SyncCreate, Transaction: &model.Transaction{ID: ownTxID, Amount: 100, Currency: "RUB", Description: "Такси"}},
+		{Operation: model.SyncUpdate, Transaction: &model.Transaction{ID: ownTxID, Amount: 150, Currency: "RUB", Description: "Такси"}, BaseUpdatedAt: &stale},
+		{Operation: model.SyncUpdate, Transaction: &model.Transaction{ID: otherTxID, Amount: 1, Currency: "RUB", Description: "Кофе"}, BaseUpdatedAt: &zero},
+		{Operation: model.SyncDelete, Transaction: &model.Transaction{ID: removeID}, BaseUpdatedAt: &stale},
+		{Operation: model.SyncDelete, Transaction: &model.Transaction{ID: removeID}, BaseUpdatedAt: &version},
+		{Operation: model.SyncUpdate, Transaction: &model.Transaction{ID: removeID, Amount: 5, Currency: "RUB", Description: "Кофе"}, BaseUpdatedAt: &version},
+		{Operation: model.SyncDelete, Transaction: &model.Transaction{ID: goneTxID}},
+	}
+
+	results, err := svc.Push(context.Background(), "user-1", changes)
+	if err != nil {
+		t.Fatalf("Push returned error: %v", err)
+	}
+
+	want := []struct {
+		status model.SyncStatus
+		err    error
+		server bool
+	}{
+		{model.SyncApplied, nil, true},
+		{model.SyncConflict, ErrSyncExists, true},
+		{model.SyncConflict, ErrSyncModified, true},
+		{model.SyncRejected, ErrForbidden, false},
+		{model.SyncConflict, ErrSyncModified, true},
+		{model.SyncApplied, nil, false},
+		{model.SyncConflict, ErrSyncDeleted, false},
+		{model.SyncApplied, nil, false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if result.Status != want[i].status || !errors.Is(result.Err, want[i].err) {
+			t.Errorf("result %d (%s %s): expected %s/%v, got %s/%v", i, result.Operation, result.ID, want[i].status, want[i].err, result.Status, result.Err)
+		}
+		if (result.Transaction != nil) != want[i].server {
+			t.Errorf("result %d: expected server version %v, got %+v", i, want[i].server, result.Transaction)
+		}
+	}
+
+	// Клиентские ID и категория сохраняются
+	created, ok := txRepo.txs[newID]
+	if !ok {
+		t.Fatalf("Expected transaction %s to be created with client id", newID)
+	}
+	if created.UserID != "user-1" || created.CategoryID == nil || *created.CategoryID != 1 || !created.IsConfirmed {
+		t.Errorf("Expected client category to be kept, got %+v", created)
+	}
+
+	if _, ok := txRepo.deleted[removeID]; !ok {
+		t.Error("Expected transaction to be soft-deleted")
+	}
+	if tx := txRepo.txs[ownTxID]; tx.Amount != 0 {
+		t.Errorf("Conflicting update must not be applied, got amount %v", tx.Amount)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTagNotFound  = apperror.New(apperror.KindNotFound, "tag_not_found")
+	ErrTagNameTaken = apperror.New(apperror.KindConflict, "tag_name_taken")
+	ErrInvalidTag   = apperror.New(apperror.KindBadRequest, "invalid_tag")
+)
+
+type TagService interface {
+	// Возвращает метки пользователя
+	GetAll(ctx context.Context, userID string) ([]*model.Tag, error)
+
+	// Возвращает метку по ID
+	GetByID(ctx context.Context, userID, id string) (*model.Tag, error)
+
+	// Создаёт метку
+	Create(ctx context.Context, userID string, tag *model.Tag) (*model.Tag, error)
+
+	// Обновляет метку
+	Update(ctx context.Context, userID string, tag *model.Tag) (*model.Tag, error)
+
+	// Удаляет метку, связи с транзакциями удаляются каскадно
+	Delete(ctx context.Context, userID, id string) error
+
+	// Ставит или снимает метки с многих транзакций в одной транзакции БД
+	Apply(ctx context.Context, userID string, action model.TagAction, tagIDs, transactionIDs []string) (*model.TagBulkResult, error)
+
+	// Заменяет набор меток пользователя на транзакции, метки других участников пространства остаются
+	SetTransactionTags(ctx context.Context, userID, transactionID string, tagIDs []string) ([]*model.Tag, error)
+}
+
+type tagServiceImpl struct {
+	tagRepo    repository.TagRepository
+	txRepo     repository.TransactionRepository
+	transactor repository.Transactor
+	policy     AccessPolicy
+}
+
+func NewTagService(
+	tagRepo repository.TagRepository,
+	txRepo repository.TransactionRepository,
+	transactor repository.Transactor,
+	policy AccessPolicy,
+) TagService {
+	return &tagServiceImpl{
+		tagRepo:    tagRepo,
+		txRepo:     txRepo,
+		transactor: transactor,
+		policy:     policy,
+	}
+}
+
+func (s *tagServiceImpl) GetAll(ctx context.Context, userID string) ([]*model.Tag, error) {
+	return s.tagRepo.GetByUserID(ctx, userID)
+}
+
+func (s *tagServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Tag, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrTagNotFound
+	}
+
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrTagNotFound)
+	}
+
+	if tag.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	return tag, nil
+}
+
+func (s *tagServiceImpl) Create(ctx context.Context, userID string, tag *model.Tag) (*model.Tag, error) {
+	tag.UserID = userID
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, tagNameTaken(err)
+	}
+
+	return tag, nil
+}
+
+func (s *tagServiceImpl) Update(ctx context.Context, userID string, tag *model.Tag) (*model.Tag, error) {
+	existing, err := s.GetByID(ctx, userID, tag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.UserID = existing.UserID
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, notFound(tagNameTaken(err), ErrTagNotFound)
+	}
+
+	return tag, nil
+}
+
+func (s *tagServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
+
+func (s *tagServiceImpl) Apply(ctx context.Context, userID string, action model.TagAction, tagIDs, transactionIDs []string) (*model.TagBulkResult, error) {
+	tagIDs = canonicalIDs(tagIDs)
+	transactionIDs = canonicalIDs(transactionIDs)
+
+	if err := s.checkTags(ctx, userID, tagIDs); err != nil {
+		return nil, err
+	}
+
+	result := &model.TagBulkResult{}
+	owned, skipped, err := s.ownTransactions(ctx, userID, transactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	result.Skipped = skipped
+	if len(owned) == 0 {
+		return result, nil
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if action == model.TagActionRemove {
+			result.Affected, err = s.tagRepo.Detach(ctx, tagIDs, owned)
+		} else {
+			result.Affected, err = s.tagRepo.Attach(ctx, tagIDs, owned)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *tagServiceImpl) SetTransactionTags(ctx context.Context, userID, transactionID string, tagIDs []string) ([]*model.Tag, error) {
+	tx, err := s.txRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, notFound(err, ErrTransactionNotFound)
+	}
+	if err := s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, model.PermissionWrite); err != nil {
+		return nil, err
+	}
+
+	tagIDs = canonicalIDs(tagIDs)
+	if err := s.checkTags(ctx, userID, tagIDs); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.tagRepo.ReplaceForTransaction(ctx, userID, transactionID, tagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.GetByTransactionIDs(ctx, []string{transactionID})
+	if err != nil {
+		return nil, err
+	}
+	return tags[transactionID], nil
+}
+
+// checkTags проверяет, что все метки существуют и принадлежат пользователю
+func (s *tagServiceImpl) checkTags(ctx context.Context, userID string, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	for _, id := range tagIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrInvalidTag
+		}
+	}
+
+	tags, err := s.tagRepo.GetByIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag.UserID == userID {
+			owned[tag.ID] = true
+		}
+	}
+	for _, id := range tagIDs {
+		if !owned[id] {
+			return ErrInvalidTag
+		}
+	}
+	return nil
+}
+
+// ownTransactions разделяет ID на транзакции, которые пользователь может изменять, и пропущенные:
+// несуществующие и недоступные для записи
+func (s *tagServiceImpl) ownTransactions(ctx context.Context, userID string, ids []string) (owned, skipped []string, err error) {
+	var valid []string
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+
+	isOwned := make(map[string]bool)
+	if len(valid) > 0 {
+		txs, err := s.txRepo.GetByIDs(ctx, valid)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tx := range txs {
+			err := s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, model.PermissionWrite)
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				return nil, nil, err
+			}
+			isOwned[tx.ID] = err == nil
+		}
+	}
+
+	for _, id := range ids {
+		if isOwned[id] {
+			owned = append(owned, id)
+		} else {
+			skipped = append(skipped, id)
+		}
+	}
+	return owned, skipped, nil
+}
+
+// tagNameTaken заменяет ошибку уникальности репозитория на доменную
+func tagNameTaken(err error) error {
+	if errors.Is(err, repo.ErrTagNameTaken) {
+		return ErrTagNameTaken
+	}
+	return err
+}
+
+// canonicalIDs приводит UUID к виду, в котором их возвращает БД; некорректные значения не меняются
+func canonicalIDs(ids []string) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		if parsed, err := uuid.Parse(id); err == nil {
+			id = parsed.String()
+		}
+		result[i] = id
+	}
+	return result
+}
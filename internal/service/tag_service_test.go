@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+type stubTagRepository struct {
+	mockTagRepository
+	tags     map[string]*model.Tag
+	attached map[string][]string
+}
+
+func (m *stubTagRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	for _, id := range ids {
+		if tag, ok := m.tags[id]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (m *stubTagRepository) Attach(ctx context.Context, tagIDs, transactionIDs []string) (int64, error) {
+	for _, txID := range transactionIDs {
+		m.attached[txID] = append(m.attached[txID], tagIDs...)
+	}
+	return int64(len(tagIDs) * len(transactionIDs)), nil
+}
+
+// ReplaceForTransaction снимает с транзакции только метки userID
+func (m *stubTagRepository) ReplaceForTransaction(ctx context.Context, userID, transactionID string, tagIDs []string) error {
+	var kept []string
+	for _, id := range m.attached[transactionID] {
+		if m.tags[id].UserID != userID {
+			kept = append(kept, id)
+		}
+	}
+	m.attached[transactionID] = append(kept, tagIDs...)
+	return nil
+}
+
+const (
+	ownTagID   = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	otherTagID = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+)
+
+func TestTagService_Apply(t *testing.T) {
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-1"},
+		&model.Transaction{ID: otherTxID, UserID: "user-2"},
+	)
+	tagRepo := &stubTagRepository{
+		tags: map[string]*model.Tag{
+			ownTagID:   {ID: ownTagID, UserID: "user-1", Name: "vacation-2026"},
+			otherTagID: {ID: otherTagID, UserID: "user-2", Name: "business"},
+		},
+		attached: make(map[string][]string),
+	}
+	svc := NewTagService(tagRepo, txRepo, &mockTransactor{}, NewAccessPolicy(&mockWorkspaceRepository{}))
+	ctx := context.Background()
+
+	// ID в верхнем регистре приводятся к каноническому виду
+	result, err := svc.Apply(ctx, "user-1", model.TagActionAdd,
+		[]string{"AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA"},
+		[]string{ownTxID, otherTxID, goneTxID, "bad-id"})
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if result.Affected != 1 {
+		t.Errorf("Expected 1 new link, got %d", result.Affected)
+	}
+	if len(result.Skipped) != 3 {
+		t.Errorf("Expected 3 skipped transactions, got %v", result.Skipped)
+	}
+	if len(tagRepo.attached[otherTxID]) != 0 {
+		t.Error("Transaction of another user must not be tagged")
+	}
+
+	if _, err := svc.Apply(ctx, "user-1", model.TagActionAdd, []string{otherTagID}, []string{ownTxID}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("Expected ErrInvalidTag for foreign tag, got %v", err)
+	}
+}
+
+func TestTagService_Workspace(t *testing.T) {
+	workspaceID := "66666666-6666-6666-6666-666666666666"
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-2", WorkspaceID: &workspaceID},
+	)
+	tagRepo := &stubTagRepository{
+		tags: map[string]*model.Tag{
+			ownTagID:   {ID: ownTagID, UserID: "user-1", Name: "vacation-2026"},
+			otherTagID: {ID: otherTagID, UserID: "user-2", Name: "business"},
+		},
+		attached: map[string][]string{ownTxID: {otherTagID}},
+	}
+	policy := NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {"user-1": model.WorkspaceEditor, "user-2": model.WorkspaceOwner, "user-3": model.WorkspaceViewer},
+	}})
+	svc := NewTagService(tagRepo, txRepo, &mockTransactor{}, policy)
+	ctx := context.Background()
+
+	// Метки ставятся на транзакции, которые пользователь может изменять, остальные пропускаются
+	result, err := svc.Apply(ctx, "user-3", model.TagActionAdd, nil, []string{ownTxID})
+	if err != nil || len(result.Skipped) != 1 {
+		t.Errorf("viewer Apply: %+v, %v, expected transaction to be skipped", result, err)
+	}
+	result, err = svc.Apply(ctx, "user-1", model.TagActionAdd, []string{ownTagID}, []string{ownTxID})
+	if err != nil || len(result.Skipped) != 0 || result.Affected != 1 {
+		t.Errorf("editor Apply: %+v, %v, expected transaction to be tagged", result, err)
+	}
+
+	// Замена меток не снимает метки других участников
+	if _, err := svc.SetTransactionTags(ctx, "user-1", ownTxID, nil); err != nil {
+		t.Fatalf("SetTransactionTags returned error: %v", err)
+	}
+	if got := tagRepo.attached[ownTxID]; len(got) != 1 || got[0] != otherTagID {
+		t.Errorf("Expected only tags of user-2 to remain, got %v", got)
+	}
+}
@@ -2,46 +2,88 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
 	"github.com/gibbon/finace-dashboard/internal/domain/model"
 	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+	"github.com/gibbon/finace-dashboard/pkg/merchant"
 	"github.com/google/uuid"
 )
 
 type TransactionService interface {
-	// Создаёт новую транзакцию с автоматической категоризацией
+	// Создаёт новую транзакцию с автоматической категоризацией.
+	// ID и категория, заданные клиентом (синхронизация офлайн-изменений), сохраняются.
+	// Транзакция с WorkspaceID создаётся в рабочем пространстве и требует права на запись в нём
 	Create(ctx context.Context, userID string, tx *model.Transaction) (*model.Transaction, error)
 
 	// Возвращает транзакцию по ID
 	GetByID(ctx context.Context, userID, id string) (*model.Transaction, error)
 
-	// Возвращает транзакции пользователя с фильтрацией
+	// Возвращает личные транзакции пользователя или транзакции пространства filter.WorkspaceID
 	GetByUserID(ctx context.Context, filter model.TransactionFilter) ([]*model.Transaction, error)
 
-	// Обновляет транзакцию
-	Update(ctx context.Context, userID string, tx *model.Transaction) (*model.Transaction, error)
+	// Возвращает количество транзакций, отобранных фильтром как в GetByUserID, без учёта страницы
+	GetTotalCount(ctx context.Context, filter model.TransactionFilter) (int64, error)
+
+	// Обновляет транзакцию. Если expectedUpdatedAt задан, изменение применяется только
+	// к этой версии транзакции (optimistic concurrency), иначе возвращается ErrPreconditionFailed
+	Update(ctx context.Context, userID string, tx *model.Transaction, expectedUpdatedAt *time.Time) (*model.Transaction, error)
+
+	// Возвращает прежние версии транзакции, последние первыми
+	GetHistory(ctx context.Context, userID, id string) ([]*model.TransactionRevision, error)
+
+	// Возвращает транзакцию к прежней версии. Текущее состояние сохраняется новой версией,
+	// поэтому откат можно отменить таким же откатом
+	Revert(ctx context.Context, userID, id string, revision int) (*model.Transaction, error)
 
 	// Удаляет транзакцию
 	Delete(ctx context.Context, userID, id string) error
 
-	// Выполняет категоризацию транзакции
+	// Выполняет массовые операции в одной транзакции БД.
+	// Элементы, не прошедшие проверку владельца, не применяются и возвращаются с ошибкой
+	Bulk(ctx context.Context, userID string, ops []*model.BulkOperation) ([]*model.BulkItemResult, error)
+
+	// Выполняет категоризацию транзакции. Для транзакции пространства требуется право записи в нём
 	Categorize(ctx context.Context, userID string, tx *model.Transaction) error
 
-	// Создаёт правило категоризации
-	CreateRule(ctx context.Context, userID string, keyword string, categoryID int) (*model.UserCategoryRule, error)
+	// Создаёт личное правило категоризации или правило пространства workspaceID
+	CreateRule(ctx context.Context, userID string, workspaceID *string, keyword string, categoryID int) (*model.UserCategoryRule, error)
 
-	// Возвращает правила пользователя
-	GetRules(ctx context.Context, userID string) ([]*model.UserCategoryRule, error)
+	// Возвращает личные правила пользователя или правила пространства workspaceID
+	GetRules(ctx context.Context, userID string, workspaceID *string) ([]*model.UserCategoryRule, error)
 
 	// Удаляет правило
 	DeleteRule(ctx context.Context, userID, ruleID string) error
 
-	// Возвращает все категории
-	GetCategories(ctx context.Context) ([]*model.Category, error)
+	// Возвращает очередь неподтверждённых личных транзакций или транзакций пространства
+	// filter.WorkspaceID с предложенными категориями
+	GetReviewQueue(ctx context.Context, filter model.ReviewFilter, locale string) ([]*model.ReviewItem, error)
+
+	// Возвращает счётчики очереди проверки личных транзакций или транзакций пространства workspaceID
+	GetReviewCounts(ctx context.Context, userID string, workspaceID *string) (*model.ReviewCounts, error)
+
+	// Подтверждает транзакцию из очереди или назначает ей другую категорию
+	Review(ctx context.Context, userID, id string, action model.ReviewAction, categoryID *int) (*model.Transaction, error)
+
+	// Возвращает категории, доступные пользователю, с названиями на языке locale
+	GetCategories(ctx context.Context, userID, locale string) ([]*model.Category, error)
 }
 
+var (
+	ErrTransactionNotFound = apperror.New(apperror.KindNotFound, "transaction_not_found")
+	ErrRuleNotFound        = apperror.New(apperror.KindNotFound, "rule_not_found")
+	ErrInvalidCategory     = apperror.New(apperror.KindBadRequest, "invalid_category")
+	ErrPreconditionFailed  = apperror.New(apperror.KindPreconditionFailed, "precondition_failed")
+	ErrNoSuggestion        = apperror.New(apperror.KindBadRequest, "no_category_suggestion")
+	ErrRevisionNotFound    = apperror.New(apperror.KindNotFound, "transaction_revision_not_found")
+)
+
 // Результат категоризации
 type categorizationResult struct {
 	categoryID  *int
@@ -52,31 +94,71 @@ type transactionServiceImpl struct {
 	txRepo       repository.TransactionRepository
 	categoryRepo repository.CategoryRepository
 	ruleRepo     repository.UserCategoryRuleRepository
+	splitRepo    repository.TransactionSplitRepository
+	tagRepo      repository.TagRepository
+	merchantRepo repository.MerchantRepository
+	events       EventPublisher
+	transactor   repository.Transactor
+	policy       AccessPolicy
 }
 
 func NewTransactionService(
 	txRepo repository.TransactionRepository,
 	categoryRepo repository.CategoryRepository,
 	ruleRepo repository.UserCategoryRuleRepository,
+	splitRepo repository.TransactionSplitRepository,
+	tagRepo repository.TagRepository,
+	merchantRepo repository.MerchantRepository,
+	events EventPublisher,
+	transactor repository.Transactor,
+	policy AccessPolicy,
 ) TransactionService {
 	return &transactionServiceImpl{
 		txRepo:       txRepo,
 		categoryRepo: categoryRepo,
 		ruleRepo:     ruleRepo,
+		splitRepo:    splitRepo,
+		tagRepo:      tagRepo,
+		merchantRepo: merchantRepo,
+		events:       events,
+		transactor:   transactor,
+		policy:       policy,
 	}
 }
 
 func (s *transactionServiceImpl) Create(ctx context.Context, userID string, tx *model.Transaction) (*model.Transaction, error) {
-	tx.ID = uuid.New().String()
+	if tx.ID == "" {
+		tx.ID = uuid.New().String()
+	}
 	tx.UserID = userID
 	tx.CreatedAt = time.Now()
 	tx.UpdatedAt = time.Now()
 
-	if err := s.Categorize(ctx, userID, tx); err != nil {
+	if tx.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *tx.WorkspaceID, model.PermissionWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	if tx.CategoryID != nil {
+		if err := s.checkCategory(ctx, userID, tx.WorkspaceID, *tx.CategoryID); err != nil {
+			return nil, err
+		}
+	} else if err := s.Categorize(ctx, userID, tx); err != nil {
 		return nil, err
 	}
 
-	if err := s.txRepo.Create(ctx, tx); err != nil {
+	// Новый продавец и событие создаются только вместе с транзакцией
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := assignMerchants(ctx, s.merchantRepo, userID, tx); err != nil {
+			return err
+		}
+		if err := s.txRepo.Create(ctx, tx); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewTransactionCreated(tx))
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -84,73 +166,540 @@ func (s *transactionServiceImpl) Create(ctx context.Context, userID string, tx *
 }
 
 func (s *transactionServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Transaction, error) {
-	tx, err := s.txRepo.GetByID(ctx, id)
+	tx, err := s.get(ctx, userID, id, model.PermissionRead)
 	if err != nil {
 		return nil, err
 	}
 
-	// Проверка что транзакция принадлежит пользователю
-	if tx.UserID != userID {
-		return nil, ErrUnauthorized
+	if err := s.loadTags(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// get загружает транзакцию и проверяет право пользователя на неё
+func (s *transactionServiceImpl) get(ctx context.Context, userID, id string, perm model.Permission) (*model.Transaction, error) {
+	tx, err := s.txRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrTransactionNotFound)
+	}
+
+	if err := s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, perm); err != nil {
+		return nil, err
 	}
 
 	return tx, nil
 }
 
 func (s *transactionServiceImpl) GetByUserID(ctx context.Context, filter model.TransactionFilter) ([]*model.Transaction, error) {
-	// Проверка что пользователь запрашивает свои транзакции
-	filter.UserID = filter.UserID
-	return s.txRepo.GetByUserID(ctx, filter)
+	if filter.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, filter.UserID, *filter.WorkspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+
+	txs, err := s.txRepo.GetByUserID(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, txs...); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// loadTags заполняет метки транзакций одним запросом
+func (s *transactionServiceImpl) loadTags(ctx context.Context, txs ...*model.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.ID
+	}
+
+	tags, err := s.tagRepo.GetByTransactionIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		tx.Tags = tags[tx.ID]
+	}
+	return nil
 }
 
-func (s *transactionServiceImpl) Update(ctx context.Context, userID string, tx *model.Transaction) (*model.Transaction, error) {
-	// Получаем существующую транзакцию
-	existing, err := s.txRepo.GetByID(ctx, tx.ID)
+func (s *transactionServiceImpl) GetTotalCount(ctx context.Context, filter model.TransactionFilter) (int64, error) {
+	if filter.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, filter.UserID, *filter.WorkspaceID, model.PermissionRead); err != nil {
+			return 0, err
+		}
+	}
+	return s.txRepo.GetTotalCount(ctx, filter)
+}
+
+func (s *transactionServiceImpl) Update(ctx context.Context, userID string, tx *model.Transaction, expectedUpdatedAt *time.Time) (*model.Transaction, error) {
+	existing, err := s.get(ctx, userID, tx.ID, model.PermissionWrite)
 	if err != nil {
 		return nil, err
 	}
+	// Транзакция не переносится между пространствами
+	tx.WorkspaceID = existing.WorkspaceID
 
-	if existing.UserID != userID {
-		return nil, ErrUnauthorized
+	if tx.CategoryID != nil {
+		if err := s.checkCategory(ctx, userID, tx.WorkspaceID, *tx.CategoryID); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := s.txRepo.Update(ctx, tx); err != nil {
+	if expectedUpdatedAt != nil && !existing.UpdatedAt.Equal(*expectedUpdatedAt) {
+		return nil, ErrPreconditionFailed
+	}
+
+	// Сумма разбитой транзакции меняется только вместе со строками разбивки
+	if model.Cents(existing.Amount) != model.Cents(tx.Amount) {
+		splits, err := s.splitRepo.GetByTransactionID(ctx, tx.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(splits) > 0 {
+			return nil, ErrTransactionHasSplit
+		}
+	}
+
+	var updated *model.Transaction
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Продавец определяется заново только при смене описания
+		if tx.Description == existing.Description {
+			tx.MerchantID = existing.MerchantID
+		} else if err := assignMerchants(ctx, s.merchantRepo, userID, tx); err != nil {
+			return err
+		}
+
+		if err := s.txRepo.Update(ctx, tx, expectedUpdatedAt); err != nil {
+			if errors.Is(err, repo.ErrVersionConflict) {
+				return ErrPreconditionFailed
+			}
+			return notFound(err, ErrTransactionNotFound)
+		}
+
+		var err error
+		if updated, err = s.txRepo.GetByID(ctx, tx.ID); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewTransactionUpdated(updated, existing))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *transactionServiceImpl) GetHistory(ctx context.Context, userID, id string) ([]*model.TransactionRevision, error) {
+	if _, err := s.get(ctx, userID, id, model.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	return s.txRepo.GetRevisions(ctx, id)
+}
+
+func (s *transactionServiceImpl) Revert(ctx context.Context, userID, id string, revision int) (*model.Transaction, error) {
+	existing, err := s.get(ctx, userID, id, model.PermissionWrite)
+	if err != nil {
 		return nil, err
 	}
 
-	return s.txRepo.GetByID(ctx, tx.ID)
+	rev, err := s.txRepo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, notFound(err, ErrRevisionNotFound)
+	}
+
+	// Версия заменяет данные транзакции, как полный PUT, с теми же проверками.
+	// Метки и разбивка не версионируются и остаются текущими
+	tx := *rev.Transaction
+	tx.ID, tx.UserID = id, existing.UserID
+	return s.Update(ctx, userID, &tx, &existing.UpdatedAt)
 }
 
 func (s *transactionServiceImpl) Delete(ctx context.Context, userID, id string) error {
-	tx, err := s.txRepo.GetByID(ctx, id)
+	tx, err := s.get(ctx, userID, id, model.PermissionWrite)
+	if err != nil {
+		return err
+	}
+
+	// Событие содержит транзакцию в состоянии до удаления
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.txRepo.Delete(ctx, id); err != nil {
+			return notFound(err, ErrTransactionNotFound)
+		}
+		return s.events.Publish(ctx, event.NewTransactionDeleted(tx))
+	})
+}
+
+// bulkPlan элементы операции, прошедшие проверки и применяемые в транзакции БД
+type bulkPlan struct {
+	op      *model.BulkOperation
+	results []*model.BulkItemResult
+	ids     []string
+	txs     []*model.Transaction
+}
+
+func (s *transactionServiceImpl) Bulk(ctx context.Context, userID string, ops []*model.BulkOperation) ([]*model.BulkItemResult, error) {
+	owned, err := s.loadOwnership(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*bulkPlan, len(ops))
+	for i, op := range ops {
+		plan, err := s.planBulkOperation(ctx, userID, i, op, owned)
+		if err != nil {
+			return nil, err
+		}
+		plans[i] = plan
+	}
+
+	// Все прошедшие проверку элементы применяются атомарно
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, plan := range plans {
+			if err := s.applyBulkPlan(ctx, plan, owned); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*model.BulkItemResult
+	for _, plan := range plans {
+		results = append(results, plan.results...)
+	}
+	return results, nil
+}
+
+// loadOwnership загружает одним запросом все транзакции, упомянутые в операциях
+func (s *transactionServiceImpl) loadOwnership(ctx context.Context, ops []*model.BulkOperation) (map[string]*model.Transaction, error) {
+	var ids []string
+	for _, op := range ops {
+		for _, id := range op.IDs {
+			if parsed, err := uuid.Parse(id); err == nil {
+				ids = append(ids, parsed.String())
+			}
+		}
+	}
+
+	owned := make(map[string]*model.Transaction, len(ids))
+	if len(ids) == 0 {
+		return owned, nil
+	}
+
+	txs, err := s.txRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		owned[tx.ID] = tx
+	}
+	return owned, nil
+}
+
+// planBulkOperation проверяет элементы операции и отбирает применимые
+func (s *transactionServiceImpl) planBulkOperation(ctx context.Context, userID string, index int, op *model.BulkOperation, owned map[string]*model.Transaction) (*bulkPlan, error) {
+	plan := &bulkPlan{op: op}
+
+	if op.Type == model.BulkCreate {
+		// Право на запись проверяется, как при Create, один раз на каждое пространство
+		workspaceErrs := make(map[string]error)
+		for i, tx := range op.Transactions {
+			result := &model.BulkItemResult{Operation: index, Type: op.Type, Index: i}
+			plan.results = append(plan.results, result)
+
+			if tx.WorkspaceID != nil {
+				accessErr, ok := workspaceErrs[*tx.WorkspaceID]
+				if !ok {
+					accessErr = s.policy.AuthorizeWorkspace(ctx, userID, *tx.WorkspaceID, model.PermissionWrite)
+					if accessErr != nil && !errors.Is(accessErr, ErrForbidden) {
+						return nil, accessErr
+					}
+					workspaceErrs[*tx.WorkspaceID] = accessErr
+				}
+				if accessErr != nil {
+					result.Err = accessErr
+					continue
+				}
+			}
+
+			tx.ID = uuid.New().String()
+			tx.UserID = userID
+			tx.CreatedAt = time.Now()
+			tx.UpdatedAt = tx.CreatedAt
+			if err := s.Categorize(ctx, userID, tx); err != nil {
+				return nil, err
+			}
+			result.ID = tx.ID
+			plan.txs = append(plan.txs, tx)
+		}
+		return plan, nil
+	}
+
+	// Несуществующая категория отклоняет все элементы операции, доступность категории
+	// проверяется для пространства каждой транзакции
+	var (
+		opErr    error
+		category *model.Category
+	)
+	if op.Type == model.BulkUpdateCategory && op.CategoryID != nil {
+		var err error
+		category, err = s.categoryRepo.GetByID(ctx, *op.CategoryID)
+		if err != nil {
+			if !errors.Is(err, repo.ErrNotFound) {
+				return nil, err
+			}
+			opErr = ErrInvalidCategory
+		}
+	}
+
+	for i, id := range op.IDs {
+		// ID из БД приходят в каноническом виде
+		if parsed, err := uuid.Parse(id); err == nil {
+			id = parsed.String()
+		}
+		result := &model.BulkItemResult{Operation: index, Type: op.Type, Index: i, ID: id}
+		existing, ok := owned[id]
+		var accessErr error
+		if ok {
+			accessErr = s.policy.Authorize(ctx, userID, existing.UserID, existing.WorkspaceID, model.PermissionWrite)
+			if accessErr != nil && !errors.Is(accessErr, ErrForbidden) {
+				return nil, accessErr
+			}
+			if accessErr == nil && category != nil {
+				accessErr = categoryUsable(ctx, s.policy, userID, existing.WorkspaceID, category)
+				if accessErr != nil && !errors.Is(accessErr, ErrInvalidCategory) {
+					return nil, accessErr
+				}
+			}
+		}
+		switch {
+		case !ok:
+			result.Err = ErrTransactionNotFound
+		case accessErr != nil:
+			result.Err = accessErr
+		case opErr != nil:
+			result.Err = opErr
+		default:
+			plan.ids = append(plan.ids, id)
+		}
+		plan.results = append(plan.results, result)
+	}
+	return plan, nil
+}
+
+// applyBulkPlan применяет операцию внутри транзакции БД и публикует события.
+// Элементы, которые не были затронуты (например, удалены предыдущей операцией), помечаются как ненайденные
+func (s *transactionServiceImpl) applyBulkPlan(ctx context.Context, plan *bulkPlan, owned map[string]*model.Transaction) error {
+	var (
+		affected []string
+		err      error
+	)
+
+	switch plan.op.Type {
+	case model.BulkCreate:
+		if len(plan.txs) == 0 {
+			return nil
+		}
+		if err := assignMerchants(ctx, s.merchantRepo, plan.txs[0].UserID, plan.txs...); err != nil {
+			return err
+		}
+		if err := s.txRepo.CreateMany(ctx, plan.txs); err != nil {
+			return err
+		}
+		events := make([]eventbus.Event, len(plan.txs))
+		for i, tx := range plan.txs {
+			events[i] = event.NewTransactionCreated(tx)
+		}
+		return s.events.Publish(ctx, events...)
+	case model.BulkUpdateCategory:
+		if len(plan.ids) == 0 {
+			return nil
+		}
+		affected, err = s.txRepo.SetCategory(ctx, plan.ids, plan.op.CategoryID)
+	case model.BulkConfirm:
+		if len(plan.ids) == 0 {
+			return nil
+		}
+		affected, err = s.txRepo.Confirm(ctx, plan.ids)
+	case model.BulkDelete:
+		if len(plan.ids) == 0 {
+			return nil
+		}
+		affected, err = s.txRepo.DeleteMany(ctx, plan.ids)
+	}
 	if err != nil {
 		return err
 	}
 
-	if tx.UserID != userID {
-		return ErrUnauthorized
+	done := make(map[string]bool, len(affected))
+	for _, id := range affected {
+		done[id] = true
+	}
+	for _, result := range plan.results {
+		if result.Err == nil && !done[result.ID] {
+			result.Err = ErrTransactionNotFound
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+
+	var events []eventbus.Event
+	if plan.op.Type == model.BulkDelete {
+		for _, id := range affected {
+			if tx, ok := owned[id]; ok {
+				events = append(events, event.NewTransactionDeleted(tx))
+			}
+		}
+		return s.events.Publish(ctx, events...)
 	}
 
-	return s.txRepo.Delete(ctx, id)
+	updated, err := s.txRepo.GetByIDs(ctx, affected)
+	if err != nil {
+		return err
+	}
+	for _, tx := range updated {
+		events = append(events, event.NewTransactionUpdated(tx, owned[tx.ID]))
+		// Следующая операция запроса увидит транзакцию уже изменённой
+		owned[tx.ID] = tx
+	}
+	return s.events.Publish(ctx, events...)
+}
+
+func (s *transactionServiceImpl) GetReviewQueue(ctx context.Context, filter model.ReviewFilter, locale string) ([]*model.ReviewItem, error) {
+	if filter.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, filter.UserID, *filter.WorkspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.txRepo.GetForReview(ctx, filter)
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+
+	categories, err := visibleCategories(ctx, s.categoryRepo, filter.UserID, filter.WorkspaceID, locale)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*model.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	for _, item := range items {
+		if item.SuggestedCategoryID == nil {
+			continue
+		}
+		// Категория могла быть удалена или недоступна — тогда предложения нет
+		category, ok := byID[*item.SuggestedCategoryID]
+		if !ok {
+			item.SuggestedCategoryID = nil
+			continue
+		}
+		item.SuggestedCategory = category
+	}
+
+	return items, nil
+}
+
+func (s *transactionServiceImpl) GetReviewCounts(ctx context.Context, userID string, workspaceID *string) (*model.ReviewCounts, error) {
+	if workspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *workspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+	return s.txRepo.CountForReview(ctx, userID, workspaceID)
+}
+
+func (s *transactionServiceImpl) Review(ctx context.Context, userID, id string, action model.ReviewAction, categoryID *int) (*model.Transaction, error) {
+	tx, err := s.get(ctx, userID, id, model.PermissionWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTags(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	// accept без явной категории подтверждает текущую или предложенную по истории
+	if action == model.ReviewAccept && categoryID == nil {
+		categoryID = tx.CategoryID
+		if categoryID == nil {
+			categoryID, err = s.txRepo.SuggestCategory(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if categoryID == nil {
+		return nil, ErrNoSuggestion
+	}
+
+	if err := s.checkCategory(ctx, userID, tx.WorkspaceID, *categoryID); err != nil {
+		return nil, err
+	}
+
+	previous := *tx
+	tx.CategoryID = categoryID
+	tx.IsConfirmed = true
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.txRepo.Update(ctx, tx, nil); err != nil {
+			return notFound(err, ErrTransactionNotFound)
+		}
+		return s.events.Publish(ctx, event.NewTransactionUpdated(tx, &previous))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
 }
 
 // Categorize выполняет автоматическую категоризацию транзакции
 func (s *transactionServiceImpl) Categorize(ctx context.Context, userID string, tx *model.Transaction) error {
+	// Правила пространства применяются только к транзакциям, которые пользователь может в нём создавать
+	if tx.WorkspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *tx.WorkspaceID, model.PermissionWrite); err != nil {
+			return err
+		}
+	}
+
 	if tx.Description == "" {
 		return nil
 	}
 
-	// Получаем правила пользователя
-	rules, err := s.ruleRepo.GetByUserID(ctx, userID)
+	// Транзакция пространства категоризуется правилами пространства, личная — правилами пользователя
+	rules, err := s.rules(ctx, userID, tx.WorkspaceID)
 	if err != nil {
 		return err
 	}
 
-	// Ищем совпадение по ключевым словам
+	// Ищем совпадение по ключевым словам: в самом описании или в нормализованном
+	// названии продавца, чтобы правило "Пятёрочка" срабатывало и на "PYATEROCHKA 1234 MOSCOW"
 	description := strings.ToUpper(tx.Description)
+	key := merchant.Key(tx.Description)
 	for _, rule := range rules {
 		keyword := strings.ToUpper(rule.Keyword)
-		if strings.Contains(description, keyword) {
+		ruleKey := merchant.Key(rule.Keyword)
+		if strings.Contains(description, keyword) || (key != "" && ruleKey != "" && strings.Contains(key, ruleKey)) {
 			tx.CategoryID = &rule.CategoryID
 			tx.IsConfirmed = true
 			return nil
@@ -164,47 +713,93 @@ func (s *transactionServiceImpl) Categorize(ctx context.Context, userID string,
 	return nil
 }
 
-func (s *transactionServiceImpl) CreateRule(ctx context.Context, userID string, keyword string, categoryID int) (*model.UserCategoryRule, error) {
-	// Проверяем что категория существует
-	_, err := s.categoryRepo.GetByID(ctx, categoryID)
-	if err != nil {
+func (s *transactionServiceImpl) CreateRule(ctx context.Context, userID string, workspaceID *string, keyword string, categoryID int) (*model.UserCategoryRule, error) {
+	if workspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *workspaceID, model.PermissionWrite); err != nil {
+			return nil, err
+		}
+	}
+
+	// Проверяем что категория существует и доступна пользователю
+	if err := s.checkCategory(ctx, userID, workspaceID, categoryID); err != nil {
 		return nil, err
 	}
 
 	rule := &model.UserCategoryRule{
-		UserID:     userID,
-		Keyword:    keyword,
-		CategoryID: categoryID,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Keyword:     keyword,
+		CategoryID:  categoryID,
 	}
 
-	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ruleRepo.Create(ctx, rule); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewRuleCreated(rule))
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return rule, nil
 }
 
-func (s *transactionServiceImpl) GetRules(ctx context.Context, userID string) ([]*model.UserCategoryRule, error) {
+func (s *transactionServiceImpl) GetRules(ctx context.Context, userID string, workspaceID *string) ([]*model.UserCategoryRule, error) {
+	if workspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *workspaceID, model.PermissionRead); err != nil {
+			return nil, err
+		}
+	}
+	return s.rules(ctx, userID, workspaceID)
+}
+
+// rules возвращает правила пространства workspaceID или, без него, личные правила пользователя
+func (s *transactionServiceImpl) rules(ctx context.Context, userID string, workspaceID *string) ([]*model.UserCategoryRule, error) {
+	if workspaceID != nil {
+		return s.ruleRepo.GetByWorkspaceID(ctx, *workspaceID)
+	}
 	return s.ruleRepo.GetByUserID(ctx, userID)
 }
 
 func (s *transactionServiceImpl) DeleteRule(ctx context.Context, userID, ruleID string) error {
-	// Получаем правило
-	rules, err := s.ruleRepo.GetByUserID(ctx, userID)
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
 	if err != nil {
+		return notFound(err, ErrRuleNotFound)
+	}
+
+	if err := s.policy.Authorize(ctx, userID, rule.UserID, rule.WorkspaceID, model.PermissionWrite); err != nil {
 		return err
 	}
 
-	// Проверяем что правило принадлежит пользователю
-	for _, rule := range rules {
-		if rule.ID == ruleID {
-			return s.ruleRepo.Delete(ctx, ruleID)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ruleRepo.Delete(ctx, ruleID); err != nil {
+			return err
 		}
+		return s.events.Publish(ctx, event.NewRuleDeleted(rule))
+	})
+}
+
+func (s *transactionServiceImpl) GetCategories(ctx context.Context, userID, locale string) ([]*model.Category, error) {
+	categories, err := s.categoryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localizeCategories(ctx, s.categoryRepo, locale, categories...); err != nil {
+		return nil, err
 	}
 
-	return ErrUnauthorized
+	return categories, nil
 }
 
-func (s *transactionServiceImpl) GetCategories(ctx context.Context) ([]*model.Category, error) {
-	return s.categoryRepo.GetAll(ctx)
+// checkCategory проверяет, что категория существует и доступна личным данным пользователя
+// или пространству workspaceID
+func (s *transactionServiceImpl) checkCategory(ctx context.Context, userID string, workspaceID *string, categoryID int) error {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return notFound(err, ErrInvalidCategory)
+	}
+
+	return categoryUsable(ctx, s.policy, userID, workspaceID, category)
 }
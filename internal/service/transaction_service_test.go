@@ -0,0 +1,666 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+)
+
+// mockTransactionRepository хранит транзакции в памяти.
+// Неиспользуемые методы интерфейса не реализованы
+type mockTransactionRepository struct {
+	repository.TransactionRepository
+	txs       map[string]*model.Transaction
+	deleted   map[string]*model.Transaction
+	created   []*model.Transaction
+	revisions map[string][]*model.TransactionRevision
+}
+
+func newMockTransactionRepository(txs ...*model.Transaction) *mockTransactionRepository {
+	m := &mockTransactionRepository{txs: make(map[string]*model.Transaction)}
+	for _, tx := range txs {
+		m.txs[tx.ID] = tx
+	}
+	return m
+}
+
+func (m *mockTransactionRepository) GetByID(ctx context.Context, id string) (*model.Transaction, error) {
+	tx, ok := m.txs[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return tx, nil
+}
+
+func (m *mockTransactionRepository) GetByIDs(ctx context.Context, ids []string) ([]*model.Transaction, error) {
+	var txs []*model.Transaction
+	for _, id := range ids {
+		if tx, ok := m.txs[id]; ok {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (m *mockTransactionRepository) GetByIDsWithDeleted(ctx context.Context, ids []string) ([]*model.Transaction, error) {
+	txs, _ := m.GetByIDs(ctx, ids)
+	for _, id := range ids {
+		if tx, ok := m.deleted[id]; ok {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (m *mockTransactionRepository) Create(ctx context.Context, tx *model.Transaction) error {
+	m.txs[tx.ID] = tx
+	m.created = append(m.created, tx)
+	return nil
+}
+
+func (m *mockTransactionRepository) Delete(ctx context.Context, id string) error {
+	tx, ok := m.txs[id]
+	if !ok {
+		return nil
+	}
+	if m.deleted == nil {
+		m.deleted = make(map[string]*model.Transaction)
+	}
+	now := time.Now()
+	tx.DeletedAt = &now
+	m.deleted[id] = tx
+	delete(m.txs, id)
+	return nil
+}
+
+func (m *mockTransactionRepository) Restore(ctx context.Context, id string) error {
+	tx, ok := m.deleted[id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	tx.DeletedAt = nil
+	m.txs[id] = tx
+	delete(m.deleted, id)
+	return nil
+}
+
+func (m *mockTransactionRepository) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var purged int64
+	for id, tx := range m.deleted {
+		if purged == int64(limit) {
+			break
+		}
+		if tx.DeletedAt.Before(before) {
+			delete(m.deleted, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *mockTransactionRepository) CreateMany(ctx context.Context, txs []*model.Transaction) error {
+	for _, tx := range txs {
+		m.txs[tx.ID] = tx
+	}
+	m.created = append(m.created, txs...)
+	return nil
+}
+
+func (m *mockTransactionRepository) SetCategory(ctx context.Context, ids []string, categoryID *int) ([]string, error) {
+	return m.each(ids, func(tx *model.Transaction) { tx.CategoryID = categoryID }), nil
+}
+
+func (m *mockTransactionRepository) Confirm(ctx context.Context, ids []string) ([]string, error) {
+	return m.each(ids, func(tx *model.Transaction) { tx.IsConfirmed = true }), nil
+}
+
+func (m *mockTransactionRepository) DeleteMany(ctx context.Context, ids []string) ([]string, error) {
+	return m.each(ids, func(tx *model.Transaction) { delete(m.txs, tx.ID) }), nil
+}
+
+func (m *mockTransactionRepository) Touch(ctx context.Context, tx *model.Transaction) error {
+	if _, ok := m.txs[tx.ID]; !ok {
+		return repo.ErrNotFound
+	}
+	tx.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *mockTransactionRepository) Update(ctx context.Context, tx *model.Transaction, expectedUpdatedAt *time.Time) error {
+	previous, ok := m.txs[tx.ID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if m.revisions == nil {
+		m.revisions = make(map[string][]*model.TransactionRevision)
+	}
+	revisions := m.revisions[tx.ID]
+	m.revisions[tx.ID] = append(revisions, &model.TransactionRevision{Transaction: previous, Revision: len(revisions) + 1})
+	m.txs[tx.ID] = tx
+	return nil
+}
+
+func (m *mockTransactionRepository) GetRevisions(ctx context.Context, id string) ([]*model.TransactionRevision, error) {
+	revisions := m.revisions[id]
+	newest := make([]*model.TransactionRevision, len(revisions))
+	for i, rev := range revisions {
+		newest[len(revisions)-1-i] = rev
+	}
+	return newest, nil
+}
+
+func (m *mockTransactionRepository) GetRevision(ctx context.Context, id string, revision int) (*model.TransactionRevision, error) {
+	revisions := m.revisions[id]
+	if revision < 1 || revision > len(revisions) {
+		return nil, repo.ErrNotFound
+	}
+	return revisions[revision-1], nil
+}
+
+// SuggestCategory возвращает самую частую категорию подтверждённых личных транзакций автора с тем же описанием
+func (m *mockTransactionRepository) CountForReview(ctx context.Context, userID string, workspaceID *string) (*model.ReviewCounts, error) {
+	return &model.ReviewCounts{}, nil
+}
+
+func (m *mockTransactionRepository) SuggestCategory(ctx context.Context, target *model.Transaction) (*int, error) {
+	counts := make(map[int]int)
+	var best *int
+	for _, tx := range m.txs {
+		if tx.UserID != target.UserID || !tx.IsConfirmed || tx.CategoryID == nil || !strings.EqualFold(tx.Description, target.Description) {
+			continue
+		}
+		id := *tx.CategoryID
+		counts[id]++
+		if best == nil || counts[id] > counts[*best] {
+			best = &id
+		}
+	}
+	return best, nil
+}
+
+func (m *mockTransactionRepository) each(ids []string, fn func(tx *model.Transaction)) []string {
+	var affected []string
+	for _, id := range ids {
+		if tx, ok := m.txs[id]; ok {
+			fn(tx)
+			affected = append(affected, id)
+		}
+	}
+	return affected
+}
+
+type mockCategoryRepository struct {
+	repository.CategoryRepository
+	categories map[int]*model.Category
+	workspaces map[int][]string
+	inUse      map[int]bool
+}
+
+func (m *mockCategoryRepository) GetDefault(ctx context.Context) ([]*model.Category, error) {
+	var categories []*model.Category
+	for _, category := range m.categories {
+		if category.IsDefault {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (m *mockCategoryRepository) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	category, ok := m.categories[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return category, nil
+}
+
+func (m *mockCategoryRepository) GetChildren(ctx context.Context, parentID int) ([]*model.Category, error) {
+	var children []*model.Category
+	for _, category := range m.categories {
+		if category.ParentID != nil && *category.ParentID == parentID {
+			children = append(children, category)
+		}
+	}
+	return children, nil
+}
+
+func (m *mockCategoryRepository) GetWorkspaceIDs(ctx context.Context, id int) ([]string, error) {
+	return m.workspaces[id], nil
+}
+
+func (m *mockCategoryRepository) Delete(ctx context.Context, id int, reassignTo *int) error {
+	// Без цели переназначения категория с транзакциями не удаляется
+	if reassignTo == nil && m.inUse[id] {
+		return repo.ErrCategoryInUse
+	}
+	delete(m.categories, id)
+	return nil
+}
+
+func (m *mockCategoryRepository) Create(ctx context.Context, category *model.Category) error {
+	category.ID = len(m.categories) + 100
+	m.categories[category.ID] = category
+	return nil
+}
+
+func (m *mockCategoryRepository) Update(ctx context.Context, category *model.Category) error {
+	m.categories[category.ID] = category
+	return nil
+}
+
+type mockRuleRepository struct {
+	repository.UserCategoryRuleRepository
+	rules []*model.UserCategoryRule
+}
+
+func (m *mockRuleRepository) GetByUserID(ctx context.Context, userID string) ([]*model.UserCategoryRule, error) {
+	return m.rules, nil
+}
+
+func (m *mockRuleRepository) GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.UserCategoryRule, error) {
+	var rules []*model.UserCategoryRule
+	for _, rule := range m.rules {
+		if rule.WorkspaceID != nil && *rule.WorkspaceID == workspaceID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+type mockSplitRepository struct {
+	repository.TransactionSplitRepository
+	splits map[string][]*model.TransactionSplit
+}
+
+func (m *mockSplitRepository) GetByTransactionID(ctx context.Context, transactionID string) ([]*model.TransactionSplit, error) {
+	return m.splits[transactionID], nil
+}
+
+func (m *mockSplitRepository) DeleteByTransactionID(ctx context.Context, transactionID string) error {
+	delete(m.splits, transactionID)
+	return nil
+}
+
+func (m *mockSplitRepository) Replace(ctx context.Context, transactionID string, splits []*model.TransactionSplit) error {
+	if m.splits == nil {
+		m.splits = make(map[string][]*model.TransactionSplit)
+	}
+	m.splits[transactionID] = splits
+	return nil
+}
+
+type mockTagRepository struct {
+	repository.TagRepository
+}
+
+func (m *mockTagRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*model.Tag, error) {
+	return map[string][]*model.Tag{}, nil
+}
+
+// mockMerchantRepository выдаёт продавцу ID вида "merchant-<ключ>"
+type mockMerchantRepository struct {
+	repository.MerchantRepository
+	resolved map[string]string
+}
+
+func (m *mockMerchantRepository) Resolve(ctx context.Context, userID string, names map[string]string) (map[string]string, error) {
+	if m.resolved == nil {
+		m.resolved = make(map[string]string)
+	}
+	ids := make(map[string]string, len(names))
+	for key, name := range names {
+		m.resolved[key] = name
+		ids[key] = "merchant-" + key
+	}
+	return ids, nil
+}
+
+// mockWorkspaceRepository хранит роли участников: roles[workspaceID][userID]
+type mockWorkspaceRepository struct {
+	repository.WorkspaceRepository
+	roles map[string]map[string]model.WorkspaceRole
+}
+
+func (m *mockWorkspaceRepository) GetMemberRole(ctx context.Context, workspaceID, userID string) (model.WorkspaceRole, error) {
+	role, ok := m.roles[workspaceID][userID]
+	if !ok {
+		return "", repo.ErrNotFound
+	}
+	return role, nil
+}
+
+// mockEventPublisher запоминает опубликованные события
+type mockEventPublisher struct {
+	events []eventbus.Event
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, events ...eventbus.Event) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+// published возвращает ID транзакций из событий с именем name
+func (m *mockEventPublisher) published(name string) []string {
+	var ids []string
+	for _, e := range m.events {
+		if e.EventName() != name {
+			continue
+		}
+		switch e := e.(type) {
+		case event.TransactionCreated:
+			ids = append(ids, e.Transaction.ID)
+		case event.TransactionUpdated:
+			ids = append(ids, e.Transaction.ID)
+		case event.TransactionDeleted:
+			ids = append(ids, e.Transaction.ID)
+		}
+	}
+	return ids
+}
+
+// mockTransactor выполняет fn без транзакции и может имитировать ошибку фиксации
+type mockTransactor struct {
+	calls int
+	err   error
+}
+
+func (m *mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return m.err
+}
+
+func (m *mockTransactor) AfterCommit(ctx context.Context, fn func()) {
+	fn()
+}
+
+const (
+	ownTxID   = "11111111-1111-1111-1111-111111111111"
+	otherTxID = "22222222-2222-2222-2222-222222222222"
+	goneTxID  = "33333333-3333-3333-3333-333333333333"
+)
+
+func newBulkTestService(transactor repository.Transactor) (TransactionService, *mockTransactionRepository) {
+	otherUser := "user-2"
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-1", Description: "Такси"},
+		&model.Transaction{ID: otherTxID, UserID: "user-2", Description: "Кофе"},
+	)
+	categoryRepo := &mockCategoryRepository{categories: map[int]*model.Category{
+		1:  {ID: 1, Name: "Транспорт", IsDefault: true},
+		50: {ID: 50, Name: "Чужая", UserID: &otherUser},
+	}}
+	ruleRepo := &mockRuleRepository{rules: []*model.UserCategoryRule{
+		{UserID: "user-1", Keyword: "ПЯТЕРОЧКА", CategoryID: 1},
+	}}
+	return NewTransactionService(txRepo, categoryRepo, ruleRepo, &mockSplitRepository{}, &mockTagRepository{}, &mockMerchantRepository{}, &mockEventPublisher{}, transactor, NewAccessPolicy(&mockWorkspaceRepository{})), txRepo
+}
+
+func TestTransactionService_Bulk(t *testing.T) {
+	transactor := &mockTransactor{}
+	svc, txRepo := newBulkTestService(transactor)
+
+	ops := []*model.BulkOperation{
+		{Type: model.BulkCreate, Transactions: []*model.Transaction{
+			{Amount: 100, Currency: "RUB", Description: "Пятерочка"},
+			{Amount: 200, Currency: "RUB", Description: "Аптека"},
+		}},
+		{Type: model.BulkUpdateCategory, CategoryID: intPtr(1), IDs: []string{ownTxID, otherTxID, goneTxID, "not-a-uuid"}},
+		{Type: model.BulkUpdateCategory, CategoryID: intPtr(50), IDs: []string{ownTxID}},
+		{Type: model.BulkDelete, IDs: []string{ownTxID}},
+		{Type: model.BulkConfirm, IDs: []string{ownTxID}},
+	}
+
+	results, err := svc.Bulk(context.Background(), "user-1", ops)
+	if err != nil {
+		t.Fatalf("Bulk returned error: %v", err)
+	}
+	if transactor.calls != 1 {
+		t.Errorf("Expected one DB transaction, got %d", transactor.calls)
+	}
+
+	want := []error{
+		nil, nil, // create
+		nil, ErrForbidden, ErrTransactionNotFound, ErrTransactionNotFound, // update_category
+		ErrInvalidCategory,     // чужая категория
+		nil,                    // delete
+		ErrTransactionNotFound, // confirm после удаления
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if !errors.Is(result.Err, want[i]) {
+			t.Errorf("result %d (%s #%d): expected %v, got %v", i, result.Type, result.Index, want[i], result.Err)
+		}
+	}
+
+	if len(txRepo.created) != 2 {
+		t.Fatalf("Expected 2 created transactions, got %d", len(txRepo.created))
+	}
+	if c := txRepo.created[0]; c.UserID != "user-1" || c.CategoryID == nil || *c.CategoryID != 1 {
+		t.Errorf("Expected created transaction to be owned and categorized, got %+v", c)
+	}
+	if c := txRepo.created[0]; c.MerchantID == nil || *c.MerchantID != "merchant-pyaterochka" {
+		t.Errorf("Expected created transaction to get a merchant, got %v", c.MerchantID)
+	}
+	if _, ok := txRepo.txs[otherTxID]; !ok || txRepo.txs[otherTxID].CategoryID != nil {
+		t.Error("Transaction of another user must not be modified")
+	}
+
+	// События только для затронутых транзакций
+	events := svc.(*transactionServiceImpl).events.(*mockEventPublisher)
+	if got := events.published(event.TransactionCreatedName); len(got) != 2 {
+		t.Errorf("Expected 2 created events, got %v", got)
+	}
+	if got := events.published(event.TransactionUpdatedName); len(got) != 1 || got[0] != ownTxID {
+		t.Errorf("Expected one updated event for %s, got %v", ownTxID, got)
+	}
+	if got := events.published(event.TransactionDeletedName); len(got) != 1 || got[0] != ownTxID {
+		t.Errorf("Expected one deleted event for %s, got %v", ownTxID, got)
+	}
+}
+
+func TestTransactionService_BulkRollback(t *testing.T) {
+	commitErr := errors.New("commit failed")
+	svc, _ := newBulkTestService(&mockTransactor{err: commitErr})
+
+	ops := []*model.BulkOperation{{Type: model.BulkConfirm, IDs: []string{ownTxID}}}
+	results, err := svc.Bulk(context.Background(), "user-1", ops)
+	if !errors.Is(err, commitErr) {
+		t.Fatalf("Expected commit error, got %v", err)
+	}
+	if results != nil {
+		t.Errorf("Expected no results on failure, got %v", results)
+	}
+}
+
+func TestTransactionService_Review(t *testing.T) {
+	svc, txRepo := newBulkTestService(&mockTransactor{})
+	txRepo.txs["44444444-4444-4444-4444-444444444444"] = &model.Transaction{
+		ID: "44444444-4444-4444-4444-444444444444", UserID: "user-1", Description: "такси", CategoryID: intPtr(1), IsConfirmed: true,
+	}
+	ctx := context.Background()
+
+	// accept без категории берёт предложение из истории
+	tx, err := svc.Review(ctx, "user-1", ownTxID, model.ReviewAccept, nil)
+	if err != nil {
+		t.Fatalf("accept returned error: %v", err)
+	}
+	if tx.CategoryID == nil || *tx.CategoryID != 1 || !tx.IsConfirmed {
+		t.Errorf("Expected confirmed transaction with category 1, got %+v", tx)
+	}
+
+	// reject с чужой категорией отклоняется
+	if _, err := svc.Review(ctx, "user-1", ownTxID, model.ReviewReject, intPtr(50)); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("Expected ErrInvalidCategory, got %v", err)
+	}
+
+	// нет ни текущей категории, ни истории
+	txRepo.txs[goneTxID] = &model.Transaction{ID: goneTxID, UserID: "user-1", Description: "Новое место"}
+	if _, err := svc.Review(ctx, "user-1", goneTxID, model.ReviewAccept, nil); !errors.Is(err, ErrNoSuggestion) {
+		t.Errorf("Expected ErrNoSuggestion, got %v", err)
+	}
+
+	// чужая транзакция
+	if _, err := svc.Review(ctx, "user-1", otherTxID, model.ReviewAccept, intPtr(1)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestTransactionService_Revert(t *testing.T) {
+	svc, txRepo := newBulkTestService(&mockTransactor{})
+	ctx := context.Background()
+
+	_, err := svc.Update(ctx, "user-1", &model.Transaction{ID: ownTxID, UserID: "user-1", Amount: 300, Description: "Такси", CategoryID: intPtr(1)}, nil)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		revision int
+		wantErr  error
+	}{
+		{"чужая транзакция", otherTxID, 1, ErrForbidden},
+		{"несуществующая транзакция", goneTxID, 1, ErrTransactionNotFound},
+		{"несуществующая версия", ownTxID, 5, ErrRevisionNotFound},
+		{"первая версия", ownTxID, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Revert(ctx, "user-1", tt.id, tt.revision)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Revert() error = %v, ожидалось %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	current := txRepo.txs[ownTxID]
+	if current.Amount != 0 || current.CategoryID != nil {
+		t.Errorf("после отката сумма %v, категория %v: ожидалась первая версия", current.Amount, current.CategoryID)
+	}
+
+	// Откат сохраняет заменённое состояние новой версией
+	history, err := svc.GetHistory(ctx, "user-1", ownTxID)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Revision != 2 || history[0].Amount != 300 {
+		t.Errorf("GetHistory() = %d версий, ожидалась последней версия 2 с суммой 300", len(history))
+	}
+}
+
+func TestTransactionService_WorkspaceAccess(t *testing.T) {
+	const sharedTxID = "55555555-5555-5555-5555-555555555555"
+	workspaceID := "ws-1"
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: sharedTxID, UserID: "user-2", WorkspaceID: &workspaceID, Description: "Продукты"},
+	)
+	member, outsider := "user-2", "user-4"
+	categoryRepo := &mockCategoryRepository{categories: map[int]*model.Category{
+		1: {ID: 1, Name: "Продукты", IsDefault: true},
+		2: {ID: 2, Name: "Дача", UserID: &member},
+		3: {ID: 3, Name: "Хобби", UserID: &outsider},
+	}}
+	ruleRepo := &mockRuleRepository{rules: []*model.UserCategoryRule{
+		{UserID: "user-2", WorkspaceID: &workspaceID, Keyword: "ПЯТЕРОЧКА", CategoryID: 1},
+	}}
+	policy := NewAccessPolicy(&mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+		workspaceID: {"user-1": model.WorkspaceViewer, "user-2": model.WorkspaceOwner, "user-3": model.WorkspaceEditor},
+	}})
+	svc := NewTransactionService(txRepo, categoryRepo, ruleRepo, &mockSplitRepository{}, &mockTagRepository{}, &mockMerchantRepository{}, &mockEventPublisher{}, &mockTransactor{}, policy)
+	ctx := context.Background()
+
+	// Читать транзакцию пространства может любой участник, посторонний — нет
+	if _, err := svc.GetByID(ctx, "user-1", sharedTxID); err != nil {
+		t.Errorf("viewer GetByID() error = %v", err)
+	}
+	if _, err := svc.GetByID(ctx, "user-4", sharedTxID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("outsider GetByID() error = %v, ожидалось ErrForbidden", err)
+	}
+
+	// Изменять может редактор, но не читатель
+	update := func(userID string) error {
+		_, err := svc.Update(ctx, userID, &model.Transaction{ID: sharedTxID, UserID: "user-2", Amount: 500, Description: "Продукты"}, nil)
+		return err
+	}
+	if err := update("user-1"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("viewer Update() error = %v, ожидалось ErrForbidden", err)
+	}
+	if err := update("user-3"); err != nil {
+		t.Fatalf("editor Update() error = %v", err)
+	}
+	if tx := txRepo.txs[sharedTxID]; tx.WorkspaceID == nil || *tx.WorkspaceID != workspaceID {
+		t.Errorf("после изменения транзакция в пространстве %v, ожидалось %s", tx.WorkspaceID, workspaceID)
+	}
+
+	// Транзакция пространства категоризуется правилами пространства
+	created, err := svc.Create(ctx, "user-3", &model.Transaction{Amount: 100, Currency: "RUB", Description: "Пятерочка", WorkspaceID: &workspaceID})
+	if err != nil {
+		t.Fatalf("editor Create() error = %v", err)
+	}
+	if created.CategoryID == nil || *created.CategoryID != 1 {
+		t.Errorf("Create() категория %v, ожидалась 1 по правилу пространства", created.CategoryID)
+	}
+	if _, err := svc.Create(ctx, "user-1", &model.Transaction{Amount: 100, Currency: "RUB", Description: "Кофе", WorkspaceID: &workspaceID}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("viewer Create() error = %v, ожидалось ErrForbidden", err)
+	}
+	if err := svc.Categorize(ctx, "user-4", &model.Transaction{Description: "Пятерочка", WorkspaceID: &workspaceID}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("outsider Categorize() error = %v, ожидалось ErrForbidden", err)
+	}
+
+	// Массовое создание проверяет право записи так же, как Create
+	results, err := svc.Bulk(ctx, "user-1", []*model.BulkOperation{{Type: model.BulkCreate, Transactions: []*model.Transaction{
+		{Amount: 100, Currency: "RUB", Description: "Пятерочка", WorkspaceID: &workspaceID},
+		{Amount: 200, Currency: "RUB", Description: "Кофе"},
+	}}})
+	if err != nil {
+		t.Fatalf("viewer Bulk() error = %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrForbidden) || results[0].ID != "" {
+		t.Errorf("viewer Bulk() в пространство: %+v, ожидалось ErrForbidden", results[0])
+	}
+	if results[1].Err != nil || txRepo.txs[results[1].ID] == nil {
+		t.Errorf("viewer Bulk() личная транзакция: %+v, ожидалось создание", results[1])
+	}
+
+	// Транзакции пространства доступны категории всех его участников, но не посторонних
+	setCategory := func(categoryID int) error {
+		_, err := svc.Update(ctx, "user-3", &model.Transaction{ID: sharedTxID, UserID: "user-2", Amount: 500, Description: "Продукты", CategoryID: &categoryID}, nil)
+		return err
+	}
+	if err := setCategory(2); err != nil {
+		t.Errorf("Update() с категорией участника error = %v", err)
+	}
+	if err := setCategory(3); !errors.Is(err, ErrInvalidCategory) {
+		t.Errorf("Update() с категорией постороннего error = %v, ожидалось ErrInvalidCategory", err)
+	}
+	results, err = svc.Bulk(ctx, "user-3", []*model.BulkOperation{{Type: model.BulkUpdateCategory, IDs: []string{sharedTxID}, CategoryID: intPtr(3)}})
+	if err != nil {
+		t.Fatalf("Bulk() error = %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrInvalidCategory) {
+		t.Errorf("Bulk() с категорией постороннего: %+v, ожидалось ErrInvalidCategory", results[0])
+	}
+
+	// Очередь проверки пространства видна его участникам
+	if _, err := svc.GetReviewCounts(ctx, "user-1", &workspaceID); err != nil {
+		t.Errorf("viewer GetReviewCounts() error = %v", err)
+	}
+	if _, err := svc.GetReviewCounts(ctx, "user-4", &workspaceID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("outsider GetReviewCounts() error = %v, ожидалось ErrForbidden", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// trashPurgeBatch сколько транзакций удаляется окончательно за один запрос
+const trashPurgeBatch = 1000
+
+var ErrNotInTrash = apperror.New(apperror.KindNotFound, "transaction_not_in_trash")
+
+type TrashService interface {
+	// Возвращает удалённые личные транзакции пользователя или транзакции пространства workspaceID
+	// и их общее количество, последние удалённые первыми
+	GetAll(ctx context.Context, userID string, workspaceID *string, limit, offset int) ([]*model.TrashedTransaction, int64, error)
+
+	// Восстанавливает транзакцию из корзины
+	Restore(ctx context.Context, userID, id string) (*model.Transaction, error)
+
+	// Окончательно удаляет транзакции, пролежавшие в корзине дольше срока хранения,
+	// и возвращает их количество
+	Purge(ctx context.Context) (int64, error)
+
+	// Периодически очищает корзину, пока не отменён ctx
+	RunPurge(ctx context.Context, interval time.Duration)
+}
+
+type trashServiceImpl struct {
+	txRepo     repository.TransactionRepository
+	tagRepo    repository.TagRepository
+	events     EventPublisher
+	transactor repository.Transactor
+	policy     AccessPolicy
+	retention  time.Duration
+	now        func() time.Time
+}
+
+func NewTrashService(
+	txRepo repository.TransactionRepository,
+	tagRepo repository.TagRepository,
+	events EventPublisher,
+	transactor repository.Transactor,
+	policy AccessPolicy,
+	retention time.Duration,
+) TrashService {
+	return &trashServiceImpl{
+		txRepo:     txRepo,
+		tagRepo:    tagRepo,
+		events:     events,
+		transactor: transactor,
+		policy:     policy,
+		retention:  retention,
+		now:        time.Now,
+	}
+}
+
+func (s *trashServiceImpl) GetAll(ctx context.Context, userID string, workspaceID *string, limit, offset int) ([]*model.TrashedTransaction, int64, error) {
+	if workspaceID != nil {
+		if err := s.policy.AuthorizeWorkspace(ctx, userID, *workspaceID, model.PermissionRead); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	txs, err := s.txRepo.GetDeleted(ctx, userID, workspaceID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.txRepo.CountDeleted(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.loadTags(ctx, txs...); err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]*model.TrashedTransaction, len(txs))
+	for i, tx := range txs {
+		trashed[i] = &model.TrashedTransaction{Transaction: tx, PurgeAt: tx.DeletedAt.Add(s.retention)}
+	}
+	return trashed, total, nil
+}
+
+func (s *trashServiceImpl) Restore(ctx context.Context, userID, id string) (*model.Transaction, error) {
+	// Приведение к uuid[] в запросе не примет произвольную строку
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrTransactionNotFound
+	}
+
+	txs, err := s.txRepo.GetByIDsWithDeleted(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, ErrTransactionNotFound
+	}
+
+	tx := txs[0]
+	if err := s.policy.Authorize(ctx, userID, tx.UserID, tx.WorkspaceID, model.PermissionWrite); err != nil {
+		return nil, err
+	}
+	if tx.DeletedAt == nil {
+		return nil, ErrNotInTrash
+	}
+
+	// Для подписчиков восстановленная транзакция появляется заново
+	var restored *model.Transaction
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.txRepo.Restore(ctx, id); err != nil {
+			return notFound(err, ErrNotInTrash)
+		}
+
+		var err error
+		if restored, err = s.txRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, event.NewTransactionCreated(restored))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, restored); err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// loadTags заполняет метки транзакций одним запросом
+func (s *trashServiceImpl) loadTags(ctx context.Context, txs ...*model.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.ID
+	}
+
+	tags, err := s.tagRepo.GetByTransactionIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		tx.Tags = tags[tx.ID]
+	}
+	return nil
+}
+
+func (s *trashServiceImpl) Purge(ctx context.Context) (int64, error) {
+	before := s.now().Add(-s.retention)
+
+	var total int64
+	for {
+		purged, err := s.txRepo.Purge(ctx, before, trashPurgeBatch)
+		if err != nil {
+			return total, err
+		}
+		total += purged
+		if purged < trashPurgeBatch {
+			return total, nil
+		}
+	}
+}
+
+func (s *trashServiceImpl) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Purge(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("trash: purge failed: %v", err)
+			}
+		}
+	}
+}
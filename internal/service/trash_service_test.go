@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+)
+
+func TestTrashService_Restore(t *testing.T) {
+	txRepo := newMockTransactionRepository(
+		&model.Transaction{ID: ownTxID, UserID: "user-1", Description: "Такси"},
+		&model.Transaction{ID: otherTxID, UserID: "user-2", Description: "Кофе"},
+	)
+	txRepo.Delete(context.Background(), ownTxID)
+	txRepo.Delete(context.Background(), otherTxID)
+
+	events := &mockEventPublisher{}
+	svc := NewTrashService(txRepo, &mockTagRepository{}, events, &mockTransactor{}, NewAccessPolicy(&mockWorkspaceRepository{}), time.Hour)
+
+	tests := []struct {
+		name    string
+		userID  string
+		id      string
+		wantErr error
+	}{
+		{"чужая транзакция", "user-1", otherTxID, ErrForbidden},
+		{"несуществующая транзакция", "user-1", goneTxID, ErrTransactionNotFound},
+		{"некорректный ID", "user-1", "not-a-uuid", ErrTransactionNotFound},
+		{"транзакция из корзины", "user-1", ownTxID, nil},
+		{"уже восстановленная транзакция", "user-1", ownTxID, ErrNotInTrash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := svc.Restore(context.Background(), tt.userID, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (tx.ID != tt.id || tx.DeletedAt != nil) {
+				t.Errorf("Expected restored transaction %s, got %+v", tt.id, tx)
+			}
+		})
+	}
+
+	if _, ok := txRepo.txs[ownTxID]; !ok {
+		t.Error("Expected transaction to be back in the repository")
+	}
+	if ids := events.published(event.TransactionCreatedName); len(ids) != 1 || ids[0] != ownTxID {
+		t.Errorf("Expected one created event for %s, got %v", ownTxID, ids)
+	}
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	txRepo := newMockTransactionRepository()
+	txRepo.deleted = make(map[string]*model.Transaction)
+	for i := 0; i < trashPurgeBatch+5; i++ {
+		id := "old-" + strconv.Itoa(i)
+		deletedAt := now.Add(-31 * 24 * time.Hour)
+		txRepo.deleted[id] = &model.Transaction{ID: id, UserID: "user-1", DeletedAt: &deletedAt}
+	}
+	recent := now.Add(-24 * time.Hour)
+	txRepo.deleted[ownTxID] = &model.Transaction{ID: ownTxID, UserID: "user-1", DeletedAt: &recent}
+
+	svc := NewTrashService(txRepo, &mockTagRepository{}, &mockEventPublisher{}, &mockTransactor{}, NewAccessPolicy(&mockWorkspaceRepository{}), 30*24*time.Hour).(*trashServiceImpl)
+	svc.now = func() time.Time { return now }
+
+	purged, err := svc.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("Purge returned error: %v", err)
+	}
+	if purged != trashPurgeBatch+5 {
+		t.Errorf("Expected %d purged transactions, got %d", trashPurgeBatch+5, purged)
+	}
+	if _, ok := txRepo.deleted[ownTxID]; !ok || len(txRepo.deleted) != 1 {
+		t.Errorf("Expected only the recently deleted transaction to stay in trash, got %d", len(txRepo.deleted))
+	}
+}
@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookNotFound         = apperror.New(apperror.KindNotFound, "webhook_not_found")
+	ErrWebhookDeliveryNotFound = apperror.New(apperror.KindNotFound, "webhook_delivery_not_found")
+	ErrWebhookDeliveryPending  = apperror.New(apperror.KindConflict, "webhook_delivery_pending")
+)
+
+const (
+	// webhookBatchSize сколько доставок выбирается за один запрос к очереди
+	webhookBatchSize = 50
+	// webhookLease на сколько откладывается доставка, взятая на отправку
+	webhookLease = 5 * time.Minute
+	// maxWebhookAttempts после стольких неудачных попыток доставка прекращается
+	maxWebhookAttempts = 8
+	// Задержка перед повторной попыткой растёт вдвое с каждой попыткой от 30 секунд до 12 часов
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 12 * time.Hour
+	// webhookRetention сколько хранится журнал завершённых доставок
+	webhookRetention = 30 * 24 * time.Hour
+	// maxWebhookURL соответствует длине колонки webhooks.url
+	maxWebhookURL = 2048
+)
+
+// WebhookSender отправляет подписанное событие подписчику
+type WebhookSender interface {
+	Send(ctx context.Context, r *webhook.Request) (*webhook.Response, error)
+}
+
+type WebhookService interface {
+	// Возвращает вебхуки пользователя
+	GetAll(ctx context.Context, userID string) ([]*model.Webhook, error)
+
+	// Возвращает вебхук по ID
+	GetByID(ctx context.Context, userID, id string) (*model.Webhook, error)
+
+	// Создаёт вебхук с новым ключом подписи
+	Create(ctx context.Context, userID string, hook *model.Webhook) (*model.Webhook, error)
+
+	// Обновляет адрес, события и активность вебхука. Ключ подписи не меняется
+	Update(ctx context.Context, userID string, hook *model.Webhook) (*model.Webhook, error)
+
+	// Удаляет вебхук вместе с журналом доставок
+	Delete(ctx context.Context, userID, id string) error
+
+	// Возвращает журнал доставок вебхука
+	GetDeliveries(ctx context.Context, userID string, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+
+	// Повторно отправляет событие доставки: создаёт новую доставку с тем же телом
+	Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (*model.WebhookDelivery, error)
+
+	// Подписывается на события транзакций: доставки ставятся в очередь синхронно,
+	// в транзакции изменения, чтобы событие не потерялось
+	Subscribe(bus *eventbus.Bus)
+
+	// Отправляет доставки, срок которых наступил. Возвращает число успешных
+	Deliver(ctx context.Context) (int, error)
+
+	// Периодически отправляет доставки и удаляет старый журнал, пока не отменён ctx
+	RunDelivery(ctx context.Context, interval time.Duration)
+}
+
+type webhookServiceImpl struct {
+	webhookRepo repository.WebhookRepository
+	sender      WebhookSender
+	now         func() time.Time
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, sender WebhookSender) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		now:         time.Now,
+	}
+}
+
+// webhookEvent тело запроса вебхука. ID одинаков у всех доставок события
+type webhookEvent struct {
+	ID        string             `json:"id"`
+	Event     model.WebhookEvent `json:"event"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      any                `json:"data"`
+}
+
+func (s *webhookServiceImpl) GetAll(ctx context.Context, userID string) ([]*model.Webhook, error) {
+	return s.webhookRepo.GetByUserID(ctx, userID)
+}
+
+func (s *webhookServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Webhook, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	hook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+	if hook.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return hook, nil
+}
+
+func (s *webhookServiceImpl) Create(ctx context.Context, userID string, hook *model.Webhook) (*model.Webhook, error) {
+	events, err := validateWebhook(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	created := &model.Webhook{
+		UserID: userID,
+		URL:    hook.URL,
+		Secret: secret,
+		Events: events,
+		Active: hook.Active,
+	}
+	if err := s.webhookRepo.Create(ctx, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *webhookServiceImpl) Update(ctx context.Context, userID string, hook *model.Webhook) (*model.Webhook, error) {
+	existing, err := s.GetByID(ctx, userID, hook.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := validateWebhook(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.URL = hook.URL
+	existing.Events = events
+	existing.Active = hook.Active
+	if err := s.webhookRepo.Update(ctx, existing); err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+	return existing, nil
+}
+
+func (s *webhookServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+func (s *webhookServiceImpl) GetDeliveries(ctx context.Context, userID string, filter model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	hook, err := s.GetByID(ctx, userID, filter.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	filter.WebhookID = hook.ID
+	return s.webhookRepo.GetDeliveries(ctx, filter)
+}
+
+func (s *webhookServiceImpl) Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (*model.WebhookDelivery, error) {
+	hook, err := s.GetByID(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	original, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookDeliveryNotFound)
+	}
+	if original.WebhookID != hook.ID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if original.Status == model.WebhookDeliveryPending {
+		return nil, ErrWebhookDeliveryPending
+	}
+
+	redelivery := &model.WebhookDelivery{
+		WebhookID:    hook.ID,
+		UserID:       hook.UserID,
+		EventID:      original.EventID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, []*model.WebhookDelivery{redelivery}); err != nil {
+		return nil, err
+	}
+	return redelivery, nil
+}
+
+func (s *webhookServiceImpl) Subscribe(bus *eventbus.Bus) {
+	bus.Subscribe(event.TransactionCreatedName, s.handleTransactionEvent)
+	bus.Subscribe(event.TransactionUpdatedName, s.handleTransactionEvent)
+	bus.Subscribe(event.TransactionDeletedName, s.handleTransactionEvent)
+}
+
+func (s *webhookServiceImpl) handleTransactionEvent(ctx context.Context, e eventbus.Event) error {
+	switch e := e.(type) {
+	case event.TransactionCreated:
+		return s.emit(ctx, e.ID, model.WebhookTransactionCreated, e.Transaction)
+	case event.TransactionUpdated:
+		return s.emit(ctx, e.ID, model.WebhookTransactionUpdated, e.Transaction)
+	case event.TransactionDeleted:
+		return s.emit(ctx, e.ID, model.WebhookTransactionDeleted, e.Transaction)
+	}
+	return nil
+}
+
+// emit ставит в очередь событие о транзакции для подписанных вебхуков её владельца.
+// ID события в теле совпадает с ID доменного события
+func (s *webhookServiceImpl) emit(ctx context.Context, eventID string, name model.WebhookEvent, tx *model.Transaction) error {
+	hooks, err := s.webhookRepo.GetSubscribed(ctx, tx.UserID, name)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(webhookEvent{
+		ID:        eventID,
+		Event:     name,
+		CreatedAt: s.now().UTC(),
+		Data:      toTransactionPayload(tx),
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*model.WebhookDelivery, len(hooks))
+	for i, hook := range hooks {
+		deliveries[i] = &model.WebhookDelivery{
+			WebhookID: hook.ID,
+			UserID:    hook.UserID,
+			EventID:   eventID,
+			Event:     name,
+			Payload:   payload,
+		}
+	}
+	return s.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
+
+func (s *webhookServiceImpl) Deliver(ctx context.Context) (int, error) {
+	hooks := make(map[string]*model.Webhook)
+	succeeded := 0
+	for {
+		batch, err := s.webhookRepo.ClaimDue(ctx, webhookBatchSize, webhookLease)
+		if err != nil {
+			return succeeded, err
+		}
+
+		for _, d := range batch {
+			hook, ok := hooks[d.WebhookID]
+			if !ok {
+				if hook, err = s.webhookRepo.GetByID(ctx, d.WebhookID); err != nil && !errors.Is(err, repo.ErrNotFound) {
+					return succeeded, err
+				}
+				hooks[d.WebhookID] = hook
+			}
+			if hook == nil {
+				// Вебхук удалён вместе с журналом, пока доставка была в работе
+				continue
+			}
+
+			s.attempt(ctx, hook, d)
+			if ctx.Err() != nil {
+				return succeeded, ctx.Err()
+			}
+			if err := s.webhookRepo.RecordAttempt(ctx, d); err != nil {
+				return succeeded, err
+			}
+			if d.Status == model.WebhookDeliverySucceeded {
+				succeeded++
+			}
+		}
+
+		if len(batch) < webhookBatchSize {
+			return succeeded, nil
+		}
+	}
+}
+
+// attempt отправляет доставку и записывает в неё результат попытки
+func (s *webhookServiceImpl) attempt(ctx context.Context, hook *model.Webhook, d *model.WebhookDelivery) {
+	d.ResponseStatus, d.ResponseBody, d.DurationMs, d.LastError = nil, nil, nil, nil
+
+	if !hook.Active {
+		message := "webhook is disabled"
+		d.Status = model.WebhookDeliveryFailed
+		d.LastError = &message
+		return
+	}
+
+	d.Attempts++
+	resp, err := s.sender.Send(ctx, &webhook.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      string(d.Event),
+		DeliveryID: d.ID,
+		Body:       d.Payload,
+	})
+	if err == nil {
+		status, duration := resp.StatusCode, resp.Duration.Milliseconds()
+		d.ResponseStatus, d.ResponseBody, d.DurationMs = &status, &resp.Body, &duration
+		if resp.OK() {
+			now := s.now()
+			d.Status = model.WebhookDeliverySucceeded
+			d.DeliveredAt = &now
+			return
+		}
+		message := "unexpected status " + strconv.Itoa(status)
+		d.LastError = &message
+	} else {
+		message := err.Error()
+		d.LastError = &message
+	}
+
+	if d.Attempts >= maxWebhookAttempts {
+		d.Status = model.WebhookDeliveryFailed
+		return
+	}
+	d.Status = model.WebhookDeliveryPending
+	d.NextAttemptAt = s.now().Add(retryDelay(d.Attempts, webhookRetryBase, webhookRetryMax))
+}
+
+func (s *webhookServiceImpl) RunDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Deliver(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("webhooks: delivery failed: %v", err)
+			}
+			if _, err := s.webhookRepo.DeleteDeliveriesBefore(ctx, s.now().Add(-webhookRetention)); err != nil && ctx.Err() == nil {
+				log.Printf("webhooks: cleanup failed: %v", err)
+			}
+		}
+	}
+}
+
+// validateWebhook проверяет адрес и события вебхука и возвращает события без повторов
+func validateWebhook(hook *model.Webhook) ([]model.WebhookEvent, error) {
+	var fields []apperror.FieldError
+	if webhook.ValidateURL(hook.URL) != nil {
+		fields = append(fields, apperror.Field("url", "invalid_format"))
+	} else if len(hook.URL) > maxWebhookURL {
+		fields = append(fields, apperror.FieldWithParams("url", "too_long", map[string]any{"max": maxWebhookURL}))
+	}
+
+	if len(hook.Events) == 0 {
+		fields = append(fields, apperror.Field("events", "required"))
+	}
+	seen := make(map[model.WebhookEvent]bool, len(hook.Events))
+	var events []model.WebhookEvent
+	for i, event := range hook.Events {
+		if !event.IsValid() {
+			fields = append(fields, apperror.FieldWithParams("events["+strconv.Itoa(i)+"]", "not_allowed",
+				map[string]any{"allowed": "transaction.created, transaction.updated, transaction.deleted"}))
+			continue
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	if len(fields) > 0 {
+		return nil, apperror.Validation(fields...)
+	}
+	return events, nil
+}
+
+// newWebhookSecret создаёт ключ подписи из 32 случайных байт
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
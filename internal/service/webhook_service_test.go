@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/event"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/eventbus"
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
+)
+
+// stubWebhookRepository хранит вебхуки и очередь доставок в памяти
+type stubWebhookRepository struct {
+	repository.WebhookRepository
+	hooks      []*model.Webhook
+	deliveries []*model.WebhookDelivery
+	recorded   []*model.WebhookDelivery
+}
+
+func (m *stubWebhookRepository) GetByID(ctx context.Context, id string) (*model.Webhook, error) {
+	for _, hook := range m.hooks {
+		if hook.ID == id {
+			return hook, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (m *stubWebhookRepository) GetSubscribed(ctx context.Context, userID string, event model.WebhookEvent) ([]*model.Webhook, error) {
+	var hooks []*model.Webhook
+	for _, hook := range m.hooks {
+		if hook.UserID == userID && hook.Active && hook.Subscribed(event) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (m *stubWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	for _, d := range deliveries {
+		d.ID = "delivery-" + d.WebhookID
+		d.Status = model.WebhookDeliveryPending
+	}
+	m.deliveries = append(m.deliveries, deliveries...)
+	return nil
+}
+
+func (m *stubWebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	claimed := m.deliveries
+	m.deliveries = nil
+	return claimed, nil
+}
+
+func (m *stubWebhookRepository) RecordAttempt(ctx context.Context, d *model.WebhookDelivery) error {
+	m.recorded = append(m.recorded, d)
+	return nil
+}
+
+// stubWebhookSender отвечает статусом, заданным для адреса, или ошибкой соединения
+type stubWebhookSender struct {
+	statuses map[string]int
+	requests []*webhook.Request
+}
+
+func (s *stubWebhookSender) Send(ctx context.Context, r *webhook.Request) (*webhook.Response, error) {
+	s.requests = append(s.requests, r)
+	status, ok := s.statuses[r.URL]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return &webhook.Response{StatusCode: status, Duration: 15 * time.Millisecond}, nil
+}
+
+func TestWebhookService_Subscribe(t *testing.T) {
+	hooks := &stubWebhookRepository{hooks: []*model.Webhook{
+		{ID: "all", UserID: "user-1", Active: true, Events: model.WebhookEvents},
+		{ID: "created", UserID: "user-1", Active: true, Events: []model.WebhookEvent{model.WebhookTransactionCreated}},
+		{ID: "disabled", UserID: "user-1", Active: false, Events: model.WebhookEvents},
+		{ID: "other", UserID: "user-2", Active: true, Events: model.WebhookEvents},
+	}}
+	bus := eventbus.New(eventbus.Config{})
+	NewWebhookService(hooks, &stubWebhookSender{}).Subscribe(bus)
+
+	tx := &model.Transaction{ID: "tx-1", UserID: "user-1", Amount: 250, Currency: "RUB", Description: "Такси"}
+	if err := bus.Publish(context.Background(), event.NewTransactionUpdated(tx, tx)); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+
+	if len(hooks.deliveries) != 1 || hooks.deliveries[0].WebhookID != "all" {
+		t.Fatalf("Expected one delivery to the subscribed webhook, got %+v", hooks.deliveries)
+	}
+
+	var payload struct {
+		ID    string             `json:"id"`
+		Event model.WebhookEvent `json:"event"`
+		Data  struct {
+			ID     string  `json:"id"`
+			Amount float64 `json:"amount"`
+		} `json:"data"`
+	}
+	d := hooks.deliveries[0]
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		t.Fatalf("Payload is not JSON: %v", err)
+	}
+	if payload.ID == "" || payload.ID != d.EventID || payload.Event != model.WebhookTransactionUpdated || payload.Data.ID != "tx-1" || payload.Data.Amount != 250 {
+		t.Errorf("Unexpected payload %s", d.Payload)
+	}
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	hooks := &stubWebhookRepository{
+		hooks: []*model.Webhook{
+			{ID: "ok", URL: "https://ok.example.com", Secret: "s1", Active: true},
+			{ID: "down", URL: "https://down.example.com", Secret: "s2", Active: true},
+			{ID: "error", URL: "https://error.example.com", Secret: "s3", Active: true},
+			{ID: "disabled", URL: "https://ok.example.com", Active: false},
+		},
+		deliveries: []*model.WebhookDelivery{
+			{ID: "d1", WebhookID: "ok", Event: model.WebhookTransactionCreated, Payload: []byte(`{}`)},
+			{ID: "d2", WebhookID: "down", Event: model.WebhookTransactionCreated, Payload: []byte(`{}`)},
+			{ID: "d3", WebhookID: "error", Event: model.WebhookTransactionCreated, Payload: []byte(`{}`), Attempts: maxWebhookAttempts - 1},
+			{ID: "d4", WebhookID: "disabled", Event: model.WebhookTransactionCreated, Payload: []byte(`{}`)},
+			{ID: "d5", WebhookID: "deleted", Event: model.WebhookTransactionCreated, Payload: []byte(`{}`)},
+		},
+	}
+	sender := &stubWebhookSender{statuses: map[string]int{
+		"https://ok.example.com":    204,
+		"https://error.example.com": 500,
+	}}
+	svc := NewWebhookService(hooks, sender).(*webhookServiceImpl)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	succeeded, err := svc.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	if succeeded != 1 {
+		t.Errorf("Expected 1 successful delivery, got %d", succeeded)
+	}
+	if len(sender.requests) != 3 {
+		t.Errorf("Expected 3 requests (disabled and deleted webhooks are skipped), got %d", len(sender.requests))
+	}
+	if r := sender.requests[0]; r.Secret != "s1" || r.DeliveryID != "d1" || r.Event != string(model.WebhookTransactionCreated) {
+		t.Errorf("Unexpected request %+v", r)
+	}
+
+	byID := make(map[string]*model.WebhookDelivery)
+	for _, d := range hooks.recorded {
+		byID[d.ID] = d
+	}
+	if len(byID) != 4 {
+		t.Fatalf("Expected 4 recorded attempts, got %d", len(byID))
+	}
+
+	if d := byID["d1"]; d.Status != model.WebhookDeliverySucceeded || d.DeliveredAt == nil || *d.ResponseStatus != 204 {
+		t.Errorf("d1: expected success, got %+v", d)
+	}
+	// Сеть недоступна — повтор через базовую задержку
+	if d := byID["d2"]; d.Status != model.WebhookDeliveryPending || d.Attempts != 1 || !d.NextAttemptAt.Equal(now.Add(webhookRetryBase)) || d.LastError == nil {
+		t.Errorf("d2: expected retry after %s, got %+v", webhookRetryBase, d)
+	}
+	// Последняя попытка исчерпана
+	if d := byID["d3"]; d.Status != model.WebhookDeliveryFailed || *d.ResponseStatus != 500 {
+		t.Errorf("d3: expected failure after last attempt, got %+v", d)
+	}
+	if d := byID["d4"]; d.Status != model.WebhookDeliveryFailed || d.Attempts != 0 {
+		t.Errorf("d4: expected disabled webhook to fail without attempt, got %+v", d)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{20, 12 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt, webhookRetryBase, webhookRetryMax); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/apperror"
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	"github.com/gibbon/finace-dashboard/internal/domain/repository"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+	"github.com/gibbon/finace-dashboard/pkg/notify"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWorkspaceNotFound           = apperror.New(apperror.KindNotFound, "workspace_not_found")
+	ErrWorkspaceMemberNotFound     = apperror.New(apperror.KindNotFound, "workspace_member_not_found")
+	ErrWorkspaceMemberExists       = apperror.New(apperror.KindConflict, "workspace_member_exists")
+	ErrWorkspaceOwner              = apperror.New(apperror.KindConflict, "workspace_owner_immutable")
+	ErrWorkspaceInvitationNotFound = apperror.New(apperror.KindNotFound, "workspace_invitation_not_found")
+	ErrWorkspaceInvitationExpired  = apperror.New(apperror.KindConflict, "workspace_invitation_expired")
+	ErrWorkspaceInvitationEmail    = apperror.New(apperror.KindForbidden, "workspace_invitation_email_mismatch")
+)
+
+// workspaceInvitationTTL сколько действует приглашение в рабочее пространство
+const workspaceInvitationTTL = 7 * 24 * time.Hour
+
+type WorkspaceService interface {
+	// Возвращает пространства, в которых состоит пользователь
+	GetAll(ctx context.Context, userID string) ([]*model.Workspace, error)
+
+	// Возвращает пространство по ID. Не участнику пространство не видно
+	GetByID(ctx context.Context, userID, id string) (*model.Workspace, error)
+
+	// Создаёт пространство, создатель становится его владельцем
+	Create(ctx context.Context, userID string, workspace *model.Workspace) (*model.Workspace, error)
+
+	// Переименовывает пространство. Доступно владельцу
+	Update(ctx context.Context, userID string, workspace *model.Workspace) (*model.Workspace, error)
+
+	// Удаляет пространство. Его транзакции становятся личными транзакциями авторов, правила удаляются
+	Delete(ctx context.Context, userID, id string) error
+
+	// Возвращает участников пространства
+	GetMembers(ctx context.Context, userID, workspaceID string) ([]*model.WorkspaceMember, error)
+
+	// Меняет роль участника. Роль владельца не меняется
+	UpdateMember(ctx context.Context, userID, workspaceID, memberID string, role model.WorkspaceRole) error
+
+	// Исключает участника. Участник может выйти сам, владелец выйти не может
+	RemoveMember(ctx context.Context, userID, workspaceID, memberID string) error
+
+	// Приглашает пользователя по email и возвращает приглашение с токеном.
+	// Токен показывается только один раз и отправляется на email, если почта настроена
+	Invite(ctx context.Context, userID, workspaceID, email string, role model.WorkspaceRole) (*model.WorkspaceInvitation, string, error)
+
+	// Возвращает непринятые приглашения пространства
+	GetInvitations(ctx context.Context, userID, workspaceID string) ([]*model.WorkspaceInvitation, error)
+
+	// Отзывает приглашение
+	RevokeInvitation(ctx context.Context, userID, workspaceID, id string) error
+
+	// Принимает приглашение по токену: пользователь с адресом из приглашения становится участником
+	AcceptInvitation(ctx context.Context, userID, token string) (*model.Workspace, error)
+}
+
+type workspaceServiceImpl struct {
+	workspaceRepo repository.WorkspaceRepository
+	userRepo      repository.UserRepository
+	notifier      notify.Notifier
+	transactor    repository.Transactor
+	now           func() time.Time
+}
+
+// NewWorkspaceService создаёт сервис рабочих пространств. notifier отправляет приглашения
+// по email и может быть nil, тогда токен приглашения передаётся только в ответе
+func NewWorkspaceService(
+	workspaceRepo repository.WorkspaceRepository,
+	userRepo repository.UserRepository,
+	notifier notify.Notifier,
+	transactor repository.Transactor,
+) WorkspaceService {
+	return &workspaceServiceImpl{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		notifier:      notifier,
+		transactor:    transactor,
+		now:           time.Now,
+	}
+}
+
+func (s *workspaceServiceImpl) GetAll(ctx context.Context, userID string) ([]*model.Workspace, error) {
+	return s.workspaceRepo.GetByUserID(ctx, userID)
+}
+
+func (s *workspaceServiceImpl) GetByID(ctx context.Context, userID, id string) (*model.Workspace, error) {
+	return s.get(ctx, userID, id, model.PermissionRead)
+}
+
+func (s *workspaceServiceImpl) Create(ctx context.Context, userID string, workspace *model.Workspace) (*model.Workspace, error) {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.workspaceRepo.Create(ctx, workspace); err != nil {
+			return err
+		}
+		return s.workspaceRepo.AddMember(ctx, &model.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        model.WorkspaceOwner,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workspace.Role = model.WorkspaceOwner
+	return workspace, nil
+}
+
+func (s *workspaceServiceImpl) Update(ctx context.Context, userID string, workspace *model.Workspace) (*model.Workspace, error) {
+	existing, err := s.get(ctx, userID, workspace.ID, model.PermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return nil, notFound(err, ErrWorkspaceNotFound)
+	}
+
+	workspace.Role = existing.Role
+	return workspace, nil
+}
+
+func (s *workspaceServiceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.get(ctx, userID, id, model.PermissionManage); err != nil {
+		return err
+	}
+	return s.workspaceRepo.Delete(ctx, id)
+}
+
+func (s *workspaceServiceImpl) GetMembers(ctx context.Context, userID, workspaceID string) ([]*model.WorkspaceMember, error) {
+	if _, err := s.get(ctx, userID, workspaceID, model.PermissionRead); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.GetMembers(ctx, workspaceID)
+}
+
+func (s *workspaceServiceImpl) UpdateMember(ctx context.Context, userID, workspaceID, memberID string, role model.WorkspaceRole) error {
+	if err := checkMemberRole(role); err != nil {
+		return err
+	}
+	if _, err := s.get(ctx, userID, workspaceID, model.PermissionManage); err != nil {
+		return err
+	}
+	if err := s.checkNotOwner(ctx, workspaceID, memberID); err != nil {
+		return err
+	}
+
+	return notFound(s.workspaceRepo.UpdateMemberRole(ctx, workspaceID, memberID, role), ErrWorkspaceMemberNotFound)
+}
+
+func (s *workspaceServiceImpl) RemoveMember(ctx context.Context, userID, workspaceID, memberID string) error {
+	// Выйти из пространства может любой участник, исключить другого — только владелец
+	perm := model.PermissionManage
+	if memberID == userID {
+		perm = model.PermissionRead
+	}
+	if _, err := s.get(ctx, userID, workspaceID, perm); err != nil {
+		return err
+	}
+	if err := s.checkNotOwner(ctx, workspaceID, memberID); err != nil {
+		return err
+	}
+
+	return notFound(s.workspaceRepo.RemoveMember(ctx, workspaceID, memberID), ErrWorkspaceMemberNotFound)
+}
+
+func (s *workspaceServiceImpl) Invite(ctx context.Context, userID, workspaceID, email string, role model.WorkspaceRole) (*model.WorkspaceInvitation, string, error) {
+	if err := checkMemberRole(role); err != nil {
+		return nil, "", err
+	}
+	workspace, err := s.get(ctx, userID, workspaceID, model.PermissionManage)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	invitation := &model.WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		Email:       normalizeEmail(email),
+		Role:        role,
+		TokenHash:   hashInvitationToken(token),
+		InvitedBy:   userID,
+		ExpiresAt:   s.now().Add(workspaceInvitationTTL),
+	}
+	if err := s.workspaceRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, "", err
+	}
+
+	s.sendInvitation(ctx, workspace, invitation, token)
+	return invitation, token, nil
+}
+
+func (s *workspaceServiceImpl) GetInvitations(ctx context.Context, userID, workspaceID string) ([]*model.WorkspaceInvitation, error) {
+	if _, err := s.get(ctx, userID, workspaceID, model.PermissionManage); err != nil {
+		return nil, err
+	}
+	return s.workspaceRepo.GetPendingInvitations(ctx, workspaceID)
+}
+
+func (s *workspaceServiceImpl) RevokeInvitation(ctx context.Context, userID, workspaceID, id string) error {
+	if _, err := s.get(ctx, userID, workspaceID, model.PermissionManage); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrWorkspaceInvitationNotFound
+	}
+
+	return notFound(s.workspaceRepo.DeleteInvitation(ctx, workspaceID, id), ErrWorkspaceInvitationNotFound)
+}
+
+func (s *workspaceServiceImpl) AcceptInvitation(ctx context.Context, userID, token string) (*model.Workspace, error) {
+	invitation, err := s.workspaceRepo.GetInvitationByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil {
+		return nil, notFound(err, ErrWorkspaceInvitationNotFound)
+	}
+	if invitation.AcceptedAt != nil {
+		return nil, ErrWorkspaceInvitationNotFound
+	}
+	if !s.now().Before(invitation.ExpiresAt) {
+		return nil, ErrWorkspaceInvitationExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if normalizeEmail(user.Email) != invitation.Email {
+		return nil, ErrWorkspaceInvitationEmail
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Приглашение могли принять параллельно по тому же токену
+		if err := s.workspaceRepo.AcceptInvitation(ctx, invitation.ID); err != nil {
+			return notFound(err, ErrWorkspaceInvitationNotFound)
+		}
+
+		err := s.workspaceRepo.AddMember(ctx, &model.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      userID,
+			Role:        invitation.Role,
+		})
+		if errors.Is(err, repo.ErrMemberExists) {
+			return ErrWorkspaceMemberExists
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.get(ctx, userID, invitation.WorkspaceID, model.PermissionRead)
+}
+
+// get загружает пространство с ролью пользователя и проверяет, что роль даёт право perm
+func (s *workspaceServiceImpl) get(ctx context.Context, userID, id string, perm model.Permission) (*model.Workspace, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	workspace, err := s.workspaceRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, notFound(err, ErrWorkspaceNotFound)
+	}
+	if !workspace.Role.Can(perm) {
+		return nil, ErrForbidden
+	}
+	return workspace, nil
+}
+
+// checkNotOwner запрещает менять роль владельца и исключать его
+func (s *workspaceServiceImpl) checkNotOwner(ctx context.Context, workspaceID, memberID string) error {
+	role, err := s.workspaceRepo.GetMemberRole(ctx, workspaceID, memberID)
+	if err != nil {
+		return notFound(err, ErrWorkspaceMemberNotFound)
+	}
+	if role == model.WorkspaceOwner {
+		return ErrWorkspaceOwner
+	}
+	return nil
+}
+
+// sendInvitation отправляет токен приглашения на email. Ошибка отправки не отменяет
+// приглашение: токен уже возвращён пригласившему и может быть передан вручную
+func (s *workspaceServiceImpl) sendInvitation(ctx context.Context, workspace *model.Workspace, invitation *model.WorkspaceInvitation, token string) {
+	if s.notifier == nil {
+		return
+	}
+
+	err := s.notifier.Send(ctx, &notify.Message{
+		To:      invitation.Email,
+		Event:   "workspace.invitation",
+		Subject: "Приглашение в пространство «" + workspace.Name + "»",
+		Text: "Вас пригласили в рабочее пространство «" + workspace.Name + "» с ролью " + string(invitation.Role) + ".\n" +
+			"Токен приглашения: " + token + "\n" +
+			"Приглашение действует до " + invitation.ExpiresAt.Format("02.01.2006 15:04") + ".",
+	})
+	if err != nil {
+		log.Printf("workspaces: failed to send invitation %s: %v", invitation.ID, err)
+	}
+}
+
+// checkMemberRole проверяет роль, которую можно выдать участнику. Владелец у пространства один
+func checkMemberRole(role model.WorkspaceRole) error {
+	if role != model.WorkspaceEditor && role != model.WorkspaceViewer {
+		return apperror.Validation(apperror.FieldWithParams("role", "not_allowed", map[string]any{"allowed": "editor, viewer"}))
+	}
+	return nil
+}
+
+// newInvitationToken создаёт токен приглашения из 32 случайных байт
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashInvitationToken хэш токена, под которым приглашение хранится в базе
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/internal/domain/model"
+	repo "github.com/gibbon/finace-dashboard/internal/repository"
+)
+
+// mockWorkspaceInvitations дополняет mockWorkspaceRepository приглашениями
+type mockWorkspaceInvitations struct {
+	*mockWorkspaceRepository
+	invitations map[string]*model.WorkspaceInvitation
+}
+
+func (m *mockWorkspaceInvitations) GetByID(ctx context.Context, id, userID string) (*model.Workspace, error) {
+	role, err := m.GetMemberRole(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Workspace{ID: id, Name: "Семья", Role: role}, nil
+}
+
+func (m *mockWorkspaceInvitations) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*model.WorkspaceInvitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.TokenHash == tokenHash {
+			return invitation, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+func (m *mockWorkspaceInvitations) AcceptInvitation(ctx context.Context, id string) error {
+	invitation, ok := m.invitations[id]
+	if !ok || invitation.AcceptedAt != nil {
+		return repo.ErrNotFound
+	}
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	return nil
+}
+
+func (m *mockWorkspaceInvitations) AddMember(ctx context.Context, member *model.WorkspaceMember) error {
+	if _, ok := m.roles[member.WorkspaceID][member.UserID]; ok {
+		return repo.ErrMemberExists
+	}
+	m.roles[member.WorkspaceID][member.UserID] = member.Role
+	return nil
+}
+
+func (m *mockWorkspaceInvitations) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.WorkspaceRole) error {
+	if _, ok := m.roles[workspaceID][userID]; !ok {
+		return repo.ErrNotFound
+	}
+	m.roles[workspaceID][userID] = role
+	return nil
+}
+
+func (m *mockWorkspaceInvitations) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	if _, ok := m.roles[workspaceID][userID]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(m.roles[workspaceID], userID)
+	return nil
+}
+
+func TestWorkspaceService_AcceptInvitation(t *testing.T) {
+	const (
+		workspaceID = "66666666-6666-6666-6666-666666666666"
+		token       = "valid-token"
+	)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	workspaceRepo := &mockWorkspaceInvitations{
+		mockWorkspaceRepository: &mockWorkspaceRepository{roles: map[string]map[string]model.WorkspaceRole{
+			workspaceID: {"owner": model.WorkspaceOwner},
+		}},
+		invitations: map[string]*model.WorkspaceInvitation{
+			"inv-1": {ID: "inv-1", WorkspaceID: workspaceID, Email: "partner@example.com", Role: model.WorkspaceEditor,
+				TokenHash: hashInvitationToken(token), ExpiresAt: now.Add(time.Hour)},
+			"inv-2": {ID: "inv-2", WorkspaceID: workspaceID, Email: "partner@example.com", Role: model.WorkspaceViewer,
+				TokenHash: hashInvitationToken("expired-token"), ExpiresAt: now.Add(-time.Hour)},
+		},
+	}
+	userRepo := newMockUserRepository()
+	userRepo.users["partner"] = &model.User{ID: "partner", Email: "Partner@Example.com"}
+	userRepo.users["stranger"] = &model.User{ID: "stranger", Email: "stranger@example.com"}
+
+	svc := NewWorkspaceService(workspaceRepo, userRepo, nil, &mockTransactor{}).(*workspaceServiceImpl)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		userID  string
+		token   string
+		wantErr error
+	}{
+		{"неизвестный токен", "partner", "unknown", ErrWorkspaceInvitationNotFound},
+		{"истёкшее приглашение", "partner", "expired-token", ErrWorkspaceInvitationExpired},
+		{"другой email", "stranger", token, ErrWorkspaceInvitationEmail},
+		{"принято", "partner", token, nil},
+		{"повторное принятие", "partner", token, ErrWorkspaceInvitationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace, err := svc.AcceptInvitation(ctx, tt.userID, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AcceptInvitation() error = %v, ожидалось %v", err, tt.wantErr)
+			}
+			if err == nil && workspace.Role != model.WorkspaceEditor {
+				t.Errorf("AcceptInvitation() роль %q, ожидалась editor", workspace.Role)
+			}
+		})
+	}
+
+	// Исключать участников может только владелец, а сам владелец выйти не может
+	if err := svc.RemoveMember(ctx, "partner", workspaceID, "owner"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("editor RemoveMember(owner) error = %v, ожидалось ErrForbidden", err)
+	}
+	if err := svc.RemoveMember(ctx, "owner", workspaceID, "owner"); !errors.Is(err, ErrWorkspaceOwner) {
+		t.Errorf("owner RemoveMember(owner) error = %v, ожидалось ErrWorkspaceOwner", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package blobstore хранит двоичные объекты (файлы вложений) по ключу.
+// Реализации: локальная файловая система и S3-совместимое хранилище
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrNotFound объект с таким ключом отсутствует
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey ключ пустой или выходит за пределы хранилища
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранилище объектов
+type BlobStore interface {
+	// Put сохраняет объект, перезаписывая существующий
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+
+	// Get открывает объект на чтение. Вызывающий обязан закрыть поток
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete удаляет объект. Отсутствие объекта не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey допускает только относительные ключи из латиницы, цифр и "/._-" без сегментов ".."
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '/', r == '.', r == '_', r == '-':
+		default:
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
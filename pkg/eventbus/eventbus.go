@@ -0,0 +1,235 @@
+// Package eventbus доставляет события подписчикам внутри процесса.
+//
+// Синхронные подписчики вызываются в Publish с контекстом публикующего: ошибка подписчика
+// возвращается публикующему, а внутри транзакции БД отменяет её. Асинхронные подписчики
+// вызываются в фоне после фиксации транзакции, в которой опубликовано событие, на том же
+// экземпляре сервиса. Широковещательные подписчики тоже вызываются в фоне, но получают события
+// всех экземпляров, если задан Transport (например, RedisStreams)
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultQueueSize сколько событий ждёт асинхронных подписчиков, прежде чем новые начнут отбрасываться
+const defaultQueueSize = 1024
+
+// Event событие. EventName определяет подписчиков и тип при декодировании из Transport
+type Event interface {
+	EventName() string
+}
+
+// Handler обрабатывает событие. Подписчик не должен изменять событие:
+// один и тот же экземпляр получают все подписчики
+type Handler func(ctx context.Context, e Event) error
+
+// Message событие в Transport: имя и событие в JSON
+type Message struct {
+	Name string
+	Data []byte
+}
+
+// Transport передаёт события между экземплярами сервиса.
+// Consume получает сообщения всех экземпляров, включая собственные, пока не отменён ctx или не случилась ошибка
+type Transport interface {
+	Publish(ctx context.Context, msg *Message) error
+	Consume(ctx context.Context, handle func(*Message)) error
+}
+
+// Config параметры шины. Decode обязателен вместе с Transport.
+// AfterCommit откладывает fn до фиксации транзакции из ctx; без него асинхронные подписчики
+// получают событие сразу
+type Config struct {
+	Transport   Transport
+	Decode      func(name string, data []byte) (Event, error)
+	AfterCommit func(ctx context.Context, fn func())
+	QueueSize   int
+}
+
+// job событие для фоновых подписчиков. publish — отправить событие в Transport
+type job struct {
+	event    Event
+	handlers []Handler
+	publish  bool
+}
+
+// Bus шина событий. Фоновые подписчики работают, пока запущен Run
+type Bus struct {
+	cfg       Config
+	mu        sync.RWMutex
+	sync      map[string][]Handler
+	async     map[string][]Handler
+	broadcast map[string][]Handler
+	queue     chan job
+}
+
+func New(cfg Config) *Bus {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	return &Bus{
+		cfg:       cfg,
+		sync:      make(map[string][]Handler),
+		async:     make(map[string][]Handler),
+		broadcast: make(map[string][]Handler),
+		queue:     make(chan job, cfg.QueueSize),
+	}
+}
+
+// Subscribe подписывает h на событие синхронно
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.subscribe(b.sync, name, h)
+}
+
+// SubscribeAsync подписывает h на событие этого экземпляра после фиксации транзакции
+func (b *Bus) SubscribeAsync(name string, h Handler) {
+	b.subscribe(b.async, name, h)
+}
+
+// SubscribeBroadcast подписывает h на событие любого экземпляра после фиксации транзакции
+func (b *Bus) SubscribeBroadcast(name string, h Handler) {
+	b.subscribe(b.broadcast, name, h)
+}
+
+func (b *Bus) subscribe(handlers map[string][]Handler, name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	handlers[name] = append(handlers[name], h)
+}
+
+func (b *Bus) handlers(handlers map[string][]Handler, name string) []Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return handlers[name]
+}
+
+// Publish вызывает синхронных подписчиков и ставит события в очередь фоновых.
+// Если синхронный подписчик вернул ошибку, остальные события не публикуются
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	for _, e := range events {
+		for _, h := range b.handlers(b.sync, e.EventName()) {
+			if err := h(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Фоновые подписчики не должны увидеть события отменённой транзакции
+	dispatch := func() {
+		for _, e := range events {
+			b.dispatch(e)
+		}
+	}
+	if b.cfg.AfterCommit != nil {
+		b.cfg.AfterCommit(ctx, dispatch)
+	} else {
+		dispatch()
+	}
+	return nil
+}
+
+// dispatch ставит событие в очередь фоновых подписчиков. При Transport широковещательные
+// подписчики получат событие из Transport, как и на остальных экземплярах
+func (b *Bus) dispatch(e Event) {
+	name := e.EventName()
+	if handlers := b.handlers(b.async, name); len(handlers) > 0 {
+		b.enqueue(job{event: e, handlers: handlers})
+	}
+	if b.cfg.Transport != nil {
+		b.enqueue(job{event: e, publish: true})
+	} else if handlers := b.handlers(b.broadcast, name); len(handlers) > 0 {
+		b.enqueue(job{event: e, handlers: handlers})
+	}
+}
+
+// enqueue не блокирует публикующего: при переполненной очереди событие отбрасывается
+func (b *Bus) enqueue(j job) {
+	select {
+	case b.queue <- j:
+	default:
+		log.Printf("eventbus: queue is full, %s dropped", j.event.EventName())
+	}
+}
+
+// receive передаёт событие из Transport широковещательным подписчикам
+func (b *Bus) receive(msg *Message) {
+	handlers := b.handlers(b.broadcast, msg.Name)
+	if len(handlers) == 0 {
+		return
+	}
+
+	e, err := b.cfg.Decode(msg.Name, msg.Data)
+	if err != nil {
+		log.Printf("eventbus: decode %s failed: %v", msg.Name, err)
+		return
+	}
+	b.enqueue(job{event: e, handlers: handlers})
+}
+
+// Run вызывает фоновых подписчиков по очереди и получает события из Transport, пока не отменён ctx
+func (b *Bus) Run(ctx context.Context) {
+	if b.cfg.Transport != nil {
+		go b.consume(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-b.queue:
+			b.process(ctx, j)
+		}
+	}
+}
+
+// consume получает события из Transport, переподключаясь после ошибок
+func (b *Bus) consume(ctx context.Context) {
+	for {
+		err := b.cfg.Transport.Consume(ctx, b.receive)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("eventbus: consume failed: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (b *Bus) process(ctx context.Context, j job) {
+	name := j.event.EventName()
+	if j.publish {
+		data, err := json.Marshal(j.event)
+		if err == nil {
+			err = b.cfg.Transport.Publish(ctx, &Message{Name: name, Data: data})
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("eventbus: publish %s failed: %v", name, err)
+		}
+		return
+	}
+
+	for _, h := range j.handlers {
+		b.call(ctx, name, h, j.event)
+	}
+}
+
+// call вызывает подписчика так, чтобы его ошибка или паника не остановила остальных
+func (b *Bus) call(ctx context.Context, name string, h Handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("eventbus: %s handler panicked: %v", name, r)
+		}
+	}()
+
+	if err := h(ctx, e); err != nil && ctx.Err() == nil {
+		log.Printf("eventbus: %s handler failed: %v", name, err)
+	}
+}
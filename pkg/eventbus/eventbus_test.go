@@ -0,0 +1,151 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	ID string `json:"id"`
+}
+
+func (testEvent) EventName() string { return "test.happened" }
+
+// recorder собирает ID событий, полученных подписчиком
+type recorder struct {
+	mu  sync.Mutex
+	ids []string
+	got chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{got: make(chan struct{}, 16)}
+}
+
+func (r *recorder) handle(ctx context.Context, e Event) error {
+	r.mu.Lock()
+	r.ids = append(r.ids, e.(testEvent).ID)
+	r.mu.Unlock()
+	r.got <- struct{}{}
+	return nil
+}
+
+func (r *recorder) wait(t *testing.T, n int) []string {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.got:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d events, got %d", n, i)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids...)
+}
+
+// memoryTransport передаёт сообщения подписчику Consume через канал
+type memoryTransport struct {
+	messages chan *Message
+}
+
+func (m *memoryTransport) Publish(ctx context.Context, msg *Message) error {
+	m.messages <- msg
+	return nil
+}
+
+func (m *memoryTransport) Consume(ctx context.Context, handle func(*Message)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-m.messages:
+			handle(msg)
+		}
+	}
+}
+
+func TestBus_Publish(t *testing.T) {
+	// Фоновые подписчики получают события только после «фиксации»
+	var commit []func()
+	bus := New(Config{AfterCommit: func(ctx context.Context, fn func()) { commit = append(commit, fn) }})
+
+	syncRec, asyncRec, broadcastRec := newRecorder(), newRecorder(), newRecorder()
+	bus.Subscribe("test.happened", syncRec.handle)
+	bus.SubscribeAsync("test.happened", asyncRec.handle)
+	bus.SubscribeBroadcast("test.happened", broadcastRec.handle)
+	bus.SubscribeAsync("other.happened", func(ctx context.Context, e Event) error {
+		t.Error("Handler of another event must not be called")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	if err := bus.Publish(ctx, testEvent{ID: "1"}, testEvent{ID: "2"}); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if got := syncRec.wait(t, 2); len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Errorf("Expected sync handler to get events in order, got %v", got)
+	}
+	if len(asyncRec.got) != 0 {
+		t.Error("Async handler must not run before commit")
+	}
+
+	for _, fn := range commit {
+		fn()
+	}
+	if got := asyncRec.wait(t, 2); got[0] != "1" || got[1] != "2" {
+		t.Errorf("Expected async handler to get events in order, got %v", got)
+	}
+	broadcastRec.wait(t, 2)
+}
+
+func TestBus_PublishSyncError(t *testing.T) {
+	bus := New(Config{})
+	failure := errors.New("outbox is unavailable")
+	bus.Subscribe("test.happened", func(ctx context.Context, e Event) error { return failure })
+	asyncRec := newRecorder()
+	bus.SubscribeAsync("test.happened", asyncRec.handle)
+
+	if err := bus.Publish(context.Background(), testEvent{ID: "1"}); !errors.Is(err, failure) {
+		t.Fatalf("Expected sync handler error, got %v", err)
+	}
+	if len(bus.queue) != 0 {
+		t.Error("Event rejected by a sync handler must not reach async handlers")
+	}
+}
+
+func TestBus_Transport(t *testing.T) {
+	transport := &memoryTransport{messages: make(chan *Message, 4)}
+	bus := New(Config{
+		Transport: transport,
+		Decode: func(name string, data []byte) (Event, error) {
+			var e testEvent
+			err := json.Unmarshal(data, &e)
+			return e, err
+		},
+	})
+	broadcastRec := newRecorder()
+	bus.SubscribeBroadcast("test.happened", broadcastRec.handle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	// Событие другого экземпляра приходит только из Transport
+	transport.messages <- &Message{Name: "test.happened", Data: []byte(`{"id":"remote"}`)}
+	if err := bus.Publish(ctx, testEvent{ID: "local"}); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+
+	got := broadcastRec.wait(t, 2)
+	if len(got) != 2 || got[0] != "remote" || got[1] != "local" {
+		t.Errorf("Expected remote and local events once each, got %v", got)
+	}
+}
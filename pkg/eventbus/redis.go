@@ -0,0 +1,284 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisConfig поток Redis Streams, через который экземпляры сервиса обмениваются событиями.
+// MaxLen ограничивает длину потока приблизительно (XADD MAXLEN ~)
+type RedisConfig struct {
+	Addr        string
+	Password    string
+	Stream      string
+	MaxLen      int64
+	Block       time.Duration
+	DialTimeout time.Duration
+}
+
+// RedisStreams Transport поверх Redis Streams: XADD для публикации и XREAD для чтения.
+// Каждый экземпляр читает поток целиком, начиная с момента подключения
+type RedisStreams struct {
+	cfg RedisConfig
+
+	mu  sync.Mutex
+	pub *redisConn
+
+	// lastID последнее прочитанное сообщение, чтобы после переподключения не пропустить события
+	lastID string
+}
+
+func NewRedisStreams(cfg RedisConfig) *RedisStreams {
+	if cfg.Stream == "" {
+		cfg.Stream = "events"
+	}
+	if cfg.MaxLen <= 0 {
+		cfg.MaxLen = 10000
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 5 * time.Second
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	return &RedisStreams{cfg: cfg, lastID: "$"}
+}
+
+func (r *RedisStreams) Publish(ctx context.Context, msg *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pub == nil {
+		conn, err := r.dial(ctx)
+		if err != nil {
+			return err
+		}
+		r.pub = conn
+	}
+
+	_, err := r.pub.do("XADD", r.cfg.Stream, "MAXLEN", "~", strconv.FormatInt(r.cfg.MaxLen, 10), "*",
+		"name", msg.Name, "data", string(msg.Data))
+	if err != nil {
+		// Соединение в неизвестном состоянии: следующая публикация откроет новое
+		r.pub.Close()
+		r.pub = nil
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisStreams) Consume(ctx context.Context, handle func(*Message)) error {
+	conn, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Закрытие соединения прерывает блокирующий XREAD
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	block := strconv.FormatInt(r.cfg.Block.Milliseconds(), 10)
+	for {
+		reply, err := conn.do("XREAD", "COUNT", "100", "BLOCK", block, "STREAMS", r.cfg.Stream, r.lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("redis: %w", err)
+		}
+		if reply == nil {
+			// Истёк BLOCK без новых сообщений
+			continue
+		}
+
+		entries, err := streamEntries(reply)
+		if err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		for _, entry := range entries {
+			r.lastID = entry.id
+			if entry.msg.Name != "" {
+				handle(entry.msg)
+			}
+		}
+	}
+}
+
+func (r *RedisStreams) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: r.cfg.DialTimeout}
+	nc, err := dialer.DialContext(ctx, "tcp", r.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
+	conn := &redisConn{conn: nc, r: bufio.NewReader(nc)}
+	if r.cfg.Password != "" {
+		if _, err := conn.do("AUTH", r.cfg.Password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis: auth: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+type streamEntry struct {
+	id  string
+	msg *Message
+}
+
+// streamEntries разбирает ответ XREAD: [[stream, [[id, [field, value, ...]], ...]]]
+func streamEntries(reply any) ([]streamEntry, error) {
+	streams, ok := reply.([]any)
+	if !ok {
+		return nil, errUnexpectedReply
+	}
+
+	var entries []streamEntry
+	for _, s := range streams {
+		stream, ok := s.([]any)
+		if !ok || len(stream) != 2 {
+			return nil, errUnexpectedReply
+		}
+		items, ok := stream[1].([]any)
+		if !ok {
+			return nil, errUnexpectedReply
+		}
+
+		for _, item := range items {
+			pair, ok := item.([]any)
+			if !ok || len(pair) != 2 {
+				return nil, errUnexpectedReply
+			}
+			id, ok := pair[0].(string)
+			if !ok {
+				return nil, errUnexpectedReply
+			}
+			fields, _ := pair[1].([]any)
+
+			msg := &Message{}
+			for i := 0; i+1 < len(fields); i += 2 {
+				key, _ := fields[i].(string)
+				value, _ := fields[i+1].(string)
+				switch key {
+				case "name":
+					msg.Name = value
+				case "data":
+					msg.Data = []byte(value)
+				}
+			}
+			entries = append(entries, streamEntry{id: id, msg: msg})
+		}
+	}
+	return entries, nil
+}
+
+var errUnexpectedReply = errors.New("unexpected reply")
+
+// redisError ошибка, которую вернул сервер
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisConn соединение с Redis по протоколу RESP2
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *redisConn) Close() error {
+	return c.conn.Close()
+}
+
+// do отправляет команду и читает ответ: string, int64, nil, []any или redisError
+func (c *redisConn) do(args ...string) (any, error) {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, "\r\n"...)
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, "\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, err
+	}
+
+	reply, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	if rerr, ok := reply.(redisError); ok {
+		return nil, rerr
+	}
+	return reply, nil
+}
+
+func (c *redisConn) read() (any, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errUnexpectedReply
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return redisError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, errUnexpectedReply
+}
+
+func (c *redisConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errUnexpectedReply
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,169 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis понимает AUTH, XADD и XREAD в объёме, нужном RedisStreams
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu      sync.Mutex
+	entries [][]string // id, name, data
+	added   chan struct{}
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, password: password, added: make(chan struct{}, 16)}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	authed := f.password == ""
+
+	for {
+		reply, err := rc.read()
+		if err != nil {
+			return
+		}
+		raw, _ := reply.([]any)
+		args := make([]string, len(raw))
+		for i, a := range raw {
+			args[i], _ = a.(string)
+		}
+
+		switch {
+		case args[0] == "AUTH":
+			if args[1] != f.password {
+				conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+				continue
+			}
+			authed = true
+			conn.Write([]byte("+OK\r\n"))
+		case !authed:
+			conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+		case args[0] == "XADD":
+			// XADD stream MAXLEN ~ n * name <name> data <data>
+			f.mu.Lock()
+			id := strconv.Itoa(len(f.entries)+1) + "-0"
+			f.entries = append(f.entries, []string{id, args[7], args[9]})
+			f.mu.Unlock()
+			f.added <- struct{}{}
+			conn.Write([]byte("$" + strconv.Itoa(len(id)) + "\r\n" + id + "\r\n"))
+		case args[0] == "XREAD":
+			conn.Write([]byte(f.xread(args[len(args)-1])))
+		}
+	}
+}
+
+// xread отвечает сообщениями после lastID или пустым ответом, как по истечении BLOCK
+func (f *fakeRedis) xread(lastID string) string {
+	select {
+	case <-f.added:
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var items []string
+	for _, e := range f.entries {
+		if lastID == "$" || seq(e[0]) <= seq(lastID) {
+			continue
+		}
+		items = append(items, "*2\r\n"+bulk(e[0])+"*4\r\n"+bulk("name")+bulk(e[1])+bulk("data")+bulk(e[2]))
+	}
+	if len(items) == 0 {
+		return "*-1\r\n"
+	}
+
+	reply := "*1\r\n*2\r\n" + bulk("events") + "*" + strconv.Itoa(len(items)) + "\r\n"
+	for _, item := range items {
+		reply += item
+	}
+	return reply
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func seq(id string) int {
+	n, _ := strconv.Atoi(id[:len(id)-2])
+	return n
+}
+
+func TestRedisStreams(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	streams := NewRedisStreams(RedisConfig{Addr: server.ln.Addr().String(), Password: "secret", Stream: "events"})
+	// Читатель начинает с начала потока, а не с текущего конца
+	streams.lastID = "0-0"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *Message, 4)
+	done := make(chan error, 1)
+	go func() { done <- streams.Consume(ctx, func(m *Message) { received <- m }) }()
+
+	for _, data := range []string{`{"id":"1"}`, "{\"id\":\"2\",\"note\":\"line\r\nbreak\"}"} {
+		if err := streams.Publish(ctx, &Message{Name: "test.happened", Data: []byte(data)}); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+	}
+
+	for i, want := range []string{`{"id":"1"}`, "{\"id\":\"2\",\"note\":\"line\r\nbreak\"}"} {
+		select {
+		case m := <-received:
+			if m.Name != "test.happened" || string(m.Data) != want {
+				t.Errorf("message %d: got %s %q", i, m.Name, m.Data)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("message %d was not received", i)
+		}
+	}
+	if streams.lastID != "2-0" {
+		t.Errorf("Expected last ID 2-0, got %s", streams.lastID)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Consume did not stop after cancel")
+	}
+}
+
+func TestRedisStreams_AuthError(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	streams := NewRedisStreams(RedisConfig{Addr: server.ln.Addr().String(), Password: "wrong"})
+
+	err := streams.Publish(context.Background(), &Message{Name: "test.happened", Data: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("Expected auth error")
+	}
+}
@@ -0,0 +1,148 @@
+// Package fiscal разбирает строку QR-кода кассового чека по формату ФНС России:
+// t=20240315T1842&s=1234.50&fn=7380440700347812&i=28461&fp=3287654321&n=1
+package fiscal
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxAmount верхняя граница суммы чека: transactions.amount имеет тип DECIMAL(15,2)
+const maxAmount = 1e13
+
+// Operation признак расчёта (параметр n)
+type Operation int
+
+const (
+	// OperationIncome приход: покупатель оплачивает покупку
+	OperationIncome Operation = 1
+	// OperationIncomeRefund возврат прихода: продавец возвращает деньги покупателю
+	OperationIncomeRefund Operation = 2
+	// OperationExpense расход: организация выплачивает деньги клиенту (например, при скупке)
+	OperationExpense Operation = 3
+	// OperationExpenseRefund возврат расхода
+	OperationExpenseRefund Operation = 4
+)
+
+// IsInflow true, если по чеку деньги поступают покупателю (возврат прихода или расход)
+func (o Operation) IsInflow() bool {
+	return o == OperationIncomeRefund || o == OperationExpense
+}
+
+// Receipt реквизиты чека из QR-кода.
+// FN, FD и FP (номер фискального накопителя, документа и фискальный признак)
+// однозначно идентифицируют чек
+type Receipt struct {
+	Time      time.Time
+	Amount    float64
+	FN        string
+	FD        string
+	FP        string
+	Operation Operation
+}
+
+// Error ошибка в параметре строки чека
+type Error struct {
+	Param   string
+	Missing bool
+}
+
+func (e *Error) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("fiscal: missing parameter %q", e.Param)
+	}
+	return fmt.Sprintf("fiscal: invalid parameter %q", e.Param)
+}
+
+// timeLayouts форматы параметра t: с секундами и без
+var timeLayouts = []string{"20060102T150405", "20060102T1504"}
+
+// Parse разбирает строку QR-кода. Время в чеке указано без часового пояса
+// и интерпретируется в loc (местное время точки продаж)
+func Parse(raw string, loc *time.Location) (*Receipt, error) {
+	values, err := url.ParseQuery(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, &Error{Param: "qr"}
+	}
+
+	get := func(name string) (string, error) {
+		value := strings.TrimSpace(values.Get(name))
+		if value == "" {
+			return "", &Error{Param: name, Missing: true}
+		}
+		return value, nil
+	}
+
+	receipt := &Receipt{}
+
+	t, err := get("t")
+	if err != nil {
+		return nil, err
+	}
+	parsed := false
+	for _, layout := range timeLayouts {
+		if receipt.Time, err = time.ParseInLocation(layout, t, loc); err == nil {
+			parsed = true
+			break
+		}
+	}
+	if !parsed {
+		return nil, &Error{Param: "t"}
+	}
+
+	s, err := get("s")
+	if err != nil {
+		return nil, err
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	amount = math.Round(amount*100) / 100
+	// NaN не проходит ни одно сравнение, поэтому проверяется отдельно
+	if err != nil || math.IsNaN(amount) || amount <= 0 || amount >= maxAmount {
+		return nil, &Error{Param: "s"}
+	}
+	receipt.Amount = amount
+
+	for _, field := range []struct {
+		name   string
+		dst    *string
+		maxLen int
+	}{
+		{"fn", &receipt.FN, 16},
+		{"i", &receipt.FD, 10},
+		{"fp", &receipt.FP, 10},
+	} {
+		value, err := get(field.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(value) > field.maxLen || !isDigits(value) {
+			return nil, &Error{Param: field.name}
+		}
+		*field.dst = value
+	}
+
+	n, err := get("n")
+	if err != nil {
+		return nil, err
+	}
+	operation, err := strconv.Atoi(n)
+	if err != nil || operation < int(OperationIncome) || operation > int(OperationExpenseRefund) {
+		return nil, &Error{Param: "n"}
+	}
+	receipt.Operation = Operation(operation)
+
+	return receipt, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,62 @@
+package fiscal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	receipt, err := Parse("t=20240315T1842&s=1234.50&fn=7380440700347812&i=28461&fp=3287654321&n=1", msk)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := &Receipt{
+		Time:      time.Date(2024, 3, 15, 18, 42, 0, 0, msk),
+		Amount:    1234.5,
+		FN:        "7380440700347812",
+		FD:        "28461",
+		FP:        "3287654321",
+		Operation: OperationIncome,
+	}
+	if !receipt.Time.Equal(want.Time) || receipt.Amount != want.Amount || receipt.FN != want.FN ||
+		receipt.FD != want.FD || receipt.FP != want.FP || receipt.Operation != want.Operation {
+		t.Errorf("Expected %+v, got %+v", want, receipt)
+	}
+
+	// Порядок параметров произвольный, время может быть с секундами
+	receipt, err = Parse("n=2&fp=1&i=2&fn=3&s=99&t=20240101T000005", msk)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if receipt.Time.Second() != 5 || !receipt.Operation.IsInflow() {
+		t.Errorf("Unexpected receipt %+v", receipt)
+	}
+
+	tests := []struct {
+		raw     string
+		param   string
+		missing bool
+	}{
+		{"s=1&fn=1&i=1&fp=1&n=1", "t", true},
+		{"t=2024-03-15&s=1&fn=1&i=1&fp=1&n=1", "t", false},
+		{"t=20240315T1842&s=-5&fn=1&i=1&fp=1&n=1", "s", false},
+		{"t=20240315T1842&s=NaN&fn=1&i=1&fp=1&n=1", "s", false},
+		{"t=20240315T1842&s=1e20&fn=1&i=1&fp=1&n=1", "s", false},
+		{"t=20240315T1842&s=Inf&fn=1&i=1&fp=1&n=1", "s", false},
+		{"t=20240315T1842&s=0.001&fn=1&i=1&fp=1&n=1", "s", false},
+		{"t=20240315T1842&s=1&fn=12345678901234567&i=1&fp=1&n=1", "fn", false},
+		{"t=20240315T1842&s=1&fn=1&i=abc&fp=1&n=1", "i", false},
+		{"t=20240315T1842&s=1&fn=1&i=1&n=1", "fp", true},
+		{"t=20240315T1842&s=1&fn=1&i=1&fp=1&n=7", "n", false},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.raw, msk)
+		var ferr *Error
+		if !errors.As(err, &ferr) || ferr.Param != tt.param || ferr.Missing != tt.missing {
+			t.Errorf("%s: expected error for %q (missing=%v), got %v", tt.raw, tt.param, tt.missing, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package merchant нормализует описания банковских операций до названия продавца.
+//
+// Строки вида "PYATEROCHKA 1234 MOSCOW RUS" и "Пятёрочка 5678" приводятся
+// к одному ключу: из описания убираются номера терминалов, маски карт,
+// город и страна, кириллица транслитерируется, а варианты транслитерации
+// сводятся к одному написанию.
+package merchant
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// cardMask номера карт вида 427601******1234, *1234, XXXX1234
+var cardMask = regexp.MustCompile(`(?i)\d{0,6}[*xх•]{2,}\d{2,4}|\*\d{4}\b`)
+
+// noiseWords юридические формы и служебные слова выписки, которые удаляются в любом месте
+var noiseWords = map[string]bool{
+	"ooo": true, "ооо": true, "ip": true, "ип": true, "ao": true, "ао": true,
+	"pao": true, "пао": true, "zao": true, "зао": true, "oao": true, "оао": true,
+	"llc": true, "ltd": true, "www": true,
+	"pokupka": true, "покупка": true, "oplata": true, "оплата": true,
+	"retail": true, "purchase": true, "pos": true, "card": true, "карта": true,
+}
+
+// locationWords города, страны и домены, которые удаляются только в конце описания
+var locationWords = map[string]bool{
+	"rus": true, "ru": true, "rf": true, "russia": true, "рф": true, "россия": true,
+	"com": true, "net": true, "org": true,
+	"g": true, "г": true, "gorod": true, "город": true,
+	"moscow": true, "moskva": true, "msk": true, "москва": true, "мск": true,
+	"sankt": true, "saint": true, "st": true, "peterburg": true, "petersburg": true,
+	"spb": true, "санкт": true, "петербург": true, "спб": true,
+	"ekaterinburg": true, "yekaterinburg": true, "екатеринбург": true,
+	"novosibirsk": true, "новосибирск": true, "kazan": true, "казань": true,
+	"nizhniy": true, "nizhny": true, "novgorod": true, "нижний": true, "новгород": true,
+	"krasnodar": true, "краснодар": true, "samara": true, "самара": true,
+	"rostov": true, "na": true, "donu": true, "ростов": true, "на": true, "дону": true,
+	"ufa": true, "уфа": true, "chelyabinsk": true, "челябинск": true,
+	"omsk": true, "омск": true, "perm": true, "пермь": true, "voronezh": true, "воронеж": true,
+	"krasnoyarsk": true, "красноярск": true, "volgograd": true, "волгоград": true,
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// spellingFolds сводят разные системы транслитерации к одному написанию.
+// Применяются по порядку к уже транслитерированной строке
+var spellingFolds = [][2]string{
+	{"shch", "sh"}, {"sch", "sh"}, {"kh", "h"}, {"ts", "c"}, {"x", "ks"},
+	{"w", "v"}, {"ph", "f"},
+	{"yo", "e"}, {"jo", "e"}, {"j", "y"},
+	{"iy", "y"}, {"ii", "y"}, {"yy", "y"},
+}
+
+// Clean убирает из описания номера, маски карт, юридические формы и город в конце.
+// Регистр и алфавит сохраняются. Возвращает пустую строку, если названия не осталось
+func Clean(description string) string {
+	text := cardMask.ReplaceAllString(description, " ")
+	tokens := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+
+	kept := tokens[:0]
+	for _, token := range tokens {
+		lower := strings.ToLower(token)
+		if noiseWords[lower] || isNumberLike(token) || token == "&" {
+			continue
+		}
+		kept = append(kept, token)
+	}
+
+	for len(kept) > 1 && locationWords[strings.ToLower(kept[len(kept)-1])] {
+		kept = kept[:len(kept)-1]
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// Key возвращает ключ продавца для сравнения описаний: очищенное название
+// в нижнем регистре латиницей без пробелов и знаков
+func Key(description string) string {
+	return fold(Clean(description))
+}
+
+// fold транслитерирует строку и сводит варианты написания
+func fold(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if latin, ok := translit[r]; ok {
+			b.WriteString(latin)
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	key := b.String()
+	for _, f := range spellingFolds {
+		key = strings.ReplaceAll(key, f[0], f[1])
+	}
+	return key
+}
+
+// Name возвращает название для нового продавца. Описания, набранные
+// целиком заглавными буквами, приводятся к виду "Pyaterochka"
+func Name(description string) string {
+	name := Clean(description)
+	if name != strings.ToUpper(name) {
+		return name
+	}
+
+	words := strings.Fields(strings.ToLower(name))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// isNumberLike определяет номера терминалов и магазинов: токены,
+// в которых цифр не меньше половины символов
+func isNumberLike(token string) bool {
+	digits, total := 0, 0
+	for _, r := range token {
+		total++
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	return digits > 0 && digits*2 >= total
+}
@@ -0,0 +1,58 @@
+package merchant
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	groups := [][]string{
+		{"PYATEROCHKA 1234 MOSCOW RUS", "Пятёрочка 5678", "Пятерочка", "PYATYOROCHKA 0012 G MOSKVA", "ПЯТЕРОЧКА*7721 Москва"},
+		{"YANDEX*GO", "Яндекс Go", "yandex.go"},
+		{"MAGNIT MM SEMEJNYJ 123 SANKT-PETERBURG RUS", "Магнит ММ Семейный", "MAGNIT MM SEMEYNYY"},
+		{"OOO VKUSVILL 427601******1234", "ВкусВилл", "ИП ВКУСВИЛЛ"},
+		{"SHOKOLADNICA", "Шоколадница", "SHOKOLADNITSA"},
+		{"OZON.RU", "Ozon"},
+	}
+
+	for _, group := range groups {
+		want := Key(group[0])
+		if want == "" {
+			t.Fatalf("Empty key for %q", group[0])
+		}
+		for _, description := range group[1:] {
+			if got := Key(description); got != want {
+				t.Errorf("Key(%q) = %q, want %q", description, got, want)
+			}
+		}
+	}
+
+	if a, b := Key("PYATEROCHKA"), Key("PEREKRESTOK"); a == b {
+		t.Errorf("Different merchants share key %q", a)
+	}
+	if got := Key("1234 5678 *1234"); got != "" {
+		t.Errorf("Expected empty key, got %q", got)
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := map[string]string{
+		"PYATEROCHKA 1234 MOSCOW RUS": "PYATEROCHKA",
+		"Moscow Burger 12 г Москва":   "Moscow Burger",
+		"H&M 0456 ST PETERSBURG":      "H&M",
+		"Покупка ООО Ромашка *4321":   "Ромашка",
+		"AZS 0123 LUKOIL":             "AZS LUKOIL",
+		"Kazan":                       "Kazan",
+	}
+	for description, want := range tests {
+		if got := Clean(description); got != want {
+			t.Errorf("Clean(%q) = %q, want %q", description, got, want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("MAGNIT MM SEMEYNYY 123 MOSCOW"); got != "Magnit Mm Semeynyy" {
+		t.Errorf("Unexpected name %q", got)
+	}
+	if got := Name("Пятёрочка 5678"); got != "Пятёрочка" {
+		t.Errorf("Unexpected name %q", got)
+	}
+}
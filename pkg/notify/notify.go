@@ -0,0 +1,66 @@
+// Package notify доставляет уведомления пользователям.
+// Реализации: электронная почта (SMTP), HTTPS-вебхук и бот Telegram
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Message уведомление для одного получателя. To — адрес в канале:
+// email, URL вебхука или chat_id Telegram. Data передаётся только вебхуком
+type Message struct {
+	To      string
+	Event   string
+	Subject string
+	Text    string
+	Data    json.RawMessage
+}
+
+// Notifier канал доставки уведомлений
+type Notifier interface {
+	// Send доставляет сообщение. Ошибка, обёрнутая в PermanentError,
+	// означает, что повторная отправка не поможет
+	Send(ctx context.Context, msg *Message) error
+}
+
+// PermanentError ошибка, после которой повторять отправку бессмысленно:
+// неверный адрес, получатель отклонён или заблокировал бота
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent помечает ошибку как неисправимую
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent проверяет, помечена ли ошибка как неисправимая
+func IsPermanent(err error) bool {
+	var perr *PermanentError
+	return errors.As(err, &perr)
+}
+
+// statusError переводит неуспешный ответ HTTP в ошибку. Ответы 4xx, кроме
+// 408 и 429, неисправимы: повтор того же запроса получит тот же ответ
+func statusError(prefix string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err := fmt.Errorf("%s: unexpected status %d: %s", prefix, resp.StatusCode, body)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpStub минимальный SMTP-сервер: принимает одно соединение и отклоняет получателя reject
+type smtpStub struct {
+	listener net.Listener
+	reject   string
+	rcpt     chan string
+	data     chan string
+}
+
+func newSMTPStub(t *testing.T, reject string) *smtpStub {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStub{listener: l, reject: reject, rcpt: make(chan string, 1), data: make(chan string, 1)}
+	t.Cleanup(func() { l.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 stub ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-stub")
+			tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+			if to == s.reject {
+				tp.PrintfLine("550 no such user")
+				continue
+			}
+			s.rcpt <- to
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.data <- strings.Join(lines, "\n")
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	stub := newSMTPStub(t, "")
+	n, err := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "Finance <noreply@example.com>"})
+	if err != nil {
+		t.Fatalf("NewSMTPNotifier: %v", err)
+	}
+
+	err = n.Send(context.Background(), &Message{
+		To:      "user@example.com",
+		Event:   "anomaly.detected",
+		Subject: "Необычная трата",
+		Text:    "5000.00 RUB — в 10 раз больше обычного",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if to := <-stub.rcpt; to != "user@example.com" {
+		t.Errorf("Expected recipient user@example.com, got %q", to)
+	}
+
+	data := <-stub.data
+	header, body, _ := strings.Cut(data, "\n\n")
+	if !strings.Contains(header, "Subject: =?utf-8?q?") || !strings.Contains(header, "X-Event: anomaly.detected") {
+		t.Errorf("Unexpected headers:\n%s", header)
+	}
+	text, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\n", ""))
+	if err != nil || string(text) != "5000.00 RUB — в 10 раз больше обычного" {
+		t.Errorf("Unexpected body %q (%v)", text, err)
+	}
+}
+
+func TestSMTPNotifier_RejectedRecipientIsPermanent(t *testing.T) {
+	stub := newSMTPStub(t, "gone@example.com")
+	n, _ := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "noreply@example.com"})
+
+	err := n.Send(context.Background(), &Message{To: "gone@example.com", Subject: "s", Text: "t"})
+	if err == nil || !IsPermanent(err) {
+		t.Errorf("Expected permanent error, got %v", err)
+	}
+
+	if err := n.Send(context.Background(), &Message{To: "Name <a@example.com>"}); !IsPermanent(err) {
+		t.Errorf("Expected invalid address to be permanent, got %v", err)
+	}
+}
+
+func TestSMTPNotifier_UnreachableServerIsTemporary(t *testing.T) {
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	n, _ := NewSMTPNotifier(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com"})
+	err := n.Send(context.Background(), &Message{To: "user@example.com"})
+	if err == nil || IsPermanent(err) {
+		t.Errorf("Expected temporary error, got %v", err)
+	}
+}
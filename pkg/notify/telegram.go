@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// chatIDPattern числовой chat_id (у групп отрицательный) или @имя публичного канала
+var chatIDPattern = regexp.MustCompile(`^(-?\d{1,20}|@[A-Za-z][A-Za-z0-9_]{4,31})$`)
+
+// TelegramConfig параметры бота. BaseURL меняется на адрес заглушки в тестах
+type TelegramConfig struct {
+	Token      string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// TelegramNotifier отправляет уведомление сообщением бота через Bot API
+type TelegramNotifier struct {
+	cfg    TelegramConfig
+	client *http.Client
+}
+
+// telegramResponse ответ Bot API
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+// NewTelegramNotifier создаёт отправителя сообщений бота
+func NewTelegramNotifier(cfg TelegramConfig) (*TelegramNotifier, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("telegram bot token is required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.telegram.org"
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &TelegramNotifier{cfg: cfg, client: client}, nil
+}
+
+// ValidateChatID проверяет chat_id получателя
+func ValidateChatID(chatID string) error {
+	if !chatIDPattern.MatchString(chatID) {
+		return fmt.Errorf("telegram: invalid chat id %q", chatID)
+	}
+	return nil
+}
+
+func (n *TelegramNotifier) Send(ctx context.Context, msg *Message) error {
+	if err := ValidateChatID(msg.To); err != nil {
+		return Permanent(err)
+	}
+
+	text := msg.Text
+	if msg.Subject != "" {
+		text = msg.Subject + "\n\n" + msg.Text
+	}
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  msg.To,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	endpoint := n.cfg.BaseURL + "/bot" + n.cfg.Token + "/sendMessage"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(fmt.Errorf("telegram: invalid base url"))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// В адресе запроса токен бота, он не должен попасть в журнал
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("telegram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram: unexpected status %d", resp.StatusCode)
+	}
+	if result.OK {
+		return nil
+	}
+
+	err = fmt.Errorf("telegram: %d %s", result.ErrorCode, result.Description)
+	// 400 — чат не найден, 403 — бот заблокирован пользователем
+	if result.ErrorCode == http.StatusBadRequest || result.ErrorCode == http.StatusForbidden {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegramNotifier_Send(t *testing.T) {
+	var path string
+	var request map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request["chat_id"] == "42" {
+			w.Write([]byte(`{"ok":true,"result":{}}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+	}))
+	defer srv.Close()
+
+	n, err := NewTelegramNotifier(TelegramConfig{Token: "123:abc", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewTelegramNotifier: %v", err)
+	}
+
+	if err := n.Send(context.Background(), &Message{To: "42", Subject: "Subject", Text: "Body"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if path != "/bot123:abc/sendMessage" || request["text"] != "Subject\n\nBody" {
+		t.Errorf("Unexpected request %s %v", path, request)
+	}
+
+	err = n.Send(context.Background(), &Message{To: "7", Text: "Body"})
+	if !IsPermanent(err) || !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("Expected permanent error for blocked bot, got %v", err)
+	}
+
+	if err := n.Send(context.Background(), &Message{To: "not a chat"}); !IsPermanent(err) {
+		t.Errorf("Expected invalid chat id to be permanent, got %v", err)
+	}
+}
+
+func TestTelegramNotifier_ErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	n, _ := NewTelegramNotifier(TelegramConfig{Token: "123:secret-token", BaseURL: srv.URL})
+	err := n.Send(context.Background(), &Message{To: "42", Text: "Body"})
+	if err == nil || IsPermanent(err) || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected temporary error without token, got %v", err)
+	}
+}
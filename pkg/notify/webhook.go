@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
+)
+
+// WebhookConfig параметры отправки вебхуков. Если задан Secret,
+// тело запроса подписывается и подпись передаётся в заголовке X-Signature
+type WebhookConfig struct {
+	Secret     string
+	HTTPClient *http.Client
+}
+
+// WebhookNotifier отправляет уведомление POST-запросом с JSON на HTTPS-адрес получателя
+type WebhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// webhookPayload тело запроса вебхука
+type webhookPayload struct {
+	Event   string          `json:"event"`
+	Subject string          `json:"subject"`
+	Text    string          `json:"text"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	SentAt  time.Time       `json:"sent_at"`
+}
+
+// NewWebhookNotifier создаёт отправителя вебхуков
+func NewWebhookNotifier(cfg WebhookConfig) *WebhookNotifier {
+	client := cfg.HTTPClient
+	if client == nil {
+		// Переадресация не выполняется, локальные и частные адреса недоступны
+		client = webhook.NewClient()
+	}
+	return &WebhookNotifier{cfg: cfg, client: client, now: time.Now}
+}
+
+func (n *WebhookNotifier) Send(ctx context.Context, msg *Message) error {
+	if err := webhook.ValidateURL(msg.To); err != nil {
+		return Permanent(err)
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Event:   msg.Event,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		Data:    msg.Data,
+		SentAt:  n.now().UTC(),
+	})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event", msg.Event)
+	if n.cfg.Secret != "" {
+		req.Header.Set("X-Signature", webhook.Sign(n.cfg.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError("webhook", resp)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gibbon/finace-dashboard/pkg/webhook"
+)
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	var body []byte
+	var signature, event string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature")
+		event = r.Header.Get("X-Event")
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(WebhookConfig{Secret: "s3cret", HTTPClient: srv.Client()})
+	err := n.Send(context.Background(), &Message{
+		To:      srv.URL + "/hook",
+		Event:   "anomaly.detected",
+		Subject: "Unusual spending",
+		Text:    "text",
+		Data:    json.RawMessage(`{"id":"1"}`),
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if !webhook.Verify("s3cret", body, signature) {
+		t.Errorf("Signature %q does not match body", signature)
+	}
+	if event != "anomaly.detected" {
+		t.Errorf("Expected X-Event header, got %q", event)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Subject != "Unusual spending" || string(payload.Data) != `{"id":"1"}` {
+		t.Errorf("Unexpected payload %s (%v)", body, err)
+	}
+}
+
+func TestWebhookNotifier_Errors(t *testing.T) {
+	status := http.StatusGone
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(WebhookConfig{HTTPClient: srv.Client()})
+	msg := &Message{To: srv.URL, Event: "anomaly.detected"}
+
+	if err := n.Send(context.Background(), msg); !IsPermanent(err) {
+		t.Errorf("Expected 410 to be permanent, got %v", err)
+	}
+
+	for _, status = range []int{http.StatusTooManyRequests, http.StatusBadGateway} {
+		if err := n.Send(context.Background(), msg); err == nil || IsPermanent(err) {
+			t.Errorf("Expected %d to be retried, got %v", status, err)
+		}
+	}
+
+	if err := n.Send(context.Background(), &Message{To: "http://example.com/hook"}); !IsPermanent(err) {
+		t.Errorf("Expected plain http url to be rejected, got %v", err)
+	}
+}
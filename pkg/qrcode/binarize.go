@@ -0,0 +1,104 @@
+package qrcode
+
+import "image"
+
+// bitmap чёрно-белое изображение: true — тёмный пиксель
+type bitmap struct {
+	width, height int
+	dark          []bool
+}
+
+func (b *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return false
+	}
+	return b.dark[y*b.width+x]
+}
+
+// luminance переводит изображение в оттенки серого (0–255)
+func luminance(img image.Image) ([]uint8, int, int) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	gray := make([]uint8, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			// Прозрачные пиксели считаются белым фоном
+			lum := (299*r + 587*g + 114*b) / 1000
+			lum += 0xffff - a
+			gray[y*width+x] = uint8(min(lum, 0xffff) >> 8)
+		}
+	}
+	return gray, width, height
+}
+
+// globalThreshold бинаризация по порогу Оцу: подходит для сканов и скриншотов
+func globalThreshold(gray []uint8, width, height int) *bitmap {
+	var histogram [256]int
+	for _, v := range gray {
+		histogram[v]++
+	}
+
+	total := len(gray)
+	var sum float64
+	for i, count := range histogram {
+		sum += float64(i * count)
+	}
+
+	var sumBackground, best float64
+	var weightBackground, threshold int
+	for i, count := range histogram {
+		weightBackground += count
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+		sumBackground += float64(i * count)
+		meanBackground := sumBackground / float64(weightBackground)
+		meanForeground := (sum - sumBackground) / float64(weightForeground)
+		between := float64(weightBackground) * float64(weightForeground) * (meanBackground - meanForeground) * (meanBackground - meanForeground)
+		if between > best {
+			best = between
+			threshold = i
+		}
+	}
+
+	bm := &bitmap{width: width, height: height, dark: make([]bool, len(gray))}
+	for i, v := range gray {
+		bm.dark[i] = int(v) <= threshold
+	}
+	return bm
+}
+
+// adaptiveThreshold бинаризация по среднему в окрестности (метод Брэдли):
+// устойчива к неравномерному освещению на фотографиях
+func adaptiveThreshold(gray []uint8, width, height int) *bitmap {
+	// Интегральное изображение для быстрого расчёта среднего в окне
+	integral := make([]int64, (width+1)*(height+1))
+	for y := 0; y < height; y++ {
+		var row int64
+		for x := 0; x < width; x++ {
+			row += int64(gray[y*width+x])
+			integral[(y+1)*(width+1)+x+1] = integral[y*(width+1)+x+1] + row
+		}
+	}
+
+	half := max(width, height) / 16
+	half = max(half, 7)
+
+	bm := &bitmap{width: width, height: height, dark: make([]bool, len(gray))}
+	for y := 0; y < height; y++ {
+		y0, y1 := max(0, y-half), min(height, y+half+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := max(0, x-half), min(width, x+half+1)
+			count := int64((x1 - x0) * (y1 - y0))
+			sum := integral[y1*(width+1)+x1] - integral[y0*(width+1)+x1] - integral[y1*(width+1)+x0] + integral[y0*(width+1)+x0]
+			// Тёмный, если заметно темнее среднего по окну (на 15%)
+			bm.dark[y*width+x] = int64(gray[y*width+x])*count*100 < sum*85
+		}
+	}
+	return bm
+}
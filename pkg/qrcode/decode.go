@@ -0,0 +1,337 @@
+package qrcode
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	errFormat      = errors.New("qrcode: unreadable format information")
+	errUnsupported = errors.New("qrcode: unsupported data mode")
+	errTruncated   = errors.New("qrcode: truncated data")
+)
+
+// functionModules отмечает служебные модули: поисковые и выравнивающие узоры,
+// синхронизацию, информацию о формате и версии
+func functionModules(version int) [][]bool {
+	dim := dimension(version)
+	mask := make([][]bool, dim)
+	for i := range mask {
+		mask[i] = make([]bool, dim)
+	}
+	fill := func(row, col, height, width int) {
+		for r := row; r < row+height; r++ {
+			for c := col; c < col+width; c++ {
+				mask[r][c] = true
+			}
+		}
+	}
+
+	// Поисковые узоры с разделителями и областями формата
+	fill(0, 0, 9, 9)
+	fill(0, dim-8, 9, 8)
+	fill(dim-8, 0, 8, 9)
+
+	// Линии синхронизации
+	fill(6, 0, 1, dim)
+	fill(0, 6, dim, 1)
+
+	positions := alignmentPositions[version]
+	last := len(positions) - 1
+	for i, row := range positions {
+		for j, col := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			fill(row-2, col-2, 5, 5)
+		}
+	}
+
+	// Информация о версии
+	if version >= 7 {
+		fill(0, dim-11, 6, 3)
+		fill(dim-11, 0, 3, 6)
+	}
+
+	return mask
+}
+
+// formatCells координаты (строка, столбец) двух копий информации о формате,
+// старший бит первым
+func formatCells(dim int) ([15][2]int, [15][2]int) {
+	var first, second [15][2]int
+	i := 0
+	for col := 0; col <= 5; col++ {
+		first[i] = [2]int{8, col}
+		i++
+	}
+	first[6] = [2]int{8, 7}
+	first[7] = [2]int{8, 8}
+	first[8] = [2]int{7, 8}
+	i = 9
+	for row := 5; row >= 0; row-- {
+		first[i] = [2]int{row, 8}
+		i++
+	}
+
+	i = 0
+	for row := dim - 1; row >= dim-7; row-- {
+		second[i] = [2]int{row, 8}
+		i++
+	}
+	for col := dim - 8; col < dim; col++ {
+		second[i] = [2]int{8, col}
+		i++
+	}
+	return first, second
+}
+
+// readFormat находит ближайшую допустимую строку формата (исправляет до 3 ошибок)
+func readFormat(grid [][]bool) (ecLevel, int, error) {
+	first, second := formatCells(len(grid))
+	read := func(cells [15][2]int) int {
+		value := 0
+		for _, cell := range cells {
+			value <<= 1
+			if grid[cell[0]][cell[1]] {
+				value |= 1
+			}
+		}
+		return value
+	}
+	copies := []int{read(first), read(second)}
+
+	bestDistance, bestLevel, bestMask := 16, ecLevelL, 0
+	for level := ecLevelL; level <= ecLevelH; level++ {
+		for mask := 0; mask < 8; mask++ {
+			code := formatBits(level, mask)
+			for _, value := range copies {
+				if d := bits.OnesCount(uint(code ^ value)); d < bestDistance {
+					bestDistance, bestLevel, bestMask = d, level, mask
+				}
+			}
+		}
+	}
+	if bestDistance > 3 {
+		return 0, 0, errFormat
+	}
+	return bestLevel, bestMask, nil
+}
+
+// readCodewords снимает маску и считывает кодовые слова зигзагом снизу вверх
+func readCodewords(grid [][]bool, version, mask int) []byte {
+	dim := len(grid)
+	function := functionModules(version)
+	codewords := make([]byte, numRawDataModules(version)/8)
+
+	bit := 0
+	for right := dim - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < dim; vert++ {
+			row := vert
+			if upward {
+				row = dim - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				col := right - j
+				if function[row][col] || bit >= len(codewords)*8 {
+					continue
+				}
+				if grid[row][col] != maskBit(mask, row, col) {
+					codewords[bit/8] |= 0x80 >> (bit % 8)
+				}
+				bit++
+			}
+		}
+	}
+	return codewords
+}
+
+// correctData разделяет кодовые слова по блокам, исправляет ошибки
+// и возвращает кодовые слова данных
+func correctData(codewords []byte, version int, level ecLevel) ([]byte, error) {
+	numBlocks := numErrorCorrectionBlocks[version][level]
+	eccLen := eccCodewordsPerBlock[version][level]
+	numShort := numBlocks - len(codewords)%numBlocks
+	shortLen := len(codewords) / numBlocks
+	shortData := shortLen - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	for i := range blocks {
+		length := shortLen
+		if i >= numShort {
+			length++
+		}
+		blocks[i] = make([]byte, length)
+	}
+
+	// Данные чередуются по блокам; у длинных блоков на одно слово данных больше
+	pos := 0
+	for i := 0; i <= shortData; i++ {
+		for j := range blocks {
+			if i == shortData && j < numShort {
+				continue
+			}
+			blocks[j][i] = codewords[pos]
+			pos++
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j][len(blocks[j])-eccLen+i] = codewords[pos]
+			pos++
+		}
+	}
+
+	var data []byte
+	for _, block := range blocks {
+		if err := rsCorrect(block, eccLen); err != nil {
+			return nil, err
+		}
+		data = append(data, block[:len(block)-eccLen]...)
+	}
+	return data, nil
+}
+
+// bitReader последовательно читает биты из кодовых слов
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) remaining() int {
+	return len(r.data)*8 - r.pos
+}
+
+func (r *bitReader) read(n int) (int, error) {
+	if n > r.remaining() {
+		return 0, errTruncated
+	}
+	value := 0
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if r.data[r.pos/8]&(0x80>>(r.pos%8)) != 0 {
+			value |= 1
+		}
+		r.pos++
+	}
+	return value, nil
+}
+
+const alphanumericChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// decodeSegments разбирает поток данных: числовой, буквенно-цифровой и байтовый режимы, ECI
+func decodeSegments(data []byte, version int) (string, error) {
+	r := &bitReader{data: data}
+	var out strings.Builder
+	var raw []byte
+
+	countBits := func(small, large int) int {
+		if version <= 9 {
+			return small
+		}
+		return large
+	}
+
+	for r.remaining() >= 4 {
+		mode, _ := r.read(4)
+		switch mode {
+		case 0x0:
+			return finishText(&out, raw), nil
+
+		case 0x1: // Числовой режим: по 3 цифры в 10 битах
+			count, err := r.read(countBits(10, 12))
+			if err != nil {
+				return "", err
+			}
+			for count > 0 {
+				n := min(count, 3)
+				value, err := r.read([]int{0, 4, 7, 10}[n])
+				if err != nil {
+					return "", err
+				}
+				digits := []byte{'0', '0', '0'}
+				for i := n - 1; i >= 0; i-- {
+					digits[i] = byte('0' + value%10)
+					value /= 10
+				}
+				raw = append(raw, digits[:n]...)
+				count -= n
+			}
+
+		case 0x2: // Буквенно-цифровой режим: по 2 символа в 11 битах
+			count, err := r.read(countBits(9, 11))
+			if err != nil {
+				return "", err
+			}
+			for count > 0 {
+				if count == 1 {
+					value, err := r.read(6)
+					if err != nil || value >= len(alphanumericChars) {
+						return "", errTruncated
+					}
+					raw = append(raw, alphanumericChars[value])
+					break
+				}
+				value, err := r.read(11)
+				if err != nil || value/45 >= len(alphanumericChars) {
+					return "", errTruncated
+				}
+				raw = append(raw, alphanumericChars[value/45], alphanumericChars[value%45])
+				count -= 2
+			}
+
+		case 0x4: // Байтовый режим
+			count, err := r.read(countBits(8, 16))
+			if err != nil {
+				return "", err
+			}
+			for i := 0; i < count; i++ {
+				b, err := r.read(8)
+				if err != nil {
+					return "", err
+				}
+				raw = append(raw, byte(b))
+			}
+
+		case 0x7: // ECI: кодировка указывается явно, текст ниже трактуется как UTF-8 при возможности
+			first, err := r.read(8)
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case first&0x80 == 0:
+			case first&0xc0 == 0x80:
+				_, err = r.read(8)
+			default:
+				_, err = r.read(16)
+			}
+			if err != nil {
+				return "", err
+			}
+
+		default:
+			return "", errUnsupported
+		}
+	}
+
+	return finishText(&out, raw), nil
+}
+
+// finishText переводит байты в строку. Без ECI кодировка по стандарту — ISO-8859-1,
+// но на практике генераторы пишут UTF-8, поэтому он проверяется первым
+func finishText(out *strings.Builder, raw []byte) string {
+	if utf8.Valid(raw) {
+		out.Write(raw)
+		return out.String()
+	}
+	for _, b := range raw {
+		out.WriteRune(rune(b))
+	}
+	return out.String()
+}
@@ -0,0 +1,427 @@
+package qrcode
+
+import (
+	"math"
+	"sort"
+)
+
+// point координаты на изображении
+type point struct {
+	x, y float64
+}
+
+func distance(a, b point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+// finderPattern кандидат в поисковый узор (квадрат 7x7 в углу символа)
+type finderPattern struct {
+	center     point
+	moduleSize float64
+	count      int
+}
+
+// finderRatio проверяет пропорции 1:1:3:1:1 для серий тёмный-светлый-тёмный-светлый-тёмный
+func finderRatio(counts [5]int) bool {
+	total := 0
+	for _, c := range counts {
+		if c == 0 {
+			return false
+		}
+		total += c
+	}
+	if total < 7 {
+		return false
+	}
+	module := float64(total) / 7
+	variance := module / 2
+	return math.Abs(module-float64(counts[0])) < variance &&
+		math.Abs(module-float64(counts[1])) < variance &&
+		math.Abs(3*module-float64(counts[2])) < 3*variance &&
+		math.Abs(module-float64(counts[3])) < variance &&
+		math.Abs(module-float64(counts[4])) < variance
+}
+
+// findFinderPatterns ищет центры поисковых узоров построчным сканированием
+// с перекрёстной проверкой по вертикали и горизонтали
+func findFinderPatterns(bm *bitmap) []*finderPattern {
+	var candidates []*finderPattern
+
+	for y := 0; y < bm.height; y++ {
+		var counts [5]int
+		state := 0
+		for x := 0; x <= bm.width; x++ {
+			dark := x < bm.width && bm.at(x, y)
+			if dark {
+				if state%2 == 1 {
+					state++
+				}
+				counts[state]++
+				continue
+			}
+
+			if state%2 == 0 {
+				if state < 4 {
+					if counts[state] > 0 {
+						state++
+						counts[state]++
+					}
+					continue
+				}
+
+				// Завершена пятая серия: проверяем кандидата
+				if finderRatio(counts) {
+					centerX := float64(x-counts[4]-counts[3]) - float64(counts[2])/2
+					if p := confirmFinder(bm, centerX, float64(y), counts); p != nil {
+						candidates = mergeFinder(candidates, p)
+					}
+				}
+				// Сдвигаем окно на две серии
+				counts = [5]int{counts[2], counts[3], counts[4], 1, 0}
+				state = 3
+				continue
+			}
+
+			counts[state]++
+		}
+	}
+
+	return candidates
+}
+
+// confirmFinder уточняет центр кандидата по вертикали и горизонтали
+func confirmFinder(bm *bitmap, centerX, centerY float64, horizontal [5]int) *finderPattern {
+	total := 0
+	for _, c := range horizontal {
+		total += c
+	}
+
+	centerY, vTotal, ok := crossCheck(bm, int(centerX), int(centerY), 0, 1, total)
+	if !ok {
+		return nil
+	}
+	centerX, hTotal, ok := crossCheck(bm, int(centerX), int(centerY), 1, 0, total)
+	if !ok {
+		return nil
+	}
+
+	return &finderPattern{
+		center:     point{centerX, centerY},
+		moduleSize: float64(vTotal+hTotal) / 14,
+		count:      1,
+	}
+}
+
+// crossCheck проверяет пропорции узора вдоль направления (dx, dy) через точку (x, y)
+// и возвращает уточнённую координату центра вдоль этого направления
+func crossCheck(bm *bitmap, x, y, dx, dy, expectedTotal int) (float64, int, bool) {
+	if !bm.at(x, y) {
+		return 0, 0, false
+	}
+
+	var counts [5]int
+	limit := expectedTotal * 2
+
+	// Назад от центра: центральная серия, светлая, внешняя тёмная
+	cx, cy := x, y
+	for step := 0; step < limit && bm.at(cx, cy); step++ {
+		counts[2]++
+		cx, cy = cx-dx, cy-dy
+	}
+	for step := 0; step < limit && inside(bm, cx, cy) && !bm.at(cx, cy); step++ {
+		counts[1]++
+		cx, cy = cx-dx, cy-dy
+	}
+	for step := 0; step < limit && bm.at(cx, cy); step++ {
+		counts[0]++
+		cx, cy = cx-dx, cy-dy
+	}
+	start := cx*dx + cy*dy + 1
+
+	// Вперёд от центра
+	cx, cy = x+dx, y+dy
+	for step := 0; step < limit && bm.at(cx, cy); step++ {
+		counts[2]++
+		cx, cy = cx+dx, cy+dy
+	}
+	for step := 0; step < limit && inside(bm, cx, cy) && !bm.at(cx, cy); step++ {
+		counts[3]++
+		cx, cy = cx+dx, cy+dy
+	}
+	for step := 0; step < limit && bm.at(cx, cy); step++ {
+		counts[4]++
+		cx, cy = cx+dx, cy+dy
+	}
+
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if !finderRatio(counts) || 5*abs(total-expectedTotal) >= 2*expectedTotal {
+		return 0, 0, false
+	}
+
+	center := float64(start+counts[0]+counts[1]) + float64(counts[2])/2
+	return center, total, true
+}
+
+func inside(bm *bitmap, x, y int) bool {
+	return x >= 0 && y >= 0 && x < bm.width && y < bm.height
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// mergeFinder объединяет кандидата с уже найденным узором в той же точке
+func mergeFinder(candidates []*finderPattern, p *finderPattern) []*finderPattern {
+	for _, c := range candidates {
+		if distance(c.center, p.center) <= c.moduleSize*2 &&
+			math.Abs(c.moduleSize-p.moduleSize) <= c.moduleSize/2 {
+			n := float64(c.count)
+			c.center = point{(c.center.x*n + p.center.x) / (n + 1), (c.center.y*n + p.center.y) / (n + 1)}
+			c.moduleSize = (c.moduleSize*n + p.moduleSize) / (n + 1)
+			c.count++
+			return candidates
+		}
+	}
+	return append(candidates, p)
+}
+
+// finderTriple тройка узоров, упорядоченная как верхний левый, верхний правый, нижний левый
+type finderTriple struct {
+	topLeft, topRight, bottomLeft *finderPattern
+	score                         float64
+}
+
+// maxFinderCandidates ограничивает перебор троек на зашумлённых изображениях
+const maxFinderCandidates = 12
+
+// selectTriples перебирает тройки кандидатов, образующие прямоугольный равнобедренный
+// треугольник, от наиболее правдоподобной к наименее
+func selectTriples(candidates []*finderPattern) []finderTriple {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].count > candidates[j].count })
+	if len(candidates) > maxFinderCandidates {
+		candidates = candidates[:maxFinderCandidates]
+	}
+
+	var triples []finderTriple
+	for i := 0; i < len(candidates); i++ {
+		for j := i + 1; j < len(candidates); j++ {
+			for k := j + 1; k < len(candidates); k++ {
+				if t, ok := orderTriple(candidates[i], candidates[j], candidates[k]); ok {
+					triples = append(triples, t)
+				}
+			}
+		}
+	}
+
+	sort.Slice(triples, func(i, j int) bool { return triples[i].score < triples[j].score })
+	return triples
+}
+
+func orderTriple(a, b, c *finderPattern) (finderTriple, bool) {
+	sizes := []float64{a.moduleSize, b.moduleSize, c.moduleSize}
+	sort.Float64s(sizes)
+	if sizes[2] > sizes[0]*1.5 {
+		return finderTriple{}, false
+	}
+
+	// Вершина прямого угла противолежит самой длинной стороне
+	ab, bc, ac := distance(a.center, b.center), distance(b.center, c.center), distance(a.center, c.center)
+	var corner, p, q *finderPattern
+	var leg1, leg2, hypotenuse float64
+	switch {
+	case bc >= ab && bc >= ac:
+		corner, p, q, leg1, leg2, hypotenuse = a, b, c, ab, ac, bc
+	case ac >= ab && ac >= bc:
+		corner, p, q, leg1, leg2, hypotenuse = b, a, c, ab, bc, ac
+	default:
+		corner, p, q, leg1, leg2, hypotenuse = c, a, b, ac, bc, ab
+	}
+
+	// Катеты почти равны, гипотенуза близка к катет·√2, а между узорами помещается символ
+	if leg1 < 0.75*leg2 || leg2 < 0.75*leg1 || leg1 < 7*sizes[0] {
+		return finderTriple{}, false
+	}
+	expected := math.Sqrt(leg1*leg1 + leg2*leg2)
+	if math.Abs(hypotenuse-expected) > 0.15*expected {
+		return finderTriple{}, false
+	}
+
+	// В координатах изображения (ось y вниз) верхний правый узор лежит по часовой стрелке
+	cross := (p.center.x-corner.center.x)*(q.center.y-corner.center.y) -
+		(p.center.y-corner.center.y)*(q.center.x-corner.center.x)
+	if cross < 0 {
+		p, q = q, p
+	}
+
+	return finderTriple{
+		topLeft:    corner,
+		topRight:   p,
+		bottomLeft: q,
+		score:      math.Abs(hypotenuse-expected)/expected + math.Abs(leg1-leg2)/max(leg1, leg2),
+	}, true
+}
+
+// transform проективное преобразование координат модулей в координаты изображения
+type transform struct {
+	h [8]float64
+}
+
+func (t *transform) apply(x, y float64) point {
+	den := t.h[6]*x + t.h[7]*y + 1
+	return point{
+		(t.h[0]*x + t.h[1]*y + t.h[2]) / den,
+		(t.h[3]*x + t.h[4]*y + t.h[5]) / den,
+	}
+}
+
+// newTransform находит преобразование, переводящее четыре точки src в dst
+func newTransform(src, dst [4]point) (*transform, bool) {
+	var m [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y, u, v := src[i].x, src[i].y, dst[i].x, dst[i].y
+		m[2*i] = [9]float64{x, y, 1, 0, 0, 0, -x * u, -y * u, u}
+		m[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -x * v, -y * v, v}
+	}
+
+	// Метод Гаусса с выбором главного элемента
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			factor := m[row][col] / m[col][col]
+			for k := col; k < 9; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+		}
+	}
+
+	t := &transform{}
+	for i := 0; i < 8; i++ {
+		t.h[i] = m[i][8] / m[i][i]
+	}
+	return t, true
+}
+
+// candidateDimensions оценивает размер символа по расстоянию между поисковыми узорами.
+// Допустимые размеры имеют вид 4k+1, при неоднозначности пробуются оба соседних
+func candidateDimensions(t finderTriple) []int {
+	module := (t.topLeft.moduleSize + t.topRight.moduleSize + t.bottomLeft.moduleSize) / 3
+	estimate := (distance(t.topLeft.center, t.topRight.center)+distance(t.topLeft.center, t.bottomLeft.center))/(2*module) + 7
+
+	base := int(math.Round(estimate))
+	var dims []int
+	switch base & 3 {
+	case 0:
+		dims = []int{base + 1, base - 3}
+	case 1:
+		dims = []int{base, base + 4, base - 4}
+	case 2:
+		dims = []int{base - 1, base + 3}
+	case 3:
+		dims = []int{base - 2, base + 2}
+	}
+
+	var valid []int
+	for _, d := range dims {
+		if v := (d - 17) / 4; d >= 21 && v <= maxVersion {
+			valid = append(valid, d)
+		}
+	}
+	return valid
+}
+
+// locateTransform строит преобразование для символа размера dim. Для версий от 2
+// четвёртой точкой служит нижний правый выравнивающий узор, что компенсирует перспективу
+func locateTransform(bm *bitmap, t finderTriple, dim int) (*transform, bool) {
+	d := float64(dim)
+	src := [4]point{{3.5, 3.5}, {d - 3.5, 3.5}, {3.5, d - 3.5}, {d - 3.5, d - 3.5}}
+	tl, tr, bl := t.topLeft.center, t.topRight.center, t.bottomLeft.center
+	dst := [4]point{tl, tr, bl, {tr.x + bl.x - tl.x, tr.y + bl.y - tl.y}}
+
+	affine, ok := newTransform(src, dst)
+	if !ok || dim == 21 {
+		return affine, ok
+	}
+
+	if align, found := findAlignment(bm, affine, d-6.5); found {
+		src[3] = point{d - 6.5, d - 6.5}
+		dst[3] = align
+		if refined, ok := newTransform(src, dst); ok {
+			return refined, true
+		}
+	}
+	return affine, true
+}
+
+// findAlignment ищет выравнивающий узор (5x5: тёмная рамка, светлое кольцо, тёмный центр)
+// вблизи ожидаемой позиции модуля (c, c) и возвращает центр найденного узора
+func findAlignment(bm *bitmap, affine *transform, c float64) (point, bool) {
+	expected := affine.apply(c, c)
+	origin := affine.apply(c-1, c-1)
+	right := affine.apply(c, c-1)
+	down := affine.apply(c-1, c)
+	ux, uy := right.x-origin.x, right.y-origin.y
+	vx, vy := down.x-origin.x, down.y-origin.y
+	module := math.Hypot(ux, uy)
+
+	radius := int(math.Ceil(module * 4))
+	bestScore := 0
+	var sumX, sumY, hits float64
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			cx, cy := expected.x+float64(dx), expected.y+float64(dy)
+			score := 0
+			for my := -2; my <= 2; my++ {
+				for mx := -2; mx <= 2; mx++ {
+					want := max(abs(mx), abs(my)) != 1
+					px := cx + float64(mx)*ux + float64(my)*vx
+					py := cy + float64(mx)*uy + float64(my)*vy
+					if bm.at(int(px), int(py)) == want {
+						score++
+					}
+				}
+			}
+			switch {
+			case score > bestScore:
+				bestScore, sumX, sumY, hits = score, cx, cy, 1
+			case score == bestScore:
+				sumX, sumY, hits = sumX+cx, sumY+cy, hits+1
+			}
+		}
+	}
+
+	if bestScore < 23 {
+		return point{}, false
+	}
+	return point{sumX / hits, sumY / hits}, true
+}
+
+// sampleGrid считывает модули символа по центрам
+func sampleGrid(bm *bitmap, t *transform, dim int) [][]bool {
+	grid := make([][]bool, dim)
+	for row := 0; row < dim; row++ {
+		grid[row] = make([]bool, dim)
+		for col := 0; col < dim; col++ {
+			p := t.apply(float64(col)+0.5, float64(row)+0.5)
+			grid[row][col] = bm.at(int(math.Floor(p.x)), int(math.Floor(p.y)))
+		}
+	}
+	return grid
+}
@@ -0,0 +1,65 @@
+// Package qrcode распознаёт QR-коды на изображениях без внешних зависимостей.
+//
+// Поддерживаются версии 1–10 (до 57x57 модулей), числовой, буквенно-цифровой
+// и байтовый режимы. Символ ищется по трём поисковым узорам; для версий от 2
+// перспектива компенсируется по выравнивающему узору
+package qrcode
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrNotFound на изображении не найден читаемый QR-код
+var ErrNotFound = errors.New("qrcode: no QR code found")
+
+// Decode ищет QR-код на изображении и возвращает его содержимое
+func Decode(img image.Image) (string, error) {
+	gray, width, height := luminance(img)
+	if width == 0 || height == 0 {
+		return "", ErrNotFound
+	}
+
+	// Адаптивный порог лучше для фотографий, глобальный — для сканов с крупными модулями
+	for _, binarize := range []func([]uint8, int, int) *bitmap{adaptiveThreshold, globalThreshold} {
+		bm := binarize(gray, width, height)
+		for _, triple := range selectTriples(findFinderPatterns(bm)) {
+			for _, dim := range candidateDimensions(triple) {
+				if text, err := decodeSymbol(bm, triple, dim); err == nil {
+					return text, nil
+				}
+			}
+		}
+	}
+
+	return "", ErrNotFound
+}
+
+func decodeSymbol(bm *bitmap, triple finderTriple, dim int) (string, error) {
+	t, ok := locateTransform(bm, triple, dim)
+	if !ok {
+		return "", ErrNotFound
+	}
+	grid := sampleGrid(bm, t, dim)
+	return decodeGrid(grid)
+}
+
+// decodeGrid декодирует уже считанную матрицу модулей
+func decodeGrid(grid [][]bool) (string, error) {
+	version := (len(grid) - 17) / 4
+	if version < 1 || version > maxVersion || dimension(version) != len(grid) {
+		return "", ErrNotFound
+	}
+
+	level, mask, err := readFormat(grid)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := correctData(readCodewords(grid, version, mask), version, level)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeSegments(data, version)
+}
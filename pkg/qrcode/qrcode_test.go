@@ -0,0 +1,307 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// rsEncode вычисляет кодовые слова коррекции для блока данных
+func rsEncode(data []byte, eccLen int) []byte {
+	// Порождающий многочлен ∏(x - α^i), коэффициенты от старшей степени
+	generator := []byte{1}
+	for i := 0; i < eccLen; i++ {
+		next := make([]byte, len(generator)+1)
+		for j, g := range generator {
+			next[j] ^= g
+			next[j+1] ^= gfMul(g, gfPow(i))
+		}
+		generator = next
+	}
+
+	remainder := make([]byte, eccLen)
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[eccLen-1] = 0
+		for j := 0; j < eccLen; j++ {
+			remainder[j] ^= gfMul(generator[j+1], factor)
+		}
+	}
+	return remainder
+}
+
+// encode строит матрицу QR-кода в байтовом режиме (только для тестов декодера)
+func encode(t *testing.T, text string, version int, level ecLevel, mask int) [][]bool {
+	t.Helper()
+	dim := dimension(version)
+	numBlocks := numErrorCorrectionBlocks[version][level]
+	eccLen := eccCodewordsPerBlock[version][level]
+	rawCodewords := numRawDataModules(version) / 8
+	dataLen := rawCodewords - eccLen*numBlocks
+
+	// Поток бит: режим, длина, байты, терминатор, выравнивание и заполнители
+	var bitsBuf []bool
+	put := func(value, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bitsBuf = append(bitsBuf, value>>i&1 == 1)
+		}
+	}
+	put(0x4, 4)
+	if version <= 9 {
+		put(len(text), 8)
+	} else {
+		put(len(text), 16)
+	}
+	for i := 0; i < len(text); i++ {
+		put(int(text[i]), 8)
+	}
+	if len(bitsBuf) > dataLen*8 {
+		t.Fatalf("text does not fit version %d", version)
+	}
+	put(0, min(4, dataLen*8-len(bitsBuf)))
+	for len(bitsBuf)%8 != 0 {
+		bitsBuf = append(bitsBuf, false)
+	}
+	data := make([]byte, dataLen)
+	for i := range data {
+		if i*8 < len(bitsBuf) {
+			for j := 0; j < 8; j++ {
+				if bitsBuf[i*8+j] {
+					data[i] |= 0x80 >> j
+				}
+			}
+		} else if (i-len(bitsBuf)/8)%2 == 0 {
+			data[i] = 0xec
+		} else {
+			data[i] = 0x11
+		}
+	}
+
+	// Блоки и чередование
+	numShort := numBlocks - rawCodewords%numBlocks
+	shortLen := rawCodewords / numBlocks
+	var blocks [][]byte
+	pos := 0
+	for i := 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte{}, data[pos:pos+n]...)
+		pos += n
+		ecc := rsEncode(block, eccLen)
+		if i < numShort {
+			block = append(block, 0)
+		}
+		blocks = append(blocks, append(block, ecc...))
+	}
+	var codewords []byte
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				codewords = append(codewords, block[i])
+			}
+		}
+	}
+
+	grid := make([][]bool, dim)
+	for i := range grid {
+		grid[i] = make([]bool, dim)
+	}
+	finder := func(row, col int) {
+		for r := -1; r <= 7; r++ {
+			for c := -1; c <= 7; c++ {
+				if row+r < 0 || col+c < 0 || row+r >= dim || col+c >= dim {
+					continue
+				}
+				d := max(abs(r-3), abs(c-3))
+				grid[row+r][col+c] = d != 2 && d != 4
+			}
+		}
+	}
+	finder(0, 0)
+	finder(0, dim-7)
+	finder(dim-7, 0)
+	for i := 8; i < dim-8; i++ {
+		grid[6][i] = i%2 == 0
+		grid[i][6] = i%2 == 0
+	}
+	positions := alignmentPositions[version]
+	last := len(positions) - 1
+	for i, row := range positions {
+		for j, col := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for r := -2; r <= 2; r++ {
+				for c := -2; c <= 2; c++ {
+					grid[row+r][col+c] = max(abs(r), abs(c)) != 1
+				}
+			}
+		}
+	}
+
+	// Данные с маской
+	function := functionModules(version)
+	bit := 0
+	for right := dim - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < dim; vert++ {
+			row := vert
+			if upward {
+				row = dim - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				col := right - j
+				if function[row][col] {
+					continue
+				}
+				dark := false
+				if bit < len(codewords)*8 {
+					dark = codewords[bit/8]&(0x80>>(bit%8)) != 0
+					bit++
+				}
+				grid[row][col] = dark != maskBit(mask, row, col)
+			}
+		}
+	}
+
+	format := formatBits(level, mask)
+	first, second := formatCells(dim)
+	for i := 0; i < 15; i++ {
+		on := format>>(14-i)&1 == 1
+		grid[first[i][0]][first[i][1]] = on
+		grid[second[i][0]][second[i][1]] = on
+	}
+	grid[dim-8][8] = true
+
+	return grid
+}
+
+// render рисует матрицу с белым полем; warp задаёт искажение координат
+func render(grid [][]bool, scale, quiet int, warp func(x, y float64) (float64, float64)) image.Image {
+	dim := len(grid)
+	size := (dim + 2*quiet) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			sx, sy := float64(x), float64(y)
+			if warp != nil {
+				sx, sy = warp(sx, sy)
+			}
+			col := int(math.Floor(sx/float64(scale))) - quiet
+			row := int(math.Floor(sy/float64(scale))) - quiet
+			v := uint8(235)
+			if row >= 0 && col >= 0 && row < dim && col < dim && grid[row][col] {
+				v = 30
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+// perspective искажает квадрат size x size в трапецию с сужением сверху, как на фото под углом
+func perspective(t *testing.T, size float64) func(x, y float64) (float64, float64) {
+	toSource, ok := newTransform(
+		[4]point{{size * 0.1, size * 0.05}, {size * 0.9, 0}, {0, size}, {size, size * 0.95}},
+		[4]point{{0, 0}, {size, 0}, {0, size}, {size, size}},
+	)
+	if !ok {
+		t.Fatal("degenerate transform")
+	}
+	return func(x, y float64) (float64, float64) {
+		p := toSource.apply(x, y)
+		return p.x, p.y
+	}
+}
+
+func TestReedSolomon(t *testing.T) {
+	// Пример "HELLO WORLD", версия 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	ecc := rsEncode(data, 10)
+	if !bytes.Equal(ecc, want) {
+		t.Fatalf("Expected ECC %v, got %v", want, ecc)
+	}
+
+	block := append(append([]byte{}, data...), ecc...)
+	for _, i := range []int{0, 5, 11, 20, 25} {
+		block[i] ^= 0x5a
+	}
+	if err := rsCorrect(block, 10); err != nil {
+		t.Fatalf("rsCorrect: %v", err)
+	}
+	if !bytes.Equal(block[:16], data) {
+		t.Errorf("Expected corrected data %v, got %v", data, block[:16])
+	}
+
+	for _, i := range []int{1, 2, 3, 4, 6, 7} {
+		block[i] ^= 0x33
+	}
+	if err := rsCorrect(block, 10); err == nil && bytes.Equal(block[:16], data) {
+		t.Error("Six errors exceed the capacity of 10 ECC codewords")
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	if got := formatBits(ecLevelM, 0); got != 0b101010000010010 {
+		t.Errorf("M/0: got %015b", got)
+	}
+	if got := formatBits(ecLevelL, 4); got != 0b110011000101111 {
+		t.Errorf("L/4: got %015b", got)
+	}
+}
+
+const fiscalText = "t=20240315T1842&s=1234.50&fn=7380440700347812&i=28461&fp=3287654321&n=1"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		version int
+		level   ecLevel
+		mask    int
+		scale   int
+		warp    func(x, y float64) (float64, float64)
+	}{
+		{"version 1", "HELLO", 1, ecLevelH, 2, 4, nil},
+		{"fiscal receipt", fiscalText, 5, ecLevelM, 3, 4, nil},
+		{"small modules", fiscalText, 6, ecLevelQ, 5, 3, nil},
+		{"multiple blocks", fiscalText, 10, ecLevelH, 7, 3, nil},
+		{"rotated 90°", fiscalText, 5, ecLevelM, 0, 4, func(x, y float64) (float64, float64) {
+			return y, 4*45 - 1 - x
+		}},
+		{"perspective", fiscalText, 5, ecLevelM, 6, 6, perspective(t, 270)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := render(encode(t, tt.text, tt.version, tt.level, tt.mask), tt.scale, 4, tt.warp)
+			got, err := Decode(img)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if got != tt.text {
+				t.Errorf("Expected %q, got %q", tt.text, got)
+			}
+		})
+	}
+}
+
+func TestDecode_NoCode(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 200, 200))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 7 % 256)
+	}
+	if _, err := Decode(img); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
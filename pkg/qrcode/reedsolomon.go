@@ -0,0 +1,157 @@
+package qrcode
+
+import "errors"
+
+// errTooManyErrors блок повреждён сильнее, чем позволяет исправить код
+var errTooManyErrors = errors.New("qrcode: too many errors")
+
+// Арифметика поля GF(256) с порождающим многочленом x^8 + x^4 + x^3 + x^2 + 1
+var gfExp, gfLog = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// gfPow возвращает α^n
+func gfPow(n int) byte {
+	n %= 255
+	if n < 0 {
+		n += 255
+	}
+	return gfExp[n]
+}
+
+// rsCorrect исправляет ошибки в блоке на месте. Последние eccLen байт — кодовые слова коррекции,
+// block[0] — коэффициент при старшей степени. Корни порождающего многочлена α^0..α^(eccLen-1)
+func rsCorrect(block []byte, eccLen int) error {
+	n := len(block)
+
+	// Синдромы S_j = r(α^j)
+	syndromes := make([]byte, eccLen)
+	clean := true
+	for j := range syndromes {
+		var s byte
+		x := gfPow(j)
+		for _, c := range block {
+			s = gfMul(s, x) ^ c
+		}
+		syndromes[j] = s
+		if s != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return nil
+	}
+
+	// Многочлен локаторов ошибок (Берлекэмп — Мэсси), коэффициенты по возрастанию степени
+	locator := []byte{1}
+	prev := []byte{1}
+	errCount, shift := 0, 1
+	prevDiscrepancy := byte(1)
+	for i := 0; i < eccLen; i++ {
+		discrepancy := syndromes[i]
+		for k := 1; k <= errCount && k < len(locator); k++ {
+			discrepancy ^= gfMul(locator[k], syndromes[i-k])
+		}
+		if discrepancy == 0 {
+			shift++
+			continue
+		}
+
+		coef := gfDiv(discrepancy, prevDiscrepancy)
+		next := make([]byte, max(len(locator), len(prev)+shift))
+		copy(next, locator)
+		for k, p := range prev {
+			next[k+shift] ^= gfMul(coef, p)
+		}
+
+		if 2*errCount <= i {
+			prev = locator
+			errCount = i + 1 - errCount
+			prevDiscrepancy = discrepancy
+			shift = 1
+		} else {
+			shift++
+		}
+		locator = next
+	}
+	if 2*errCount > eccLen {
+		return errTooManyErrors
+	}
+
+	// Поиск корней перебором (Ченя): ошибка в позиции i, если Λ(α^-(n-1-i)) = 0
+	var positions []int
+	for i := 0; i < n; i++ {
+		if evalAscending(locator, gfPow(-(n-1-i))) == 0 {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) != errCount {
+		return errTooManyErrors
+	}
+
+	// Ω(x) = S(x)·Λ(x) mod x^eccLen
+	omega := make([]byte, eccLen)
+	for i, s := range syndromes {
+		for k, l := range locator {
+			if i+k < eccLen {
+				omega[i+k] ^= gfMul(s, l)
+			}
+		}
+	}
+
+	// Формальная производная Λ: в характеристике 2 остаются только нечётные степени
+	derivative := make([]byte, len(locator))
+	for k := 1; k < len(locator); k += 2 {
+		derivative[k-1] = locator[k]
+	}
+
+	// Алгоритм Форни: e = X·Ω(X^-1) / Λ'(X^-1)
+	for _, pos := range positions {
+		x := gfPow(n - 1 - pos)
+		xInv := gfPow(-(n - 1 - pos))
+		denominator := evalAscending(derivative, xInv)
+		if denominator == 0 {
+			return errTooManyErrors
+		}
+		block[pos] ^= gfMul(x, gfDiv(evalAscending(omega, xInv), denominator))
+	}
+
+	return nil
+}
+
+// evalAscending вычисляет многочлен с коэффициентами по возрастанию степени
+func evalAscending(poly []byte, x byte) byte {
+	var result byte
+	for i := len(poly) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ poly[i]
+	}
+	return result
+}
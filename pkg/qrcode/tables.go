@@ -0,0 +1,120 @@
+package qrcode
+
+// ecLevel уровень коррекции ошибок в порядке таблиц: L, M, Q, H
+type ecLevel int
+
+const (
+	ecLevelL ecLevel = iota
+	ecLevelM
+	ecLevelQ
+	ecLevelH
+)
+
+// ecLevelFromBits переводит два бита формата в уровень коррекции (L=01, M=00, Q=11, H=10)
+var ecLevelFromBits = [4]ecLevel{ecLevelM, ecLevelL, ecLevelH, ecLevelQ}
+
+// maxVersion наибольшая поддерживаемая версия (57x57 модулей).
+// Фискальные QR-коды и большинство печатных кодов укладываются в версии 1–6
+const maxVersion = 10
+
+// eccCodewordsPerBlock число кодовых слов коррекции в блоке [версия][уровень]
+var eccCodewordsPerBlock = [maxVersion + 1][4]int{
+	{},
+	{7, 10, 13, 17},
+	{10, 16, 22, 28},
+	{15, 26, 18, 22},
+	{20, 18, 26, 16},
+	{26, 24, 18, 22},
+	{18, 16, 24, 28},
+	{20, 18, 18, 26},
+	{24, 22, 22, 26},
+	{30, 22, 20, 24},
+	{18, 26, 24, 28},
+}
+
+// numErrorCorrectionBlocks число блоков [версия][уровень]
+var numErrorCorrectionBlocks = [maxVersion + 1][4]int{
+	{},
+	{1, 1, 1, 1},
+	{1, 1, 1, 1},
+	{1, 1, 2, 2},
+	{1, 2, 2, 4},
+	{1, 2, 4, 4},
+	{2, 4, 4, 4},
+	{2, 4, 6, 5},
+	{2, 4, 6, 6},
+	{2, 5, 8, 8},
+	{4, 5, 8, 8},
+}
+
+// alignmentPositions координаты центров выравнивающих узоров по каждой оси
+var alignmentPositions = [maxVersion + 1][]int{
+	{},
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// dimension размер символа версии в модулях
+func dimension(version int) int {
+	return 17 + 4*version
+}
+
+// numRawDataModules число модулей под данные и коррекцию (без служебных узоров)
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// formatBits кодирует уровень коррекции и маску в 15-битную строку формата (BCH + маска 0x5412)
+func formatBits(level ecLevel, mask int) int {
+	var levelBits int
+	for bits, l := range ecLevelFromBits {
+		if l == level {
+			levelBits = bits
+		}
+	}
+	data := levelBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// maskBit возвращает true, если маска инвертирует модуль в строке row и столбце col
+func maskBit(mask, row, col int) bool {
+	switch mask {
+	case 0:
+		return (row+col)%2 == 0
+	case 1:
+		return row%2 == 0
+	case 2:
+		return col%3 == 0
+	case 3:
+		return (row+col)%3 == 0
+	case 4:
+		return (row/2+col/3)%2 == 0
+	case 5:
+		return row*col%2+row*col%3 == 0
+	case 6:
+		return (row*col%2+row*col%3)%2 == 0
+	case 7:
+		return ((row+col)%2+row*col%3)%2 == 0
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Границы допустимых дат для правила date
+var (
+	minDate        = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxFutureDelta = 366 * 24 * time.Hour
+)
+
+var (
+	hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	iso4217Pattern  = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// nowFunc используется правилом date, подменяется в тестах
+var nowFunc = time.Now
+
+func builtinRules() map[string]Rule {
+	return map[string]Rule{
+		"email":         emailRule,
+		"password":      passwordRule,
+		"min":           minRule,
+		"max":           maxRule,
+		"gt":            gtRule,
+		"lt":            ltRule,
+		"latitude":      rangeRule(-90, 90),
+		"longitude":     rangeRule(-180, 180),
+		"date":          dateRule,
+		"hexcolor":      patternRule(hexColorPattern),
+		"iso4217":       patternRule(iso4217Pattern),
+		"oneof":         oneOfRule,
+		"required_with": requiredWithRule,
+	}
+}
+
+func emailRule(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+	value := field.String()
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value || !strings.Contains(value[strings.LastIndex(value, "@")+1:], ".") {
+		return "invalid_email", nil
+	}
+	return "", nil
+}
+
+// passwordRule требует не менее 8 символов, хотя бы одну букву и одну цифру
+func passwordRule(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+	value := field.String()
+	if utf8.RuneCountInString(value) < 8 {
+		return "too_short", map[string]any{"min": 8}
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return "weak_password", nil
+	}
+	return "", nil
+}
+
+// minRule минимальная длина строки или слайса
+func minRule(field reflect.Value, param string, _ reflect.Value) (string, map[string]any) {
+	limit, _ := strconv.Atoi(param)
+	if length(field) < limit {
+		if field.Kind() == reflect.Slice {
+			return "too_few", map[string]any{"min": limit}
+		}
+		return "too_short", map[string]any{"min": limit}
+	}
+	return "", nil
+}
+
+// maxRule максимальная длина строки или слайса
+func maxRule(field reflect.Value, param string, _ reflect.Value) (string, map[string]any) {
+	limit, _ := strconv.Atoi(param)
+	if length(field) > limit {
+		if field.Kind() == reflect.Slice {
+			return "too_many", map[string]any{"max": limit}
+		}
+		return "too_long", map[string]any{"max": limit}
+	}
+	return "", nil
+}
+
+// gtRule число строго больше параметра
+func gtRule(field reflect.Value, param string, _ reflect.Value) (string, map[string]any) {
+	limit, _ := strconv.ParseFloat(param, 64)
+	value, ok := number(field)
+	if !ok || value > limit {
+		return "", nil
+	}
+	if limit == 0 {
+		return "must_be_positive", nil
+	}
+	return "must_be_greater", map[string]any{"min": param}
+}
+
+// ltRule число строго меньше параметра
+func ltRule(field reflect.Value, param string, _ reflect.Value) (string, map[string]any) {
+	limit, _ := strconv.ParseFloat(param, 64)
+	value, ok := number(field)
+	if !ok || value < limit {
+		return "", nil
+	}
+	return "too_large", map[string]any{"max": param}
+}
+
+func rangeRule(min, max float64) Rule {
+	return func(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+		value, ok := number(field)
+		if !ok || (value >= min && value <= max) {
+			return "", nil
+		}
+		return "out_of_range", map[string]any{"min": min, "max": max}
+	}
+}
+
+// dateRule строка в формате RFC3339 в разумных пределах: не раньше 1970 года и не дальше года вперёд
+func dateRule(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+	var date time.Time
+	switch value := field.Interface().(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "invalid_date", nil
+		}
+		date = parsed
+	case time.Time:
+		date = value
+	default:
+		return "invalid_date", nil
+	}
+
+	if date.Before(minDate) || date.After(nowFunc().Add(maxFutureDelta)) {
+		return "date_out_of_range", nil
+	}
+	return "", nil
+}
+
+func patternRule(pattern *regexp.Regexp) Rule {
+	return func(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+		if !pattern.MatchString(field.String()) {
+			return "invalid_format", nil
+		}
+		return "", nil
+	}
+}
+
+// oneOfRule значение из списка, разделённого пробелами
+func oneOfRule(field reflect.Value, param string, _ reflect.Value) (string, map[string]any) {
+	value := field.String()
+	for _, allowed := range strings.Fields(param) {
+		if value == allowed {
+			return "", nil
+		}
+	}
+	return "not_allowed", map[string]any{"allowed": strings.ReplaceAll(param, " ", ", ")}
+}
+
+// requiredWithRule поле обязательно, если заполнено поле param (имя поля Go-структуры)
+func requiredWithRule(field reflect.Value, param string, parent reflect.Value) (string, map[string]any) {
+	other := parent.FieldByName(param)
+	if !other.IsValid() || isEmpty(other) || !isEmpty(field) {
+		return "", nil
+	}
+
+	name := param
+	if sf, ok := parent.Type().FieldByName(param); ok {
+		name = fieldName(sf)
+	}
+	return "required_with", map[string]any{"field": name}
+}
+
+func length(field reflect.Value) int {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return field.Len()
+	}
+	return 0
+}
+
+func number(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,197 @@
+package validator
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldError нарушение правила валидации для одного поля
+type FieldError struct {
+	Field  string
+	Code   string
+	Params map[string]any
+}
+
+// Errors список нарушений; Struct возвращает все нарушения сразу
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Code
+	}
+	return "validation failed: " + strings.Join(parts, ", ")
+}
+
+// Rule проверяет значение поля. Пустой code означает, что значение корректно.
+// parent — структура, которой принадлежит поле, для правил, зависящих от других полей
+type Rule func(field reflect.Value, param string, parent reflect.Value) (code string, params map[string]any)
+
+// Validator проверяет структуры по тегам `validate:"rule1,rule2=param"`.
+// Имена полей в ошибках берутся из тега json
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// New создаёт валидатор со встроенными правилами
+func New() *Validator {
+	v := &Validator{rules: make(map[string]Rule)}
+	for name, rule := range builtinRules() {
+		v.rules[name] = rule
+	}
+	return v
+}
+
+// Register добавляет или заменяет правило
+func (v *Validator) Register(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+// Struct проверяет структуру и вложенные структуры и слайсы структур
+func (v *Validator) Struct(s any) error {
+	var errs Errors
+	v.validateStruct(reflect.ValueOf(s), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *Validator) validateStruct(rv reflect.Value, prefix string, errs *Errors) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		path := joinPath(prefix, fieldName(sf))
+		fv := rv.Field(i)
+
+		if tag != "" && !v.validateField(rv, fv, path, tag, errs) {
+			continue
+		}
+
+		v.dive(fv, path, errs)
+	}
+}
+
+// validateField применяет правила к полю. Возвращает false, если поле не прошло проверку
+func (v *Validator) validateField(parent, fv reflect.Value, path, tag string, errs *Errors) bool {
+	for _, spec := range strings.Split(tag, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		name, param, _ := strings.Cut(spec, "=")
+
+		switch name {
+		case "omitempty":
+			if isEmpty(fv) {
+				return true
+			}
+			continue
+		case "required":
+			if isEmpty(fv) {
+				*errs = append(*errs, FieldError{Field: path, Code: "required"})
+				return false
+			}
+			continue
+		}
+
+		v.mu.RLock()
+		rule, ok := v.rules[name]
+		v.mu.RUnlock()
+		if !ok {
+			panic("validator: unknown rule " + name)
+		}
+
+		value := fv
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Ptr && value.IsNil() && name != "required_with" {
+			continue
+		}
+
+		if code, params := rule(value, param, parent); code != "" {
+			*errs = append(*errs, FieldError{Field: path, Code: code, Params: params})
+			return false
+		}
+	}
+	return true
+}
+
+// dive спускается во вложенные структуры и слайсы структур
+func (v *Validator) dive(fv reflect.Value, path string, errs *Errors) {
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return
+		}
+		fv = fv.Elem()
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		if fv.NumField() > 0 && fv.Type().PkgPath() != "time" {
+			v.validateStruct(fv, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			v.validateStruct(fv.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+// isEmpty считает пустыми nil, нулевые значения и строки из пробелов
+func isEmpty(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return fv.IsNil()
+	case reflect.String:
+		return strings.TrimSpace(fv.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return fv.Len() == 0
+	}
+	return fv.IsZero()
+}
+
+func fieldName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testPlace struct {
+	Name string   `json:"name" validate:"required,max=10"`
+	Lat  *float64 `json:"lat" validate:"required_with=Lon,omitempty,latitude"`
+	Lon  *float64 `json:"lon" validate:"required_with=Lat,omitempty,longitude"`
+}
+
+type testRequest struct {
+	Email    string      `json:"email" validate:"required,email"`
+	Password string      `json:"password" validate:"required,password"`
+	Amount   float64     `json:"amount" validate:"gt=0"`
+	Color    *string     `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Date     string      `json:"date" validate:"required,date"`
+	Sort     string      `json:"sort" validate:"omitempty,oneof=date amount"`
+	Places   []testPlace `json:"places" validate:"max=2"`
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func codes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected validator.Errors, got %v", err)
+	}
+	result := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		result[fe.Field] = fe.Code
+	}
+	return result
+}
+
+func TestStruct_Valid(t *testing.T) {
+	v := New()
+	req := testRequest{
+		Email:    "user@example.com",
+		Password: "secret123",
+		Amount:   10,
+		Date:     "2024-01-15T12:00:00Z",
+		Places:   []testPlace{{Name: "Дом", Lat: floatPtr(55.75), Lon: floatPtr(37.62)}},
+	}
+
+	if err := v.Struct(&req); err != nil {
+		t.Errorf("Expected no errors, got %v", err)
+	}
+}
+
+func TestStruct_ReportsAllViolations(t *testing.T) {
+	v := New()
+	color := "red"
+	req := testRequest{
+		Email:    "not-an-email",
+		Password: "password",
+		Amount:   -5,
+		Color:    &color,
+		Date:     "15.01.2024",
+		Sort:     "name",
+		Places: []testPlace{
+			{Name: "Очень длинное место", Lat: floatPtr(91)},
+		},
+	}
+
+	got := codes(t, v.Struct(&req))
+	want := map[string]string{
+		"email":          "invalid_email",
+		"password":       "weak_password",
+		"amount":         "must_be_positive",
+		"color":          "invalid_format",
+		"date":           "invalid_date",
+		"sort":           "not_allowed",
+		"places[0].name": "too_long",
+		"places[0].lat":  "out_of_range",
+		"places[0].lon":  "required_with",
+	}
+
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("Expected %s for %s, got %q", code, field, got[field])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d violations, got %d: %v", len(want), len(got), got)
+	}
+}
+
+func TestStruct_Required(t *testing.T) {
+	got := codes(t, New().Struct(&testRequest{Email: "  "}))
+
+	for _, field := range []string{"email", "password", "date"} {
+		if got[field] != "required" {
+			t.Errorf("Expected required for %s, got %q", field, got[field])
+		}
+	}
+}
+
+func TestDateRule_Sanity(t *testing.T) {
+	nowFunc = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { nowFunc = time.Now }()
+
+	type dated struct {
+		Date string `json:"date" validate:"date"`
+	}
+
+	tests := map[string]string{
+		"2025-06-01T00:00:00Z": "",
+		"1969-12-31T23:59:59Z": "date_out_of_range",
+		"2030-01-01T00:00:00Z": "date_out_of_range",
+	}
+
+	for date, want := range tests {
+		err := New().Struct(dated{Date: date})
+		if want == "" {
+			if err != nil {
+				t.Errorf("Expected %s to be valid, got %v", date, err)
+			}
+			continue
+		}
+		if got := codes(t, err)["date"]; got != want {
+			t.Errorf("Expected %s for %s, got %q", want, date, got)
+		}
+	}
+}
+
+func TestRegister_CustomRule(t *testing.T) {
+	v := New()
+	v.Register("even", func(field reflect.Value, _ string, _ reflect.Value) (string, map[string]any) {
+		if field.Int()%2 != 0 {
+			return "not_even", nil
+		}
+		return "", nil
+	})
+
+	type number struct {
+		Value int `json:"value" validate:"even"`
+	}
+
+	if err := v.Struct(number{Value: 2}); err != nil {
+		t.Errorf("Expected no errors, got %v", err)
+	}
+	if got := codes(t, v.Struct(number{Value: 3}))["value"]; got != "not_even" {
+		t.Errorf("Expected not_even, got %q", got)
+	}
+}
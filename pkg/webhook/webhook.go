@@ -0,0 +1,163 @@
+// Package webhook отправляет подписанные события на адреса подписчиков.
+// Тело запроса подписывается HMAC-SHA256 ключом подписки, подпись передаётся
+// в заголовке X-Webhook-Signature в виде "sha256=<hex>"
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Заголовки запроса с событием
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody сколько байт ответа подписчика сохраняется для журнала доставок
+const maxResponseBody = 1024
+
+// Request событие для одного подписчика
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Response ответ подписчика. Body усечён до maxResponseBody байт
+type Response struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// OK проверяет, что подписчик принял событие
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Sender отправляет события подписчикам
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender создаёт отправителя. Без client используется NewClient
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = NewClient()
+	}
+	return &Sender{client: client, now: time.Now}
+}
+
+// NewClient возвращает клиент для запросов на адреса пользователей: таймаут 10 секунд,
+// без переадресации и прокси, соединения только с публичными IP.
+// Адрес проверяется после разрешения имени, поэтому DNS-запись на внутренний адрес не поможет
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: denyPrivate,
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// cgnat разделяемое адресное пространство провайдеров (RFC 6598)
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// denyPrivate запрещает соединения с локальными, частными и служебными адресами
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("webhook: address %s is not allowed", host)
+	}
+	return nil
+}
+
+// publicIP проверяет, что адрес маршрутизируется в интернете
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || cgnat.Contains(ip))
+}
+
+// Send отправляет событие POST-запросом. Ошибка возвращается, только если ответа нет;
+// ответ с любым кодом возвращается без ошибки, его проверяет вызывающий
+func (s *Sender) Send(ctx context.Context, r *Request) (*Response, error) {
+	if err := ValidateURL(r.URL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "finance-dashboard-webhooks/1")
+	req.Header.Set(HeaderEvent, r.Event)
+	req.Header.Set(HeaderDelivery, r.DeliveryID)
+	req.Header.Set(HeaderSignature, Sign(r.Secret, r.Body))
+
+	started := s.now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		Duration:   s.now().Sub(started),
+	}, nil
+}
+
+// ValidateURL допускает только абсолютные адреса https без учётных данных.
+// Куда указывает адрес, проверяет клиент NewClient при соединении
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("webhook: invalid url %q", raw)
+	}
+	return nil
+}
+
+// Sign возвращает подпись тела HMAC-SHA256 в виде "sha256=<hex>"
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись тела за постоянное время. Используется получателями событий
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
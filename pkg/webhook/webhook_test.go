@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSign_KnownVector сверяет подпись с результатом `printf '{"a":1}' | openssl dgst -sha256 -hmac secret`
+func TestSign_KnownVector(t *testing.T) {
+	want := "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494"
+	if got := Sign("secret", []byte(`{"a":1}`)); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+	if !Verify("secret", []byte(`{"a":1}`), want) || Verify("other", []byte(`{"a":1}`), want) {
+		t.Error("Verify does not match Sign")
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(strings.Repeat("x", 2*maxResponseBody)))
+	}))
+	defer srv.Close()
+
+	resp, err := NewSender(srv.Client()).Send(context.Background(), &Request{
+		URL:        srv.URL,
+		Secret:     "whsec",
+		Event:      "transaction.created",
+		DeliveryID: "d-1",
+		Body:       []byte(`{"id":"e-1"}`),
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if !resp.OK() || resp.StatusCode != http.StatusAccepted || len(resp.Body) != maxResponseBody {
+		t.Errorf("Unexpected response %d with %d bytes", resp.StatusCode, len(resp.Body))
+	}
+	if string(body) != `{"id":"e-1"}` || !Verify("whsec", body, header.Get(HeaderSignature)) {
+		t.Errorf("Signature %q does not match body %s", header.Get(HeaderSignature), body)
+	}
+	if header.Get(HeaderEvent) != "transaction.created" || header.Get(HeaderDelivery) != "d-1" {
+		t.Errorf("Unexpected headers %v", header)
+	}
+}
+
+func TestSender_Errors(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	sender := NewSender(srv.Client())
+
+	resp, err := sender.Send(context.Background(), &Request{URL: srv.URL, Body: []byte(`{}`)})
+	if err != nil || resp.OK() || resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected 500 response without error, got %+v %v", resp, err)
+	}
+
+	srv.Close()
+	if _, err := sender.Send(context.Background(), &Request{URL: srv.URL, Body: []byte(`{}`)}); err == nil {
+		t.Error("Expected error for unreachable server")
+	}
+	if _, err := sender.Send(context.Background(), &Request{URL: "http://example.com"}); err == nil {
+		t.Error("Expected plain http url to be rejected")
+	}
+}
+
+func TestNewClient_DeniesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// Адрес httptest — loopback, клиент по умолчанию не должен к нему подключаться
+	if _, err := NewSender(nil).Send(context.Background(), &Request{URL: srv.URL, Body: []byte(`{}`)}); err == nil {
+		t.Error("Expected loopback address to be rejected")
+	}
+
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"127.0.0.1:443", false},
+		{"[::1]:443", false},
+		{"10.1.2.3:443", false},
+		{"172.16.0.1:443", false},
+		{"192.168.1.1:443", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:443", false},
+		{"[fd00::1]:443", false},
+		{"100.64.0.1:443", false},
+		{"0.0.0.0:443", false},
+		{"224.0.0.1:443", false},
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1::1]:443", true},
+	}
+	for _, tt := range tests {
+		if err := denyPrivate("tcp", tt.address, nil); (err == nil) != tt.allowed {
+			t.Errorf("denyPrivate(%s) error = %v, allowed %v", tt.address, err, tt.allowed)
+		}
+	}
+}